@@ -0,0 +1,15 @@
+package cli
+
+import (
+	"one-api/common/logger"
+	"one-api/model"
+)
+
+// EncryptExistingData 加密存量数据，轮换主密钥后也使用该命令重新加密
+func EncryptExistingData() {
+	if err := model.EncryptExistingData(); err != nil {
+		logger.SysError("Failed to encrypt data: " + err.Error())
+		return
+	}
+	logger.SysLog("Data encrypted with the current master key")
+}
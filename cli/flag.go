@@ -17,6 +17,7 @@ var (
 	logDir       = flag.String("log-dir", "", "specify the log directory")
 	Config       = flag.String("config", "config.yaml", "specify the config.yaml path")
 	export       = flag.Bool("export", false, "Exports prices to a JSON file.")
+	EncryptData  = flag.Bool("encrypt-data", false, "Encrypts existing secrets with the current master key and exits.")
 )
 
 func InitCli() {
@@ -61,5 +62,5 @@ func help() {
 	fmt.Println("Copyright (C) 2024 MartialBE. All rights reserved.")
 	fmt.Println("Original copyright holder: JustSong")
 	fmt.Println("GitHub: https://github.com/MartialBE/one-hub")
-	fmt.Println("Usage: one-api [--port <port>] [--log-dir <log directory>] [--config <config.yaml path>] [--version] [--help]")
+	fmt.Println("Usage: one-api [--port <port>] [--log-dir <log directory>] [--config <config.yaml path>] [--encrypt-data] [--version] [--help]")
 }
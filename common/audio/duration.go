@@ -0,0 +1,521 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"mime/multipart"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported audio format")
+
+// 与 whisper-1 的定价一致：$0.006 / 分钟，按 200 tokens / 分钟折算
+const TokensPerMinute = 200
+
+// DurationToTokens 按秒折算计费 tokens，不足一个 token 的部分向上取整
+func DurationToTokens(seconds float64) int {
+	if seconds <= 0 {
+		return 0
+	}
+	return int(math.Ceil(seconds * TokensPerMinute / 60))
+}
+
+// GetFileDuration 获取上传音频的时长（秒）
+func GetFileDuration(file *multipart.FileHeader) (float64, error) {
+	if file == nil {
+		return 0, errors.New("file is empty")
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+
+	return GetDuration(data)
+}
+
+// GetDuration 根据文件头识别格式并计算时长（秒），支持 wav、mp3、flac、ogg、m4a/mp4、webm
+func GetDuration(data []byte) (float64, error) {
+	var (
+		duration float64
+		err      error
+	)
+
+	switch {
+	case len(data) >= 12 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WAVE")):
+		duration, err = wavDuration(data)
+	case len(data) >= 4 && bytes.Equal(data[:4], []byte("OggS")):
+		duration, err = oggDuration(data)
+	case len(data) >= 4 && bytes.Equal(data[:4], []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		duration, err = webmDuration(data)
+	case len(data) >= 8 && bytes.Equal(data[4:8], []byte("ftyp")):
+		duration, err = mp4Duration(data)
+	default:
+		// flac 和 mp3 前面可能带有 ID3 标签
+		data = skipID3(data)
+		if len(data) >= 4 && bytes.Equal(data[:4], []byte("fLaC")) {
+			duration, err = flacDuration(data)
+		} else {
+			duration, err = mp3Duration(data)
+		}
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 || math.IsNaN(duration) || math.IsInf(duration, 0) {
+		return 0, ErrUnsupportedFormat
+	}
+	return duration, nil
+}
+
+func skipID3(data []byte) []byte {
+	for len(data) >= 10 && bytes.Equal(data[:3], []byte("ID3")) {
+		// 标签大小为 synchsafe 整数，不包含 10 字节的头部
+		size := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+		size += 10
+		if data[5]&0x10 != 0 {
+			size += 10
+		}
+		if size > len(data) {
+			return nil
+		}
+		data = data[size:]
+	}
+	return data
+}
+
+// wav 中可以按采样参数校验码率的编码：PCM、IEEE float、WAVE_FORMAT_EXTENSIBLE
+var wavLinearFormats = map[uint16]bool{1: true, 3: true, 0xFFFE: true}
+
+// wavDuration 根据采样率、声道数和位深计算码率，文件头中的码率与之不一致时视为无法识别，
+// 避免伪造的码率导致少计费
+func wavDuration(data []byte) (float64, error) {
+	var byteRate uint32
+	offset := 12
+	for offset+8 <= len(data) {
+		id := string(data[offset : offset+4])
+		size := binary.LittleEndian.Uint32(data[offset+4 : offset+8])
+		body := offset + 8
+
+		switch id {
+		case "fmt ":
+			if body+16 > len(data) {
+				return 0, ErrUnsupportedFormat
+			}
+			format := binary.LittleEndian.Uint16(data[body : body+2])
+			channels := uint32(binary.LittleEndian.Uint16(data[body+2 : body+4]))
+			sampleRate := binary.LittleEndian.Uint32(data[body+4 : body+8])
+			headerByteRate := binary.LittleEndian.Uint32(data[body+8 : body+12])
+			bitsPerSample := uint32(binary.LittleEndian.Uint16(data[body+14 : body+16]))
+
+			if !wavLinearFormats[format] || channels == 0 || channels > 32 || sampleRate == 0 || sampleRate > 768000 ||
+				bitsPerSample == 0 || bitsPerSample > 64 || bitsPerSample%8 != 0 {
+				return 0, ErrUnsupportedFormat
+			}
+			byteRate = sampleRate * channels * bitsPerSample / 8
+			if headerByteRate != byteRate {
+				return 0, ErrUnsupportedFormat
+			}
+		case "data":
+			if byteRate == 0 {
+				return 0, ErrUnsupportedFormat
+			}
+			// 流式写入的文件 data 长度可能未填写，使用剩余长度
+			dataSize := int64(size)
+			if remain := int64(len(data) - body); size == 0 || size == math.MaxUint32 || dataSize > remain {
+				dataSize = remain
+			}
+			return float64(dataSize) / float64(byteRate), nil
+		}
+
+		offset = body + int(size) + int(size&1)
+	}
+
+	return 0, ErrUnsupportedFormat
+}
+
+var mp3Bitrates = [2][3][16]int{
+	// MPEG-1: Layer I, II, III
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	},
+	// MPEG-2/2.5: Layer I, II, III
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	},
+}
+
+var mp3SampleRates = map[int][3]int{
+	3: {44100, 48000, 32000}, // MPEG-1
+	2: {22050, 24000, 16000}, // MPEG-2
+	0: {11025, 12000, 8000},  // MPEG-2.5
+}
+
+type mp3Frame struct {
+	version    int
+	layer      int
+	sampleRate int
+	samples    int
+	size       int
+	mono       bool
+}
+
+func parseMP3Frame(header []byte) (*mp3Frame, bool) {
+	if len(header) < 4 || header[0] != 0xFF || header[1]&0xE0 != 0xE0 {
+		return nil, false
+	}
+
+	version := int(header[1]>>3) & 0x03
+	layerBits := int(header[1]>>1) & 0x03
+	bitrateIndex := int(header[2]>>4) & 0x0F
+	sampleRateIndex := int(header[2]>>2) & 0x03
+	padding := int(header[2]>>1) & 0x01
+	if version == 1 || layerBits == 0 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+		return nil, false
+	}
+
+	layer := 4 - layerBits
+	versionIndex := 1
+	if version == 3 {
+		versionIndex = 0
+	}
+	bitrate := mp3Bitrates[versionIndex][layer-1][bitrateIndex] * 1000
+	sampleRate := mp3SampleRates[version][sampleRateIndex]
+
+	frame := &mp3Frame{
+		version:    version,
+		layer:      layer,
+		sampleRate: sampleRate,
+		mono:       header[3]>>6 == 3,
+	}
+	switch {
+	case layer == 1:
+		frame.samples = 384
+		frame.size = (12*bitrate/sampleRate + padding) * 4
+	case layer == 3 && version != 3:
+		frame.samples = 576
+		frame.size = 72*bitrate/sampleRate + padding
+	default:
+		frame.samples = 1152
+		frame.size = 144*bitrate/sampleRate + padding
+	}
+	if frame.size < 4 {
+		return nil, false
+	}
+
+	return frame, true
+}
+
+func mp3Duration(data []byte) (float64, error) {
+	offset := 0
+	for offset+4 <= len(data) {
+		if _, ok := parseMP3Frame(data[offset:]); ok {
+			break
+		}
+		offset++
+	}
+	if offset+4 > len(data) {
+		return 0, ErrUnsupportedFormat
+	}
+
+	// 优先使用 VBR 头中记录的总帧数
+	first, _ := parseMP3Frame(data[offset:])
+	if frames := mp3VBRFrames(data[offset:], first); frames > 0 {
+		return float64(frames) * float64(first.samples) / float64(first.sampleRate), nil
+	}
+
+	var samples float64
+	for offset+4 <= len(data) {
+		frame, ok := parseMP3Frame(data[offset:])
+		if !ok {
+			offset++
+			continue
+		}
+		samples += float64(frame.samples) / float64(frame.sampleRate)
+		offset += frame.size
+	}
+
+	return samples, nil
+}
+
+// mp3VBRFrames 读取 Xing/Info 或 VBRI 头中的总帧数
+func mp3VBRFrames(data []byte, frame *mp3Frame) int {
+	sideInfo := 32
+	switch {
+	case frame.version == 3 && frame.mono:
+		sideInfo = 17
+	case frame.version != 3 && !frame.mono:
+		sideInfo = 17
+	case frame.version != 3 && frame.mono:
+		sideInfo = 9
+	}
+
+	xing := 4 + sideInfo
+	if xing+12 <= len(data) {
+		tag := string(data[xing : xing+4])
+		if tag == "Xing" || tag == "Info" {
+			flags := binary.BigEndian.Uint32(data[xing+4 : xing+8])
+			if flags&0x01 != 0 {
+				return int(binary.BigEndian.Uint32(data[xing+8 : xing+12]))
+			}
+		}
+	}
+
+	vbri := 4 + 32
+	if vbri+18 <= len(data) && string(data[vbri:vbri+4]) == "VBRI" {
+		return int(binary.BigEndian.Uint32(data[vbri+14 : vbri+18]))
+	}
+
+	return 0
+}
+
+func flacDuration(data []byte) (float64, error) {
+	// STREAMINFO 必须是第一个元数据块
+	if len(data) < 4+4+18 || data[4]&0x7F != 0 {
+		return 0, ErrUnsupportedFormat
+	}
+
+	info := data[8:]
+	sampleRate := int(info[10])<<12 | int(info[11])<<4 | int(info[12])>>4
+	totalSamples := uint64(info[13]&0x0F)<<32 | uint64(binary.BigEndian.Uint32(info[14:18]))
+	if sampleRate == 0 || totalSamples == 0 {
+		return 0, ErrUnsupportedFormat
+	}
+
+	return float64(totalSamples) / float64(sampleRate), nil
+}
+
+func oggDuration(data []byte) (float64, error) {
+	// 第一页包含编码的识别头
+	if len(data) < 27 {
+		return 0, ErrUnsupportedFormat
+	}
+	segments := int(data[26])
+	packet := 27 + segments
+	if packet > len(data) {
+		return 0, ErrUnsupportedFormat
+	}
+	serial := binary.LittleEndian.Uint32(data[14:18])
+
+	var (
+		sampleRate int
+		preSkip    int
+	)
+	head := data[packet:]
+	switch {
+	case len(head) >= 12 && bytes.Equal(head[:8], []byte("OpusHead")):
+		// Opus 的采样位置固定为 48kHz
+		sampleRate = 48000
+		preSkip = int(binary.LittleEndian.Uint16(head[10:12]))
+	case len(head) >= 16 && bytes.Equal(head[:7], []byte("\x01vorbis")):
+		sampleRate = int(binary.LittleEndian.Uint32(head[12:16]))
+	default:
+		return 0, ErrUnsupportedFormat
+	}
+	if sampleRate == 0 {
+		return 0, ErrUnsupportedFormat
+	}
+
+	// 最后一页的 granule position 即总采样数
+	for offset := bytes.LastIndex(data, []byte("OggS")); offset >= 0; offset = bytes.LastIndex(data[:offset], []byte("OggS")) {
+		if offset+27 > len(data) || binary.LittleEndian.Uint32(data[offset+14:offset+18]) != serial {
+			continue
+		}
+		granule := int64(binary.LittleEndian.Uint64(data[offset+6 : offset+14]))
+		if granule <= 0 {
+			continue
+		}
+		return float64(granule-int64(preSkip)) / float64(sampleRate), nil
+	}
+
+	return 0, ErrUnsupportedFormat
+}
+
+func mp4Duration(data []byte) (float64, error) {
+	moov := findMP4Box(data, "moov")
+	if moov == nil {
+		return 0, ErrUnsupportedFormat
+	}
+	mvhd := findMP4Box(moov, "mvhd")
+	if len(mvhd) < 4 {
+		return 0, ErrUnsupportedFormat
+	}
+
+	var timescale, duration uint64
+	if mvhd[0] == 1 {
+		if len(mvhd) < 32 {
+			return 0, ErrUnsupportedFormat
+		}
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[20:24]))
+		duration = binary.BigEndian.Uint64(mvhd[24:32])
+	} else {
+		if len(mvhd) < 20 {
+			return 0, ErrUnsupportedFormat
+		}
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+	}
+	if timescale == 0 {
+		return 0, ErrUnsupportedFormat
+	}
+
+	return float64(duration) / float64(timescale), nil
+}
+
+// findMP4Box 返回同一层级中指定类型 box 的内容
+func findMP4Box(data []byte, boxType string) []byte {
+	offset := 0
+	for offset+8 <= len(data) {
+		size := uint64(binary.BigEndian.Uint32(data[offset : offset+4]))
+		name := string(data[offset+4 : offset+8])
+		header := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data) - offset)
+		case 1:
+			if offset+16 > len(data) {
+				return nil
+			}
+			size = binary.BigEndian.Uint64(data[offset+8 : offset+16])
+			header = 16
+		}
+		if size < header || uint64(offset)+size > uint64(len(data)) {
+			return nil
+		}
+
+		if name == boxType {
+			return data[uint64(offset)+header : uint64(offset)+size]
+		}
+		offset += int(size)
+	}
+	return nil
+}
+
+// Matroska/WebM 元素 ID
+const (
+	ebmlSegment       = 0x18538067
+	ebmlInfo          = 0x1549A966
+	ebmlTimecodeScale = 0x2AD7B1
+	ebmlDuration      = 0x4489
+	ebmlCluster       = 0x1F43B675
+	ebmlTimecode      = 0xE7
+	ebmlBlockGroup    = 0xA0
+	ebmlBlock         = 0xA1
+	ebmlSimpleBlock   = 0xA3
+)
+
+// webmDuration 优先读取 Info 中的 Duration，浏览器录制的文件通常没有该字段，
+// 此时使用最后一个数据块的时间戳
+func webmDuration(data []byte) (float64, error) {
+	timecodeScale := uint64(1000000)
+	var (
+		duration     float64
+		clusterTime  uint64
+		lastBlock    int64
+		hasBlockTime bool
+	)
+
+	offset := 0
+	for offset < len(data) {
+		id, idLen := readEBMLVint(data[offset:], true)
+		if idLen == 0 {
+			break
+		}
+		size, sizeLen := readEBMLVint(data[offset+idLen:], false)
+		if sizeLen == 0 {
+			break
+		}
+		body := offset + idLen + sizeLen
+		unknownSize := size == 1<<(7*sizeLen)-1
+
+		switch id {
+		case ebmlSegment, ebmlInfo, ebmlCluster, ebmlBlockGroup:
+			// 只关心部分子元素，直接进入容器内部解析
+			offset = body
+			continue
+		}
+		if unknownSize || uint64(len(data)-body) < size {
+			break
+		}
+		value := data[body : body+int(size)]
+
+		switch id {
+		case ebmlTimecodeScale:
+			if scale := readEBMLUint(value); scale > 0 {
+				timecodeScale = scale
+			}
+		case ebmlDuration:
+			switch len(value) {
+			case 4:
+				duration = float64(math.Float32frombits(binary.BigEndian.Uint32(value)))
+			case 8:
+				duration = math.Float64frombits(binary.BigEndian.Uint64(value))
+			}
+		case ebmlTimecode:
+			clusterTime = readEBMLUint(value)
+		case ebmlSimpleBlock, ebmlBlock:
+			_, trackLen := readEBMLVint(value, false)
+			if trackLen > 0 && len(value) >= trackLen+2 {
+				blockTime := int64(clusterTime) + int64(int16(binary.BigEndian.Uint16(value[trackLen:trackLen+2])))
+				if !hasBlockTime || blockTime > lastBlock {
+					lastBlock = blockTime
+					hasBlockTime = true
+				}
+			}
+		}
+		offset = body + int(size)
+	}
+
+	if duration > 0 {
+		return duration * float64(timecodeScale) / 1e9, nil
+	}
+	if hasBlockTime && lastBlock > 0 {
+		return float64(lastBlock) * float64(timecodeScale) / 1e9, nil
+	}
+	return 0, ErrUnsupportedFormat
+}
+
+// readEBMLVint 读取变长整数，keepMarker 为 true 时保留长度标记位（用于元素 ID）
+func readEBMLVint(data []byte, keepMarker bool) (uint64, int) {
+	if len(data) == 0 || data[0] == 0 {
+		return 0, 0
+	}
+
+	length := 1
+	for mask := byte(0x80); data[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+	if length > 8 || length > len(data) {
+		return 0, 0
+	}
+
+	value := uint64(data[0])
+	if !keepMarker {
+		value &= uint64(0xFF >> length)
+	}
+	for i := 1; i < length; i++ {
+		value = value<<8 | uint64(data[i])
+	}
+	return value, length
+}
+
+func readEBMLUint(data []byte) uint64 {
+	var value uint64
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
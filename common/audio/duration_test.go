@@ -0,0 +1,180 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type wavHeader struct {
+	format        uint16
+	channels      uint16
+	sampleRate    uint32
+	byteRate      uint32
+	bitsPerSample uint16
+	dataSize      uint32
+}
+
+func buildWav(h wavHeader, payload int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+payload))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, h.format)
+	binary.Write(&buf, binary.LittleEndian, h.channels)
+	binary.Write(&buf, binary.LittleEndian, h.sampleRate)
+	binary.Write(&buf, binary.LittleEndian, h.byteRate)
+	binary.Write(&buf, binary.LittleEndian, h.channels*h.bitsPerSample/8)
+	binary.Write(&buf, binary.LittleEndian, h.bitsPerSample)
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, h.dataSize)
+	buf.Write(make([]byte, payload))
+	return buf.Bytes()
+}
+
+func TestWavDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  wavHeader
+		payload int
+		want    float64
+		wantErr bool
+	}{
+		{
+			name:    "pcm 16k mono",
+			header:  wavHeader{format: 1, channels: 1, sampleRate: 16000, byteRate: 32000, bitsPerSample: 16, dataSize: 64000},
+			payload: 64000,
+			want:    2,
+		},
+		{
+			name:    "pcm 44.1k stereo",
+			header:  wavHeader{format: 1, channels: 2, sampleRate: 44100, byteRate: 176400, bitsPerSample: 16, dataSize: 176400},
+			payload: 176400,
+			want:    1,
+		},
+		{
+			name:    "float 48k mono",
+			header:  wavHeader{format: 3, channels: 1, sampleRate: 48000, byteRate: 192000, bitsPerSample: 32, dataSize: 96000},
+			payload: 96000,
+			want:    0.5,
+		},
+		{
+			name:    "data size not filled uses remaining length",
+			header:  wavHeader{format: 1, channels: 1, sampleRate: 8000, byteRate: 16000, bitsPerSample: 16, dataSize: 0},
+			payload: 32000,
+			want:    2,
+		},
+		{
+			name:    "data size larger than file uses remaining length",
+			header:  wavHeader{format: 1, channels: 1, sampleRate: 8000, byteRate: 16000, bitsPerSample: 16, dataSize: 0xFFFFFFF0},
+			payload: 16000,
+			want:    1,
+		},
+		{
+			name:    "forged byte rate",
+			header:  wavHeader{format: 1, channels: 1, sampleRate: 16000, byteRate: 0xFFFFFFFF, bitsPerSample: 16, dataSize: 64000},
+			payload: 64000,
+			wantErr: true,
+		},
+		{
+			name:    "byte rate lower than sample parameters",
+			header:  wavHeader{format: 1, channels: 1, sampleRate: 16000, byteRate: 16000, bitsPerSample: 16, dataSize: 64000},
+			payload: 64000,
+			wantErr: true,
+		},
+		{
+			name:    "zero channels",
+			header:  wavHeader{format: 1, channels: 0, sampleRate: 16000, byteRate: 0, bitsPerSample: 16, dataSize: 64000},
+			payload: 64000,
+			wantErr: true,
+		},
+		{
+			name:    "zero sample rate",
+			header:  wavHeader{format: 1, channels: 1, sampleRate: 0, byteRate: 0, bitsPerSample: 16, dataSize: 64000},
+			payload: 64000,
+			wantErr: true,
+		},
+		{
+			name:    "odd bits per sample",
+			header:  wavHeader{format: 1, channels: 1, sampleRate: 16000, byteRate: 24000, bitsPerSample: 12, dataSize: 64000},
+			payload: 64000,
+			wantErr: true,
+		},
+		{
+			name:    "compressed format",
+			header:  wavHeader{format: 0x11, channels: 1, sampleRate: 16000, byteRate: 8000, bitsPerSample: 4, dataSize: 64000},
+			payload: 64000,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duration, err := GetDuration(buildWav(tt.header, tt.payload))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, duration, 0.0001)
+		})
+	}
+}
+
+func TestWavDurationTruncated(t *testing.T) {
+	data := buildWav(wavHeader{format: 1, channels: 1, sampleRate: 16000, byteRate: 32000, bitsPerSample: 16, dataSize: 32000}, 32000)
+
+	for _, size := range []int{12, 20, 30, 36, 44} {
+		_, err := GetDuration(data[:size])
+		assert.Error(t, err, "size %d", size)
+	}
+}
+
+func TestDurationToTokens(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    int
+	}{
+		{seconds: 0, want: 0},
+		{seconds: -1, want: 0},
+		{seconds: 0.1, want: 1},
+		{seconds: 60, want: TokensPerMinute},
+		{seconds: 90, want: TokensPerMinute * 3 / 2},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DurationToTokens(tt.seconds), "seconds %v", tt.seconds)
+	}
+}
+
+func TestGetDurationUnknownFormat(t *testing.T) {
+	for _, data := range [][]byte{nil, []byte("hello"), []byte("RIFF\x00\x00\x00\x00WAVE"), []byte("OggS"), []byte("ID3\x04\x00\x00\x7f\x7f\x7f\x7f")} {
+		_, err := GetDuration(data)
+		assert.Error(t, err)
+	}
+}
+
+// FuzzGetDuration 任意输入都不能 panic，识别出的时长必须为有限正数
+func FuzzGetDuration(f *testing.F) {
+	f.Add(buildWav(wavHeader{format: 1, channels: 1, sampleRate: 16000, byteRate: 32000, bitsPerSample: 16, dataSize: 320}, 320))
+	f.Add([]byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00"))
+	f.Add([]byte{0x1A, 0x45, 0xDF, 0xA3, 0x9F, 0x42, 0x86, 0x81, 0x01})
+	f.Add([]byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"))
+	f.Add([]byte("fLaC\x00\x00\x00\x22"))
+	f.Add([]byte("ID3\x03\x00\x00\x00\x00\x00\x00\xFF\xFB\x90\x64"))
+	f.Add([]byte{0xFF, 0xFB, 0x90, 0x64, 0x00, 0x00})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		duration, err := GetDuration(data)
+		if err != nil {
+			return
+		}
+		if duration <= 0 || duration != duration {
+			t.Fatalf("invalid duration %v", duration)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"one-api/common/utils"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+var ErrTranscoderNotFound = errors.New("ffmpeg not found, unable to transcode audio")
+
+// OpenAI 约定 pcm 输出为 24kHz、16 位有符号小端、单声道
+const (
+	DefaultPCMSampleRate = 24000
+	DefaultPCMChannels   = 1
+)
+
+var speechContentTypes = map[string]string{
+	"mp3":  "audio/mpeg",
+	"opus": "audio/ogg",
+	"aac":  "audio/aac",
+	"flac": "audio/flac",
+	"wav":  "audio/wav",
+	"pcm":  "audio/pcm",
+}
+
+// ffmpeg 输出参数
+var ffmpegOutputArgs = map[string][]string{
+	"mp3":  {"-f", "mp3"},
+	"opus": {"-c:a", "libopus", "-f", "ogg"},
+	"aac":  {"-c:a", "aac", "-f", "adts"},
+	"flac": {"-f", "flac"},
+	"wav":  {"-f", "wav"},
+	"pcm":  {"-f", "s16le", "-ar", strconv.Itoa(DefaultPCMSampleRate), "-ac", strconv.Itoa(DefaultPCMChannels)},
+}
+
+// NormalizeSpeechFormat 规范化 response_format，未指定时默认 mp3
+func NormalizeSpeechFormat(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		return "mp3"
+	}
+	return format
+}
+
+// IsSpeechFormat 是否为 OpenAI 支持的语音合成格式
+func IsSpeechFormat(format string) bool {
+	_, ok := speechContentTypes[format]
+	return ok
+}
+
+func SpeechContentType(format string) string {
+	if contentType, ok := speechContentTypes[format]; ok {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+type TranscodeOptions struct {
+	From string
+	To   string
+	// 输入为 pcm 时的采样率和声道数
+	SampleRate int
+	Channels   int
+	// 语速，0 或 1 表示不调整
+	Speed float64
+}
+
+func (o *TranscodeOptions) needSpeed() bool {
+	return o.Speed > 0 && o.Speed != 1
+}
+
+// Transcode 转换音频格式，pcm 与 wav 之间直接转换，其余格式和语速调整依赖 ffmpeg
+func Transcode(data []byte, options TranscodeOptions) ([]byte, error) {
+	if options.SampleRate == 0 {
+		options.SampleRate = DefaultPCMSampleRate
+	}
+	if options.Channels == 0 {
+		options.Channels = DefaultPCMChannels
+	}
+
+	if !options.needSpeed() {
+		switch {
+		case options.From == options.To:
+			return data, nil
+		case options.From == "pcm" && options.To == "wav":
+			return PCMToWAV(data, options.SampleRate, options.Channels), nil
+		}
+	}
+
+	return ffmpegTranscode(data, options)
+}
+
+// PCMToWAV 为 16 位 PCM 数据添加 wav 文件头
+func PCMToWAV(pcm []byte, sampleRate, channels int) []byte {
+	const bitsPerSample = 16
+	blockAlign := channels * bitsPerSample / 8
+
+	buf := bytes.NewBuffer(make([]byte, 0, 44+len(pcm)))
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(36+len(pcm)))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(buf, binary.LittleEndian, uint32(16))
+	binary.Write(buf, binary.LittleEndian, uint16(1))
+	binary.Write(buf, binary.LittleEndian, uint16(channels))
+	binary.Write(buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(buf, binary.LittleEndian, uint32(sampleRate*blockAlign))
+	binary.Write(buf, binary.LittleEndian, uint16(blockAlign))
+	binary.Write(buf, binary.LittleEndian, uint16(bitsPerSample))
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, uint32(len(pcm)))
+	buf.Write(pcm)
+
+	return buf.Bytes()
+}
+
+func ffmpegTranscode(data []byte, options TranscodeOptions) ([]byte, error) {
+	outputArgs, ok := ffmpegOutputArgs[options.To]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+
+	ffmpeg, err := exec.LookPath(utils.GetOrDefault("ffmpeg_path", "ffmpeg"))
+	if err != nil {
+		return nil, ErrTranscoderNotFound
+	}
+
+	args := []string{"-hide_banner", "-loglevel", "error"}
+	if options.From == "pcm" {
+		args = append(args, "-f", "s16le", "-ar", strconv.Itoa(options.SampleRate), "-ac", strconv.Itoa(options.Channels))
+	}
+	args = append(args, "-i", "pipe:0", "-vn")
+	if options.needSpeed() {
+		args = append(args, "-filter:a", atempoFilter(options.Speed))
+	}
+	args = append(args, outputArgs...)
+	args = append(args, "pipe:1")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(ffmpeg, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg transcode failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// atempo 单个滤镜只支持 0.5 ~ 2 倍，超出范围时串联多个
+func atempoFilter(speed float64) string {
+	filters := make([]string, 0, 2)
+	for speed > 2 {
+		filters = append(filters, "atempo=2.0")
+		speed /= 2
+	}
+	for speed < 0.5 {
+		filters = append(filters, "atempo=0.5")
+		speed /= 0.5
+	}
+	filters = append(filters, "atempo="+strconv.FormatFloat(speed, 'f', 4, 64))
+	return strings.Join(filters, ",")
+}
+
+// NewSpeechResponse 将合成的音频包装为响应
+func NewSpeechResponse(data []byte, format string) *http.Response {
+	response := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		Header:     make(http.Header),
+	}
+	response.Header.Set("Content-Type", SpeechContentType(format))
+	response.Header.Set("Content-Length", strconv.Itoa(len(data)))
+
+	return response
+}
@@ -22,6 +22,7 @@ func InitConf() {
 	UPTIMEKUMA_ENABLE = viper.GetBool("uptime_kuma.enable") != false
 	UPTIMEKUMA_DOMAIN = viper.GetString("uptime_kuma.domain")
 	UPTIMEKUMA_STATUS_PAGE_NAME = viper.GetString("uptime_kuma.status_page_name")
+	LogConsumeSQLDisabled = viper.GetBool("log_sink.disable_sql")
 }
 
 func setEnv() {
@@ -40,6 +41,7 @@ func defaultConfig() {
 	viper.SetDefault("global.api_rate_limit", 300)
 	viper.SetDefault("global.web_rate_limit", 180)
 	viper.SetDefault("connect_timeout", 5)
+	viper.SetDefault("channel.balance_history_days", 30)
 	viper.SetDefault("auto_price_updates", false)
 	viper.SetDefault("auto_price_updates_mode", "system")
 	viper.SetDefault("auto_price_updates_interval", 1440)
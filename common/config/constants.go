@@ -125,6 +125,18 @@ var SafeKeyWords = []string{
 	"methamphetamine",
 }
 
+// 正则审查自定义规则，每行一个正则表达式
+var SafeRegexRules = []string{}
+
+// Moderation 审查使用的渠道和模型
+var SafeModerationChannelId = 0
+var SafeModerationModel = "omni-moderation-latest"
+
+// LLM 审查使用的渠道、模型和提示词
+var SafeLLMJudgeChannelId = 0
+var SafeLLMJudgeModel = "gpt-4o-mini"
+var SafeLLMJudgePrompt = `You are a content safety reviewer. Decide whether the following content violates safety policies (violence, sexual content involving minors, hate, self-harm, illegal activities, etc.). Reply with JSON only: {"safe": true|false, "reason": "short reason"}.`
+
 // mj
 var MjNotifyEnabled = false
 
@@ -145,6 +157,30 @@ var MemoryCacheEnabled = false
 
 var LogConsumeEnabled = true
 
+// 日志保留天数，0 表示不自动清理
+var LogRetentionConsumeDays = 0
+var LogRetentionSystemDays = 0
+
+// 清理前是否归档到存储
+var LogArchiveEnabled = false
+
+// 不再将消费日志写入数据库，统计数据改为实时累加
+var LogConsumeSQLDisabled = false
+
+// 请求内容记录，按用户、令牌或采样比例开启
+var PayloadCaptureEnabled = false
+var PayloadCaptureUserIds = []int{}
+var PayloadCaptureTokenIds = []int{}
+
+// 采样比例，百分比
+var PayloadCaptureSampleRate = 0.0
+
+// 单项内容最大记录字节数
+var PayloadCaptureMaxBytes = 1024 * 1024
+
+// 记录保留天数，0 表示不自动清理
+var PayloadCaptureRetentionDays = 7
+
 var SMTPServer = ""
 var SMTPPort = 587
 var SMTPAccount = ""
@@ -305,6 +341,9 @@ const (
 	ChannelTypeAzureDatabricks = 54
 	ChannelTypeAzureV1         = 55
 	ChannelTypeXAI             = 56
+	ChannelTypeWhisper         = 57
+	ChannelTypeTTS             = 58
+	ChannelTypeDeclarative     = 59
 )
 
 const (
@@ -0,0 +1,65 @@
+package config
+
+// 管理权限，用于 /api 下的管理接口
+const (
+	PermissionChannelsRead     = "channels.read"
+	PermissionChannelsWrite    = "channels.write"
+	PermissionPricesRead       = "prices.read"
+	PermissionPricesWrite      = "prices.write"
+	PermissionModelsRead       = "models.read"
+	PermissionModelsWrite      = "models.write"
+	PermissionUsersRead        = "users.read"
+	PermissionUsersManage      = "users.manage"
+	PermissionUsersQuota       = "users.quota"
+	PermissionUserGroupsRead   = "user_groups.read"
+	PermissionUserGroupsWrite  = "user_groups.write"
+	PermissionRedemptionsRead  = "redemptions.read"
+	PermissionRedemptionsWrite = "redemptions.write"
+	PermissionLogsRead         = "logs.read"
+	PermissionLogsDelete       = "logs.delete"
+	PermissionLogsRestore      = "logs.restore"
+	PermissionAnalyticsRead    = "analytics.read"
+	PermissionPaymentsRead     = "payments.read"
+	PermissionPaymentsWrite    = "payments.write"
+	PermissionPaymentsRefund   = "payments.refund"
+	PermissionSafetyRead       = "safety.read"
+	PermissionSafetyWrite      = "safety.write"
+	PermissionOptionsWrite     = "options.write"
+	PermissionRolesManage      = "roles.manage"
+	PermissionAuditRead        = "audit.read"
+)
+
+var AllPermissions = []string{
+	PermissionChannelsRead,
+	PermissionChannelsWrite,
+	PermissionPricesRead,
+	PermissionPricesWrite,
+	PermissionModelsRead,
+	PermissionModelsWrite,
+	PermissionUsersRead,
+	PermissionUsersManage,
+	PermissionUsersQuota,
+	PermissionUserGroupsRead,
+	PermissionUserGroupsWrite,
+	PermissionRedemptionsRead,
+	PermissionRedemptionsWrite,
+	PermissionLogsRead,
+	PermissionLogsDelete,
+	PermissionLogsRestore,
+	PermissionAnalyticsRead,
+	PermissionPaymentsRead,
+	PermissionPaymentsWrite,
+	PermissionPaymentsRefund,
+	PermissionSafetyRead,
+	PermissionSafetyWrite,
+	PermissionOptionsWrite,
+	PermissionRolesManage,
+	PermissionAuditRead,
+}
+
+// RootOnlyPermissions 未分配角色的管理员不具备的权限，保持与原先 RootAuth 接口一致
+var RootOnlyPermissions = []string{
+	PermissionLogsRestore,
+	PermissionOptionsWrite,
+	PermissionRolesManage,
+}
@@ -0,0 +1,31 @@
+package config
+
+// 令牌范围，限制令牌可以调用的接口
+const (
+	TokenScopeChat           = "chat"
+	TokenScopeEmbeddings     = "embeddings"
+	TokenScopeImages         = "images"
+	TokenScopeAudio          = "audio"
+	TokenScopeRealtime       = "realtime"
+	TokenScopeTasks          = "tasks"
+	TokenScopeFiles          = "files"
+	TokenScopeManagementRead = "management.read"
+	TokenScopeBillingRead    = "billing.read" // OpenAI 兼容的 /dashboard/billing 余额和用量查询
+)
+
+var AllTokenScopes = []string{
+	TokenScopeChat,
+	TokenScopeEmbeddings,
+	TokenScopeImages,
+	TokenScopeAudio,
+	TokenScopeRealtime,
+	TokenScopeTasks,
+	TokenScopeFiles,
+	TokenScopeManagementRead,
+	TokenScopeBillingRead,
+}
+
+// ExplicitTokenScopes 未设置范围的令牌不具备的范围，需要显式授予
+var ExplicitTokenScopes = []string{
+	TokenScopeManagementRead,
+}
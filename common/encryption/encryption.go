@@ -0,0 +1,193 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"one-api/common/logger"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// 加密后的格式为 enc:v1:{主密钥 ID}:{加密后的数据密钥}:{密文}，
+// 每个进程生成一个数据密钥，由 KMS 使用主密钥加密后随密文一起保存
+const encryptedPrefix = "enc:v1:"
+
+var (
+	kms        KMS
+	mu         sync.Mutex
+	currentKey *dataKey
+	// 已解密的数据密钥，键为 {主密钥 ID}:{加密后的数据密钥}
+	dataKeys sync.Map
+)
+
+type dataKey struct {
+	keyId   string
+	wrapped string
+	aead    cipher.AEAD
+}
+
+// InitEncryption 根据配置初始化 KMS，开启后初始化失败会直接退出，避免明文写入数据库
+func InitEncryption() {
+	if !viper.GetBool("encryption.enable") {
+		return
+	}
+
+	name := viper.GetString("encryption.kms")
+	if name == "" {
+		name = "local"
+	}
+	factory, ok := kmsFactories[name]
+	if !ok {
+		logger.FatalLog("unknown encryption kms: " + name)
+	}
+
+	instance, err := factory("encryption." + name)
+	if err != nil {
+		logger.FatalLog("failed to init encryption kms " + name + ": " + err.Error())
+	}
+	kms = instance
+	logger.SysLog("encryption enabled, kms: " + name + ", primary key: " + kms.PrimaryKeyId())
+}
+
+func Enabled() bool {
+	return kms != nil
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// Encrypt 未开启加密、空值或已加密的值原样返回
+func Encrypt(plaintext string) (string, error) {
+	if kms == nil || plaintext == "" || IsEncrypted(plaintext) {
+		return plaintext, nil
+	}
+
+	key, err := getCurrentKey()
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(key.aead, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedPrefix + key.keyId + ":" + key.wrapped + ":" + base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt 未加密的值原样返回，兼容开启加密前写入的数据
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if kms == nil {
+		return "", errors.New("encryption is not enabled, unable to decrypt data")
+	}
+
+	keyId, wrapped, encoded, err := parseEncrypted(value)
+	if err != nil {
+		return "", err
+	}
+	key, err := getDataKey(keyId, wrapped)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(key.aead, ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation 开启加密后，明文或未使用当前主密钥加密的值需要重新加密
+func NeedsRotation(value string) bool {
+	if kms == nil || value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+
+	keyId, _, _, err := parseEncrypted(value)
+	return err == nil && keyId != kms.PrimaryKeyId()
+}
+
+// Rotate 使用当前主密钥重新加密
+func Rotate(value string) (string, error) {
+	plaintext, err := Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return Encrypt(plaintext)
+}
+
+func parseEncrypted(value string) (keyId, wrapped, ciphertext string, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedPrefix), ":", 3)
+	if len(parts) != 3 {
+		return "", "", "", errors.New("invalid encrypted data")
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func getCurrentKey() (*dataKey, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if currentKey != nil && currentKey.keyId == kms.PrimaryKeyId() {
+		return currentKey, nil
+	}
+
+	plainKey := make([]byte, 32)
+	if _, err := rand.Read(plainKey); err != nil {
+		return nil, err
+	}
+	keyId, wrapped, err := kms.WrapKey(plainKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(plainKey)
+	if err != nil {
+		return nil, err
+	}
+
+	currentKey = &dataKey{
+		keyId:   keyId,
+		wrapped: base64.RawStdEncoding.EncodeToString(wrapped),
+		aead:    aead,
+	}
+	dataKeys.Store(currentKey.keyId+":"+currentKey.wrapped, currentKey)
+	return currentKey, nil
+}
+
+func getDataKey(keyId, wrapped string) (*dataKey, error) {
+	cacheKey := keyId + ":" + wrapped
+	if key, ok := dataKeys.Load(cacheKey); ok {
+		return key.(*dataKey), nil
+	}
+
+	wrappedBytes, err := base64.RawStdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	plainKey, err := kms.UnwrapKey(keyId, wrappedBytes)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(plainKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &dataKey{keyId: keyId, wrapped: wrapped, aead: aead}
+	dataKeys.Store(cacheKey, key)
+	return key, nil
+}
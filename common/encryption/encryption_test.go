@@ -0,0 +1,173 @@
+package encryption
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testOldMasterKey = "old-master-key-0123456789abcdefghij"
+	testNewMasterKey = "new-master-key-0123456789abcdefghij"
+)
+
+// setupTestKMS 使用本地 KMS，第一个为主密钥，其余为历史密钥
+func setupTestKMS(t *testing.T, keys ...string) {
+	t.Helper()
+	v := viper.GetViper()
+	v.Set("encryption.local.master_key", "")
+	v.Set("encryption.local.previous_keys", []string{})
+	if len(keys) > 0 {
+		v.Set("encryption.local.master_key", keys[0])
+		v.Set("encryption.local.previous_keys", keys[1:])
+	}
+
+	kms = nil
+	if len(keys) > 0 {
+		instance, err := newLocalKMS("encryption.local")
+		require.NoError(t, err)
+		kms = instance
+	}
+	currentKey = nil
+	dataKeys = sync.Map{}
+
+	t.Cleanup(func() {
+		kms = nil
+		currentKey = nil
+		dataKeys = sync.Map{}
+	})
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		wantEncrypted bool
+	}{
+		{name: "plaintext", value: "sk-123456", wantEncrypted: true},
+		{name: "empty value", value: ""},
+		{name: "multiline", value: "line1\nline2:with:colons", wantEncrypted: true},
+		{name: "unicode", value: "密钥🔑", wantEncrypted: true},
+	}
+
+	setupTestKMS(t, testOldMasterKey)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := Encrypt(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEncrypted, IsEncrypted(encrypted))
+			if tt.wantEncrypted {
+				assert.NotContains(t, encrypted, tt.value)
+			}
+
+			// 已加密的值不会重复加密
+			again, err := Encrypt(encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, encrypted, again)
+
+			decrypted, err := Decrypt(encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, decrypted)
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	setupTestKMS(t, testOldMasterKey)
+	oldKeyId := kms.PrimaryKeyId()
+	oldValue, err := Encrypt("sk-old")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		keys          []string
+		wantDecrypt   bool
+		wantRotation  bool
+		wantPlaintext string
+	}{
+		{name: "same primary key", keys: []string{testOldMasterKey}, wantDecrypt: true, wantPlaintext: "sk-old"},
+		{name: "new primary key with previous key", keys: []string{testNewMasterKey, testOldMasterKey}, wantDecrypt: true, wantRotation: true, wantPlaintext: "sk-old"},
+		{name: "previous key removed", keys: []string{testNewMasterKey}, wantRotation: true},
+		{name: "encryption disabled", keys: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestKMS(t, tt.keys...)
+			assert.Equal(t, tt.wantRotation, NeedsRotation(oldValue))
+
+			plaintext, err := Decrypt(oldValue)
+			if !tt.wantDecrypt {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPlaintext, plaintext)
+
+			rotated, err := Rotate(oldValue)
+			require.NoError(t, err)
+			assert.False(t, NeedsRotation(rotated))
+			keyId, _, _, err := parseEncrypted(rotated)
+			require.NoError(t, err)
+			assert.Equal(t, kms.PrimaryKeyId(), keyId)
+			if tt.wantRotation {
+				assert.NotEqual(t, oldKeyId, keyId)
+			}
+
+			plaintext, err = Decrypt(rotated)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPlaintext, plaintext)
+		})
+	}
+}
+
+func TestNeedsRotation(t *testing.T) {
+	setupTestKMS(t, testOldMasterKey)
+	encrypted, err := Encrypt("sk-123456")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{name: "empty value", value: "", want: false},
+		{name: "plaintext", value: "sk-123456", want: true},
+		{name: "current primary key", value: encrypted, want: false},
+		{name: "invalid encrypted value", value: encryptedPrefix + "broken", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NeedsRotation(tt.value))
+		})
+	}
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	setupTestKMS(t, testOldMasterKey)
+	encrypted, err := Encrypt("sk-123456")
+	require.NoError(t, err)
+	keyId, wrapped, ciphertext, err := parseEncrypted(encrypted)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "missing parts", value: encryptedPrefix + keyId},
+		{name: "unknown master key", value: encryptedPrefix + "ffffffff:" + wrapped + ":" + ciphertext},
+		{name: "invalid ciphertext encoding", value: encryptedPrefix + keyId + ":" + wrapped + ":***"},
+		{name: "tampered ciphertext", value: encryptedPrefix + keyId + ":" + wrapped + ":" + ciphertext[:len(ciphertext)-4] + "AAAA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decrypt(tt.value)
+			assert.Error(t, err)
+		})
+	}
+}
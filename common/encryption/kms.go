@@ -0,0 +1,142 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// KMS 主密钥管理，只负责加密和解密数据密钥，主密钥不离开 KMS
+type KMS interface {
+	Name() string
+	// PrimaryKeyId 当前用于加密的主密钥 ID
+	PrimaryKeyId() string
+	// WrapKey 使用当前主密钥加密数据密钥
+	WrapKey(dataKey []byte) (keyId string, wrapped []byte, err error)
+	// UnwrapKey 使用指定的主密钥解密数据密钥
+	UnwrapKey(keyId string, wrapped []byte) ([]byte, error)
+}
+
+type kmsFactory func(prefix string) (KMS, error)
+
+var kmsFactories = map[string]kmsFactory{
+	"local": newLocalKMS,
+}
+
+// RegisterKMS 注册其他 KMS 实现，需在 InitEncryption 之前调用
+func RegisterKMS(name string, factory func(prefix string) (KMS, error)) {
+	kmsFactories[name] = factory
+}
+
+// localKMS 主密钥来自配置、环境变量或本地文件
+type localKMS struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+func newLocalKMS(prefix string) (KMS, error) {
+	var secrets []string
+	if masterKey := viper.GetString(prefix + ".master_key"); masterKey != "" {
+		secrets = append(secrets, masterKey)
+	}
+	if keyFile := viper.GetString(prefix + ".key_file"); keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				secrets = append(secrets, line)
+			}
+		}
+	}
+	secrets = append(secrets, viper.GetStringSlice(prefix+".previous_keys")...)
+
+	if len(secrets) == 0 {
+		return nil, errors.New("master key is required")
+	}
+
+	kms := &localKMS{keys: make(map[string]cipher.AEAD, len(secrets))}
+	for _, secret := range secrets {
+		if len(secret) < 32 {
+			return nil, errors.New("master key must be at least 32 characters")
+		}
+		// 任意长度的主密钥统一派生为 AES-256 密钥，ID 取派生密钥摘要的前 8 位
+		key := sha256.Sum256([]byte(secret))
+		id := localKeyId(key[:])
+		if _, ok := kms.keys[id]; ok {
+			continue
+		}
+
+		aead, err := newAEAD(key[:])
+		if err != nil {
+			return nil, err
+		}
+		kms.keys[id] = aead
+		if kms.primary == "" {
+			kms.primary = id
+		}
+	}
+
+	return kms, nil
+}
+
+func localKeyId(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+func (k *localKMS) Name() string {
+	return "local"
+}
+
+func (k *localKMS) PrimaryKeyId() string {
+	return k.primary
+}
+
+func (k *localKMS) WrapKey(dataKey []byte) (string, []byte, error) {
+	wrapped, err := seal(k.keys[k.primary], dataKey)
+	return k.primary, wrapped, err
+}
+
+func (k *localKMS) UnwrapKey(keyId string, wrapped []byte) ([]byte, error) {
+	aead, ok := k.keys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("master key %s not found", keyId)
+	}
+	return open(aead, wrapped)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal 返回 nonce 与密文拼接后的结果
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,87 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// ErrMaskEmpty 蒙版中没有需要编辑的区域
+var ErrMaskEmpty = errors.New("mask has no transparent area")
+
+// ReadImageFile 读取上传的图片，返回内容和 MIME 类型
+func ReadImageFile(file *multipart.FileHeader) ([]byte, string, error) {
+	if file == nil {
+		return nil, "", errors.New("image is required")
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, http.DetectContentType(data), nil
+}
+
+// ConvertAlphaMask 将 OpenAI 格式的蒙版（透明区域为编辑区域）转换为灰度蒙版（白色为编辑区域，黑色为保留区域），返回 PNG
+// 未上传蒙版时 OpenAI 使用原图的透明区域，因此也可以直接传入原图
+func ConvertAlphaMask(data []byte) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	mask := image.NewGray(bounds)
+	hasEditArea := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			_, _, _, a := src.At(x, y).RGBA()
+			// 半透明按透明处理
+			if a < 0x8000 {
+				mask.SetGray(x, y, color.Gray{Y: 0xff})
+				hasEditArea = true
+			}
+		}
+	}
+
+	if !hasEditArea {
+		return nil, ErrMaskEmpty
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, mask); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GetEditMask 获取编辑请求的灰度蒙版，优先使用上传的蒙版，否则使用原图的透明区域
+// 两者都没有透明区域时返回 nil，表示对整张图片进行编辑
+func GetEditMask(imageData []byte, maskFile *multipart.FileHeader) ([]byte, error) {
+	if maskFile != nil {
+		maskData, _, err := ReadImageFile(maskFile)
+		if err != nil {
+			return nil, err
+		}
+		return ConvertAlphaMask(maskData)
+	}
+
+	mask, err := ConvertAlphaMask(imageData)
+	if errors.Is(err, ErrMaskEmpty) {
+		return nil, nil
+	}
+	return mask, err
+}
@@ -0,0 +1,68 @@
+package logsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// clickHouseSink 通过 ClickHouse 的 HTTP 接口以 JSONEachRow 格式批量写入
+// 表结构需要自行创建，字段与日志的 JSON 字段对应，未知字段会被忽略
+type clickHouseSink struct {
+	url     string
+	headers map[string]string
+}
+
+func newClickHouseSink(prefix string) (Sink, error) {
+	endpoint := viper.GetString(prefix + ".url")
+	if endpoint == "" {
+		return nil, errors.New("url is required")
+	}
+
+	table := viper.GetString(prefix + ".table")
+	if table == "" {
+		table = "logs"
+	}
+	if database := viper.GetString(prefix + ".database"); database != "" {
+		table = database + "." + table
+	}
+
+	query := url.Values{}
+	query.Set("query", "INSERT INTO "+table+" FORMAT JSONEachRow")
+	query.Set("input_format_skip_unknown_fields", "1")
+	query.Set("input_format_json_read_objects_as_strings", "1")
+
+	headers := map[string]string{"Content-Type": "application/x-ndjson"}
+	if username := viper.GetString(prefix + ".username"); username != "" {
+		auth := username + ":" + viper.GetString(prefix+".password")
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+	}
+
+	return &clickHouseSink{
+		url:     strings.TrimSuffix(endpoint, "/") + "/?" + query.Encode(),
+		headers: headers,
+	}, nil
+}
+
+func (s *clickHouseSink) Name() string {
+	return "clickhouse"
+}
+
+func (s *clickHouseSink) Write(ctx context.Context, entries []*Entry) error {
+	var body bytes.Buffer
+	for _, entry := range entries {
+		body.Write(entry.Data)
+		body.WriteByte('\n')
+	}
+
+	return postBody(ctx, s.url, body.Bytes(), s.headers)
+}
+
+func (s *clickHouseSink) Close() error {
+	return nil
+}
@@ -0,0 +1,60 @@
+package logsink
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// fileSink 以 JSON Lines 格式追加写入本地文件，主要用于测试
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newFileSink(prefix string) (Sink, error) {
+	path := viper.GetString(prefix + ".path")
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Name() string {
+	return "file"
+}
+
+func (s *fileSink) Write(_ context.Context, entries []*Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writer := bufio.NewWriter(s.file)
+	for _, entry := range entries {
+		writer.Write(entry.Data)
+		writer.WriteByte('\n')
+	}
+
+	return writer.Flush()
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
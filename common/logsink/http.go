@@ -0,0 +1,138 @@
+package logsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var httpClient = &http.Client{Timeout: writeTimeout}
+
+// postBody 发送请求并检查状态码
+func postBody(ctx context.Context, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("status code %d: %s", resp.StatusCode, string(message))
+	}
+
+	return nil
+}
+
+// httpSink 以 JSON 数组的形式批量发送到任意 HTTP 地址
+type httpSink struct {
+	url     string
+	headers map[string]string
+}
+
+func newHTTPSink(prefix string) (Sink, error) {
+	url := viper.GetString(prefix + ".url")
+	if url == "" {
+		return nil, errors.New("url is required")
+	}
+
+	headers := viper.GetStringMapString(prefix + ".headers")
+	headers["Content-Type"] = "application/json"
+
+	return &httpSink{url: url, headers: headers}, nil
+}
+
+func (s *httpSink) Name() string {
+	return "http"
+}
+
+func (s *httpSink) Write(ctx context.Context, entries []*Entry) error {
+	var body bytes.Buffer
+	body.WriteByte('[')
+	for i, entry := range entries {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(entry.Data)
+	}
+	body.WriteByte(']')
+
+	return postBody(ctx, s.url, body.Bytes(), s.headers)
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
+
+// lokiSink 使用 Loki 的 push API 发送日志
+type lokiSink struct {
+	url     string
+	labels  map[string]string
+	headers map[string]string
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+func newLokiSink(prefix string) (Sink, error) {
+	url := viper.GetString(prefix + ".url")
+	if url == "" {
+		return nil, errors.New("url is required")
+	}
+
+	labels := viper.GetStringMapString(prefix + ".labels")
+	if len(labels) == 0 {
+		labels = map[string]string{"job": "one-hub"}
+	}
+
+	headers := viper.GetStringMapString(prefix + ".headers")
+	headers["Content-Type"] = "application/json"
+
+	return &lokiSink{url: url, labels: labels, headers: headers}, nil
+}
+
+func (s *lokiSink) Name() string {
+	return "loki"
+}
+
+func (s *lokiSink) Write(ctx context.Context, entries []*Entry) error {
+	values := make([][2]string, 0, len(entries))
+	for _, entry := range entries {
+		timestamp := time.Unix(entry.CreatedAt, 0).UnixNano()
+		values = append(values, [2]string{fmt.Sprintf("%d", timestamp), string(entry.Data)})
+	}
+
+	body, err := json.Marshal(lokiPushRequest{
+		Streams: []lokiStream{{Stream: s.labels, Values: values}},
+	})
+	if err != nil {
+		return err
+	}
+
+	return postBody(ctx, s.url, body, s.headers)
+}
+
+func (s *lokiSink) Close() error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package logsink
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/compress"
+	"github.com/spf13/viper"
+)
+
+// kafkaSink 发送到 Kafka，兼容 Kafka、Redpanda 等
+// 以 key 的哈希选择分区，没有 key 时轮询
+type kafkaSink struct {
+	writer *kafka.Writer
+}
+
+var kafkaCompressions = map[string]compress.Compression{
+	"":       0,
+	"none":   0,
+	"gzip":   compress.Gzip,
+	"snappy": compress.Snappy,
+	"lz4":    compress.Lz4,
+	"zstd":   compress.Zstd,
+}
+
+func newKafkaSink(prefix string) (Sink, error) {
+	brokers := viper.GetStringSlice(prefix + ".brokers")
+	if len(brokers) == 0 {
+		return nil, errors.New("brokers is required")
+	}
+
+	topic := viper.GetString(prefix + ".topic")
+	if topic == "" {
+		return nil, errors.New("topic is required")
+	}
+
+	acks := kafka.RequireOne
+	if viper.IsSet(prefix + ".acks") {
+		acks = kafka.RequiredAcks(viper.GetInt(prefix + ".acks"))
+	}
+	switch acks {
+	case kafka.RequireNone, kafka.RequireOne, kafka.RequireAll:
+	default:
+		return nil, fmt.Errorf("invalid acks %d", acks)
+	}
+
+	compression, ok := kafkaCompressions[viper.GetString(prefix+".compression")]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression %s", viper.GetString(prefix+".compression"))
+	}
+
+	transport := &kafka.Transport{
+		ClientID:    "one-hub",
+		DialTimeout: writeTimeout,
+	}
+	if viper.GetBool(prefix + ".tls") {
+		transport.TLS = &tls.Config{}
+	}
+
+	return &kafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: acks,
+			Compression:  compression,
+			WriteTimeout: writeTimeout,
+			// 日志已经由 batchWriter 攒批，这里不再等待
+			BatchTimeout: 10 * time.Millisecond,
+			BatchSize:    1000,
+			Transport:    transport,
+		},
+	}, nil
+}
+
+func (s *kafkaSink) Name() string {
+	return "kafka"
+}
+
+func (s *kafkaSink) Write(ctx context.Context, entries []*Entry) error {
+	return s.writer.WriteMessages(ctx, kafkaMessages(entries)...)
+}
+
+func (s *kafkaSink) Close() error {
+	return s.writer.Close()
+}
+
+// kafkaMessages 转换为 Kafka 消息，没有 key 时不设置
+func kafkaMessages(entries []*Entry) []kafka.Message {
+	messages := make([]kafka.Message, 0, len(entries))
+	for _, entry := range entries {
+		message := kafka.Message{
+			Value: entry.Data,
+			Time:  time.Unix(entry.CreatedAt, 0),
+		}
+		if entry.Key != "" {
+			message.Key = []byte(entry.Key)
+		}
+		messages = append(messages, message)
+	}
+
+	return messages
+}
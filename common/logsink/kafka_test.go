@@ -0,0 +1,83 @@
+package logsink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/compress"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKafkaSink(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          map[string]any
+		wantErr         bool
+		wantAcks        kafka.RequiredAcks
+		wantCompression compress.Compression
+		wantTLS         bool
+	}{
+		{
+			name:     "defaults",
+			config:   map[string]any{"brokers": []string{"127.0.0.1:9092"}, "topic": "logs"},
+			wantAcks: kafka.RequireOne,
+		},
+		{
+			name:            "all options",
+			config:          map[string]any{"brokers": []string{"a:9092", "b:9092"}, "topic": "logs", "acks": -1, "compression": "zstd", "tls": true},
+			wantAcks:        kafka.RequireAll,
+			wantCompression: compress.Zstd,
+			wantTLS:         true,
+		},
+		{
+			name:     "no acks",
+			config:   map[string]any{"brokers": []string{"127.0.0.1:9092"}, "topic": "logs", "acks": 0},
+			wantAcks: kafka.RequireNone,
+		},
+		{name: "missing brokers", config: map[string]any{"topic": "logs"}, wantErr: true},
+		{name: "missing topic", config: map[string]any{"brokers": []string{"127.0.0.1:9092"}}, wantErr: true},
+		{name: "invalid acks", config: map[string]any{"brokers": []string{"127.0.0.1:9092"}, "topic": "logs", "acks": 2}, wantErr: true},
+		{name: "invalid compression", config: map[string]any{"brokers": []string{"127.0.0.1:9092"}, "topic": "logs", "compression": "brotli"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			for key, value := range tt.config {
+				viper.Set("log_sink.kafka."+key, value)
+			}
+
+			sink, err := newKafkaSink("log_sink.kafka")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			writer := sink.(*kafkaSink).writer
+			assert.Equal(t, "logs", writer.Topic)
+			assert.Equal(t, tt.wantAcks, writer.RequiredAcks)
+			assert.Equal(t, tt.wantCompression, writer.Compression)
+			assert.Equal(t, tt.wantTLS, writer.Transport.(*kafka.Transport).TLS != nil)
+			assert.NoError(t, sink.Close())
+		})
+	}
+}
+
+func TestKafkaMessages(t *testing.T) {
+	entries := []*Entry{
+		{CreatedAt: 1700000000, Key: "42", Data: []byte(`{"id":1}`)},
+		{CreatedAt: 1700000001, Data: []byte(`{"id":2}`)},
+	}
+
+	messages := kafkaMessages(entries)
+	require.Len(t, messages, 2)
+	assert.Equal(t, []byte("42"), messages[0].Key)
+	assert.Equal(t, []byte(`{"id":1}`), messages[0].Value)
+	assert.Equal(t, time.Unix(1700000000, 0), messages[0].Time)
+	assert.Nil(t, messages[1].Key, "empty key must not be sent so the balancer can spread messages")
+}
@@ -0,0 +1,106 @@
+package logsink
+
+import (
+	"context"
+	"fmt"
+	"one-api/common/logger"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Entry 一条待发送的日志
+type Entry struct {
+	// CreatedAt 日志时间，秒级时间戳
+	CreatedAt int64
+	// Key 分区使用的键，例如用户 ID
+	Key string
+	// Data JSON 格式的日志内容
+	Data []byte
+}
+
+// Sink 日志的外部存储
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, entries []*Entry) error
+	Close() error
+}
+
+type sinkFactory func(prefix string) (Sink, error)
+
+var sinkFactories = map[string]sinkFactory{
+	"file":       newFileSink,
+	"clickhouse": newClickHouseSink,
+	"loki":       newLokiSink,
+	"http":       newHTTPSink,
+	"kafka":      newKafkaSink,
+}
+
+var (
+	writers []*batchWriter
+	mu      sync.RWMutex
+)
+
+// InitLogSinks 根据配置初始化日志外部存储
+func InitLogSinks() {
+	batchSize := viper.GetInt("log_sink.batch_size")
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	flushInterval := time.Duration(viper.GetInt("log_sink.flush_interval")) * time.Second
+	if flushInterval <= 0 {
+		flushInterval = 5 * time.Second
+	}
+	bufferSize := viper.GetInt("log_sink.buffer_size")
+	if bufferSize <= 0 {
+		bufferSize = 10000
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for name, factory := range sinkFactories {
+		prefix := "log_sink." + name
+		if !viper.IsSet(prefix) || !viper.GetBool(prefix+".enable") {
+			continue
+		}
+
+		sink, err := factory(prefix)
+		if err != nil {
+			logger.SysError(fmt.Sprintf("failed to init log sink %s: %s", name, err.Error()))
+			continue
+		}
+
+		writers = append(writers, newBatchWriter(sink, batchSize, bufferSize, flushInterval))
+		logger.SysLog("log sink enabled: " + name)
+	}
+}
+
+// Enabled 是否配置了外部存储
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return len(writers) > 0
+}
+
+// Push 将日志加入发送队列，队列已满时丢弃
+func Push(entry *Entry) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, writer := range writers {
+		writer.push(entry)
+	}
+}
+
+// Close 发送队列中剩余的日志并关闭所有存储
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, writer := range writers {
+		writer.close()
+	}
+	writers = nil
+}
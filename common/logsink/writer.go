@@ -0,0 +1,95 @@
+package logsink
+
+import (
+	"context"
+	"fmt"
+	"one-api/common/logger"
+	"sync/atomic"
+	"time"
+)
+
+const writeTimeout = 30 * time.Second
+
+// batchWriter 异步批量写入，每个存储单独一个队列，互不影响
+type batchWriter struct {
+	sink          Sink
+	entries       chan *Entry
+	batchSize     int
+	flushInterval time.Duration
+	dropped       atomic.Int64
+	done          chan struct{}
+}
+
+func newBatchWriter(sink Sink, batchSize, bufferSize int, flushInterval time.Duration) *batchWriter {
+	w := &batchWriter{
+		sink:          sink,
+		entries:       make(chan *Entry, bufferSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		done:          make(chan struct{}),
+	}
+	go w.run()
+
+	return w
+}
+
+func (w *batchWriter) push(entry *Entry) {
+	select {
+	case w.entries <- entry:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+func (w *batchWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Entry, 0, w.batchSize)
+	for {
+		select {
+		case entry, ok := <-w.entries:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= w.batchSize {
+				w.flush(batch)
+				batch = make([]*Entry, 0, w.batchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = make([]*Entry, 0, w.batchSize)
+			}
+			if dropped := w.dropped.Swap(0); dropped > 0 {
+				logger.SysError(fmt.Sprintf("log sink %s queue is full, dropped %d logs", w.sink.Name(), dropped))
+			}
+		}
+	}
+}
+
+func (w *batchWriter) flush(batch []*Entry) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if err := w.sink.Write(ctx, batch); err != nil {
+		logger.SysError(fmt.Sprintf("log sink %s failed to write %d logs: %s", w.sink.Name(), len(batch), err.Error()))
+	}
+}
+
+func (w *batchWriter) close() {
+	close(w.entries)
+	<-w.done
+
+	if err := w.sink.Close(); err != nil {
+		logger.SysError(fmt.Sprintf("log sink %s failed to close: %s", w.sink.Name(), err.Error()))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"one-api/common"
+	"one-api/common/tracing"
 	"one-api/common/utils"
 	"one-api/types"
 	"strconv"
@@ -72,9 +73,15 @@ func (r *HTTPRequester) NewRequest(method, url string, setters ...requestOption)
 	return req, nil
 }
 
+// doRequest 发送请求，传递链路信息并按需记录请求内容
+func doRequest(req *http.Request) (*http.Response, error) {
+	tracing.InjectHeader(req.Context(), req.Header)
+	return sendWithCapture(req)
+}
+
 // 发送请求
 func (r *HTTPRequester) SendRequest(req *http.Request, response any, outputResp bool) (*http.Response, *types.OpenAIErrorWithStatusCode) {
-	resp, err := HTTPClient.Do(req)
+	resp, err := doRequest(req)
 	if err != nil {
 		return nil, common.ErrorWrapper(err, "http_request_failed", http.StatusInternalServerError)
 	}
@@ -114,7 +121,7 @@ func (r *HTTPRequester) SendRequest(req *http.Request, response any, outputResp
 // 发送请求 RAW
 func (r *HTTPRequester) SendRequestRaw(req *http.Request) (*http.Response, *types.OpenAIErrorWithStatusCode) {
 	// 发送请求
-	resp, err := HTTPClient.Do(req)
+	resp, err := doRequest(req)
 	if err != nil {
 		return nil, common.ErrorWrapper(err, "http_request_failed", http.StatusInternalServerError)
 	}
@@ -0,0 +1,148 @@
+package requester
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type payloadRecorderKey struct{}
+
+// PayloadRecorder 记录发送给上游的请求和上游的原始响应
+// 重试时会被覆盖，最终保留最后一次上游请求
+type PayloadRecorder struct {
+	sync.Mutex
+	limit            int
+	upstreamURL      string
+	upstreamRequest  []byte
+	upstreamResponse limitedBuffer
+}
+
+func NewPayloadRecorder(limit int) *PayloadRecorder {
+	return &PayloadRecorder{
+		limit:            limit,
+		upstreamResponse: limitedBuffer{limit: limit},
+	}
+}
+
+// WithPayloadRecorder 将记录器绑定到上游请求使用的 context
+func WithPayloadRecorder(ctx context.Context, recorder *PayloadRecorder) context.Context {
+	return context.WithValue(ctx, payloadRecorderKey{}, recorder)
+}
+
+func getPayloadRecorder(ctx context.Context) *PayloadRecorder {
+	recorder, _ := ctx.Value(payloadRecorderKey{}).(*PayloadRecorder)
+	return recorder
+}
+
+// Get 获取记录的上游地址、请求和响应
+func (p *PayloadRecorder) Get() (url string, request []byte, response []byte) {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.upstreamURL, p.upstreamRequest, bytes.Clone(p.upstreamResponse.Bytes())
+}
+
+func (p *PayloadRecorder) recordRequest(req *http.Request) {
+	var body []byte
+	if req.GetBody != nil {
+		if reader, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(io.LimitReader(reader, int64(p.limit)))
+			reader.Close()
+		}
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	p.upstreamURL = maskURL(req.URL)
+	p.upstreamRequest = body
+	p.upstreamResponse.Reset()
+}
+
+// maskURL 隐藏地址中的认证信息和查询参数的值，
+// 部分渠道（百度、Gemini、声明式渠道的 query 认证等）将密钥放在查询参数中
+func maskURL(u *url.URL) string {
+	masked := *u
+	masked.User = nil
+	if masked.RawQuery == "" {
+		return masked.String()
+	}
+
+	query := masked.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		keys[i] = url.QueryEscape(key) + "=***"
+	}
+	masked.RawQuery = strings.Join(keys, "&")
+	return masked.String()
+}
+
+// wrapResponse 在读取上游响应时同步记录，不影响原有的读取流程
+func (p *PayloadRecorder) wrapResponse(resp *http.Response) {
+	resp.Body = &captureReadCloser{
+		ReadCloser: resp.Body,
+		recorder:   p,
+	}
+}
+
+func (p *PayloadRecorder) write(data []byte) {
+	p.Lock()
+	defer p.Unlock()
+	p.upstreamResponse.Write(data)
+}
+
+type captureReadCloser struct {
+	io.ReadCloser
+	recorder *PayloadRecorder
+}
+
+func (c *captureReadCloser) Read(data []byte) (int, error) {
+	n, err := c.ReadCloser.Read(data)
+	if n > 0 {
+		c.recorder.write(data[:n])
+	}
+	return n, err
+}
+
+// limitedBuffer 超过限制的内容直接丢弃
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	if remain := b.limit - b.Len(); remain > 0 {
+		if len(data) > remain {
+			b.Buffer.Write(data[:remain])
+		} else {
+			b.Buffer.Write(data)
+		}
+	}
+	return len(data), nil
+}
+
+// sendWithCapture 发送请求，如果请求绑定了记录器则记录请求和响应
+func sendWithCapture(req *http.Request) (*http.Response, error) {
+	recorder := getPayloadRecorder(req.Context())
+	if recorder != nil {
+		recorder.recordRequest(req)
+	}
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil || recorder == nil {
+		return resp, err
+	}
+
+	recorder.wrapResponse(resp)
+
+	return resp, nil
+}
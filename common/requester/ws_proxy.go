@@ -2,6 +2,7 @@ package requester
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -19,6 +20,9 @@ type WSProxy struct {
 	done           chan struct{}
 	userClosed     chan struct{}
 	supplierClosed chan struct{}
+	// 消息处理函数可以回复消息来源，同一连接可能被两个方向同时写入
+	userLock     sync.Mutex
+	supplierLock sync.Mutex
 }
 
 type MessageSource int
@@ -28,7 +32,14 @@ const (
 	SupplierMessage
 )
 
-type MessageHandler func(source MessageSource, messageType int, message []byte) (bool, *types.UsageEvent, []byte, error)
+// ProxyMessage 处理后的消息，Reply 为 true 时发回消息来源，用于协议转换
+type ProxyMessage struct {
+	Data  []byte
+	Reply bool
+}
+
+// MessageHandler 返回的消息为 nil 时原样转发，否则转发替换后的消息（空切片表示丢弃原消息）
+type MessageHandler func(source MessageSource, messageType int, message []byte) (bool, *types.UsageEvent, []ProxyMessage, error)
 type UsageHandler func(usage *types.UsageEvent) error
 
 func NewWSProxy(userConn, supplierConn *websocket.Conn, timeout time.Duration, handler MessageHandler, usageHandler UsageHandler) *WSProxy {
@@ -82,10 +93,10 @@ func (p *WSProxy) transfer(src, dst *websocket.Conn, source MessageSource, close
 		}
 
 		if p.handler != nil {
-			shouldContinue, usage, newMessage, err := p.handler(source, messageType, message)
+			shouldContinue, usage, newMessages, err := p.handler(source, messageType, message)
 			if err != nil {
 				errMsg := []byte(err.Error())
-				dst.WriteMessage(websocket.TextMessage, errMsg)
+				p.write(dst, websocket.TextMessage, errMsg)
 				logger.SysError(fmt.Sprintf("source: %d, handler error: %s", source, err.Error()))
 				return
 			}
@@ -94,26 +105,59 @@ func (p *WSProxy) transfer(src, dst *websocket.Conn, source MessageSource, close
 				return
 			}
 
-			if newMessage != nil {
-				message = newMessage
+			// 转换后的消息按文本发送，原样转发的消息保持原类型
+			outType := websocket.TextMessage
+			if newMessages == nil {
+				newMessages = []ProxyMessage{{Data: message}}
+				outType = messageType
 			}
 
 			if usage != nil && p.usageHandler != nil {
 				err := p.usageHandler(usage)
 				if err != nil {
-					dst.WriteMessage(websocket.TextMessage, message)
+					p.writeMessages(src, dst, outType, newMessages)
 					errMsg := []byte(err.Error())
-					dst.WriteMessage(websocket.TextMessage, errMsg)
+					p.write(dst, websocket.TextMessage, errMsg)
 					logger.SysError(fmt.Sprintf("source: %d, usageHandler error: %s", source, err.Error()))
 					return
 				}
 			}
+
+			if err := p.writeMessages(src, dst, outType, newMessages); err != nil {
+				logger.SysError(fmt.Sprintf("source: %d, WriteMessage error: %s", source, err.Error()))
+				return
+			}
+			continue
 		}
 
-		err = dst.WriteMessage(messageType, message)
+		err = p.write(dst, messageType, message)
 		if err != nil {
 			logger.SysError(fmt.Sprintf("source: %d, WriteMessage error: %s", source, err.Error()))
 			return
 		}
 	}
 }
+
+func (p *WSProxy) writeMessages(src, dst *websocket.Conn, messageType int, messages []ProxyMessage) error {
+	for _, message := range messages {
+		conn := dst
+		if message.Reply {
+			conn = src
+		}
+		if err := p.write(conn, messageType, message.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *WSProxy) write(conn *websocket.Conn, messageType int, message []byte) error {
+	lock := &p.supplierLock
+	if conn == p.userConn {
+		lock = &p.userLock
+	}
+	lock.Lock()
+	defer lock.Unlock()
+
+	return conn.WriteMessage(messageType, message)
+}
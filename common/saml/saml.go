@@ -0,0 +1,212 @@
+package saml
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+	"github.com/spf13/viper"
+)
+
+const (
+	MetadataPath = "/api/saml/metadata"
+	AcsPath      = "/api/saml/acs"
+)
+
+// UserInfo 从断言中解析出的用户信息
+type UserInfo struct {
+	NameID      string
+	Username    string
+	Email       string
+	DisplayName string
+	Groups      []string
+}
+
+var (
+	serviceProvider *saml.ServiceProvider
+	mu              sync.Mutex
+)
+
+func Enabled() bool {
+	return viper.GetBool("saml.enable")
+}
+
+// GetServiceProvider 获取 SP 实例，首次调用时加载证书和 IdP 元数据
+func GetServiceProvider() (*saml.ServiceProvider, error) {
+	if !Enabled() {
+		return nil, errors.New("saml is not enabled")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if serviceProvider != nil {
+		return serviceProvider, nil
+	}
+
+	sp, err := newServiceProvider()
+	if err != nil {
+		logger.SysError("SAML配置错误, err:" + err.Error())
+		return nil, err
+	}
+	serviceProvider = sp
+	logger.SysLog("SAML功能启用")
+
+	return serviceProvider, nil
+}
+
+func newServiceProvider() (*saml.ServiceProvider, error) {
+	keyPair, err := tls.LoadX509KeyPair(viper.GetString("saml.cert_file"), viper.GetString("saml.key_file"))
+	if err != nil {
+		return nil, fmt.Errorf("load sp certificate: %w", err)
+	}
+	key, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("sp private key must be RSA")
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("parse sp certificate: %w", err)
+	}
+
+	idpMetadata, err := loadIDPMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("load idp metadata: %w", err)
+	}
+
+	rootURL, err := url.Parse(strings.TrimSuffix(config.ServerAddress, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid server address: %w", err)
+	}
+	metadataURL := rootURL.ResolveReference(&url.URL{Path: MetadataPath})
+	acsURL := rootURL.ResolveReference(&url.URL{Path: AcsPath})
+
+	entityID := viper.GetString("saml.entity_id")
+	if entityID == "" {
+		entityID = metadataURL.String()
+	}
+
+	return &saml.ServiceProvider{
+		EntityID:          entityID,
+		Key:               key,
+		Certificate:       cert,
+		MetadataURL:       *metadataURL,
+		AcsURL:            *acsURL,
+		IDPMetadata:       idpMetadata,
+		AuthnNameIDFormat: saml.UnspecifiedNameIDFormat,
+		AllowIDPInitiated: viper.GetBool("saml.allow_idp_initiated"),
+		SignatureMethod:   viper.GetString("saml.signature_method"),
+	}, nil
+}
+
+// loadIDPMetadata 优先从 URL 获取 IdP 元数据，其次读取本地文件
+func loadIDPMetadata() (*saml.EntityDescriptor, error) {
+	if metadataURL := viper.GetString("saml.idp_metadata_url"); metadataURL != "" {
+		u, err := url.Parse(metadataURL)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return samlsp.FetchMetadata(ctx, http.DefaultClient, *u)
+	}
+
+	metadataFile := viper.GetString("saml.idp_metadata_file")
+	if metadataFile == "" {
+		return nil, errors.New("idp_metadata_url or idp_metadata_file is required")
+	}
+	data, err := os.ReadFile(metadataFile)
+	if err != nil {
+		return nil, err
+	}
+	return samlsp.ParseMetadata(data)
+}
+
+// ParseUserInfo 按配置的属性名解析用户信息，未配置用户名属性时使用 NameID
+func ParseUserInfo(assertion *saml.Assertion) *UserInfo {
+	info := &UserInfo{}
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		info.NameID = assertion.Subject.NameID.Value
+	}
+
+	info.Username = firstAttribute(assertion, viper.GetString("saml.attributes.username"))
+	if info.Username == "" {
+		info.Username = info.NameID
+	}
+	info.Email = firstAttribute(assertion, viper.GetString("saml.attributes.email"))
+	info.DisplayName = firstAttribute(assertion, viper.GetString("saml.attributes.display_name"))
+	info.Groups = attributeValues(assertion, viper.GetString("saml.attributes.group"))
+
+	return info
+}
+
+// MapGroup 将 IdP 的分组映射为系统用户分组，按配置顺序取第一个匹配项
+func MapGroup(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+
+	owned := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		owned[group] = true
+	}
+
+	var mappings []struct {
+		IdpGroup  string `mapstructure:"idp_group"`
+		UserGroup string `mapstructure:"user_group"`
+	}
+	if err := viper.UnmarshalKey("saml.group_mapping", &mappings); err != nil {
+		logger.SysError("invalid saml.group_mapping: " + err.Error())
+		return ""
+	}
+
+	for _, mapping := range mappings {
+		if owned[mapping.IdpGroup] {
+			return mapping.UserGroup
+		}
+	}
+
+	return ""
+}
+
+func attributeValues(assertion *saml.Assertion, name string) []string {
+	values := make([]string, 0)
+	if name == "" {
+		return values
+	}
+
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			if attr.Name != name && attr.FriendlyName != name {
+				continue
+			}
+			for _, value := range attr.Values {
+				if value.Value != "" {
+					values = append(values, value.Value)
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+func firstAttribute(assertion *saml.Assertion, name string) string {
+	values := attributeValues(assertion, name)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/viper"
+)
+
+// ArchiveDrive 用于归档文件的存储，需要支持按 key 读取
+type ArchiveDrive interface {
+	Name() string
+	Put(key string, reader io.ReadSeeker) error
+	Get(key string) (io.ReadCloser, error)
+}
+
+var archiveDrives []ArchiveDrive
+
+func AddArchiveDrive(drive ArchiveDrive) {
+	for _, d := range archiveDrives {
+		if d.Name() == drive.Name() {
+			return
+		}
+	}
+	archiveDrives = append(archiveDrives, drive)
+}
+
+// getArchiveDrive 获取指定名称的归档存储，名称为空时使用配置的存储或第一个可用的存储
+func getArchiveDrive(name string) (ArchiveDrive, error) {
+	if name == "" {
+		name = viper.GetString("storage.archive_drive")
+	}
+
+	for _, drive := range archiveDrives {
+		if name == "" || drive.Name() == name {
+			return drive, nil
+		}
+	}
+
+	if name == "" {
+		return nil, errors.New("no archive storage available")
+	}
+	return nil, fmt.Errorf("archive storage %s not found", name)
+}
+
+func ArchiveEnabled() bool {
+	_, err := getArchiveDrive("")
+	return err == nil
+}
+
+// Archive 保存归档文件，返回使用的存储名称
+func Archive(key string, reader io.ReadSeeker) (string, error) {
+	drive, err := getArchiveDrive("")
+	if err != nil {
+		return "", err
+	}
+
+	if err := drive.Put(key, reader); err != nil {
+		return "", fmt.Errorf("%s: %w", drive.Name(), err)
+	}
+
+	return drive.Name(), nil
+}
+
+// ReadArchive 从指定的存储读取归档文件，调用方负责关闭
+func ReadArchive(driveName, key string) (io.ReadCloser, error) {
+	drive, err := getArchiveDrive(driveName)
+	if err != nil {
+		return nil, err
+	}
+
+	return drive.Get(key)
+}
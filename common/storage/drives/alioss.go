@@ -3,6 +3,8 @@ package drives
 import (
 	"bytes"
 	"fmt"
+	"io"
+
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 )
 
@@ -26,17 +28,26 @@ func (a *AliOSSUpload) Name() string {
 	return "AliOSS"
 }
 
-func (a *AliOSSUpload) Upload(data []byte, fileName string) (string, error) {
+func (a *AliOSSUpload) bucket() (*oss.Bucket, error) {
 	// Create OSS Client
 	client, err := oss.New(a.Endpoint, a.AccessKeyId, a.AccessKeySecret)
 	if err != nil {
-		return "", fmt.Errorf("creating OSS client: %w", err)
+		return nil, fmt.Errorf("creating OSS client: %w", err)
 	}
 
 	// Create Bucket
 	bucket, err := client.Bucket(a.BucketName)
 	if err != nil {
-		return "", fmt.Errorf("getting bucket: %w", err)
+		return nil, fmt.Errorf("getting bucket: %w", err)
+	}
+
+	return bucket, nil
+}
+
+func (a *AliOSSUpload) Upload(data []byte, fileName string) (string, error) {
+	bucket, err := a.bucket()
+	if err != nil {
+		return "", err
 	}
 
 	// Upload File
@@ -54,3 +65,31 @@ func (a *AliOSSUpload) Upload(data []byte, fileName string) (string, error) {
 
 	return objectURL, nil
 }
+
+// Put 按指定的 key 保存文件，用于归档
+func (a *AliOSSUpload) Put(key string, reader io.ReadSeeker) error {
+	bucket, err := a.bucket()
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.PutObject(key, reader); err != nil {
+		return fmt.Errorf("uploading file: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AliOSSUpload) Get(key string) (io.ReadCloser, error) {
+	bucket, err := a.bucket()
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := bucket.GetObject(key)
+	if err != nil {
+		return nil, fmt.Errorf("getting file: %w", err)
+	}
+
+	return reader, nil
+}
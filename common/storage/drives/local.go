@@ -0,0 +1,62 @@
+package drives
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalArchive 本地目录存储，仅用于归档
+type LocalArchive struct {
+	Path string
+}
+
+func NewLocalArchive(path string) *LocalArchive {
+	return &LocalArchive{Path: path}
+}
+
+func (l *LocalArchive) Name() string {
+	return "local"
+}
+
+func (l *LocalArchive) filePath(key string) (string, error) {
+	cleanKey := filepath.Clean("/" + key)
+	if strings.Contains(cleanKey, "..") {
+		return "", errors.New("invalid key")
+	}
+	return filepath.Join(l.Path, cleanKey), nil
+}
+
+func (l *LocalArchive) Put(key string, reader io.ReadSeeker) error {
+	path, err := l.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func (l *LocalArchive) Get(key string) (io.ReadCloser, error) {
+	path, err := l.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -39,21 +40,24 @@ func (a *S3Upload) Name() string {
 	return "S3"
 }
 
-func (a *S3Upload) Upload(data []byte, s3Key string) (string, error) {
-
-	ctx := context.Background()
-
+func (a *S3Upload) client() *s3.Client {
 	cfg := aws.Config{
 		Region:      "auto",
 		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(a.AccessKeyId, a.AccessKeySecret, "")),
 	}
 
-	svc := s3.NewFromConfig(cfg, func(o *s3.Options) {
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
 		o.UsePathStyle = true
 		if a.EndPoint != "" {
 			o.BaseEndpoint = aws.String(a.EndPoint)
 		}
 	})
+}
+
+func (a *S3Upload) Upload(data []byte, s3Key string) (string, error) {
+
+	ctx := context.Background()
+	svc := a.client()
 
 	// 获取当前日期作为文件名前缀
 	now := time.Now()
@@ -95,3 +99,29 @@ func (a *S3Upload) Upload(data []byte, s3Key string) (string, error) {
 
 	return fmt.Sprintf("%s/%s", a.CustomDomain, datedKey), nil
 }
+
+// Put 按指定的 key 保存文件，用于归档，不添加日期前缀和过期时间
+func (a *S3Upload) Put(key string, reader io.ReadSeeker) error {
+	_, err := a.client().PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(a.BucketName),
+		Key:    aws.String(key),
+		Body:   reader,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload file to S3: %w", err)
+	}
+
+	return nil
+}
+
+func (a *S3Upload) Get(key string) (io.ReadCloser, error) {
+	output, err := a.client().GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(a.BucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file from S3: %w", err)
+	}
+
+	return output.Body, nil
+}
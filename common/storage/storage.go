@@ -15,6 +15,17 @@ func InitStorage() {
 	InitSMStorage()
 	InitALIOSSStorage()
 	InitS3Storage()
+	InitLocalArchive()
+}
+
+// InitLocalArchive 本地目录仅用于保存归档文件
+func InitLocalArchive() {
+	path := viper.GetString("storage.local.path")
+	if path == "" {
+		return
+	}
+
+	AddArchiveDrive(drives.NewLocalArchive(path))
 }
 
 func InitALIOSSStorage() {
@@ -38,6 +49,7 @@ func InitALIOSSStorage() {
 
 	aliUpload := drives.NewAliOSSUpload(endpoint, accessKeyId, accessKeySecret, bucketName)
 	AddStorageDrive(aliUpload)
+	AddArchiveDrive(aliUpload)
 }
 
 func InitSMStorage() {
@@ -86,4 +98,5 @@ func InitS3Storage() {
 
 	s3Upload := drives.NewS3Upload(endpoint, accessKeyId, accessKeySecret, bucketName, cdnurl, expirationDays)
 	AddStorageDrive(s3Upload)
+	AddArchiveDrive(s3Upload)
 }
@@ -269,9 +269,9 @@ func CountTokenRerankMessages(messages types.RerankRequest, model string, preCos
 		if ok {
 			textMsg.WriteString(docStr + "\n")
 		} else {
-			docMultimodal, ok := document.(map[string]string)
+			docMultimodal, ok := document.(map[string]any)
 			if ok {
-				text := docMultimodal["text"]
+				text, _ := docMultimodal["text"].(string)
 				if text != "" {
 					textMsg.WriteString(text + "\n")
 				} else {
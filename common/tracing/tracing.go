@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterStdout   = "stdout"
+)
+
+// 未初始化时 otel 使用空实现，埋点不会产生额外开销
+var tracer = otel.Tracer("one-hub")
+
+var tracerProvider *sdktrace.TracerProvider
+
+func InitTracing() {
+	if !viper.GetBool("tracing.enable") {
+		return
+	}
+
+	exporter, err := newExporter(viper.GetString("tracing.exporter"))
+	if err != nil {
+		logger.SysError("failed to init tracing exporter: " + err.Error())
+		return
+	}
+
+	serviceName := viper.GetString("tracing.service_name")
+	if serviceName == "" {
+		serviceName = "one-hub"
+	}
+
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(config.Version),
+	)
+
+	sampleRate := 1.0
+	if viper.IsSet("tracing.sample_rate") {
+		sampleRate = viper.GetFloat64("tracing.sample_rate")
+	}
+
+	tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	logger.SysLog(fmt.Sprintf("tracing enabled, exporter: %s", viper.GetString("tracing.exporter")))
+}
+
+func newExporter(exporterType string) (sdktrace.SpanExporter, error) {
+	ctx := context.Background()
+	endpoint := viper.GetString("tracing.endpoint")
+	insecure := viper.GetBool("tracing.insecure")
+	headers := viper.GetStringMapString("tracing.headers")
+
+	switch exporterType {
+	case ExporterOTLPGRPC, "":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithHeaders(headers)}
+		if endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithHeaders(headers)}
+		if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	}
+
+	return nil, fmt.Errorf("unknown tracing exporter: %s", exporterType)
+}
+
+// Shutdown 退出前将未发送的 span 全部导出
+func Shutdown() {
+	if tracerProvider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		logger.SysError("failed to shutdown tracing: " + err.Error())
+	}
+}
+
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartGin 以当前请求的 span 为父级创建子 span，不会替换请求的 context
+func StartGin(c *gin.Context, name string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := Start(c.Request.Context(), name, attrs...)
+	return span
+}
+
+// StartRequest 创建请求的根 span，如果请求头中带有 traceparent 则接续上游的链路
+func StartRequest(c *gin.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
+
+// InjectHeader 将当前链路信息写入请求头，传递给上游
+func InjectHeader(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// SetStatus 根据 HTTP 状态码设置 span 状态，5xx 视为错误
+func SetStatus(span trace.Span, statusCode int, message string) {
+	span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+	if statusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, message)
+	}
+}
+
+func SetError(span trace.Span, message string) {
+	span.SetStatus(codes.Error, message)
+}
@@ -312,6 +312,20 @@ func DifferenceSets[T comparable](set1, set2 map[T]bool) (diff1, diff2 []T) {
 	return diff1, diff2
 }
 
+// ChunkSlice 按指定大小分批
+func ChunkSlice[T any](items []T, size int) [][]T {
+	if size <= 0 {
+		return [][]T{items}
+	}
+
+	chunks := make([][]T, 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		end := min(start+size, len(items))
+		chunks = append(chunks, items[start:end])
+	}
+	return chunks
+}
+
 func Filter[T any](arr []T, f func(T) bool) []T {
 	var res []T
 	for _, v := range arr {
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/model"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetAllPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    config.AllPermissions,
+	})
+}
+
+func GetSelfPermissions(c *gin.Context) {
+	permissions, err := model.GetUserPermissions(c.GetInt("id"), c.GetInt("role"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    permissions,
+	})
+}
+
+// checkGrantable 只能授予自己具备的权限，避免持有 roles.manage 的管理员借助角色提升权限
+func checkGrantable(c *gin.Context, permissions []string) error {
+	for _, permission := range permissions {
+		if !model.HasPermission(c.GetInt("id"), c.GetInt("role"), permission) {
+			return errors.New("不能授予自己不具备的权限 " + permission)
+		}
+	}
+	return nil
+}
+
+func GetAllAdminRoles(c *gin.Context) {
+	roles, err := model.GetAllAdminRoles()
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    roles,
+	})
+}
+
+func GetAdminRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	role, err := model.GetAdminRole(id)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    role,
+	})
+}
+
+func CreateAdminRole(c *gin.Context) {
+	role := model.AdminRole{}
+	if err := c.ShouldBindJSON(&role); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if err := role.Validate(); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if err := checkGrantable(c, role.Permissions); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	role.Id = 0
+	if err := role.Insert(); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	recordAudit(c, model.AuditActionCreate, model.AuditEntityAdminRole, role.Id, nil, role)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    role,
+	})
+}
+
+func UpdateAdminRole(c *gin.Context) {
+	role := model.AdminRole{}
+	if err := c.ShouldBindJSON(&role); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if role.Id == 0 {
+		common.APIRespondWithError(c, http.StatusOK, errors.New("id不能为空"))
+		return
+	}
+	if err := role.Validate(); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	before, err := model.GetAdminRole(role.Id)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	// 修改角色会影响已分配该角色的用户，原有权限和新权限都需要自己具备
+	if err := checkGrantable(c, slices.Concat(before.Permissions, role.Permissions)); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if err := role.Update(); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	after, _ := model.GetAdminRole(role.Id)
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityAdminRole, role.Id, before, after)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    after,
+	})
+}
+
+func DeleteAdminRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	before, err := model.GetAdminRole(id)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if err := model.DeleteAdminRole(id); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	recordAudit(c, model.AuditActionDelete, model.AuditEntityAdminRole, id, before, nil)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+	})
+}
+
+type AssignAdminRoleRequest struct {
+	UserId int `json:"user_id" binding:"required"`
+	RoleId int `json:"role_id"`
+}
+
+// AssignAdminRole 为用户分配角色，role_id 为 0 时取消分配
+func AssignAdminRole(c *gin.Context) {
+	var req AssignAdminRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	user, err := model.GetUserById(req.UserId, false)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if user.Role == config.RoleRootUser {
+		common.APIRespondWithError(c, http.StatusOK, errors.New("超级管理员不能分配角色"))
+		return
+	}
+	if user.Id == c.GetInt("id") {
+		common.APIRespondWithError(c, http.StatusOK, errors.New("不能修改自己的角色"))
+		return
+	}
+	if req.RoleId > 0 {
+		if _, err := model.GetAdminRole(req.RoleId); err != nil {
+			common.APIRespondWithError(c, http.StatusOK, errors.New("角色不存在"))
+			return
+		}
+	}
+	// 取消分配后管理员会恢复为不受限的管理员，按分配前后实际具备的权限校验
+	permissions := slices.Concat(model.GetRolePermissions(user.AdminRoleId, user.Role), model.GetRolePermissions(req.RoleId, user.Role))
+	if err := checkGrantable(c, permissions); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	if err := model.SetUserAdminRole(user.Id, req.RoleId); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityUserRole, user.Id,
+		gin.H{"admin_role_id": user.AdminRoleId}, gin.H{"admin_role_id": req.RoleId})
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+	})
+}
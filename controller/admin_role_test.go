@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"one-api/common/cache"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAdminRoleTest(t *testing.T) (map[string]*model.User, map[string]*model.AdminRole) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.User{}, &model.AdminRole{}, &model.AuditLog{}))
+
+	originDB := model.DB
+	model.DB = db
+	cache.InitCacheManager()
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	t.Cleanup(func() {
+		model.DB = originDB
+		model.AdminRolesInstance = &model.AdminRoles{}
+	})
+
+	roles := map[string]*model.AdminRole{
+		"manager": {Name: "manager", Permissions: datatypes.JSONSlice[string]{config.PermissionRolesManage, config.PermissionLogsRead}},
+		"viewer":  {Name: "viewer", Permissions: datatypes.JSONSlice[string]{config.PermissionLogsRead}},
+		"editor":  {Name: "editor", Permissions: datatypes.JSONSlice[string]{config.PermissionChannelsWrite}},
+	}
+	for _, role := range roles {
+		require.NoError(t, role.Insert())
+	}
+
+	users := map[string]*model.User{
+		"root":    {Username: "root", Role: config.RoleRootUser},
+		"manager": {Username: "manager", Role: config.RoleAdminUser, AdminRoleId: roles["manager"].Id},
+		"viewer":  {Username: "viewer", Role: config.RoleAdminUser, AdminRoleId: roles["viewer"].Id},
+		"common":  {Username: "common", Role: config.RoleCommonUser},
+	}
+	for name, user := range users {
+		user.AccessToken = name
+		user.AffCode = name
+		require.NoError(t, db.Create(user).Error)
+	}
+
+	return users, roles
+}
+
+func callAdminRoleHandler(handler gin.HandlerFunc, caller *model.User, body any) bool {
+	data, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("id", caller.Id)
+	c.Set("role", caller.Role)
+	handler(c)
+
+	var resp struct {
+		Success bool `json:"success"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Success
+}
+
+func TestAdminRoleEscalation(t *testing.T) {
+	tests := []struct {
+		name        string
+		caller      string
+		handler     gin.HandlerFunc
+		body        func(users map[string]*model.User, roles map[string]*model.AdminRole) any
+		wantSuccess bool
+	}{
+		{
+			name: "create role with held permission", caller: "manager", handler: CreateAdminRole, wantSuccess: true,
+			body: func(_ map[string]*model.User, _ map[string]*model.AdminRole) any {
+				return gin.H{"name": "new", "permissions": []string{config.PermissionLogsRead}}
+			},
+		},
+		{
+			name: "create role with root only permission", caller: "manager", handler: CreateAdminRole,
+			body: func(_ map[string]*model.User, _ map[string]*model.AdminRole) any {
+				return gin.H{"name": "new", "permissions": []string{config.PermissionOptionsWrite}}
+			},
+		},
+		{
+			name: "root creates role with root only permission", caller: "root", handler: CreateAdminRole, wantSuccess: true,
+			body: func(_ map[string]*model.User, _ map[string]*model.AdminRole) any {
+				return gin.H{"name": "new", "permissions": []string{config.PermissionOptionsWrite}}
+			},
+		},
+		{
+			name: "add root only permission to own role", caller: "manager", handler: UpdateAdminRole,
+			body: func(_ map[string]*model.User, roles map[string]*model.AdminRole) any {
+				return gin.H{"id": roles["manager"].Id, "name": "manager", "permissions": []string{config.PermissionRolesManage, config.PermissionLogsRestore}}
+			},
+		},
+		{
+			name: "edit role with permissions the caller lacks", caller: "manager", handler: UpdateAdminRole,
+			body: func(_ map[string]*model.User, roles map[string]*model.AdminRole) any {
+				return gin.H{"id": roles["editor"].Id, "name": "editor", "permissions": []string{config.PermissionLogsRead}}
+			},
+		},
+		{
+			name: "edit role within held permissions", caller: "manager", handler: UpdateAdminRole, wantSuccess: true,
+			body: func(_ map[string]*model.User, roles map[string]*model.AdminRole) any {
+				return gin.H{"id": roles["viewer"].Id, "name": "viewer", "description": "read logs", "permissions": []string{config.PermissionLogsRead}}
+			},
+		},
+		{
+			name: "assign role to self", caller: "manager", handler: AssignAdminRole,
+			body: func(users map[string]*model.User, roles map[string]*model.AdminRole) any {
+				return gin.H{"user_id": users["manager"].Id, "role_id": roles["viewer"].Id}
+			},
+		},
+		{
+			name: "assign held role to other user", caller: "manager", handler: AssignAdminRole, wantSuccess: true,
+			body: func(users map[string]*model.User, roles map[string]*model.AdminRole) any {
+				return gin.H{"user_id": users["common"].Id, "role_id": roles["viewer"].Id}
+			},
+		},
+		{
+			name: "assign role with permissions the caller lacks", caller: "manager", handler: AssignAdminRole,
+			body: func(users map[string]*model.User, roles map[string]*model.AdminRole) any {
+				return gin.H{"user_id": users["common"].Id, "role_id": roles["editor"].Id}
+			},
+		},
+		{
+			name: "unassign role from admin", caller: "manager", handler: AssignAdminRole,
+			body: func(users map[string]*model.User, _ map[string]*model.AdminRole) any {
+				return gin.H{"user_id": users["viewer"].Id, "role_id": 0}
+			},
+		},
+		{
+			name: "root unassigns role from admin", caller: "root", handler: AssignAdminRole, wantSuccess: true,
+			body: func(users map[string]*model.User, _ map[string]*model.AdminRole) any {
+				return gin.H{"user_id": users["viewer"].Id, "role_id": 0}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, roles := setupAdminRoleTest(t)
+			assert.Equal(t, tt.wantSuccess, callAdminRoleHandler(tt.handler, users[tt.caller], tt.body(users, roles)))
+		})
+	}
+}
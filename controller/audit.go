@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"one-api/common"
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordAudit 记录当前管理员对实体的变更
+func recordAudit(c *gin.Context, action, entityType string, entityId any, before, after any) {
+	model.RecordAuditLog(&model.AuditLog{
+		UserId:     c.GetInt("id"),
+		Username:   c.GetString("username"),
+		Ip:         c.ClientIP(),
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   fmt.Sprint(entityId),
+	}, before, after)
+}
+
+// recordAuditChanges 比较批量操作前后的快照，按实体分别记录新增、修改和删除
+func recordAuditChanges[K comparable, V any](c *gin.Context, entityType string, before, after map[K]V) {
+	for id, value := range before {
+		if newValue, ok := after[id]; ok {
+			recordAudit(c, model.AuditActionUpdate, entityType, id, value, newValue)
+		} else {
+			recordAudit(c, model.AuditActionDelete, entityType, id, value, nil)
+		}
+	}
+	for id, value := range after {
+		if _, ok := before[id]; !ok {
+			recordAudit(c, model.AuditActionCreate, entityType, id, nil, value)
+		}
+	}
+}
+
+func GetAuditLogsList(c *gin.Context) {
+	var params model.AuditLogsListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	logs, err := model.GetAuditLogsList(&params)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    logs,
+	})
+}
@@ -2,9 +2,13 @@ package controller
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"one-api/common"
 	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/utils"
 	"one-api/model"
 	"one-api/providers"
 	providersBase "one-api/providers/base"
@@ -46,6 +50,8 @@ type OpenAIUsageResponse struct {
 	TotalUsage float64 `json:"total_usage"` // unit: 0.01 dollar
 }
 
+var errBalanceNotImplemented = errors.New("provider not implemented")
+
 func updateChannelBalance(channel *model.Channel) (float64, error) {
 	req, err := http.NewRequest("POST", "/balance", nil)
 	if err != nil {
@@ -64,7 +70,7 @@ func updateChannelBalance(channel *model.Channel) (float64, error) {
 
 	balanceProvider, ok := provider.(providersBase.BalanceInterface)
 	if !ok {
-		return 0, errors.New("provider not implemented")
+		return 0, errBalanceNotImplemented
 	}
 
 	return balanceProvider.Balance()
@@ -96,6 +102,7 @@ func UpdateChannelBalance(c *gin.Context) {
 		})
 		return
 	}
+	checkChannelBalance(channel, balance)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -103,33 +110,95 @@ func UpdateChannelBalance(c *gin.Context) {
 	})
 }
 
+// GetChannelBalanceHistory 获取渠道的余额记录和预估可用天数
+func GetChannelBalanceHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days <= 0 {
+		days = 30
+	}
+
+	histories, err := model.GetChannelBalanceHistories(id, utils.GetTimestamp()-int64(days)*24*60*60)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	forecast, err := model.GetChannelBalanceForecast(id)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data": gin.H{
+			"histories": histories,
+			"forecast":  forecast,
+		},
+	})
+}
+
 func updateAllChannelsBalance() error {
 	channels, err := model.GetAllChannels()
 	if err != nil {
 		return err
 	}
 	for _, channel := range channels {
-		if channel.Status != config.ChannelStatusEnabled {
-			continue
-		}
-		// TODO: support Azure
-		if channel.Type != config.ChannelTypeOpenAI && channel.Type != config.ChannelTypeCustom {
+		// 自动禁用的渠道也需要更新，余额恢复后重新启用
+		if channel.Status != config.ChannelStatusEnabled && channel.Status != config.ChannelStatusAutoDisabled {
 			continue
 		}
 		balance, err := updateChannelBalance(channel)
 		if err != nil {
-			continue
-		} else {
-			// err is nil & balance <= 0 means quota is used up
-			if balance <= 0 {
-				DisableChannel(channel.Id, channel.Name, "余额不足", true)
+			if !errors.Is(err, errBalanceNotImplemented) {
+				logger.SysError(fmt.Sprintf("channel #%d(%s) update balance error: %s", channel.Id, channel.Name, err.Error()))
 			}
+			continue
 		}
+		checkChannelBalance(channel, balance)
 		time.Sleep(config.RequestInterval)
 	}
 	return nil
 }
 
+// checkChannelBalance 开启余额禁用的渠道余额不高于阈值时禁用渠道，余额恢复后只重新启用因余额不足被禁用的渠道
+func checkChannelBalance(channel *model.Channel, balance float64) {
+	if !channel.BalanceDisable {
+		return
+	}
+
+	if balance <= channel.BalanceThreshold {
+		if channel.Status == config.ChannelStatusEnabled && config.AutomaticDisableChannelEnabled {
+			model.UpdateChannelStatusWithReason(channel.Id, config.ChannelStatusAutoDisabled, model.ChannelDisabledReasonLowBalance)
+			notifyChannelDisabled(channel.Id, channel.Name, fmt.Sprintf("余额不足，当前余额 %.4f，阈值 %.4f", balance, channel.BalanceThreshold))
+		}
+		return
+	}
+
+	if channel.Status == config.ChannelStatusAutoDisabled &&
+		channel.DisabledReason == model.ChannelDisabledReasonLowBalance &&
+		config.AutomaticEnableChannelEnabled {
+		EnableChannel(channel.Id, channel.Name, true)
+	}
+}
+
+// UpdateAllChannelsBalanceJob 定时任务更新所有渠道余额
+func UpdateAllChannelsBalanceJob() {
+	logger.SysLog("updating all channels balance")
+	if err := updateAllChannelsBalance(); err != nil {
+		logger.SysError("update all channels balance error: " + err.Error())
+		return
+	}
+	logger.SysLog("channels balance update done")
+}
+
 func UpdateAllChannelsBalance(c *gin.Context) {
 	// TODO: make it async
 	err := updateAllChannelsBalance()
@@ -145,16 +214,3 @@ func UpdateAllChannelsBalance(c *gin.Context) {
 		"message": "",
 	})
 }
-
-// func AutomaticallyUpdateChannels(frequency int) {
-// 	if frequency <= 0 {
-// 		return
-// 	}
-
-// 	for {
-// 		time.Sleep(time.Duration(frequency) * time.Minute)
-// 		common.SysLog("updating all channels")
-// 		_ = updateAllChannelsBalance()
-// 		common.SysLog("channels update done")
-// 	}
-// }
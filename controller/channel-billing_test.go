@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"testing"
+
+	"one-api/common/config"
+	"one-api/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupChannelBalanceTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存数据库每个连接独立，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.Channel{}))
+
+	originDB := model.DB
+	originDisable := config.AutomaticDisableChannelEnabled
+	originEnable := config.AutomaticEnableChannelEnabled
+	model.DB = db
+	config.AutomaticDisableChannelEnabled = true
+	config.AutomaticEnableChannelEnabled = true
+	t.Cleanup(func() {
+		model.DB = originDB
+		config.AutomaticDisableChannelEnabled = originDisable
+		config.AutomaticEnableChannelEnabled = originEnable
+	})
+}
+
+func TestCheckChannelBalance(t *testing.T) {
+	tests := []struct {
+		name       string
+		channel    model.Channel
+		balance    float64
+		wantStatus int
+		wantReason string
+	}{
+		{
+			name:       "balance disable is off",
+			channel:    model.Channel{Status: config.ChannelStatusEnabled, BalanceThreshold: 1},
+			balance:    0,
+			wantStatus: config.ChannelStatusEnabled,
+		},
+		{
+			name:       "disable on low balance",
+			channel:    model.Channel{Status: config.ChannelStatusEnabled, BalanceDisable: true, BalanceThreshold: 1},
+			balance:    1,
+			wantStatus: config.ChannelStatusAutoDisabled,
+			wantReason: model.ChannelDisabledReasonLowBalance,
+		},
+		{
+			name:       "enabled channel above threshold",
+			channel:    model.Channel{Status: config.ChannelStatusEnabled, BalanceDisable: true, BalanceThreshold: 1},
+			balance:    2,
+			wantStatus: config.ChannelStatusEnabled,
+		},
+		{
+			name:       "re-enable after low balance recovered",
+			channel:    model.Channel{Status: config.ChannelStatusAutoDisabled, DisabledReason: model.ChannelDisabledReasonLowBalance, BalanceDisable: true, BalanceThreshold: 1},
+			balance:    2,
+			wantStatus: config.ChannelStatusEnabled,
+		},
+		{
+			name:       "keep channel disabled for other reasons",
+			channel:    model.Channel{Status: config.ChannelStatusAutoDisabled, BalanceDisable: true, BalanceThreshold: 1},
+			balance:    2,
+			wantStatus: config.ChannelStatusAutoDisabled,
+		},
+		{
+			name:       "keep manually disabled channel",
+			channel:    model.Channel{Status: config.ChannelStatusManuallyDisabled, BalanceDisable: true, BalanceThreshold: 1},
+			balance:    0,
+			wantStatus: config.ChannelStatusManuallyDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupChannelBalanceTestDB(t)
+			channel := tt.channel
+			channel.Name = tt.name
+			require.NoError(t, model.DB.Create(&channel).Error)
+
+			checkChannelBalance(&channel, tt.balance)
+
+			saved, err := model.GetChannelById(channel.Id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, saved.Status)
+			assert.Equal(t, tt.wantReason, saved.DisabledReason)
+		})
+	}
+}
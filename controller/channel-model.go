@@ -1,15 +1,28 @@
 package controller
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/notify"
+	"one-api/common/utils"
 	"one-api/model"
 	"one-api/providers"
 	providersBase "one-api/providers/base"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+var errModelListNotImplemented = errors.New("channel not implemented")
+
 func GetModelList(c *gin.Context) {
 	channel := &model.Channel{}
 	err := c.ShouldBindJSON(channel)
@@ -42,41 +55,41 @@ func GetModelList(c *gin.Context) {
 		}
 	}
 
-	provider := providers.GetProvider(channel, c)
-	if provider == nil {
+	uniqueModels, err := fetchChannelModels(channel, c)
+	if err != nil {
 		c.JSON(http.StatusOK, gin.H{
 			"success": false,
-			"message": "provider not found",
+			"message": err.Error(),
 		})
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    uniqueModels,
+	})
+}
+
+// fetchChannelModels 调用渠道的模型列表接口，返回去重后的上游模型
+func fetchChannelModels(channel *model.Channel, c *gin.Context) ([]string, error) {
+	provider := providers.GetProvider(channel, c)
+	if provider == nil {
+		return nil, errors.New("provider not found")
+	}
+
 	modelProvider, ok := provider.(providersBase.ModelListInterface)
 	if !ok {
-		c.JSON(http.StatusOK, gin.H{
-			"success": false,
-			"message": "channel not implemented",
-		})
-		return
+		return nil, errModelListNotImplemented
 	}
 
 	modelList, err := modelProvider.GetModelList()
 	if err != nil {
-		c.JSON(http.StatusOK, gin.H{
-			"success": false,
-			"message": err.Error(),
-		})
-		return
+		return nil, err
 	}
 
 	// 去除重复的模型名称
-	uniqueModels := removeDuplicates(modelList)
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "",
-		"data":    uniqueModels,
-	})
+	return removeDuplicates(modelList), nil
 }
 
 // 辅助函数：去除切片中的重复元素
@@ -91,3 +104,209 @@ func removeDuplicates(slice []string) []string {
 	}
 	return list
 }
+
+type channelModelSyncResult struct {
+	// Added 自动添加到渠道的新模型
+	Added []string
+	// Pending 上游新增但不匹配添加规则的模型
+	Pending []string
+	// Removed 自动移除的已下线模型
+	Removed []string
+	// Retired 上游已下线但未移除的模型
+	Retired []string
+}
+
+func (r *channelModelSyncResult) changed() bool {
+	return len(r.Added) > 0 || len(r.Pending) > 0 || len(r.Removed) > 0 || len(r.Retired) > 0
+}
+
+// syncChannelModels 对比上游模型列表和渠道模型，按渠道的同步设置添加新模型和移除下线模型
+func syncChannelModels(channel *model.Channel) (*channelModelSyncResult, error) {
+	req, err := http.NewRequest(http.MethodGet, "/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = req
+
+	syncChannel := *channel
+	syncChannel.Key = strings.Split(channel.Key, "\n")[0]
+	upstreamModels, err := fetchChannelModels(&syncChannel, c)
+	if err != nil {
+		return nil, err
+	}
+	// 上游返回空列表时不做处理，避免误删渠道的全部模型
+	if len(upstreamModels) == 0 {
+		return nil, errors.New("upstream returned an empty model list")
+	}
+
+	var currentModels []string
+	for _, modelName := range strings.Split(channel.Models, ",") {
+		if modelName = strings.TrimSpace(modelName); modelName != "" {
+			currentModels = append(currentModels, modelName)
+		}
+	}
+
+	modelMapping := make(map[string]string)
+	if mapping := channel.GetModelMapping(); mapping != "" && mapping != "{}" {
+		if err := json.Unmarshal([]byte(mapping), &modelMapping); err != nil {
+			logger.SysError(fmt.Sprintf("channel #%d(%s) model mapping is invalid: %s", channel.Id, channel.Name, err.Error()))
+		}
+	}
+
+	upstreamSet := make(map[string]bool, len(upstreamModels))
+	for _, modelName := range upstreamModels {
+		upstreamSet[modelName] = true
+	}
+
+	// 渠道模型经过映射后才是上游的模型名称
+	knownSet := make(map[string]bool, len(currentModels))
+	result := &channelModelSyncResult{}
+	var keepModels []string
+	var wildcardModels []string
+	for _, modelName := range currentModels {
+		// 通配符模型已经覆盖了匹配的上游模型，不参与下线判断
+		if strings.HasSuffix(modelName, "*") {
+			wildcardModels = append(wildcardModels, modelName)
+			keepModels = append(keepModels, modelName)
+			continue
+		}
+
+		upstreamName := modelName
+		if mapped, ok := modelMapping[modelName]; ok && mapped != "" {
+			upstreamName = mapped
+		}
+		knownSet[modelName] = true
+		knownSet[upstreamName] = true
+
+		if upstreamSet[upstreamName] {
+			keepModels = append(keepModels, modelName)
+			continue
+		}
+		if channel.ModelSyncRemove {
+			result.Removed = append(result.Removed, modelName)
+			continue
+		}
+		result.Retired = append(result.Retired, modelName)
+		keepModels = append(keepModels, modelName)
+	}
+
+	patterns := parseModelSyncPatterns(channel.ModelSyncPatterns)
+	for _, modelName := range upstreamModels {
+		if knownSet[modelName] || utils.GetModelsWithMatch(&wildcardModels, modelName) != "" {
+			continue
+		}
+		if matchModelSyncPatterns(patterns, modelName) {
+			result.Added = append(result.Added, modelName)
+			keepModels = append(keepModels, modelName)
+			continue
+		}
+		result.Pending = append(result.Pending, modelName)
+	}
+
+	if len(result.Added) > 0 || len(result.Removed) > 0 {
+		if err := channel.UpdateModels(strings.Join(keepModels, ",")); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// parseModelSyncPatterns 将逗号分隔的通配符规则转换为正则
+func parseModelSyncPatterns(patterns string) []*regexp.Regexp {
+	var list []*regexp.Regexp
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		list = append(list, regexp.MustCompile(expr))
+	}
+	return list
+}
+
+func matchModelSyncPatterns(patterns []*regexp.Regexp, modelName string) bool {
+	return slices.ContainsFunc(patterns, func(pattern *regexp.Regexp) bool {
+		return pattern.MatchString(modelName)
+	})
+}
+
+// 每个渠道上次通知的待处理模型，没有变化时不再重复通知
+var modelSyncNotified = make(map[int]string)
+
+func syncAllChannelsModels() error {
+	channels, err := model.GetAllChannels()
+	if err != nil {
+		return err
+	}
+
+	var sendMessage string
+	var addedModels []string
+	updated := false
+	for _, channel := range channels {
+		if !channel.ModelSync || channel.Status == config.ChannelStatusManuallyDisabled {
+			continue
+		}
+
+		result, err := syncChannelModels(channel)
+		time.Sleep(config.RequestInterval)
+		if err != nil {
+			if !errors.Is(err, errModelListNotImplemented) {
+				logger.SysError(fmt.Sprintf("channel #%d(%s) sync models error: %s", channel.Id, channel.Name, err.Error()))
+			}
+			continue
+		}
+		pending := strings.Join(result.Pending, ",") + "|" + strings.Join(result.Retired, ",")
+		notified := modelSyncNotified[channel.Id] == pending
+		modelSyncNotified[channel.Id] = pending
+		if len(result.Added) > 0 || len(result.Removed) > 0 {
+			updated = true
+		} else if notified || !result.changed() {
+			continue
+		}
+		addedModels = append(addedModels, result.Added...)
+
+		sendMessage += fmt.Sprintf("**通道 %s - #%d** : \n\n", utils.EscapeMarkdownText(channel.Name), channel.Id)
+		sendMessage += formatModelSyncList("已添加", result.Added)
+		sendMessage += formatModelSyncList("上游新增，未添加", result.Pending)
+		sendMessage += formatModelSyncList("上游已下线，已移除", result.Removed)
+		sendMessage += formatModelSyncList("上游已下线，未移除", result.Retired)
+	}
+
+	if updated {
+		model.ChannelGroup.Load()
+	}
+
+	if len(addedModels) > 0 {
+		pricedModels, err := model.PricingInstance.AddMissingPricesByPriceService(removeDuplicates(addedModels))
+		if err != nil {
+			logger.SysError("add prices for synced models error: " + err.Error())
+		}
+		sendMessage += formatModelSyncList("已从价格服务添加价格", pricedModels)
+	}
+
+	if sendMessage != "" {
+		notify.Send("渠道模型同步完成", sendMessage)
+	}
+
+	return nil
+}
+
+func formatModelSyncList(title string, models []string) string {
+	if len(models) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("- %s: %s\n\n", title, utils.EscapeMarkdownText(strings.Join(models, ", ")))
+}
+
+// SyncAllChannelsModelsJob 定时任务同步开启了模型同步的渠道
+func SyncAllChannelsModelsJob() {
+	logger.SysLog("syncing channels models")
+	if err := syncAllChannelsModels(); err != nil {
+		logger.SysError("sync channels models error: " + err.Error())
+		return
+	}
+	logger.SysLog("channels models sync done")
+}
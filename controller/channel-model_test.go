@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/requester"
+	"one-api/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSyncChannelModels(t *testing.T) {
+	upstreamModels := []string{"gpt-4o", "gpt-4o-mini", "gpt-4.1", "o3", "dall-e-3", "claude-3-5-sonnet"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := make([]map[string]string, 0, len(upstreamModels))
+		for _, modelName := range upstreamModels {
+			data = append(data, map[string]string{"id": modelName, "object": "model"})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.Channel{}))
+
+	originDB := model.DB
+	originClient := requester.HTTPClient
+	model.DB = db
+	if requester.HTTPClient == nil {
+		requester.InitHttpClient()
+	}
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	t.Cleanup(func() {
+		model.DB = originDB
+		requester.HTTPClient = originClient
+	})
+
+	tests := []struct {
+		name        string
+		models      string
+		mapping     string
+		patterns    string
+		remove      bool
+		wantModels  string
+		wantAdded   []string
+		wantPending []string
+		wantRemoved []string
+		wantRetired []string
+	}{
+		{
+			name:       "nothing changed",
+			models:     "gpt-4o,gpt-4o-mini,gpt-4.1,o3,dall-e-3,claude-3-5-sonnet",
+			wantModels: "gpt-4o,gpt-4o-mini,gpt-4.1,o3,dall-e-3,claude-3-5-sonnet",
+		},
+		{
+			name:        "new models without patterns are pending",
+			models:      "gpt-4o,gpt-4o-mini",
+			wantModels:  "gpt-4o,gpt-4o-mini",
+			wantPending: []string{"gpt-4.1", "o3", "dall-e-3", "claude-3-5-sonnet"},
+		},
+		{
+			name:        "new models matching patterns are added",
+			models:      "gpt-4o",
+			patterns:    "gpt-*, o3",
+			wantModels:  "gpt-4o,gpt-4o-mini,gpt-4.1,o3",
+			wantAdded:   []string{"gpt-4o-mini", "gpt-4.1", "o3"},
+			wantPending: []string{"dall-e-3", "claude-3-5-sonnet"},
+		},
+		{
+			name:        "retired models are kept by default",
+			models:      "gpt-4o,gpt-4-turbo,gpt-4o-mini,gpt-4.1,o3,dall-e-3,claude-3-5-sonnet",
+			wantModels:  "gpt-4o,gpt-4-turbo,gpt-4o-mini,gpt-4.1,o3,dall-e-3,claude-3-5-sonnet",
+			wantRetired: []string{"gpt-4-turbo"},
+		},
+		{
+			name:        "retired models are removed",
+			models:      "gpt-4o,gpt-4-turbo,gpt-4o-mini,gpt-4.1,o3,dall-e-3,claude-3-5-sonnet",
+			remove:      true,
+			wantModels:  "gpt-4o,gpt-4o-mini,gpt-4.1,o3,dall-e-3,claude-3-5-sonnet",
+			wantRemoved: []string{"gpt-4-turbo"},
+		},
+		{
+			name:        "mapped models use the upstream name",
+			models:      "gpt-4o,gpt-4o-mini,gpt-4.1,dall-e-3,claude-3-5-sonnet,reasoning,legacy",
+			mapping:     `{"reasoning":"o3","legacy":"gpt-3.5-turbo"}`,
+			remove:      true,
+			wantModels:  "gpt-4o,gpt-4o-mini,gpt-4.1,dall-e-3,claude-3-5-sonnet,reasoning",
+			wantRemoved: []string{"legacy"},
+		},
+		{
+			name:        "wildcard models cover upstream models",
+			models:      "gpt-*,claude-3-5-sonnet",
+			remove:      true,
+			wantModels:  "gpt-*,claude-3-5-sonnet",
+			wantPending: []string{"o3", "dall-e-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := &model.Channel{
+				Type:              config.ChannelTypeOpenAI,
+				Name:              "sync",
+				Key:               "sk-test\nsk-other",
+				BaseURL:           &server.URL,
+				Models:            tt.models,
+				ModelSync:         true,
+				ModelSyncPatterns: tt.patterns,
+				ModelSyncRemove:   tt.remove,
+			}
+			if tt.mapping != "" {
+				channel.ModelMapping = &tt.mapping
+			}
+			require.NoError(t, db.Create(channel).Error)
+
+			result, err := syncChannelModels(channel)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAdded, result.Added)
+			assert.Equal(t, tt.wantPending, result.Pending)
+			assert.Equal(t, tt.wantRemoved, result.Removed)
+			assert.Equal(t, tt.wantRetired, result.Retired)
+
+			saved, err := model.GetChannelById(channel.Id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantModels, saved.Models)
+		})
+	}
+
+	// 上游返回空列表时不修改渠道
+	upstreamModels = nil
+	channel := &model.Channel{Type: config.ChannelTypeOpenAI, Key: "sk-test", BaseURL: &server.URL, Models: "gpt-4o", ModelSyncRemove: true}
+	require.NoError(t, db.Create(channel).Error)
+	_, err = syncChannelModels(channel)
+	assert.Error(t, err)
+	saved, err := model.GetChannelById(channel.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o", saved.Models)
+}
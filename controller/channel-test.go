@@ -70,6 +70,8 @@ func testChannel(channel *model.Channel, testModel string) (openaiErr *types.Ope
 	if provider == nil {
 		return nil, errors.New("channel not implemented")
 	}
+	// 记录本次测试使用的密钥，测试失败时只禁用该密钥
+	channel.KeyId = provider.GetChannel().KeyId
 
 	newModelName, err := provider.ModelMappingHandler(testModel)
 	if err != nil {
@@ -202,7 +204,10 @@ func TestChannel(c *gin.Context) {
 	if openaiErr != nil {
 		if ShouldDisableChannel(channel.Type, openaiErr) {
 			msg = fmt.Sprintf("测速失败，已被禁用，原因：%s", err.Error())
-			DisableChannel(channel.Id, channel.Name, err.Error(), false)
+			if channel.KeyId > 0 {
+				msg = fmt.Sprintf("测速失败，密钥 #%d 已被禁用，原因：%s", channel.KeyId, err.Error())
+			}
+			disableTestedChannel(channel, err.Error())
 		} else {
 			msg = fmt.Sprintf("测速失败，原因：%s", err.Error())
 		}
@@ -221,6 +226,22 @@ func TestChannel(c *gin.Context) {
 	})
 }
 
+// disableTestedChannel 测试失败时禁用渠道，使用密钥池时只禁用本次测试的密钥
+func disableTestedChannel(channel *model.Channel, reason string) {
+	if channel.KeyId > 0 {
+		DisableChannelKey(channel, reason)
+		return
+	}
+	DisableChannel(channel.Id, channel.Name, reason, false)
+}
+
+func disabledTarget(channel *model.Channel) string {
+	if channel.KeyId > 0 {
+		return fmt.Sprintf("已禁用密钥 #%d", channel.KeyId)
+	}
+	return "已被禁用"
+}
+
 var testAllChannelsLock sync.Mutex
 var testAllChannelsRunning bool = false
 
@@ -275,14 +296,14 @@ func testAllChannels(isNotify bool) error {
 				// 如果通道启用状态，但是返回了错误 或者 响应时间超过阈值，需要判断是否需要禁用
 				if milliseconds > disableThreshold {
 					errMsg := fmt.Sprintf("响应时间 %.2fs 超过阈值 %.2fs ", float64(milliseconds)/1000.0, float64(disableThreshold)/1000.0)
-					sendMessage += fmt.Sprintf("- %s \n\n- 禁用\n\n", errMsg)
-					DisableChannel(channel.Id, channel.Name, errMsg, false)
+					sendMessage += fmt.Sprintf("- %s \n\n- %s\n\n", errMsg, disabledTarget(channel))
+					disableTestedChannel(channel, errMsg)
 					continue
 				}
 
 				if ShouldDisableChannel(channel.Type, openaiErr) {
-					sendMessage += fmt.Sprintf("- 已被禁用，原因：%s\n\n", utils.EscapeMarkdownText(err.Error()))
-					DisableChannel(channel.Id, channel.Name, err.Error(), false)
+					sendMessage += fmt.Sprintf("- %s，原因：%s\n\n", disabledTarget(channel), utils.EscapeMarkdownText(err.Error()))
+					disableTestedChannel(channel, err.Error())
 					continue
 				}
 
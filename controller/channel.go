@@ -2,10 +2,13 @@ package controller
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"one-api/common"
+	"one-api/common/config"
 	"one-api/common/utils"
 	"one-api/model"
+	"one-api/providers/declarative"
 	"strconv"
 	"strings"
 
@@ -65,6 +68,13 @@ func AddChannel(c *gin.Context) {
 		})
 		return
 	}
+	if err := validateChannelConfig(&channel); err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
 	channel.CreatedTime = utils.GetTimestamp()
 	keys := strings.Split(channel.Key, "\n")
 
@@ -99,6 +109,9 @@ func AddChannel(c *gin.Context) {
 		})
 		return
 	}
+	for i := range channels {
+		recordAudit(c, model.AuditActionCreate, model.AuditEntityChannel, channels[i].Id, nil, channels[i])
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -107,6 +120,7 @@ func AddChannel(c *gin.Context) {
 
 func DeleteChannel(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
+	before, _ := model.GetChannelById(id)
 	channel := model.Channel{Id: id}
 	err := channel.Delete()
 	if err != nil {
@@ -116,6 +130,7 @@ func DeleteChannel(c *gin.Context) {
 		})
 		return
 	}
+	recordAudit(c, model.AuditActionDelete, model.AuditEntityChannel, id, before, nil)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -124,6 +139,7 @@ func DeleteChannel(c *gin.Context) {
 
 func DeleteChannelTag(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
+	before, _ := model.GetChannelById(id)
 	err := model.DeleteChannelTag(id)
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{
@@ -132,6 +148,8 @@ func DeleteChannelTag(c *gin.Context) {
 		})
 		return
 	}
+	after, _ := model.GetChannelById(id)
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityChannel, id, before, after)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -139,6 +157,8 @@ func DeleteChannelTag(c *gin.Context) {
 }
 
 func DeleteDisabledChannel(c *gin.Context) {
+	disabled, _ := model.GetDisabledChannels()
+	before := channelsSnapshot(disabled)
 	rows, err := model.DeleteDisabledChannel()
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{
@@ -147,6 +167,7 @@ func DeleteDisabledChannel(c *gin.Context) {
 		})
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityChannel, before, snapshotChannels(keysOf(before)))
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -154,6 +175,16 @@ func DeleteDisabledChannel(c *gin.Context) {
 	})
 }
 
+// validateChannelConfig 检查需要解析的渠道参数，避免保存后请求时才报错
+func validateChannelConfig(channel *model.Channel) error {
+	if channel.Type == config.ChannelTypeDeclarative {
+		if _, err := declarative.ParseDefinition(channel.Other); err != nil {
+			return fmt.Errorf("接口描述错误：%s", err.Error())
+		}
+	}
+	return nil
+}
+
 func UpdateChannel(c *gin.Context) {
 	channel := model.Channel{}
 	err := c.ShouldBindJSON(&channel)
@@ -164,6 +195,14 @@ func UpdateChannel(c *gin.Context) {
 		})
 		return
 	}
+	if err := validateChannelConfig(&channel); err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+	before, _ := model.GetChannelById(channel.Id)
 	if channel.Models == "" {
 		err = channel.Update(false)
 	} else {
@@ -176,6 +215,8 @@ func UpdateChannel(c *gin.Context) {
 		})
 		return
 	}
+	after, _ := model.GetChannelById(channel.Id)
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityChannel, channel.Id, before, after)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -195,12 +236,14 @@ func BatchUpdateChannelsAzureApi(c *gin.Context) {
 		common.APIRespondWithError(c, http.StatusOK, errors.New("ids不能为空"))
 		return
 	}
+	before := snapshotChannels(params.Ids)
 	var count int64
 	count, err = model.BatchUpdateChannelsAzureApi(&params)
 	if err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityChannel, before, snapshotChannels(params.Ids))
 	c.JSON(http.StatusOK, gin.H{
 		"data":    count,
 		"success": true,
@@ -221,12 +264,14 @@ func BatchDelModelChannels(c *gin.Context) {
 		return
 	}
 
+	before := snapshotChannels(params.Ids)
 	var count int64
 	count, err = model.BatchDelModelChannels(&params)
 	if err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityChannel, before, snapshotChannels(params.Ids))
 	c.JSON(http.StatusOK, gin.H{
 		"data":    count,
 		"success": true,
@@ -247,14 +292,46 @@ func BatchDeleteChannel(c *gin.Context) {
 		return
 	}
 
+	before := snapshotChannels(params.Ids)
 	count, err := model.BatchDeleteChannel(params.Ids)
 	if err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityChannel, before, snapshotChannels(params.Ids))
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
 		"data":    count,
 	})
 }
+
+func channelsSnapshot(channels []*model.Channel) map[int]*model.Channel {
+	snapshot := make(map[int]*model.Channel, len(channels))
+	for _, channel := range channels {
+		snapshot[channel.Id] = channel
+	}
+	return snapshot
+}
+
+// snapshotChannels 查询渠道当前的状态，用于记录批量操作的审计日志
+func snapshotChannels(ids []int) map[int]*model.Channel {
+	if len(ids) == 0 {
+		return map[int]*model.Channel{}
+	}
+	channels, _ := model.GetChannelsByIds(ids)
+	return channelsSnapshot(channels)
+}
+
+func snapshotTagChannels(tag string) map[int]*model.Channel {
+	channels, _ := model.GetChannelsByTag(tag)
+	return channelsSnapshot(channels)
+}
+
+func keysOf[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/model"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetChannelKeysList(c *gin.Context) {
+	channelId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	var params model.ChannelKeysListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	params.ChannelId = channelId
+
+	keys, err := model.GetChannelKeysList(&params)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    keys,
+	})
+}
+
+type ImportChannelKeysRequest struct {
+	// Keys 每行一个密钥
+	Keys string `json:"keys" binding:"required"`
+}
+
+func ImportChannelKeys(c *gin.Context) {
+	channelId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if _, err := model.GetChannelById(channelId); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	var req ImportChannelKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	count, err := model.ImportChannelKeys(channelId, strings.Split(req.Keys, "\n"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if count > 0 {
+		recordAudit(c, model.AuditActionCreate, model.AuditEntityChannelKey, channelId, nil, gin.H{"imported": count})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    count,
+	})
+}
+
+// ExportChannelKeys 导出完整密钥，每行一个，可使用 status 参数只导出指定状态的密钥
+func ExportChannelKeys(c *gin.Context) {
+	channelId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	status, _ := strconv.Atoi(c.Query("status"))
+
+	keys, err := model.GetChannelKeys(channelId)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if status == 0 || key.Status == status {
+			lines = append(lines, key.Key)
+		}
+	}
+	recordAudit(c, model.AuditActionExport, model.AuditEntityChannelKey, channelId, nil, gin.H{"exported": len(lines)})
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    strings.Join(lines, "\n"),
+	})
+}
+
+type UpdateChannelKeyStatusRequest struct {
+	Status int `json:"status" binding:"required"`
+}
+
+func UpdateChannelKeyStatus(c *gin.Context) {
+	channelId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	keyId, err := strconv.Atoi(c.Param("key_id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	var req UpdateChannelKeyStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if req.Status != config.ChannelStatusEnabled && req.Status != config.ChannelStatusManuallyDisabled {
+		common.APIRespondWithError(c, http.StatusOK, errors.New("无效的状态"))
+		return
+	}
+
+	before, err := model.GetChannelKey(channelId, keyId)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if err := model.UpdateChannelKeyStatus(channelId, keyId, req.Status, ""); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityChannelKey, channelId,
+		gin.H{"key_id": keyId, "status": before.Status}, gin.H{"key_id": keyId, "status": req.Status})
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+	})
+}
+
+type DeleteChannelKeysRequest struct {
+	Ids []int `json:"ids" binding:"required"`
+}
+
+func DeleteChannelKeys(c *gin.Context) {
+	channelId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	var req DeleteChannelKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if len(req.Ids) == 0 {
+		common.APIRespondWithError(c, http.StatusOK, errors.New("请选择要删除的密钥"))
+		return
+	}
+
+	count, err := model.DeleteChannelKeys(channelId, req.Ids)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+	if count > 0 {
+		recordAudit(c, model.AuditActionDelete, model.AuditEntityChannelKey, channelId, gin.H{"key_ids": req.Ids}, nil)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    count,
+	})
+}
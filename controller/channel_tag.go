@@ -73,11 +73,13 @@ func UpdateChannelsTag(c *gin.Context) {
 		return
 	}
 
+	before := snapshotTagChannels(tag)
 	err = model.UpdateChannelsTag(tag, &channel)
 	if err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityChannel, before, snapshotChannels(keysOf(before)))
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -90,11 +92,13 @@ func DeleteChannelsTag(c *gin.Context) {
 		common.AbortWithMessage(c, http.StatusOK, "tag is required")
 		return
 	}
+	before := snapshotTagChannels(tag)
 	err := model.DeleteChannelsTag(tag, false)
 	if err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityChannel, before, snapshotChannels(keysOf(before)))
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -107,11 +111,13 @@ func DeleteDisabledChannelsTag(c *gin.Context) {
 		common.AbortWithMessage(c, http.StatusOK, "tag is required")
 		return
 	}
+	before := snapshotTagChannels(tag)
 	err := model.DeleteChannelsTag(tag, true)
 	if err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityChannel, before, snapshotChannels(keysOf(before)))
 }
 
 type UpdateChannelsTagParams struct {
@@ -133,6 +139,7 @@ func UpdateChannelsTagPriority(c *gin.Context) {
 		return
 	}
 
+	before := snapshotTagChannels(tag)
 	switch params.Type {
 	case "priority":
 		err = model.UpdateChannelsTagPriority(tag, params.Value)
@@ -144,6 +151,7 @@ func UpdateChannelsTagPriority(c *gin.Context) {
 		common.AbortWithMessage(c, http.StatusOK, "invalid type")
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityChannel, before, snapshotChannels(keysOf(before)))
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -172,11 +180,13 @@ func ChangeChannelsTagStatus(c *gin.Context) {
 		return
 	}
 
+	before := snapshotTagChannels(tag)
 	err := model.ChangeChannelsTagStatus(tag, statusInt)
 	if err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityChannel, before, snapshotChannels(keysOf(before)))
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"one-api/common"
 	"one-api/common/config"
+	"one-api/common/logger"
 	"one-api/common/notify"
 	"one-api/model"
 	"one-api/types"
@@ -65,11 +66,25 @@ func DisableChannel(channelId int, channelName string, reason string, sendNotify
 		return
 	}
 
+	notifyChannelDisabled(channelId, channelName, reason)
+}
+
+func notifyChannelDisabled(channelId int, channelName string, reason string) {
 	subject := fmt.Sprintf("通道「%s」（#%d）已被禁用", channelName, channelId)
 	content := fmt.Sprintf("通道「%s」（#%d）已被禁用，原因：%s", channelName, channelId, reason)
 	notify.Send(subject, content)
 }
 
+// DisableChannelKey 禁用密钥池中出错的密钥，密钥池中没有可用密钥时禁用整个渠道
+func DisableChannelKey(channel *model.Channel, reason string) {
+	logger.SysError(fmt.Sprintf("channel #%d(%s) key #%d is disabled: %s", channel.Id, channel.Name, channel.KeyId, reason))
+	if model.ChannelKeyPoolInstance.Disable(channel.Id, channel.KeyId, reason) {
+		return
+	}
+
+	DisableChannel(channel.Id, channel.Name, "密钥池中已没有可用的密钥，最后一个密钥的禁用原因："+reason, true)
+}
+
 // enable & notify
 func EnableChannel(channelId int, channelName string, sendNotify bool) {
 	model.UpdateChannelStatusById(channelId, config.ChannelStatusEnabled)
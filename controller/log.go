@@ -28,6 +28,85 @@ func GetLogsList(c *gin.Context) {
 	})
 }
 
+// GetLogDetail 获取日志详情，开启了请求内容记录时一并返回记录的内容
+func GetLogDetail(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	log, err := model.GetLogById(id)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	var payload *model.PayloadCaptureDetail
+	if requestId, ok := log.Metadata.Data()["payload_request_id"].(string); ok && requestId != "" {
+		payload, _ = model.GetPayloadCaptureByRequestId(requestId)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data": gin.H{
+			"log":     log,
+			"payload": payload,
+		},
+	})
+}
+
+func GetLogArchivesList(c *gin.Context) {
+	var params model.LogArchivesListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	archives, err := model.GetLogArchivesList(&params)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    archives,
+	})
+}
+
+type restoreLogArchiveRequest struct {
+	StartTimestamp int64 `json:"start_timestamp" binding:"required"`
+	EndTimestamp   int64 `json:"end_timestamp" binding:"required"`
+}
+
+// RestoreLogArchives 将归档的日志恢复到数据库，用于问题排查
+func RestoreLogArchives(c *gin.Context) {
+	var request restoreLogArchiveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	count, err := model.RestoreLogArchives(request.StartTimestamp, request.EndTimestamp)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": err.Error(),
+			"data":    count,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    count,
+	})
+}
+
 func GetUserLogsList(c *gin.Context) {
 	userId := c.GetInt("id")
 
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"one-api/common"
 	"one-api/common/config"
+	"one-api/common/saml"
 	"one-api/common/stmp"
 	"one-api/common/telegram"
 	"one-api/model"
@@ -29,6 +30,7 @@ func GetStatus(c *gin.Context) {
 			"github_oauth":        config.GitHubOAuthEnabled,
 			"github_client_id":    config.GitHubClientId,
 			"oidc_auth":           config.OIDCAuthEnabled,
+			"saml_auth":           saml.Enabled(),
 			"lark_login":          config.LarkAuthEnabled,
 			"lark_client_id":      config.LarkClientId,
 			"system_name":         config.SystemName,
@@ -7,6 +7,7 @@ import (
 	"one-api/common/utils"
 	"one-api/model"
 	"one-api/safty"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -83,6 +84,46 @@ func UpdateOption(c *gin.Context) {
 			})
 			return
 		}
+	case "ModelFallback":
+		if _, err := model.ParseModelFallbackChains(option.Value); err != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"success": false,
+				"message": "模型回退链格式错误：" + err.Error(),
+			})
+			return
+		}
+	case "SafetyGroupPolicy":
+		if _, err := model.ParseSafetyPolicies(option.Value); err != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"success": false,
+				"message": "内容审查策略格式错误：" + err.Error(),
+			})
+			return
+		}
+	case "PIIRedaction":
+		if _, _, err := model.ParsePIIRedactionConfig(option.Value); err != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"success": false,
+				"message": "脱敏设置格式错误：" + err.Error(),
+			})
+			return
+		}
+	case "SafeRegexRules":
+		if err := safty.ValidateRegexRules(option.Value); err != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"success": false,
+				"message": "正则规则格式错误：" + err.Error(),
+			})
+			return
+		}
+	case "PayloadCaptureSampleRate":
+		if rate, err := strconv.ParseFloat(option.Value, 64); err != nil || rate < 0 || rate > 100 {
+			c.JSON(http.StatusOK, gin.H{
+				"success": false,
+				"message": "采样比例必须在 0 到 100 之间",
+			})
+			return
+		}
 	case "TurnstileCheckEnabled":
 		if option.Value == "true" && config.TurnstileSiteKey == "" {
 			c.JSON(http.StatusOK, gin.H{
@@ -92,6 +133,7 @@ func UpdateOption(c *gin.Context) {
 			return
 		}
 	}
+	before := config.GlobalOption.Get(option.Key)
 	err = model.UpdateOption(option.Key, option.Value)
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{
@@ -100,6 +142,7 @@ func UpdateOption(c *gin.Context) {
 		})
 		return
 	}
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityOption, option.Key, gin.H{"value": before}, gin.H{"value": option.Value})
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
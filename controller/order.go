@@ -178,6 +178,34 @@ func PaymentCallback(c *gin.Context) {
 
 }
 
+// RefundOrder 管理员对支付成功的订单退款，扣回用户到帐的额度
+func RefundOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	order, err := model.RefundOrder(id)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
+	if err := model.CacheUpdateUserQuota(order.UserId); err != nil {
+		logger.SysError(fmt.Sprintf("failed to update user quota cache, trade_no: %s, error: %s", order.TradeNo, err.Error()))
+	}
+	model.RecordQuotaLog(order.UserId, model.LogTypeManage, -order.Quota, c.ClientIP(), fmt.Sprintf("订单 %s 已退款，扣除积分: %d，退款金额：%.2f %s", order.TradeNo, order.Quota, order.OrderAmount, order.OrderCurrency))
+	recordAudit(c, model.AuditActionRefund, model.AuditEntityOrder, order.ID,
+		gin.H{"status": model.OrderStatusSuccess}, gin.H{"status": order.Status, "quota": -order.Quota})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    order,
+	})
+}
+
 func CheckOrderStatus(c *gin.Context) {
 	tradeNo := c.Query("trade_no")
 	userId := c.GetInt("id")
@@ -85,6 +85,7 @@ func AddPayment(c *gin.Context) {
 		return
 	}
 
+	recordAudit(c, model.AuditActionCreate, model.AuditEntityPayment, payment.ID, nil, payment)
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"message": "Payment added successfully",
@@ -106,11 +107,14 @@ func UpdatePayment(c *gin.Context) {
 		overwrite = false
 	}
 
+	before, _ := model.GetPaymentByID(payment.ID)
 	err = payment.Update(overwrite)
 	if err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	after, _ := model.GetPaymentByID(payment.ID)
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityPayment, payment.ID, before, after)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -129,6 +133,7 @@ func DeletePayment(c *gin.Context) {
 		return
 	}
 
+	before, _ := model.GetPaymentByID(id)
 	payment := model.Payment{ID: id}
 	err = payment.Delete()
 	if err != nil {
@@ -138,6 +143,7 @@ func DeletePayment(c *gin.Context) {
 		})
 		return
 	}
+	recordAudit(c, model.AuditActionDelete, model.AuditEntityPayment, id, before, nil)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "",
@@ -73,6 +73,7 @@ func AddPrice(c *gin.Context) {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAudit(c, model.AuditActionCreate, model.AuditEntityPrice, price.Model, nil, price)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -95,10 +96,12 @@ func UpdatePrice(c *gin.Context) {
 		return
 	}
 
+	before := model.PricingInstance.GetModelPrice(modelName)
 	if err := model.PricingInstance.UpdatePrice(modelName, &price); err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityPrice, modelName, before, model.PricingInstance.GetModelPrice(price.Model))
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -115,10 +118,12 @@ func DeletePrice(c *gin.Context) {
 	modelName = modelName[1:]
 	modelName, _ = url.PathUnescape(modelName)
 
+	before := model.PricingInstance.GetModelPrice(modelName)
 	if err := model.PricingInstance.DeletePrice(modelName); err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAudit(c, model.AuditActionDelete, model.AuditEntityPrice, modelName, before, nil)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -138,10 +143,13 @@ func BatchSetPrices(c *gin.Context) {
 		return
 	}
 
+	models := append(append([]string{}, pricesBatch.OriginalModels...), pricesBatch.Models...)
+	before := snapshotPrices(models)
 	if err := model.PricingInstance.BatchSetPrices(&pricesBatch.BatchPrices, pricesBatch.OriginalModels); err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityPrice, before, snapshotPrices(models))
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -160,10 +168,12 @@ func BatchDeletePrices(c *gin.Context) {
 		return
 	}
 
+	before := snapshotPrices(pricesBatch.Models)
 	if err := model.PricingInstance.BatchDeletePrices(pricesBatch.Models); err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityPrice, before, snapshotPrices(pricesBatch.Models))
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -185,11 +195,13 @@ func SyncPricing(c *gin.Context) {
 		return
 	}
 
+	before := snapshotAllPrices()
 	err := model.PricingInstance.SyncPricing(prices, updateMode)
 	if err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAuditChanges(c, model.AuditEntityPrice, before, snapshotAllPrices())
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -205,3 +217,23 @@ func GetUpdatePriceService(c *gin.Context) {
 		"message": "",
 	})
 }
+
+func snapshotPrices(models []string) map[string]*model.Price {
+	prices := make(map[string]*model.Price, len(models))
+	for _, modelName := range models {
+		if price := model.PricingInstance.GetModelPrice(modelName); price != nil {
+			prices[modelName] = price
+		}
+	}
+	return prices
+}
+
+func snapshotAllPrices() map[string]*model.Price {
+	prices := model.PricingInstance.GetAllPricesList()
+	snapshot := make(map[string]*model.Price, len(prices))
+	for _, price := range prices {
+		priceCopy := *price
+		snapshot[price.Model] = &priceCopy
+	}
+	return snapshot
+}
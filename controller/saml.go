@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/saml"
+	"one-api/common/utils"
+	"one-api/model"
+
+	crewsaml "github.com/crewjam/saml"
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// IdP 回调是跨站 POST，session 使用 SameSite=Strict 不会被携带，请求 ID 单独使用 cookie 保存
+const samlRequestCookie = "saml_request"
+
+func SAMLMetadata(c *gin.Context) {
+	sp, err := saml.GetServiceProvider()
+	if err != nil {
+		c.String(http.StatusNotFound, "SAML is not available")
+		return
+	}
+
+	metadata, err := xml.MarshalIndent(sp.Metadata(), "", "  ")
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+// SAMLLogin 生成认证请求并跳转到 IdP
+func SAMLLogin(c *gin.Context) {
+	sp, err := saml.GetServiceProvider()
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "管理员未开启通过SAML登录",
+			"success": false,
+		})
+		return
+	}
+
+	authnRequest, err := sp.MakeAuthenticationRequest(sp.GetSSOBindingLocation(crewsaml.HTTPRedirectBinding), crewsaml.HTTPRedirectBinding, crewsaml.HTTPPostBinding)
+	if err != nil {
+		logger.SysError("创建 SAML 认证请求失败, err: " + err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"message": "创建 SAML 认证请求失败",
+			"success": false,
+		})
+		return
+	}
+
+	redirectURL, err := authnRequest.Redirect(c.Query("aff"), sp)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message": err.Error(),
+			"success": false,
+		})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteNoneMode)
+	c.SetCookie(samlRequestCookie, authnRequest.ID, 600, saml.AcsPath, "", c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https", true)
+	c.Redirect(http.StatusFound, redirectURL.String())
+}
+
+// SAMLAcs 处理 IdP 返回的断言
+// 先通过 NameID 查找用户，再查找 SCIM 预先创建且 externalId 与 NameID 一致的用户并绑定，都不存在时注册新用户
+// 不会按用户名绑定已有账号，用户名冲突时拒绝登录；配置了分组映射时每次登录同步用户分组
+func SAMLAcs(c *gin.Context) {
+	sp, err := saml.GetServiceProvider()
+	if err != nil {
+		c.String(http.StatusNotFound, "SAML is not available")
+		return
+	}
+
+	possibleRequestIDs := make([]string, 0, 1)
+	if requestID, err := c.Cookie(samlRequestCookie); err == nil && requestID != "" {
+		possibleRequestIDs = append(possibleRequestIDs, requestID)
+	}
+	c.SetSameSite(http.SameSiteNoneMode)
+	c.SetCookie(samlRequestCookie, "", -1, saml.AcsPath, "", c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https", true)
+
+	assertion, err := sp.ParseResponse(c.Request, possibleRequestIDs)
+	if err != nil {
+		var invalidErr *crewsaml.InvalidResponseError
+		if errors.As(err, &invalidErr) {
+			logger.SysError("SAML 断言校验失败, err: " + invalidErr.PrivateErr.Error())
+		}
+		c.String(http.StatusForbidden, "invalid SAML response")
+		return
+	}
+
+	info := saml.ParseUserInfo(assertion)
+	if info.NameID == "" || info.Username == "" {
+		c.String(http.StatusForbidden, "SAML assertion has no NameID")
+		return
+	}
+
+	user, err := samlFindOrCreateUser(info, c.PostForm("RelayState"))
+	if err != nil {
+		c.String(http.StatusForbidden, err.Error())
+		return
+	}
+
+	if group := saml.MapGroup(info.Groups); group != "" && group != user.Group {
+		if err := model.SetUserGroup(user.Id, group); err != nil {
+			logger.SysError("同步 SAML 用户分组失败, err: " + err.Error())
+		} else {
+			user.Group = group
+		}
+	}
+
+	session := sessions.Default(c)
+	session.Set("id", user.Id)
+	session.Set("username", user.Username)
+	session.Set("role", user.Role)
+	session.Set("status", user.Status)
+	if err := session.Save(); err != nil {
+		c.String(http.StatusInternalServerError, "无法保存会话信息，请重试")
+		return
+	}
+
+	model.UpdateUser(user.Id, map[string]interface{}{
+		"last_login_time": utils.GetTimestamp(),
+		"last_login_ip":   c.ClientIP(),
+	})
+
+	c.Redirect(http.StatusFound, "/panel/dashboard")
+}
+
+func samlFindOrCreateUser(info *saml.UserInfo, affCode string) (*model.User, error) {
+	user := &model.User{SamlId: info.NameID}
+	err := user.FillUserBySamlId()
+	if err == nil {
+		if user.Status != config.UserStatusEnabled {
+			return nil, errors.New("用户已被封禁")
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// 只绑定 SCIM 预先创建、externalId 与 NameID 一致的用户，不按用户名绑定已有账号
+	user = &model.User{ScimId: info.NameID}
+	err = user.FillUserByScimId()
+	if err == nil {
+		if user.Status != config.UserStatusEnabled {
+			return nil, errors.New("用户已被封禁")
+		}
+		if user.Role >= config.RoleAdminUser {
+			return nil, errors.New("管理员账号不能通过 SAML 绑定")
+		}
+		if user.SamlId != "" {
+			return nil, errors.New("该账号已绑定其他 SAML 用户")
+		}
+		user.SamlId = info.NameID
+		if err := user.Update(false); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if model.IsUsernameAlreadyTaken(info.Username) {
+		return nil, errors.New("用户名已被占用，请联系管理员")
+	}
+
+	if !config.RegisterEnabled {
+		return nil, errors.New("管理员关闭了新用户注册")
+	}
+
+	user = &model.User{
+		Username:    info.Username,
+		DisplayName: info.DisplayName,
+		Email:       info.Email,
+		SamlId:      info.NameID,
+		Role:        config.RoleCommonUser,
+		Status:      config.UserStatusEnabled,
+	}
+	if affCode != "" {
+		user.InviterId, _ = model.GetUserIdByAffCode(affCode)
+	}
+	if err := user.Insert(user.InviterId); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"testing"
+
+	"one-api/common/config"
+	"one-api/common/saml"
+	"one-api/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSAMLTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存数据库每个连接独立，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.User{}))
+
+	originDB := model.DB
+	originRegister := config.RegisterEnabled
+	originQuota := config.QuotaForNewUser
+	model.DB = db
+	config.RegisterEnabled = true
+	config.QuotaForNewUser = 0
+	t.Cleanup(func() {
+		model.DB = originDB
+		config.RegisterEnabled = originRegister
+		config.QuotaForNewUser = originQuota
+	})
+
+	users := []*model.User{
+		{Username: "root", Role: config.RoleRootUser, Status: config.UserStatusEnabled, AffCode: "a1"},
+		{Username: "alice", Role: config.RoleCommonUser, Status: config.UserStatusEnabled, AffCode: "a2", SamlId: "alice@idp"},
+		{Username: "bob", Role: config.RoleCommonUser, Status: config.UserStatusEnabled, AffCode: "a3", ScimId: "bob@idp"},
+		{Username: "carol", Role: config.RoleCommonUser, Status: config.UserStatusDisabled, AffCode: "a4", SamlId: "carol@idp"},
+		{Username: "ops", Role: config.RoleAdminUser, Status: config.UserStatusEnabled, AffCode: "a5", ScimId: "ops@idp"},
+		{Username: "dave", Role: config.RoleCommonUser, Status: config.UserStatusEnabled, AffCode: "a6", ScimId: "dave@idp", SamlId: "other@idp"},
+	}
+	for _, user := range users {
+		user.AccessToken = user.Username
+		require.NoError(t, db.Create(user).Error)
+	}
+}
+
+func TestSamlFindOrCreateUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		info         saml.UserInfo
+		wantUsername string
+		wantErr      bool
+	}{
+		{name: "match by saml id", info: saml.UserInfo{NameID: "alice@idp", Username: "someone"}, wantUsername: "alice"},
+		{name: "bind scim provisioned user", info: saml.UserInfo{NameID: "bob@idp", Username: "bob"}, wantUsername: "bob"},
+		{name: "username root is not linked", info: saml.UserInfo{NameID: "evil@idp", Username: "root"}, wantErr: true},
+		{name: "username of common user is not linked", info: saml.UserInfo{NameID: "evil2@idp", Username: "alice"}, wantErr: true},
+		{name: "disabled user", info: saml.UserInfo{NameID: "carol@idp", Username: "carol"}, wantErr: true},
+		{name: "admin provisioned by scim is not linked", info: saml.UserInfo{NameID: "ops@idp", Username: "ops"}, wantErr: true},
+		{name: "scim user already bound to another saml id", info: saml.UserInfo{NameID: "dave@idp", Username: "dave"}, wantErr: true},
+		{name: "create new user", info: saml.UserInfo{NameID: "erin@idp", Username: "erin"}, wantUsername: "erin"},
+	}
+
+	setupSAMLTestDB(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := samlFindOrCreateUser(&tt.info, "")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUsername, user.Username)
+			assert.Equal(t, tt.info.NameID, user.SamlId)
+			assert.Equal(t, config.RoleCommonUser, user.Role)
+		})
+	}
+
+	// 用户名冲突时不能修改原账号
+	root := &model.User{Username: "root"}
+	require.NoError(t, root.FillUserByUsername())
+	assert.Empty(t, root.SamlId)
+}
+
+func TestSamlFindOrCreateUserRegisterDisabled(t *testing.T) {
+	setupSAMLTestDB(t)
+	config.RegisterEnabled = false
+
+	_, err := samlFindOrCreateUser(&saml.UserInfo{NameID: "frank@idp", Username: "frank"}, "")
+	assert.Error(t, err)
+
+	user, err := samlFindOrCreateUser(&saml.UserInfo{NameID: "bob@idp", Username: "bob"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", user.Username)
+}
@@ -0,0 +1,758 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	scimSchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimSchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	scimSchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimSchemaSPConfig     = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	scimContentType     = "application/scim+json"
+	scimDefaultGroup    = "default"
+	scimMaxItemsPerPage = 100
+)
+
+// 仅支持 `attr eq "value"` 形式的过滤条件，身份源同步时只会用到这一种
+var scimFilterRegexp = regexp.MustCompile(`(?i)^\s*([a-zA-Z.]+)\s+eq\s+"([^"]*)"\s*$`)
+
+// members[value eq "1"] 形式的路径
+var scimMemberPathRegexp = regexp.MustCompile(`(?i)^members\[value eq "([^"]+)"\]$`)
+
+type scimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type scimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type scimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	Location     string `json:"location"`
+}
+
+type scimMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+type scimUser struct {
+	Schemas     []string     `json:"schemas"`
+	Id          string       `json:"id"`
+	ExternalId  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *scimName    `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []scimEmail  `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Groups      []scimMember `json:"groups,omitempty"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+type scimGroup struct {
+	Schemas     []string     `json:"schemas"`
+	Id          string       `json:"id"`
+	DisplayName string       `json:"displayName"`
+	Members     []scimMember `json:"members"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+type scimPatchRequest struct {
+	Operations []scimPatchOperation `json:"Operations"`
+}
+
+type scimPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+func scimError(c *gin.Context, status int, detail string) {
+	c.Header("Content-Type", scimContentType)
+	c.JSON(status, gin.H{
+		"schemas": []string{scimSchemaError},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	})
+}
+
+func scimJSON(c *gin.Context, status int, data any) {
+	c.Header("Content-Type", scimContentType)
+	c.JSON(status, data)
+}
+
+func scimListResponse(c *gin.Context, resources any, total int64, startIndex, count int) {
+	scimJSON(c, http.StatusOK, gin.H{
+		"schemas":      []string{scimSchemaListResponse},
+		"totalResults": total,
+		"startIndex":   startIndex,
+		"itemsPerPage": count,
+		"Resources":    resources,
+	})
+}
+
+// scimPagination 解析 startIndex(从 1 开始) 和 count
+func scimPagination(c *gin.Context) (startIndex, count int) {
+	startIndex, _ = strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count, _ = strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(scimMaxItemsPerPage)))
+	if count < 0 {
+		count = 0
+	}
+	if count > scimMaxItemsPerPage {
+		count = scimMaxItemsPerPage
+	}
+	return
+}
+
+func scimLocation(resourceType string, id int) string {
+	return fmt.Sprintf("%s/scim/v2/%s/%d", strings.TrimSuffix(config.ServerAddress, "/"), resourceType, id)
+}
+
+// scimBool 兼容部分身份源将布尔值以字符串形式传递
+func scimBool(raw json.RawMessage) (bool, error) {
+	var value bool
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value, nil
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(strings.ToLower(str))
+}
+
+func toScimUser(user *model.User) *scimUser {
+	active := user.Status == config.UserStatusEnabled
+	resource := &scimUser{
+		Schemas:     []string{scimSchemaUser},
+		Id:          strconv.Itoa(user.Id),
+		ExternalId:  user.ScimId,
+		UserName:    user.Username,
+		DisplayName: user.DisplayName,
+		Active:      &active,
+		Meta: &scimMeta{
+			ResourceType: "User",
+			Created:      time.Unix(user.CreatedTime, 0).UTC().Format(time.RFC3339),
+			Location:     scimLocation("Users", user.Id),
+		},
+	}
+	if user.DisplayName != "" {
+		resource.Name = &scimName{Formatted: user.DisplayName}
+	}
+	if user.Email != "" {
+		resource.Emails = []scimEmail{{Value: user.Email, Type: "work", Primary: true}}
+	}
+	if userGroup := model.GlobalUserGroupRatio.GetBySymbol(user.Group); userGroup != nil {
+		resource.Groups = []scimMember{{Value: strconv.Itoa(userGroup.Id), Display: userGroup.Symbol}}
+	}
+
+	return resource
+}
+
+// applyScimUser 将 SCIM 资源中的字段写入用户
+func applyScimUser(user *model.User, resource *scimUser) {
+	if resource.UserName != "" {
+		user.Username = resource.UserName
+	}
+	if resource.ExternalId != "" {
+		user.ScimId = resource.ExternalId
+	}
+	switch {
+	case resource.DisplayName != "":
+		user.DisplayName = resource.DisplayName
+	case resource.Name != nil && resource.Name.Formatted != "":
+		user.DisplayName = resource.Name.Formatted
+	case resource.Name != nil && (resource.Name.GivenName != "" || resource.Name.FamilyName != ""):
+		user.DisplayName = strings.TrimSpace(resource.Name.GivenName + " " + resource.Name.FamilyName)
+	}
+	for _, email := range resource.Emails {
+		if email.Primary || user.Email == "" {
+			user.Email = email.Value
+		}
+	}
+}
+
+// setScimUserActive 根据 active 状态启用用户，或禁用用户并吊销令牌
+func setScimUserActive(user *model.User, active bool) error {
+	if active {
+		if user.Status == config.UserStatusEnabled {
+			return nil
+		}
+		return model.EnableUser(user)
+	}
+
+	if user.Status == config.UserStatusDisabled {
+		return nil
+	}
+	err := model.DeprovisionUser(user)
+	if err == nil {
+		logger.SysLog(fmt.Sprintf("scim: user %d deprovisioned", user.Id))
+	}
+	return err
+}
+
+func getScimUser(c *gin.Context) *model.User {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		scimError(c, http.StatusNotFound, "user not found")
+		return nil
+	}
+	// 只能访问由身份源管理的普通用户，其他用户视为不存在
+	user, err := model.GetProvisionedUserById(id)
+	if err != nil {
+		scimError(c, http.StatusNotFound, "user not found")
+		return nil
+	}
+	return user
+}
+
+func ScimServiceProviderConfig(c *gin.Context) {
+	scimJSON(c, http.StatusOK, gin.H{
+		"schemas":        []string{scimSchemaSPConfig},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": scimMaxItemsPerPage},
+		"changePassword": gin.H{"supported": false},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": false},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication scheme using the OAuth Bearer Token Standard",
+		}},
+	})
+}
+
+func ScimListUsers(c *gin.Context) {
+	startIndex, count := scimPagination(c)
+
+	field, value := "", ""
+	if filter := c.Query("filter"); filter != "" {
+		matches := scimFilterRegexp.FindStringSubmatch(filter)
+		if matches == nil {
+			scimError(c, http.StatusBadRequest, "unsupported filter")
+			return
+		}
+		switch strings.ToLower(matches[1]) {
+		case "username":
+			field = "username"
+		case "externalid":
+			field = "scim_id"
+		case "emails.value":
+			field = "email"
+		default:
+			scimError(c, http.StatusBadRequest, "unsupported filter attribute")
+			return
+		}
+		value = matches[2]
+	}
+
+	users, total, err := model.GetProvisionedUsers(field, value, startIndex-1, count)
+	if err != nil {
+		scimError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resources := make([]*scimUser, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, toScimUser(user))
+	}
+	scimListResponse(c, resources, total, startIndex, len(resources))
+}
+
+func ScimGetUser(c *gin.Context) {
+	user := getScimUser(c)
+	if user == nil {
+		return
+	}
+	scimJSON(c, http.StatusOK, toScimUser(user))
+}
+
+// ScimCreateUser 由身份源创建用户，不受注册开关限制
+func ScimCreateUser(c *gin.Context) {
+	var resource scimUser
+	if err := c.ShouldBindJSON(&resource); err != nil || resource.UserName == "" {
+		scimError(c, http.StatusBadRequest, "userName is required")
+		return
+	}
+
+	if model.IsUsernameAlreadyTaken(resource.UserName) {
+		c.Header("Content-Type", scimContentType)
+		c.JSON(http.StatusConflict, gin.H{
+			"schemas":  []string{scimSchemaError},
+			"status":   "409",
+			"scimType": "uniqueness",
+			"detail":   "userName already exists",
+		})
+		return
+	}
+
+	// 未提供 externalId 时以 userName 标识，保证创建的用户始终由身份源管理
+	if resource.ExternalId == "" {
+		resource.ExternalId = resource.UserName
+	}
+	if model.IsScimIdTaken(resource.ExternalId, 0) {
+		scimError(c, http.StatusConflict, "externalId already exists")
+		return
+	}
+
+	user := &model.User{
+		Role:   config.RoleCommonUser,
+		Status: config.UserStatusEnabled,
+	}
+	applyScimUser(user, &resource)
+	if resource.Active != nil && !*resource.Active {
+		user.Status = config.UserStatusDisabled
+	}
+
+	if err := user.Insert(0); err != nil {
+		scimError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	scimJSON(c, http.StatusCreated, toScimUser(user))
+}
+
+func ScimReplaceUser(c *gin.Context) {
+	user := getScimUser(c)
+	if user == nil {
+		return
+	}
+
+	var resource scimUser
+	if err := c.ShouldBindJSON(&resource); err != nil {
+		scimError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if resource.UserName != "" && resource.UserName != user.Username && model.IsUsernameAlreadyTaken(resource.UserName) {
+		scimError(c, http.StatusConflict, "userName already exists")
+		return
+	}
+	if resource.ExternalId != "" && resource.ExternalId != user.ScimId && model.IsScimIdTaken(resource.ExternalId, user.Id) {
+		scimError(c, http.StatusConflict, "externalId already exists")
+		return
+	}
+
+	applyScimUser(user, &resource)
+	if err := user.Update(false); err != nil {
+		scimError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if resource.Active != nil {
+		if err := setScimUserActive(user, *resource.Active); err != nil {
+			scimError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	scimJSON(c, http.StatusOK, toScimUser(user))
+}
+
+func ScimPatchUser(c *gin.Context) {
+	user := getScimUser(c)
+	if user == nil {
+		return
+	}
+
+	var req scimPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		scimError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var active *bool
+	resource := &scimUser{}
+	for _, operation := range req.Operations {
+		if strings.EqualFold(operation.Op, "remove") {
+			continue
+		}
+
+		// 未指定 path 时 value 为包含多个属性的对象
+		values := map[string]json.RawMessage{}
+		if operation.Path == "" {
+			if err := json.Unmarshal(operation.Value, &values); err != nil {
+				scimError(c, http.StatusBadRequest, "invalid patch value")
+				return
+			}
+		} else {
+			values[operation.Path] = operation.Value
+		}
+
+		for path, value := range values {
+			var str string
+			switch strings.ToLower(path) {
+			case "active":
+				enabled, err := scimBool(value)
+				if err != nil {
+					scimError(c, http.StatusBadRequest, "invalid active value")
+					return
+				}
+				active = &enabled
+			case "username":
+				if json.Unmarshal(value, &str) == nil {
+					resource.UserName = str
+				}
+			case "externalid":
+				if json.Unmarshal(value, &str) == nil {
+					resource.ExternalId = str
+				}
+			case "displayname", "name.formatted":
+				if json.Unmarshal(value, &str) == nil {
+					resource.DisplayName = str
+				}
+			case "emails", `emails[type eq "work"].value`:
+				var emails []scimEmail
+				if json.Unmarshal(value, &emails) == nil {
+					resource.Emails = emails
+				} else if json.Unmarshal(value, &str) == nil {
+					resource.Emails = []scimEmail{{Value: str, Primary: true}}
+				}
+			}
+		}
+	}
+
+	if resource.UserName != "" && resource.UserName != user.Username && model.IsUsernameAlreadyTaken(resource.UserName) {
+		scimError(c, http.StatusConflict, "userName already exists")
+		return
+	}
+	if resource.ExternalId != "" && resource.ExternalId != user.ScimId && model.IsScimIdTaken(resource.ExternalId, user.Id) {
+		scimError(c, http.StatusConflict, "externalId already exists")
+		return
+	}
+
+	applyScimUser(user, resource)
+	if err := user.Update(false); err != nil {
+		scimError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if active != nil {
+		if err := setScimUserActive(user, *active); err != nil {
+			scimError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	scimJSON(c, http.StatusOK, toScimUser(user))
+}
+
+// ScimDeleteUser 禁用用户、吊销令牌后删除用户，日志等数据保留
+func ScimDeleteUser(c *gin.Context) {
+	user := getScimUser(c)
+	if user == nil {
+		return
+	}
+
+	if err := setScimUserActive(user, false); err != nil {
+		scimError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := user.Delete(); err != nil {
+		scimError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func toScimGroup(userGroup *model.UserGroup) (*scimGroup, error) {
+	ids, err := model.GetProvisionedUserIdsByGroup(userGroup.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]scimMember, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, scimMember{Value: strconv.Itoa(id)})
+	}
+
+	return &scimGroup{
+		Schemas:     []string{scimSchemaGroup},
+		Id:          strconv.Itoa(userGroup.Id),
+		DisplayName: userGroup.Symbol,
+		Members:     members,
+		Meta: &scimMeta{
+			ResourceType: "Group",
+			Location:     scimLocation("Groups", userGroup.Id),
+		},
+	}, nil
+}
+
+func getScimGroup(c *gin.Context) *model.UserGroup {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		scimError(c, http.StatusNotFound, "group not found")
+		return nil
+	}
+	userGroup, err := model.GetUserGroupsById(id)
+	if err != nil {
+		scimError(c, http.StatusNotFound, "group not found")
+		return nil
+	}
+	return userGroup
+}
+
+var errScimMemberNotFound = errors.New("member not found")
+
+// setScimGroupMembers 将成员加入分组，或将分组中的成员移回默认分组，
+// 成员必须都是由身份源管理的用户，否则不做任何修改
+func setScimGroupMembers(userGroup *model.UserGroup, members []scimMember, add bool) error {
+	users := make([]*model.User, 0, len(members))
+	for _, member := range members {
+		userId, err := strconv.Atoi(member.Value)
+		if err != nil {
+			return errScimMemberNotFound
+		}
+		user, err := model.GetProvisionedUserById(userId)
+		if err != nil {
+			return errScimMemberNotFound
+		}
+		users = append(users, user)
+	}
+
+	for _, user := range users {
+		var err error
+		if add {
+			err = model.SetUserGroup(user.Id, userGroup.Symbol)
+		} else if user.Group == userGroup.Symbol {
+			err = model.SetUserGroup(user.Id, scimDefaultGroup)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceScimGroupMembers 先加入新成员，成员校验失败时不会移出原有成员
+func replaceScimGroupMembers(userGroup *model.UserGroup, members []scimMember) error {
+	current, err := toScimGroup(userGroup)
+	if err != nil {
+		return err
+	}
+	if err := setScimGroupMembers(userGroup, members, true); err != nil {
+		return err
+	}
+
+	keep := make(map[string]bool, len(members))
+	for _, member := range members {
+		keep[member.Value] = true
+	}
+	removed := make([]scimMember, 0)
+	for _, member := range current.Members {
+		if !keep[member.Value] {
+			removed = append(removed, member)
+		}
+	}
+	return setScimGroupMembers(userGroup, removed, false)
+}
+
+// scimMemberError 成员不存在时返回 404
+func scimMemberError(c *gin.Context, err error) {
+	if errors.Is(err, errScimMemberNotFound) {
+		scimError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	scimError(c, http.StatusInternalServerError, err.Error())
+}
+
+func scimGroupResponse(c *gin.Context, status int, userGroup *model.UserGroup) {
+	resource, err := toScimGroup(userGroup)
+	if err != nil {
+		scimError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	scimJSON(c, status, resource)
+}
+
+func ScimListGroups(c *gin.Context) {
+	startIndex, count := scimPagination(c)
+
+	var userGroups []*model.UserGroup
+	if filter := c.Query("filter"); filter != "" {
+		matches := scimFilterRegexp.FindStringSubmatch(filter)
+		if matches == nil || !strings.EqualFold(matches[1], "displayName") {
+			scimError(c, http.StatusBadRequest, "unsupported filter")
+			return
+		}
+		userGroup, err := model.GetUserGroupBySymbol(matches[2])
+		if err == nil {
+			userGroups = append(userGroups, userGroup)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			scimError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		all, err := model.GetUserGroupsAll(false)
+		if err != nil {
+			scimError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		userGroups = all
+	}
+
+	total := len(userGroups)
+	start := min(startIndex-1, total)
+	end := min(start+count, total)
+
+	resources := make([]*scimGroup, 0, end-start)
+	for _, userGroup := range userGroups[start:end] {
+		resource, err := toScimGroup(userGroup)
+		if err != nil {
+			scimError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		resources = append(resources, resource)
+	}
+	scimListResponse(c, resources, int64(total), startIndex, len(resources))
+}
+
+func ScimGetGroup(c *gin.Context) {
+	userGroup := getScimGroup(c)
+	if userGroup == nil {
+		return
+	}
+	scimGroupResponse(c, http.StatusOK, userGroup)
+}
+
+// ScimCreateGroup displayName 对应用户分组标识，分组不存在时按默认倍率创建
+func ScimCreateGroup(c *gin.Context) {
+	var resource scimGroup
+	if err := c.ShouldBindJSON(&resource); err != nil || resource.DisplayName == "" {
+		scimError(c, http.StatusBadRequest, "displayName is required")
+		return
+	}
+
+	userGroup, err := model.GetUserGroupBySymbol(resource.DisplayName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		enable := true
+		userGroup = &model.UserGroup{
+			Symbol:  resource.DisplayName,
+			Name:    resource.DisplayName,
+			Ratio:   1,
+			APIRate: 600,
+			Enable:  &enable,
+		}
+		err = userGroup.Create()
+	}
+	if err != nil {
+		scimError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := setScimGroupMembers(userGroup, resource.Members, true); err != nil {
+		scimMemberError(c, err)
+		return
+	}
+
+	scimGroupResponse(c, http.StatusCreated, userGroup)
+}
+
+func ScimReplaceGroup(c *gin.Context) {
+	userGroup := getScimGroup(c)
+	if userGroup == nil {
+		return
+	}
+
+	var resource scimGroup
+	if err := c.ShouldBindJSON(&resource); err != nil {
+		scimError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := replaceScimGroupMembers(userGroup, resource.Members); err != nil {
+		scimMemberError(c, err)
+		return
+	}
+
+	scimGroupResponse(c, http.StatusOK, userGroup)
+}
+
+func ScimPatchGroup(c *gin.Context) {
+	userGroup := getScimGroup(c)
+	if userGroup == nil {
+		return
+	}
+
+	var req scimPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		scimError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, operation := range req.Operations {
+		var members []scimMember
+		if matches := scimMemberPathRegexp.FindStringSubmatch(operation.Path); matches != nil {
+			members = []scimMember{{Value: matches[1]}}
+		} else if strings.EqualFold(operation.Path, "members") {
+			_ = json.Unmarshal(operation.Value, &members)
+		} else {
+			// 不支持修改分组名称，分组标识与计费配置相关
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(operation.Op) {
+		case "add":
+			err = setScimGroupMembers(userGroup, members, true)
+		case "remove":
+			err = setScimGroupMembers(userGroup, members, false)
+		case "replace":
+			err = replaceScimGroupMembers(userGroup, members)
+		}
+		if err != nil {
+			scimMemberError(c, err)
+			return
+		}
+	}
+
+	scimGroupResponse(c, http.StatusOK, userGroup)
+}
+
+// ScimDeleteGroup 分组带有计费配置，不会删除分组本身，只将成员移回默认分组
+func ScimDeleteGroup(c *gin.Context) {
+	userGroup := getScimGroup(c)
+	if userGroup == nil {
+		return
+	}
+
+	current, err := toScimGroup(userGroup)
+	if err != nil {
+		scimError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := setScimGroupMembers(userGroup, current.Members, false); err != nil {
+		scimMemberError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
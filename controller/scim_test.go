@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupScimTest(t *testing.T) (*gin.Engine, map[string]*model.User, *model.UserGroup) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.User{}, &model.Token{}, &model.Log{}, &model.UserGroup{}))
+
+	originDB := model.DB
+	model.DB = db
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	t.Cleanup(func() {
+		model.DB = originDB
+	})
+
+	users := map[string]*model.User{
+		"root":        {Username: "root", Role: config.RoleRootUser, ScimId: "root@idp"},
+		"admin":       {Username: "admin", Role: config.RoleAdminUser, ScimId: "admin@idp"},
+		"local":       {Username: "local", Role: config.RoleCommonUser},
+		"provisioned": {Username: "provisioned", Role: config.RoleCommonUser, ScimId: "provisioned@idp"},
+	}
+	for name, user := range users {
+		user.Status = config.UserStatusEnabled
+		user.AccessToken = name
+		user.AffCode = name
+		user.Group = "default"
+		require.NoError(t, db.Create(user).Error)
+	}
+
+	userGroup := &model.UserGroup{Symbol: "vip", Name: "vip", Ratio: 1}
+	require.NoError(t, db.Create(userGroup).Error)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/Users", ScimListUsers)
+	engine.GET("/Users/:id", ScimGetUser)
+	engine.POST("/Users", ScimCreateUser)
+	engine.PUT("/Users/:id", ScimReplaceUser)
+	engine.PATCH("/Users/:id", ScimPatchUser)
+	engine.DELETE("/Users/:id", ScimDeleteUser)
+	engine.PATCH("/Groups/:id", ScimPatchGroup)
+	engine.PUT("/Groups/:id", ScimReplaceGroup)
+
+	return engine, users, userGroup
+}
+
+func scimRequest(engine *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestScimUserAccess(t *testing.T) {
+	tests := []struct {
+		user       string
+		wantStatus map[string]int
+	}{
+		{user: "root", wantStatus: map[string]int{http.MethodGet: 404, http.MethodPut: 404, http.MethodPatch: 404, http.MethodDelete: 404}},
+		{user: "admin", wantStatus: map[string]int{http.MethodGet: 404, http.MethodPut: 404, http.MethodPatch: 404, http.MethodDelete: 404}},
+		{user: "local", wantStatus: map[string]int{http.MethodGet: 404, http.MethodPut: 404, http.MethodPatch: 404, http.MethodDelete: 404}},
+		{user: "provisioned", wantStatus: map[string]int{http.MethodGet: 200, http.MethodPut: 200, http.MethodPatch: 200, http.MethodDelete: 204}},
+	}
+
+	bodies := map[string]string{
+		http.MethodGet:    "",
+		http.MethodPut:    `{"userName":"renamed","externalId":"evil@idp"}`,
+		http.MethodPatch:  `{"Operations":[{"op":"replace","path":"active","value":false}]}`,
+		http.MethodDelete: "",
+	}
+
+	for _, tt := range tests {
+		for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			t.Run(tt.user+" "+method, func(t *testing.T) {
+				engine, users, _ := setupScimTest(t)
+				user := users[tt.user]
+				w := scimRequest(engine, method, "/Users/"+strconv.Itoa(user.Id), bodies[method])
+				assert.Equal(t, tt.wantStatus[method], w.Code)
+
+				if w.Code == http.StatusNotFound {
+					stored, err := model.GetUserById(user.Id, false)
+					require.NoError(t, err)
+					assert.Equal(t, user.Username, stored.Username)
+					assert.Equal(t, user.ScimId, stored.ScimId)
+					assert.Equal(t, config.UserStatusEnabled, stored.Status)
+				}
+			})
+		}
+	}
+}
+
+func TestScimListUsersOnlyProvisioned(t *testing.T) {
+	engine, _, _ := setupScimTest(t)
+
+	tests := []struct {
+		name      string
+		filter    string
+		wantUsers []string
+	}{
+		{name: "all", filter: "", wantUsers: []string{"provisioned"}},
+		{name: "filter admin by username", filter: `userName eq "admin"`, wantUsers: []string{}},
+		{name: "filter provisioned by externalId", filter: `externalId eq "provisioned@idp"`, wantUsers: []string{"provisioned"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/Users"
+			if tt.filter != "" {
+				path += "?filter=" + url.QueryEscape(tt.filter)
+			}
+			w := scimRequest(engine, http.MethodGet, path, "")
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var resp struct {
+				Resources []scimUser `json:"Resources"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			usernames := make([]string, 0, len(resp.Resources))
+			for _, resource := range resp.Resources {
+				usernames = append(usernames, resource.UserName)
+			}
+			assert.Equal(t, tt.wantUsers, usernames)
+		})
+	}
+}
+
+func TestScimCreateUserExternalId(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantScimId string
+	}{
+		{name: "external id", body: `{"userName":"new","externalId":"new@idp"}`, wantStatus: http.StatusCreated, wantScimId: "new@idp"},
+		{name: "defaults to user name", body: `{"userName":"new"}`, wantStatus: http.StatusCreated, wantScimId: "new"},
+		{name: "external id of an admin", body: `{"userName":"new","externalId":"admin@idp"}`, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, _, _ := setupScimTest(t)
+			w := scimRequest(engine, http.MethodPost, "/Users", tt.body)
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+
+			var resource scimUser
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resource))
+			id, err := strconv.Atoi(resource.Id)
+			require.NoError(t, err)
+			user, err := model.GetProvisionedUserById(id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantScimId, user.ScimId)
+		})
+	}
+}
+
+func TestScimGroupMembers(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		members    []string
+		wantStatus int
+		wantGroups map[string]string
+	}{
+		{name: "add provisioned user", method: http.MethodPatch, members: []string{"provisioned"}, wantStatus: http.StatusOK, wantGroups: map[string]string{"provisioned": "vip", "admin": "default", "local": "default"}},
+		{name: "add admin", method: http.MethodPatch, members: []string{"provisioned", "admin"}, wantStatus: http.StatusNotFound, wantGroups: map[string]string{"provisioned": "default", "admin": "default"}},
+		{name: "add local user", method: http.MethodPatch, members: []string{"local"}, wantStatus: http.StatusNotFound, wantGroups: map[string]string{"local": "default"}},
+		{name: "replace with admin", method: http.MethodPut, members: []string{"admin"}, wantStatus: http.StatusNotFound, wantGroups: map[string]string{"admin": "default"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, users, userGroup := setupScimTest(t)
+
+			members := make([]scimMember, 0, len(tt.members))
+			for _, name := range tt.members {
+				members = append(members, scimMember{Value: strconv.Itoa(users[name].Id)})
+			}
+			var body []byte
+			if tt.method == http.MethodPatch {
+				value, _ := json.Marshal(members)
+				body, _ = json.Marshal(gin.H{"Operations": []gin.H{{"op": "add", "path": "members", "value": json.RawMessage(value)}}})
+			} else {
+				body, _ = json.Marshal(gin.H{"displayName": userGroup.Symbol, "members": members})
+			}
+
+			w := scimRequest(engine, tt.method, "/Groups/"+strconv.Itoa(userGroup.Id), string(body))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			for name, group := range tt.wantGroups {
+				stored, err := model.GetUserGroup(users[name].Id)
+				require.NoError(t, err)
+				assert.Equal(t, group, stored, name)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"one-api/common"
 	"one-api/common/config"
@@ -310,5 +311,11 @@ func validateTokenSetting(setting *model.TokenSetting) error {
 		}
 	}
 
+	for _, scope := range setting.Scopes {
+		if !utils.Contains(scope, config.AllTokenScopes) {
+			return fmt.Errorf("未知的令牌范围: %s", scope)
+		}
+	}
+
 	return nil
 }
@@ -787,6 +787,12 @@ func ChangeUserQuota(c *gin.Context) {
 		return
 	}
 
+	beforeQuota, err := model.GetUserQuota(userId)
+	if err != nil {
+		common.APIRespondWithError(c, http.StatusOK, err)
+		return
+	}
+
 	err = model.ChangeUserQuota(userId, req.Quota, false)
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{
@@ -795,6 +801,8 @@ func ChangeUserQuota(c *gin.Context) {
 		})
 		return
 	}
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityUserQuota, userId,
+		gin.H{"quota": beforeQuota}, gin.H{"quota": beforeQuota + req.Quota, "remark": req.Remark})
 
 	remark := fmt.Sprintf("管理员增减用户额度 %s", common.LogQuota(req.Quota))
 
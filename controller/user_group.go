@@ -57,6 +57,7 @@ func AddUserGroup(c *gin.Context) {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAudit(c, model.AuditActionCreate, model.AuditEntityUserGroup, userGroup.Id, nil, userGroup)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -72,10 +73,13 @@ func UpdateUserGroup(c *gin.Context) {
 		return
 	}
 
+	before, _ := model.GetUserGroupsById(userGroup.Id)
 	if err := userGroup.Update(); err != nil {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	after, _ := model.GetUserGroupsById(userGroup.Id)
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityUserGroup, userGroup.Id, before, after)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -101,6 +105,7 @@ func DeleteUserGroup(c *gin.Context) {
 		common.APIRespondWithError(c, http.StatusOK, err)
 		return
 	}
+	recordAudit(c, model.AuditActionDelete, model.AuditEntityUserGroup, id, userGroup, nil)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -144,6 +149,7 @@ func ChangeUserGroupEnable(c *gin.Context) {
 		})
 		return
 	}
+	recordAudit(c, model.AuditActionUpdate, model.AuditEntityUserGroup, id, gin.H{"enable": *userGroup.Enable}, gin.H{"enable": !*userGroup.Enable})
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -1,10 +1,12 @@
 package cron
 
 import (
+	"fmt"
 	"github.com/spf13/viper"
 	"one-api/common/config"
 	"one-api/common/logger"
 	"one-api/common/scheduler"
+	"one-api/controller"
 	"one-api/model"
 	"time"
 
@@ -59,6 +61,80 @@ func InitCron() {
 		}),
 	)
 
+	// 每天按保留天数清理日志
+	err = scheduler.Manager.AddJob(
+		"clean_expired_logs",
+		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(3, 30, 0))),
+		gocron.NewTask(func() {
+			model.CleanExpiredLogs()
+		}),
+	)
+	if err != nil {
+		logger.SysError("Cron job error: " + err.Error())
+	}
+
+	// 每天清理过期的请求内容记录
+	err = scheduler.Manager.AddJob(
+		"clean_payload_captures",
+		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(3, 0, 0))),
+		gocron.NewTask(func() {
+			count, err := model.DeleteExpiredPayloadCaptures(config.PayloadCaptureRetentionDays)
+			if err != nil {
+				logger.SysError("Clean payload captures error: " + err.Error())
+				return
+			}
+			logger.SysLog(fmt.Sprintf("清理过期请求内容记录 %d 条", count))
+		}),
+	)
+	if err != nil {
+		logger.SysError("Cron job error: " + err.Error())
+	}
+
+	// 定时更新渠道余额，余额低于阈值时自动禁用渠道
+	balanceUpdateFrequency := viper.GetInt("channel.update_frequency")
+	if balanceUpdateFrequency > 0 {
+		err = scheduler.Manager.AddJob(
+			"update_channels_balance",
+			gocron.DurationJob(time.Duration(balanceUpdateFrequency)*time.Minute),
+			gocron.NewTask(controller.UpdateAllChannelsBalanceJob),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			logger.SysError("Cron job error: " + err.Error())
+		}
+	}
+
+	// 定时同步开启了模型同步的渠道的上游模型列表
+	modelSyncFrequency := viper.GetInt("channel.model_sync_frequency")
+	if modelSyncFrequency > 0 {
+		err = scheduler.Manager.AddJob(
+			"sync_channels_models",
+			gocron.DurationJob(time.Duration(modelSyncFrequency)*time.Minute),
+			gocron.NewTask(controller.SyncAllChannelsModelsJob),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			logger.SysError("Cron job error: " + err.Error())
+		}
+	}
+
+	// 每天清理过期的渠道余额记录
+	err = scheduler.Manager.AddJob(
+		"clean_channel_balance_histories",
+		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(3, 15, 0))),
+		gocron.NewTask(func() {
+			count, err := model.DeleteExpiredChannelBalanceHistories(viper.GetInt("channel.balance_history_days"))
+			if err != nil {
+				logger.SysError("Clean channel balance histories error: " + err.Error())
+				return
+			}
+			logger.SysLog(fmt.Sprintf("清理过期渠道余额记录 %d 条", count))
+		}),
+	)
+	if err != nil {
+		logger.SysError("Cron job error: " + err.Error())
+	}
+
 	// 开启自动更新 并且设置了有效自动更新时间 同时自动更新模式不是system 则会从服务器拉取最新价格表
 	autoPriceUpdatesInterval := viper.GetInt("auto_price_updates_interval")
 	autoPriceUpdates := viper.GetBool("auto_price_updates")
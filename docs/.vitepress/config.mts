@@ -42,6 +42,7 @@ export default defineConfig({
           { text: '使用说明', link: '/use/index' },
           { text: '添加 VertexAI', link: '/use/VertexAI' },
           { text: 'Rerank 接口', link: '/use/Rerank' },
+          { text: '声明式渠道', link: '/use/declarative' },
           { text: '推理设置', link: '/use/reasoning' },
           { text: '价格更新', link: '/use/prices_update' },
           { text: '特殊调用', link: '/use/special' },
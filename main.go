@@ -1,14 +1,18 @@
 package main
 
 import (
+	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"net/http"
 	"one-api/cli"
 	"one-api/common"
 	"one-api/common/cache"
 	"one-api/common/config"
+	"one-api/common/encryption"
 	"one-api/common/logger"
+	"one-api/common/logsink"
 	"one-api/common/notify"
 	"one-api/common/oidc"
 	"one-api/common/redis"
@@ -16,6 +20,7 @@ import (
 	"one-api/common/search"
 	"one-api/common/storage"
 	"one-api/common/telegram"
+	"one-api/common/tracing"
 	"one-api/common/webauthn"
 	"one-api/controller"
 	"one-api/cron"
@@ -24,6 +29,9 @@ import (
 	"one-api/relay/task"
 	"one-api/router"
 	"one-api/safty"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/sessions"
@@ -32,6 +40,9 @@ import (
 	"github.com/spf13/viper"
 )
 
+// 退出时等待处理中的请求完成的最长时间
+const shutdownTimeout = 30 * time.Second
+
 //go:embed web/build
 var buildFS embed.FS
 
@@ -48,20 +59,37 @@ func main() {
 	logger.SetupLogger()
 	logger.SysLog("One Hub " + config.Version + " started")
 
+	// Initialize tracing
+	tracing.InitTracing()
+	defer tracing.Shutdown()
+
 	// Initialize user token
 	err := common.InitUserToken()
 	if err != nil {
 		logger.FatalLog("failed to initialize user token: " + err.Error())
 	}
 
+	// Initialize encryption
+	encryption.InitEncryption()
 	// Initialize SQL Database
 	model.SetupDB()
 	defer model.CloseDB()
+	if *cli.EncryptData {
+		cli.EncryptExistingData()
+		return
+	}
 	// Initialize Redis
 	redis.InitRedisClient()
 	cache.InitCacheManager()
 	// Initialize options
 	model.InitOptionMap()
+	// Initialize log sinks
+	logsink.InitLogSinks()
+	defer logsink.Close()
+	model.InitStatisticsAccumulator()
+	defer model.FlushStatistics()
+	model.InitChannelKeyPool()
+	defer model.ChannelKeyPoolInstance.FlushStats()
 	// Initialize oidc
 	oidc.InitOIDCConfig()
 	// Initialize wenauthn
@@ -90,7 +118,24 @@ func main() {
 		logger.SysLog("Enable User Invoice Monthly Data")
 		go model.InsertStatisticsMonth()
 	}
-	initHttpServer()
+	server := initHttpServer()
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.FatalLog("failed to start HTTP server: " + err.Error())
+		}
+	}()
+
+	// 收到退出信号后停止接收新请求，等待处理中的请求完成，再执行上面的 defer 写入缓冲的数据
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	logger.SysLog("shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.SysError("failed to shutdown HTTP server: " + err.Error())
+	}
 }
 
 func initMemoryCache() {
@@ -112,11 +157,10 @@ func initMemoryCache() {
 }
 
 func initSync() {
-	// go controller.AutomaticallyUpdateChannels(viper.GetInt("channel.update_frequency"))
 	go controller.AutomaticallyTestChannels(viper.GetInt("channel.test_frequency"))
 }
 
-func initHttpServer() {
+func initHttpServer() *http.Server {
 	if viper.GetString("gin_mode") != "debug" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -143,10 +187,11 @@ func initHttpServer() {
 
 	router.SetRouter(server, buildFS, indexPage)
 	port := viper.GetString("port")
+	logger.SysLog("listening on port " + port)
 
-	err := server.Run(":" + port)
-	if err != nil {
-		logger.FatalLog("failed to start HTTP server: " + err.Error())
+	return &http.Server{
+		Addr:    ":" + port,
+		Handler: server,
 	}
 }
 
@@ -160,7 +205,10 @@ func SyncChannelCache(frequency int) {
 		time.Sleep(time.Duration(frequency) * time.Second)
 		logger.SysLog("syncing channels from database")
 		model.ChannelGroup.Load()
+		model.ChannelKeyPoolInstance.Load()
+		model.VirtualModelsInstance.Load()
 		model.PricingInstance.Init()
 		model.ModelOwnedBysInstance.Load()
+		model.AdminRolesInstance.Load()
 	}
 }
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"one-api/common/config"
+	"one-api/model"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// 渠道状态需要查询数据库，缓存一段时间避免频繁抓取时压力过大
+const channelStateCacheDuration = 30 * time.Second
+
+type channelCollector struct {
+	enabledDesc   *prometheus.Desc
+	balanceDesc   *prometheus.Desc
+	cooldownsDesc *prometheus.Desc
+
+	mu        sync.Mutex
+	channels  []*model.Channel
+	updatedAt time.Time
+}
+
+func newChannelCollector() *channelCollector {
+	return &channelCollector{
+		enabledDesc: prometheus.NewDesc(
+			"channel_enabled",
+			"Whether the channel is enabled (1) or disabled (0).",
+			[]string{"channel_id", "channel_type"}, nil,
+		),
+		balanceDesc: prometheus.NewDesc(
+			"channel_balance",
+			"Balance of the channel in USD.",
+			[]string{"channel_id", "channel_type"}, nil,
+		),
+		cooldownsDesc: prometheus.NewDesc(
+			"channel_cooldown_entries",
+			"Number of channel and model pairs currently in cooldown.",
+			nil, nil,
+		),
+	}
+}
+
+func (cc *channelCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cc.enabledDesc
+	ch <- cc.balanceDesc
+	ch <- cc.cooldownsDesc
+}
+
+func (cc *channelCollector) Collect(ch chan<- prometheus.Metric) {
+	defer func() {
+		if r := recover(); r != nil {
+			RecordPanic("metrics")
+		}
+	}()
+
+	for _, channel := range cc.getChannels() {
+		channelId := strconv.Itoa(channel.Id)
+		channelType := strconv.Itoa(channel.Type)
+
+		enabled := 0.0
+		if channel.Status == config.ChannelStatusEnabled {
+			enabled = 1
+		}
+		ch <- prometheus.MustNewConstMetric(cc.enabledDesc, prometheus.GaugeValue, enabled, channelId, channelType)
+		ch <- prometheus.MustNewConstMetric(cc.balanceDesc, prometheus.GaugeValue, channel.Balance, channelId, channelType)
+	}
+
+	ch <- prometheus.MustNewConstMetric(cc.cooldownsDesc, prometheus.GaugeValue, float64(model.ChannelGroup.CountCooldowns()))
+}
+
+func (cc *channelCollector) getChannels() []*model.Channel {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if model.DB == nil || time.Since(cc.updatedAt) < channelStateCacheDuration {
+		return cc.channels
+	}
+
+	channels, err := model.GetChannelsState()
+	if err != nil {
+		return cc.channels
+	}
+	cc.channels = channels
+	cc.updatedAt = time.Now()
+
+	return cc.channels
+}
@@ -14,6 +14,15 @@ var (
 	httpRequestDuration *prometheus.HistogramVec
 	providerCounter     *prometheus.CounterVec
 	panicCounter        *prometheus.CounterVec
+
+	providerDuration    *prometheus.HistogramVec
+	providerFirstToken  *prometheus.HistogramVec
+	tokensCounter       *prometheus.CounterVec
+	quotaCounter        *prometheus.CounterVec
+	userTokensCounter   *prometheus.CounterVec
+	userQuotaCounter    *prometheus.CounterVec
+	inFlightStreams     prometheus.Gauge
+	rateLimitRejections *prometheus.CounterVec
 )
 
 func init() {
@@ -52,6 +61,72 @@ func init() {
 		[]string{"type"},
 	)
 
+	// 4. 监控上游延迟
+	providerDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "provider_request_duration_seconds",
+			Help:    "Duration of upstream provider requests in seconds.",
+			Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+		},
+		[]string{"channel_id", "model"},
+	)
+	providerFirstToken = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "provider_first_token_seconds",
+			Help:    "Time to first token of upstream provider streams in seconds.",
+			Buckets: []float64{0.1, 0.25, 0.5, 1, 2, 3, 5, 10, 20, 30, 60},
+		},
+		[]string{"channel_id", "model"},
+	)
+
+	// 5. 监控 token 和额度消耗
+	tokensCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tokens_total",
+			Help: "Total number of tokens consumed.",
+		},
+		[]string{"model", "group", "type"},
+	)
+	quotaCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "quota_consumed_total",
+			Help: "Total quota consumed.",
+		},
+		[]string{"model", "group"},
+	)
+	// 按用户统计，需要在配置中开启
+	userTokensCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "user_tokens_total",
+			Help: "Total number of tokens consumed per user.",
+		},
+		[]string{"user_id", "type"},
+	)
+	userQuotaCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "user_quota_consumed_total",
+			Help: "Total quota consumed per user.",
+		},
+		[]string{"user_id"},
+	)
+
+	// 6. 监控流式请求和限流
+	inFlightStreams = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "in_flight_streams",
+			Help: "Number of stream requests currently being relayed.",
+		},
+	)
+	rateLimitRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limit_rejections_total",
+			Help: "Total number of requests rejected by rate limiters.",
+		},
+		[]string{"limiter", "group"},
+	)
+
+	// 7. 监控渠道状态
+	prometheus.MustRegister(newChannelCollector())
 }
 
 // RecordHttp 记录 HTTP 请求
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"one-api/common/utils"
+	"one-api/types"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const otherUserLabel = "other"
+
+// userLabels 限制按用户统计时的用户数量，超过上限的用户合并到 other，避免时间序列无限增长
+var userLabels = struct {
+	sync.Mutex
+	ids map[int]struct{}
+}{ids: make(map[int]struct{})}
+
+func userLabel(userId int) string {
+	maxUsers := utils.GetOrDefault("metrics.max_users", 1000)
+
+	userLabels.Lock()
+	defer userLabels.Unlock()
+
+	if _, ok := userLabels.ids[userId]; !ok {
+		if len(userLabels.ids) >= maxUsers {
+			return otherUserLabel
+		}
+		userLabels.ids[userId] = struct{}{}
+	}
+
+	return strconv.Itoa(userId)
+}
+
+// RecordProviderLatency 记录上游请求耗时和首字耗时，firstResponseTime 为零值时不记录首字耗时
+func RecordProviderLatency(channelId int, model string, startTime, firstResponseTime time.Time) {
+	go SafelyRecordMetric(func() {
+		channel := strconv.Itoa(channelId)
+		providerDuration.WithLabelValues(channel, model).Observe(time.Since(startTime).Seconds())
+		if !firstResponseTime.IsZero() {
+			providerFirstToken.WithLabelValues(channel, model).Observe(firstResponseTime.Sub(startTime).Seconds())
+		}
+	})
+}
+
+// RecordUsage 记录 token 和额度消耗
+func RecordUsage(model, group string, userId int, usage *types.Usage, quota int) {
+	if usage == nil {
+		return
+	}
+
+	SafelyRecordMetric(func() {
+		tokens := map[string]int{
+			"prompt":     usage.PromptTokens,
+			"completion": usage.CompletionTokens,
+			"cached":     usage.PromptTokensDetails.CachedTokens,
+			"reasoning":  usage.CompletionTokensDetails.ReasoningTokens,
+		}
+
+		byUser := utils.GetOrDefault("metrics.user_labels", false)
+		user := ""
+		if byUser {
+			user = userLabel(userId)
+		}
+
+		for tokenType, count := range tokens {
+			if count <= 0 {
+				continue
+			}
+			tokensCounter.WithLabelValues(model, group, tokenType).Add(float64(count))
+			if byUser {
+				userTokensCounter.WithLabelValues(user, tokenType).Add(float64(count))
+			}
+		}
+
+		if quota > 0 {
+			quotaCounter.WithLabelValues(model, group).Add(float64(quota))
+			if byUser {
+				userQuotaCounter.WithLabelValues(user).Add(float64(quota))
+			}
+		}
+	})
+}
+
+// StreamStarted 记录开始转发的流式请求，返回结束时调用的函数
+func StreamStarted() func() {
+	inFlightStreams.Inc()
+	return func() {
+		inFlightStreams.Dec()
+	}
+}
+
+// RecordRateLimitRejection 记录被限流的请求
+func RecordRateLimitRejection(limiter, group string) {
+	rateLimitRejections.WithLabelValues(limiter, group).Inc()
+}
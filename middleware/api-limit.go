@@ -3,6 +3,7 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"one-api/metrics"
 	"one-api/model"
 	"time"
 
@@ -30,6 +31,7 @@ func DynamicRedisRateLimiter() gin.HandlerFunc {
 		key := fmt.Sprintf(LIMIT_KEY, userID)
 
 		if !limiter.Allow(key) {
+			metrics.RecordRateLimitRejection("api", userGroup)
 			abortWithMessage(c, http.StatusTooManyRequests, RATE_LIMIT_EXCEEDED_MSG)
 			return
 		}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"one-api/common/config"
+	"one-api/common/tracing"
 	"one-api/common/utils"
 	"one-api/model"
 	"strings"
@@ -13,6 +14,13 @@ import (
 )
 
 func authHelper(c *gin.Context, minRole int) {
+	if authenticate(c, minRole) {
+		c.Next()
+	}
+}
+
+// authenticate 校验登录状态和角色等级并设置上下文，失败时中断请求并返回 false
+func authenticate(c *gin.Context, minRole int) bool {
 	session := sessions.Default(c)
 	username := session.Get("username")
 	role := session.Get("role")
@@ -29,7 +37,7 @@ func authHelper(c *gin.Context, minRole int) {
 					"message": "无权进行此操作，未登录且未提供 access token",
 				})
 				c.Abort()
-				return
+				return false
 			}
 			accessToken = fmt.Sprintf("Bearer %s", token)
 		}
@@ -46,7 +54,7 @@ func authHelper(c *gin.Context, minRole int) {
 				"message": "无权进行此操作，access token 无效",
 			})
 			c.Abort()
-			return
+			return false
 		}
 	}
 	if status.(int) == config.UserStatusDisabled {
@@ -55,7 +63,7 @@ func authHelper(c *gin.Context, minRole int) {
 			"message": "用户已被封禁",
 		})
 		c.Abort()
-		return
+		return false
 	}
 	if role.(int) < minRole {
 		c.JSON(http.StatusOK, gin.H{
@@ -63,12 +71,12 @@ func authHelper(c *gin.Context, minRole int) {
 			"message": "无权进行此操作，权限不足",
 		})
 		c.Abort()
-		return
+		return false
 	}
 	c.Set("username", username)
 	c.Set("role", role)
 	c.Set("id", id)
-	c.Next()
+	return true
 }
 
 func TrySetUserBySession() func(c *gin.Context) {
@@ -113,13 +121,112 @@ func RootAuth() func(c *gin.Context) {
 	}
 }
 
+// PermissionAuth 校验用户是否具备指定的管理权限
+func PermissionAuth(permission string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		if !authenticate(c, config.RoleCommonUser) {
+			return
+		}
+		if !model.HasPermission(c.GetInt("id"), c.GetInt("role"), permission) {
+			c.JSON(http.StatusOK, gin.H{
+				"success": false,
+				"message": "无权进行此操作，缺少权限 " + permission,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// TokenScope 校验令牌是否具备调用当前接口的范围，需放在令牌认证之后
+func TokenScope(scope string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		value, _ := c.Get("token_setting")
+		setting, ok := value.(*model.TokenSetting)
+		if ok && setting != nil && !setting.HasScope(scope) {
+			abortWithMessage(c, http.StatusForbidden, "令牌无权访问此接口，缺少范围 "+scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// UserOrTokenAuth 用户登录或使用具备指定范围的令牌均可访问，令牌只能访问自身用户的数据，
+// 便于仪表盘等外部系统使用令牌拉取用量
+func UserOrTokenAuth(scope string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		key := strings.TrimPrefix(c.Request.Header.Get("Authorization"), "Bearer ")
+		if !strings.HasPrefix(key, "sk-") {
+			authHelper(c, config.RoleCommonUser)
+			return
+		}
+
+		if !authenticateScopedToken(c, strings.TrimPrefix(key, "sk-"), scope) {
+			return
+		}
+		c.Next()
+	}
+}
+
+func authenticateScopedToken(c *gin.Context, key, scope string) bool {
+	abort := func(message string) bool {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": message,
+		})
+		c.Abort()
+		return false
+	}
+
+	token, err := model.ValidateUserToken(strings.Split(key, "#")[0])
+	if err != nil {
+		return abort(err.Error())
+	}
+	setting := token.Setting.Data()
+	if !setting.HasScope(scope) {
+		return abort("无权进行此操作，令牌缺少范围 " + scope)
+	}
+	c.Set("token_setting", &setting)
+	if err := checkLimitIP(c); err != nil {
+		return abort(err.Error())
+	}
+	if enabled, err := model.CacheIsUserEnabled(token.UserId); err != nil || !enabled {
+		return abort("用户已被封禁")
+	}
+	username, err := model.CacheGetUsername(token.UserId)
+	if err != nil {
+		return abort(err.Error())
+	}
+
+	c.Set("username", username)
+	c.Set("role", config.RoleCommonUser)
+	c.Set("id", token.UserId)
+	c.Set("token_id", token.Id)
+	return true
+}
+
 func tokenAuth(c *gin.Context, key string) {
+	span := tracing.StartGin(c, "auth")
+	ok := validateToken(c, key)
+	if !ok {
+		tracing.SetError(span, "unauthorized")
+	}
+	span.End()
+
+	if ok {
+		c.Next()
+	}
+}
+
+// validateToken 校验令牌并设置上下文，失败时中断请求并返回 false
+func validateToken(c *gin.Context, key string) bool {
 	key = strings.TrimPrefix(key, "Bearer ")
 	key = strings.TrimPrefix(key, "sk-")
 
 	if len(key) < 48 {
 		abortWithMessage(c, http.StatusUnauthorized, "无效的令牌")
-		return
+		return false
 	}
 
 	parts := strings.Split(key, "#")
@@ -127,7 +234,7 @@ func tokenAuth(c *gin.Context, key string) {
 	token, err := model.ValidateUserToken(key)
 	if err != nil {
 		abortWithMessage(c, http.StatusUnauthorized, err.Error())
-		return
+		return false
 	}
 
 	c.Set("id", token.UserId)
@@ -138,7 +245,7 @@ func tokenAuth(c *gin.Context, key string) {
 	c.Set("token_setting", utils.GetPointer(token.Setting.Data()))
 	if err := checkLimitIP(c); err != nil {
 		abortWithMessage(c, http.StatusForbidden, err.Error())
-		return
+		return false
 	}
 	if len(parts) > 1 {
 		if model.IsAdmin(token.UserId) {
@@ -149,7 +256,7 @@ func tokenAuth(c *gin.Context, key string) {
 				channelId := utils.String2Int(parts[1])
 				if channelId == 0 {
 					abortWithMessage(c, http.StatusForbidden, "无效的渠道 Id")
-					return
+					return false
 				}
 				c.Set("specific_channel_id", channelId)
 				if len(parts) == 3 && parts[2] == "ignore" {
@@ -158,10 +265,11 @@ func tokenAuth(c *gin.Context, key string) {
 			}
 		} else {
 			abortWithMessage(c, http.StatusForbidden, "普通用户不支持指定渠道")
-			return
+			return false
 		}
 	}
-	c.Next()
+
+	return true
 }
 
 // 检测是否IP白名单
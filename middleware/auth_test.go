@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestTokenScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   string
+		setting *model.TokenSetting
+		want    int
+	}{
+		{name: "legacy token can read billing", scope: config.TokenScopeBillingRead, setting: &model.TokenSetting{}, want: http.StatusOK},
+		{name: "scoped token with billing read", scope: config.TokenScopeBillingRead, setting: &model.TokenSetting{Scopes: []string{config.TokenScopeChat, config.TokenScopeBillingRead}}, want: http.StatusOK},
+		{name: "chat only token cannot read billing", scope: config.TokenScopeBillingRead, setting: &model.TokenSetting{Scopes: []string{config.TokenScopeChat}}, want: http.StatusForbidden},
+		{name: "management read does not include billing", scope: config.TokenScopeBillingRead, setting: &model.TokenSetting{Scopes: []string{config.TokenScopeManagementRead}}, want: http.StatusForbidden},
+		{name: "legacy token needs explicit management read", scope: config.TokenScopeManagementRead, setting: &model.TokenSetting{}, want: http.StatusForbidden},
+		{name: "without token setting", scope: config.TokenScopeBillingRead, want: http.StatusOK},
+	}
+
+	gin.SetMode(gin.TestMode)
+	originLogger := logger.Logger
+	logger.Logger = zap.NewNop()
+	t.Cleanup(func() {
+		logger.Logger = originLogger
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				if tt.setting != nil {
+					c.Set("token_setting", tt.setting)
+				}
+				c.Next()
+			}, TokenScope(tt.scope), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tt.want, recorder.Code)
+		})
+	}
+}
@@ -3,9 +3,11 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"one-api/common/tracing"
 	"one-api/model"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // GroupDistributor 统一分组分发逻辑
@@ -60,10 +62,16 @@ func (gd *GroupDistributor) setGroupRatio(group string) error {
 
 func Distribute() func(c *gin.Context) {
 	return func(c *gin.Context) {
+		span := tracing.StartGin(c, "distribute")
 		distributor := NewGroupDistributor(c)
-		if err := distributor.SetupGroups(); err != nil {
+		err := distributor.SetupGroups()
+		span.SetAttributes(attribute.String("group", c.GetString("token_group")))
+		if err != nil {
+			tracing.SetError(span, err.Error())
+			span.End()
 			return
 		}
+		span.End()
 		c.Next()
 	}
 }
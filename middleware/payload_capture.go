@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"math/rand"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/requester"
+	"one-api/common/utils"
+	"one-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// payloadCaptureWriter 记录返回给客户端的内容，超过限制的部分不再记录
+type payloadCaptureWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *payloadCaptureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *payloadCaptureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *payloadCaptureWriter) capture(data []byte) {
+	remain := w.limit - w.body.Len()
+	if remain <= 0 {
+		return
+	}
+	if len(data) > remain {
+		data = data[:remain]
+	}
+	w.body.Write(data)
+}
+
+func shouldCapturePayload(c *gin.Context) bool {
+	if !config.PayloadCaptureEnabled {
+		return false
+	}
+
+	if utils.Contains(c.GetInt("id"), config.PayloadCaptureUserIds) {
+		return true
+	}
+
+	if utils.Contains(c.GetInt("token_id"), config.PayloadCaptureTokenIds) {
+		return true
+	}
+
+	return config.PayloadCaptureSampleRate > 0 && rand.Float64()*100 < config.PayloadCaptureSampleRate
+}
+
+// PayloadCapture 按用户、令牌或采样率记录请求和响应的原始内容，用于排查问题
+func PayloadCapture() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !shouldCapturePayload(c) {
+			c.Next()
+			return
+		}
+
+		limit := config.PayloadCaptureMaxBytes
+		recorder := requester.NewPayloadRecorder(limit)
+		writer := &payloadCaptureWriter{ResponseWriter: c.Writer, limit: limit}
+		requestId := c.GetString(logger.RequestIdKey)
+
+		c.Writer = writer
+		c.Set("payload_recorder", recorder)
+		c.Set("payload_request_id", requestId)
+
+		c.Next()
+
+		var requestBody []byte
+		if body, ok := utils.GetGinValue[[]byte](c, config.GinRequestBodyKey); ok {
+			requestBody = body
+			if len(requestBody) > limit {
+				requestBody = requestBody[:limit]
+			}
+		}
+		upstreamURL, upstreamRequest, upstreamResponse := recorder.Get()
+
+		capture := &model.PayloadCapture{
+			RequestId:        requestId,
+			UserId:           c.GetInt("id"),
+			TokenId:          c.GetInt("token_id"),
+			ChannelId:        c.GetInt("channel_id"),
+			ModelName:        c.GetString("original_model"),
+			Path:             c.Request.URL.Path,
+			StatusCode:       writer.Status(),
+			UpstreamURL:      upstreamURL,
+			RequestBody:      bytes.Clone(requestBody),
+			UpstreamRequest:  upstreamRequest,
+			UpstreamResponse: upstreamResponse,
+			ClientResponse:   bytes.Clone(writer.body.Bytes()),
+		}
+
+		go model.RecordPayloadCapture(capture)
+	}
+}
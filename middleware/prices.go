@@ -1,12 +1,16 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"one-api/common/config"
+
+	"github.com/gin-gonic/gin"
+)
 
 func PricesAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		typeParam := c.Query("type")
 		if typeParam == "old" {
-			AdminAuth()(c)
+			PermissionAuth(config.PermissionPricesRead)(c)
 		} else {
 			c.Next()
 		}
@@ -7,6 +7,7 @@ import (
 	"one-api/common/config"
 	"one-api/common/redis"
 	"one-api/common/utils"
+	"one-api/metrics"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -67,6 +68,7 @@ func redisRateLimiter(c *gin.Context, maxRequestNum int, duration int64, mark st
 		// See: https://stackoverflow.com/questions/50970900/why-is-time-since-returning-negative-durations-on-windows
 		if int64(nowTime.Sub(oldTime).Seconds()) < duration {
 			rdb.Expire(ctx, key, config.RateLimitKeyExpirationDuration)
+			metrics.RecordRateLimitRejection(mark, "")
 			c.Status(http.StatusTooManyRequests)
 			c.Abort()
 			return
@@ -81,6 +83,7 @@ func redisRateLimiter(c *gin.Context, maxRequestNum int, duration int64, mark st
 func memoryRateLimiter(c *gin.Context, maxRequestNum int, duration int64, mark string) {
 	key := mark + c.ClientIP()
 	if !inMemoryRateLimiter.Request(key, maxRequestNum, duration) {
+		metrics.RecordRateLimitRejection(mark, "")
 		c.Status(http.StatusTooManyRequests)
 		c.Abort()
 		return
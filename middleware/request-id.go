@@ -3,10 +3,12 @@ package middleware
 import (
 	"context"
 	"one-api/common/logger"
+	"one-api/common/tracing"
 	"one-api/common/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func RequestId() func(c *gin.Context) {
@@ -14,9 +16,27 @@ func RequestId() func(c *gin.Context) {
 		id := utils.GetTimeString() + utils.GetRandomString(8)
 		c.Set(logger.RequestIdKey, id)
 		c.Set("requestStartTime", time.Now())
-		ctx := context.WithValue(c.Request.Context(), logger.RequestIdKey, id)
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		ctx, span := tracing.StartRequest(c, c.Request.Method+" "+route,
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.String("http.route", route),
+			attribute.String("request_id", id),
+		)
+		defer span.End()
+
+		ctx = context.WithValue(ctx, logger.RequestIdKey, id)
 		c.Request = c.Request.WithContext(ctx)
 		c.Header(logger.RequestIdKey, id)
 		c.Next()
+
+		span.SetAttributes(
+			attribute.Int("user_id", c.GetInt("id")),
+			attribute.Int("token_id", c.GetInt("token_id")),
+		)
+		tracing.SetStatus(span, c.Writer.Status(), "")
 	}
 }
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"one-api/common/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScimAuth 使用配置的 Bearer Token 验证身份源的 SCIM 请求，未配置时接口不可用
+func ScimAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := utils.GetOrDefault("scim.token", "")
+		if token == "" {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		reqToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(reqToken), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"schemas": []string{"urn:ietf:params:scim:api:messages:2.0:Error"},
+				"status":  "401",
+				"detail":  "invalid bearer token",
+			})
+			return
+		}
+		c.Next()
+	}
+}
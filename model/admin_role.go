@@ -0,0 +1,179 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"one-api/common/cache"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/utils"
+	"sync"
+
+	"gorm.io/datatypes"
+)
+
+// AdminRole 管理角色，由一组权限组成，分配给用户后用户只具备角色中的权限
+type AdminRole struct {
+	Id          int                         `json:"id"`
+	Name        string                      `json:"name" gorm:"type:varchar(50);uniqueIndex"`
+	Description string                      `json:"description" gorm:"type:varchar(255);default:''"`
+	Permissions datatypes.JSONSlice[string] `json:"permissions" gorm:"type:json"`
+	CreatedAt   int64                       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   int64                       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (r *AdminRole) Validate() error {
+	if r.Name == "" {
+		return errors.New("角色名称不能为空")
+	}
+	for _, permission := range r.Permissions {
+		if !utils.Contains(permission, config.AllPermissions) {
+			return fmt.Errorf("未知的权限: %s", permission)
+		}
+	}
+	return nil
+}
+
+func GetAllAdminRoles() ([]*AdminRole, error) {
+	var roles []*AdminRole
+	err := DB.Order("id").Find(&roles).Error
+	return roles, err
+}
+
+func GetAdminRole(id int) (*AdminRole, error) {
+	var role AdminRole
+	err := DB.First(&role, id).Error
+	return &role, err
+}
+
+func (r *AdminRole) Insert() error {
+	err := DB.Create(r).Error
+	if err == nil {
+		AdminRolesInstance.Load()
+	}
+	return err
+}
+
+func (r *AdminRole) Update() error {
+	err := DB.Select("name", "description", "permissions").Updates(r).Error
+	if err == nil {
+		AdminRolesInstance.Load()
+	}
+	return err
+}
+
+// DeleteAdminRole 仍有用户使用的角色不能删除，避免这些用户恢复为不受限的管理员
+func DeleteAdminRole(id int) error {
+	var count int64
+	if err := DB.Model(&User{}).Where("admin_role_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("仍有 %d 个用户使用该角色", count)
+	}
+
+	err := DB.Delete(&AdminRole{}, id).Error
+	if err == nil {
+		AdminRolesInstance.Load()
+	}
+	return err
+}
+
+// SetUserAdminRole 分配角色，roleId 为 0 时取消分配
+func SetUserAdminRole(userId, roleId int) error {
+	if roleId > 0 {
+		if _, err := GetAdminRole(roleId); err != nil {
+			return errors.New("角色不存在")
+		}
+	}
+	if err := DB.Model(&User{}).Where("id = ?", userId).Update("admin_role_id", roleId).Error; err != nil {
+		return err
+	}
+	cache.DeleteCache(fmt.Sprintf(UserAdminRoleCacheKey, userId))
+	return nil
+}
+
+type AdminRoles struct {
+	sync.RWMutex
+	permissions map[int]map[string]bool
+}
+
+var AdminRolesInstance = &AdminRoles{}
+
+func (r *AdminRoles) Load() {
+	roles, err := GetAllAdminRoles()
+	if err != nil {
+		logger.SysError("failed to load admin roles: " + err.Error())
+		return
+	}
+
+	permissions := make(map[int]map[string]bool, len(roles))
+	for _, role := range roles {
+		set := make(map[string]bool, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			set[permission] = true
+		}
+		permissions[role.Id] = set
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.permissions = permissions
+}
+
+func (r *AdminRoles) Has(roleId int, permission string) bool {
+	r.RLock()
+	defer r.RUnlock()
+	return r.permissions[roleId][permission]
+}
+
+func getUserAdminRoleId(userId int) (int, error) {
+	var user User
+	err := DB.Select("admin_role_id").Where("id = ?", userId).First(&user).Error
+	return user.AdminRoleId, err
+}
+
+// HasPermission 超级管理员拥有全部权限；分配了角色的用户只具备角色中的权限；
+// 未分配角色的管理员具备除超级管理员专属权限外的全部权限
+func HasPermission(userId, role int, permission string) bool {
+	if role >= config.RoleRootUser {
+		return true
+	}
+
+	roleId, err := CacheGetUserAdminRoleId(userId)
+	if err != nil {
+		return false
+	}
+	return hasPermission(roleId, role, permission)
+}
+
+func hasPermission(roleId, role int, permission string) bool {
+	if role >= config.RoleRootUser {
+		return true
+	}
+	if roleId > 0 {
+		return AdminRolesInstance.Has(roleId, permission)
+	}
+	return role >= config.RoleAdminUser && !utils.Contains(permission, config.RootOnlyPermissions)
+}
+
+// GetUserPermissions 返回用户具备的全部权限，用于前端展示菜单
+func GetUserPermissions(userId, role int) ([]string, error) {
+	roleId, err := CacheGetUserAdminRoleId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRolePermissions(roleId, role), nil
+}
+
+// GetRolePermissions 返回指定用户角色在分配了 roleId 后具备的全部权限
+func GetRolePermissions(roleId, role int) []string {
+	permissions := make([]string, 0)
+	for _, permission := range config.AllPermissions {
+		if hasPermission(roleId, role, permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"testing"
+
+	"one-api/common/cache"
+	"one-api/common/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAdminRoleTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&User{}, &AdminRole{}, &Order{}))
+
+	originDB := DB
+	originCacheSeconds := TokenCacheSeconds
+	DB = db
+	TokenCacheSeconds = 60
+	cache.InitCacheManager()
+	t.Cleanup(func() {
+		DB = originDB
+		TokenCacheSeconds = originCacheSeconds
+		AdminRolesInstance = &AdminRoles{}
+	})
+}
+
+func TestHasPermission(t *testing.T) {
+	setupAdminRoleTestDB(t)
+
+	role := &AdminRole{Name: "viewer", Permissions: []string{config.PermissionLogsRead}}
+	require.NoError(t, role.Insert())
+	users := []*User{
+		{Username: "root", Role: config.RoleRootUser, AccessToken: "root", AffCode: "root"},
+		{Username: "admin", Role: config.RoleAdminUser, AccessToken: "admin", AffCode: "admin"},
+		{Username: "viewer", Role: config.RoleAdminUser, AdminRoleId: role.Id, AccessToken: "viewer", AffCode: "viewer"},
+		{Username: "common", Role: config.RoleCommonUser, AccessToken: "common", AffCode: "common"},
+	}
+	for _, user := range users {
+		require.NoError(t, DB.Create(user).Error)
+	}
+
+	tests := []struct {
+		name       string
+		user       *User
+		permission string
+		want       bool
+	}{
+		{"root has root only permission", users[0], config.PermissionOptionsWrite, true},
+		{"admin without role", users[1], config.PermissionPaymentsRefund, true},
+		{"admin without role lacks root only permission", users[1], config.PermissionOptionsWrite, false},
+		{"role permission", users[2], config.PermissionLogsRead, true},
+		{"permission outside role", users[2], config.PermissionPaymentsRefund, false},
+		{"common user", users[3], config.PermissionLogsRead, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HasPermission(tt.user.Id, tt.user.Role, tt.permission))
+		})
+	}
+}
+
+func TestHasPermissionCachesAdminRole(t *testing.T) {
+	setupAdminRoleTestDB(t)
+
+	role := &AdminRole{Name: "viewer", Permissions: []string{config.PermissionLogsRead}}
+	require.NoError(t, role.Insert())
+	user := &User{Username: "admin", Role: config.RoleAdminUser, AccessToken: "admin", AffCode: "admin"}
+	require.NoError(t, DB.Create(user).Error)
+
+	assert.True(t, HasPermission(user.Id, user.Role, config.PermissionChannelsWrite))
+
+	// 绕过 SetUserAdminRole 直接修改时仍使用缓存中的角色
+	require.NoError(t, DB.Model(user).Update("admin_role_id", role.Id).Error)
+	assert.True(t, HasPermission(user.Id, user.Role, config.PermissionChannelsWrite))
+
+	require.NoError(t, SetUserAdminRole(user.Id, role.Id))
+	assert.False(t, HasPermission(user.Id, user.Role, config.PermissionChannelsWrite))
+	assert.True(t, HasPermission(user.Id, user.Role, config.PermissionLogsRead))
+
+	require.NoError(t, SetUserAdminRole(user.Id, 0))
+	assert.True(t, HasPermission(user.Id, user.Role, config.PermissionChannelsWrite))
+}
+
+func TestRefundOrder(t *testing.T) {
+	setupAdminRoleTestDB(t)
+
+	user := &User{Username: "user", Quota: 1000, AccessToken: "user", AffCode: "user"}
+	require.NoError(t, DB.Create(user).Error)
+	orders := map[OrderStatus]*Order{}
+	for _, status := range []OrderStatus{OrderStatusSuccess, OrderStatusPending, OrderStatusRefunded} {
+		order := &Order{UserId: user.Id, TradeNo: string(status), Quota: 300, Status: status}
+		require.NoError(t, order.Insert())
+		orders[status] = order
+	}
+
+	tests := []struct {
+		name      string
+		order     *Order
+		wantErr   bool
+		wantQuota int
+	}{
+		{"success order", orders[OrderStatusSuccess], false, 700},
+		{"refund twice", orders[OrderStatusSuccess], true, 700},
+		{"pending order", orders[OrderStatusPending], true, 700},
+		{"refunded order", orders[OrderStatusRefunded], true, 700},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := RefundOrder(tt.order.ID)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, OrderStatusRefunded, order.Status)
+			}
+
+			quota, err := GetUserQuota(user.Id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantQuota, quota)
+		})
+	}
+}
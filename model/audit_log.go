@@ -0,0 +1,219 @@
+package model
+
+import (
+	"encoding/json"
+	"one-api/common/logger"
+	"one-api/common/utils"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	AuditActionExport = "export"
+	AuditActionRefund = "refund"
+
+	AuditEntityChannel    = "channel"
+	AuditEntityChannelKey = "channel_key"
+	AuditEntityPrice      = "price"
+	AuditEntityOption     = "option"
+	AuditEntityUserQuota  = "user_quota"
+	AuditEntityAdminRole  = "admin_role"
+	AuditEntityUserRole   = "user_role"
+	AuditEntityUserGroup  = "user_group"
+	AuditEntityPayment    = "payment"
+	AuditEntityOrder      = "order"
+
+	auditMaskPlaceholder = "******"
+)
+
+// 各实体中需要脱敏的字段，值为 JSON 对象字符串时逐个脱敏其中的值
+var auditSensitiveFields = map[string][]string{
+	AuditEntityChannel: {"key", "proxy"},
+	AuditEntityPayment: {"config"},
+}
+
+// 所有实体通用的敏感字段
+var auditCommonSensitiveFields = []string{"password", "access_token", "secret", "api_key"}
+
+// 以这些词结尾的配置项视为密钥，例如 GitHubClientSecret、SMTPToken
+var auditSensitiveOptionRegexp = regexp.MustCompile(`(Secret|Token|Password|Key)$`)
+
+// AuditLog 管理操作审计日志，Before/After 只保存发生变化的字段
+type AuditLog struct {
+	Id         int                                `json:"id"`
+	UserId     int                                `json:"user_id" gorm:"index"`
+	Username   string                             `json:"username" gorm:"type:varchar(64);default:''"`
+	Ip         string                             `json:"ip" gorm:"type:varchar(128);default:''"`
+	Action     string                             `json:"action" gorm:"type:varchar(16)"`
+	EntityType string                             `json:"entity_type" gorm:"type:varchar(32);index"`
+	EntityId   string                             `json:"entity_id" gorm:"type:varchar(191);index"`
+	Before     datatypes.JSONType[map[string]any] `json:"before" gorm:"type:json"`
+	After      datatypes.JSONType[map[string]any] `json:"after" gorm:"type:json"`
+	CreatedAt  int64                              `json:"created_at" gorm:"bigint;index"`
+}
+
+var allowedAuditLogOrderFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+}
+
+type AuditLogsListParams struct {
+	PaginationParams
+	UserId         int    `form:"user_id"`
+	Username       string `form:"username"`
+	Ip             string `form:"ip"`
+	EntityType     string `form:"entity_type"`
+	EntityId       string `form:"entity_id"`
+	Action         string `form:"action"`
+	StartTimestamp int64  `form:"start_timestamp"`
+	EndTimestamp   int64  `form:"end_timestamp"`
+}
+
+func GetAuditLogsList(params *AuditLogsListParams) (*DataResult[AuditLog], error) {
+	var logs []*AuditLog
+	db := DB
+	if params.UserId != 0 {
+		db = db.Where("user_id = ?", params.UserId)
+	}
+	if params.Username != "" {
+		db = db.Where("username = ?", params.Username)
+	}
+	if params.Ip != "" {
+		db = db.Where("ip = ?", params.Ip)
+	}
+	if params.EntityType != "" {
+		db = db.Where("entity_type = ?", params.EntityType)
+	}
+	if params.EntityId != "" {
+		db = db.Where("entity_id = ?", params.EntityId)
+	}
+	if params.Action != "" {
+		db = db.Where("action = ?", params.Action)
+	}
+	if params.StartTimestamp != 0 {
+		db = db.Where("created_at >= ?", params.StartTimestamp)
+	}
+	if params.EndTimestamp != 0 {
+		db = db.Where("created_at <= ?", params.EndTimestamp)
+	}
+
+	return PaginateAndOrder(db, &params.PaginationParams, &logs, allowedAuditLogOrderFields)
+}
+
+// RecordAuditLog 记录一次变更，before/after 可以是任意可序列化为 JSON 对象的值，
+// 新增时 before 为 nil，删除时 after 为 nil；更新前后没有差异时不记录。
+// 先比较原始值再脱敏，密钥被修改时也能记录下来
+func RecordAuditLog(log *AuditLog, before, after any) {
+	beforeMap := toAuditMap(before)
+	afterMap := toAuditMap(after)
+	if log.Action == AuditActionUpdate {
+		beforeMap, afterMap = diffAuditMap(beforeMap, afterMap)
+		if len(beforeMap) == 0 && len(afterMap) == 0 {
+			return
+		}
+	}
+
+	log.Before = datatypes.NewJSONType(maskAuditMap(log.EntityType, log.EntityId, beforeMap))
+	log.After = datatypes.NewJSONType(maskAuditMap(log.EntityType, log.EntityId, afterMap))
+	log.CreatedAt = utils.GetTimestamp()
+	if err := DB.Create(log).Error; err != nil {
+		logger.SysError("failed to record audit log: " + err.Error())
+	}
+}
+
+func toAuditMap(value any) map[string]any {
+	result := make(map[string]any)
+	if value == nil || reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil() {
+		return result
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return result
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		// 非对象类型的值统一放在 value 字段中
+		var raw any
+		if json.Unmarshal(data, &raw) == nil {
+			result["value"] = raw
+		}
+	}
+	return result
+}
+
+func diffAuditMap(before, after map[string]any) (map[string]any, map[string]any) {
+	beforeDiff := make(map[string]any)
+	afterDiff := make(map[string]any)
+
+	for key, value := range before {
+		if newValue, ok := after[key]; !ok || !reflect.DeepEqual(value, newValue) {
+			beforeDiff[key] = value
+		}
+	}
+	for key, value := range after {
+		if oldValue, ok := before[key]; !ok || !reflect.DeepEqual(oldValue, value) {
+			afterDiff[key] = value
+		}
+	}
+
+	return beforeDiff, afterDiff
+}
+
+func isAuditSensitiveField(entityType, entityId, field string) bool {
+	if entityType == AuditEntityOption {
+		return field == "value" && auditSensitiveOptionRegexp.MatchString(entityId)
+	}
+	return utils.Contains(field, auditSensitiveFields[entityType]) || utils.Contains(field, auditCommonSensitiveFields)
+}
+
+func maskAuditMap(entityType, entityId string, data map[string]any) map[string]any {
+	for field, value := range data {
+		if isAuditSensitiveField(entityType, entityId, field) {
+			data[field] = maskAuditValue(value)
+		}
+	}
+	return data
+}
+
+// maskAuditValue 保留首尾少量字符，便于区分不同的密钥
+func maskAuditValue(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return v
+		}
+		trimmed := strings.TrimSpace(v)
+		if strings.HasPrefix(trimmed, "{") {
+			var obj map[string]any
+			if json.Unmarshal([]byte(trimmed), &obj) == nil {
+				return maskAuditValue(obj)
+			}
+		}
+		if len(v) <= 12 {
+			return auditMaskPlaceholder
+		}
+		return v[:3] + auditMaskPlaceholder + v[len(v)-3:]
+	case map[string]any:
+		masked := make(map[string]any, len(v))
+		for key, item := range v {
+			masked[key] = maskAuditValue(item)
+		}
+		return masked
+	case []any:
+		masked := make([]any, 0, len(v))
+		for _, item := range v {
+			masked = append(masked, maskAuditValue(item))
+		}
+		return masked
+	default:
+		return auditMaskPlaceholder
+	}
+}
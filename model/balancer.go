@@ -93,6 +93,19 @@ func (cc *ChannelsChooser) IsInCooldown(channelId int, modelName string) bool {
 	return time.Now().Unix() < cooldownTime.(int64)
 }
 
+// CountCooldowns 统计仍在冷却中的渠道模型数量
+func (cc *ChannelsChooser) CountCooldowns() int {
+	count := 0
+	now := time.Now().Unix()
+	cc.Cooldowns.Range(func(_, value interface{}) bool {
+		if now < value.(int64) {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
 func (cc *ChannelsChooser) CleanupExpiredCooldowns() {
 	now := time.Now().Unix()
 	cc.Cooldowns.Range(func(key, value interface{}) bool {
@@ -18,6 +18,7 @@ var (
 	UsernameCacheKey            = "user_name:%d"
 	UserQuotaCacheKey           = "user_quota:%d"
 	UserEnabledCacheKey         = "user_enabled:%d"
+	UserAdminRoleCacheKey       = "user_admin_role:%d"
 	UserRealtimeQuotaKey        = "user_realtime_quota:%d"
 	UserRealtimeQuotaExpiration = 24 * time.Hour
 
@@ -120,6 +121,17 @@ func CacheIsUserEnabled(userId int) (bool, error) {
 	return enabled, err
 }
 
+// CacheGetUserAdminRoleId 未启用 Redis 时缓存在本地内存中，分配角色时清除
+func CacheGetUserAdminRoleId(id int) (int, error) {
+	return cache.GetOrSetCache(
+		fmt.Sprintf(UserAdminRoleCacheKey, id),
+		time.Duration(TokenCacheSeconds)*time.Second,
+		func() (int, error) {
+			return getUserAdminRoleId(id)
+		},
+		cache.CacheTimeout)
+}
+
 func CacheGetUsername(id int) (username string, err error) {
 	if !config.RedisEnabled {
 		return GetUsernameById(id), nil
@@ -4,6 +4,7 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"one-api/common/config"
+	"one-api/common/encryption"
 	"one-api/common/logger"
 	"one-api/common/utils"
 	"slices"
@@ -16,7 +17,7 @@ import (
 type Channel struct {
 	Id                 int     `json:"id"`
 	Type               int     `json:"type" form:"type" gorm:"default:0"`
-	Key                string  `json:"key" form:"key" gorm:"type:text"`
+	Key                string  `json:"key" form:"key" gorm:"type:text;serializer:encrypted"`
 	Status             int     `json:"status" form:"status" gorm:"default:1"`
 	Name               string  `json:"name" form:"name" gorm:"index"`
 	Weight             *uint   `json:"weight" gorm:"default:1"`
@@ -27,6 +28,11 @@ type Channel struct {
 	Other              string  `json:"other" form:"other"`
 	Balance            float64 `json:"balance"` // in USD
 	BalanceUpdatedTime int64   `json:"balance_updated_time" gorm:"bigint"`
+	// BalanceDisable 定时更新余额时，余额不高于 BalanceThreshold 自动禁用渠道
+	BalanceDisable   bool    `json:"balance_disable" gorm:"default:false"`
+	BalanceThreshold float64 `json:"balance_threshold" gorm:"default:0"`
+	// DisabledReason 自动禁用的原因，用于判断能否自动恢复
+	DisabledReason     string  `json:"disabled_reason" gorm:"type:varchar(32);default:''"`
 	Models             string  `json:"models" form:"models"`
 	Group              string  `json:"group" form:"group" gorm:"type:varchar(32);default:'default'"`
 	Tag                string  `json:"tag" form:"tag" gorm:"type:varchar(32);default:''"`
@@ -42,8 +48,20 @@ type Channel struct {
 	SystemPrompt       string  `json:"system_prompt" form:"system_prompt" gorm:"type:text"`
 	EnableSearch       bool    `json:"enable_search" gorm:"default:false"`
 	CompatibleResponse bool    `json:"compatible_response" gorm:"default:false"`
+	// KeyStrategy 密钥池的选择策略，为空时轮询
+	KeyStrategy string `json:"key_strategy" form:"key_strategy" gorm:"type:varchar(32);default:''"`
+	// KeyId 本次请求使用的密钥池中的密钥
+	KeyId int `json:"-" gorm:"-"`
+	// ModelSync 定时同步上游模型列表
+	ModelSync bool `json:"model_sync" gorm:"default:false"`
+	// ModelSyncPatterns 同步时自动添加的新模型规则，逗号分隔，支持 * 通配符，为空时只通知不添加
+	ModelSyncPatterns string `json:"model_sync_patterns" gorm:"type:varchar(1024);default:''"`
+	// ModelSyncRemove 同步时自动移除上游已下线的模型
+	ModelSyncRemove bool `json:"model_sync_remove" gorm:"default:false"`
 
 	DisabledStream *datatypes.JSONSlice[string] `json:"disabled_stream,omitempty" gorm:"type:json"`
+	// RealtimeModels 原生支持 realtime 的模型，用于名称无法识别的部署
+	RealtimeModels *datatypes.JSONSlice[string] `json:"realtime_models,omitempty" gorm:"type:json"`
 
 	Plugin    *datatypes.JSONType[PluginType] `json:"plugin" form:"plugin" gorm:"type:json"`
 	DeletedAt gorm.DeletedAt                  `json:"-" gorm:"index"`
@@ -57,6 +75,15 @@ func (c *Channel) AllowStream(modelName string) bool {
 	return !slices.Contains(*c.DisabledStream, modelName)
 }
 
+// IsRealtimeModel 模型是否在渠道配置的原生 realtime 模型中
+func (c *Channel) IsRealtimeModel(modelName string) bool {
+	if c.RealtimeModels == nil {
+		return false
+	}
+
+	return slices.Contains(*c.RealtimeModels, modelName)
+}
+
 type PluginType map[string]map[string]interface{}
 
 var allowedChannelOrderFields = map[string]bool{
@@ -117,8 +144,15 @@ func GetChannelsList(params *SearchChannelsParams) (*DataResult[Channel], error)
 	}
 
 	if params.Key != "" {
-		db = db.Where(quotePostgresField("key")+" = ?", params.Key)
-		tagDB = tagDB.Where(quotePostgresField("key")+" = ?", params.Key)
+		if encryption.Enabled() {
+			// 加密后的密文每次都不同，只能解密后比较
+			ids := getChannelIdsByKey(params.Key)
+			db = db.Where("id IN ?", ids)
+			tagDB = tagDB.Where("id IN ?", ids)
+		} else {
+			db = db.Where(quotePostgresField("key")+" = ?", params.Key)
+			tagDB = tagDB.Where(quotePostgresField("key")+" = ?", params.Key)
+		}
 	}
 
 	if params.TestModel != "" {
@@ -149,6 +183,27 @@ func GetAllChannels() ([]*Channel, error) {
 	return channels, err
 }
 
+// GetChannelsState 获取所有渠道的状态和余额，用于监控
+func GetChannelsState() (channels []*Channel, err error) {
+	err = DB.Select("id, type, status, balance").Find(&channels).Error
+	return channels, err
+}
+
+func getChannelIdsByKey(key string) []int {
+	var channels []*Channel
+	if err := DB.Select("id", "key").Find(&channels).Error; err != nil {
+		logger.SysError("failed to search channels by key: " + err.Error())
+	}
+
+	ids := make([]int, 0)
+	for _, channel := range channels {
+		if channel.Key == key {
+			ids = append(ids, channel.Id)
+		}
+	}
+	return ids
+}
+
 func GetChannelById(id int) (*Channel, error) {
 	channel := Channel{Id: id}
 	err := DB.First(&channel, "id = ?", id).Error
@@ -156,6 +211,18 @@ func GetChannelById(id int) (*Channel, error) {
 	return &channel, err
 }
 
+func GetChannelsByIds(ids []int) ([]*Channel, error) {
+	var channels []*Channel
+	err := DB.Where("id IN ?", ids).Find(&channels).Error
+	return channels, err
+}
+
+func GetDisabledChannels() ([]*Channel, error) {
+	var channels []*Channel
+	err := DB.Where("status = ? or status = ?", config.ChannelStatusAutoDisabled, config.ChannelStatusManuallyDisabled).Find(&channels).Error
+	return channels, err
+}
+
 func GetChannelsByTag(tag string) ([]*Channel, error) {
 	var channels []*Channel
 	err := DB.Where("tag = ?", tag).Find(&channels).Error
@@ -322,7 +389,20 @@ func (channel *Channel) UpdateBalance(balance float64) {
 	}).Error
 	if err != nil {
 		logger.SysError("failed to update balance: " + err.Error())
+		return
 	}
+	channel.Balance = balance
+	RecordChannelBalance(channel.Id, balance)
+}
+
+// UpdateModels 只更新渠道的模型列表，不重新加载渠道缓存
+func (channel *Channel) UpdateModels(models string) error {
+	err := DB.Model(channel).Update("models", models).Error
+	if err != nil {
+		return err
+	}
+	channel.Models = models
+	return nil
 }
 
 func (channel *Channel) Delete() error {
@@ -346,9 +426,20 @@ func (channel *Channel) StatusToStr() string {
 	return "禁用"
 }
 
+// 因余额不足被自动禁用
+const ChannelDisabledReasonLowBalance = "low_balance"
+
 func UpdateChannelStatusById(id int, status int) {
+	UpdateChannelStatusWithReason(id, status, "")
+}
+
+// UpdateChannelStatusWithReason 更新渠道状态并记录自动禁用的原因
+func UpdateChannelStatusWithReason(id int, status int, reason string) {
 	tx := DB.Begin()
-	err := tx.Model(&Channel{}).Where("id = ?", id).Update("status", status).Error
+	err := tx.Model(&Channel{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          status,
+		"disabled_reason": reason,
+	}).Error
 	if err != nil {
 		logger.SysError("failed to update channel status: " + err.Error())
 		tx.Rollback()
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"one-api/common/logger"
+	"one-api/common/utils"
+)
+
+// ChannelBalanceHistory 渠道余额记录，每次查询余额成功后写入，用于预估余额可用时间
+type ChannelBalanceHistory struct {
+	Id        int     `json:"id"`
+	ChannelId int     `json:"channel_id" gorm:"index:idx_channel_balance_time"`
+	Balance   float64 `json:"balance"`
+	CreatedAt int64   `json:"created_at" gorm:"bigint;index:idx_channel_balance_time"`
+}
+
+// ChannelBalanceForecast 根据余额记录估算的消耗速度
+type ChannelBalanceForecast struct {
+	Balance    float64 `json:"balance"`
+	DailyCost  float64 `json:"daily_cost"`
+	RunwayDays float64 `json:"runway_days"`
+	DepletedAt int64   `json:"depleted_at"`
+}
+
+// 预估时使用的记录天数
+const channelBalanceForecastDays = 7
+
+func RecordChannelBalance(channelId int, balance float64) {
+	history := &ChannelBalanceHistory{
+		ChannelId: channelId,
+		Balance:   balance,
+		CreatedAt: utils.GetTimestamp(),
+	}
+	if err := DB.Create(history).Error; err != nil {
+		logger.SysError("failed to record channel balance: " + err.Error())
+	}
+}
+
+func GetChannelBalanceHistories(channelId int, startTimestamp int64) ([]*ChannelBalanceHistory, error) {
+	var histories []*ChannelBalanceHistory
+	err := DB.Where("channel_id = ? AND created_at >= ?", channelId, startTimestamp).Order("created_at asc").Find(&histories).Error
+
+	return histories, err
+}
+
+// GetChannelBalanceForecast 统计最近几天余额的下降量计算日均消耗，余额上升视为充值，不计入消耗
+// 记录不足或没有消耗时返回的 RunwayDays 为 -1
+func GetChannelBalanceForecast(channelId int) (*ChannelBalanceForecast, error) {
+	histories, err := GetChannelBalanceHistories(channelId, utils.GetTimestamp()-channelBalanceForecastDays*24*60*60)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := &ChannelBalanceForecast{RunwayDays: -1}
+	if len(histories) == 0 {
+		return forecast, nil
+	}
+
+	last := histories[len(histories)-1]
+	forecast.Balance = last.Balance
+
+	var cost float64
+	for i := 1; i < len(histories); i++ {
+		if diff := histories[i-1].Balance - histories[i].Balance; diff > 0 {
+			cost += diff
+		}
+	}
+
+	elapsed := last.CreatedAt - histories[0].CreatedAt
+	if elapsed <= 0 || cost <= 0 {
+		return forecast, nil
+	}
+
+	forecast.DailyCost = cost / float64(elapsed) * 24 * 60 * 60
+	forecast.RunwayDays = max(forecast.Balance, 0) / forecast.DailyCost
+	forecast.DepletedAt = last.CreatedAt + int64(forecast.RunwayDays*24*60*60)
+
+	return forecast, nil
+}
+
+// DeleteExpiredChannelBalanceHistories 删除过期的余额记录
+func DeleteExpiredChannelBalanceHistories(retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+
+	targetTimestamp := utils.GetTimestamp() - int64(retentionDays)*24*60*60
+	result := DB.Where("created_at < ?", targetTimestamp).Delete(&ChannelBalanceHistory{})
+
+	return result.RowsAffected, result.Error
+}
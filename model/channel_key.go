@@ -0,0 +1,387 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/utils"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 多密钥渠道的密钥选择策略
+const (
+	ChannelKeyStrategyRoundRobin   = "round_robin"
+	ChannelKeyStrategyRandom       = "random"
+	ChannelKeyStrategyLeastErrored = "least_errored"
+)
+
+var ChannelKeyStrategies = []string{
+	ChannelKeyStrategyRoundRobin,
+	ChannelKeyStrategyRandom,
+	ChannelKeyStrategyLeastErrored,
+}
+
+// ChannelKey 渠道的密钥池，渠道存在启用的密钥时使用密钥池代替 Channel.Key，
+// 状态沿用渠道状态
+type ChannelKey struct {
+	Id             int    `json:"id"`
+	ChannelId      int    `json:"channel_id" gorm:"index"`
+	Key            string `json:"key" gorm:"type:text;serializer:encrypted"`
+	Status         int    `json:"status" gorm:"default:1"`
+	RequestCount   int64  `json:"request_count" gorm:"bigint;default:0"`
+	ErrorCount     int64  `json:"error_count" gorm:"bigint;default:0"`
+	LastUsedTime   int64  `json:"last_used_time" gorm:"bigint;default:0"`
+	LastErrorTime  int64  `json:"last_error_time" gorm:"bigint;default:0"`
+	DisabledReason string `json:"disabled_reason" gorm:"type:varchar(255);default:''"`
+	CreatedTime    int64  `json:"created_time" gorm:"bigint"`
+}
+
+var allowedChannelKeyOrderFields = map[string]bool{
+	"id":              true,
+	"status":          true,
+	"request_count":   true,
+	"error_count":     true,
+	"last_used_time":  true,
+	"last_error_time": true,
+}
+
+type ChannelKeysListParams struct {
+	PaginationParams
+	ChannelId int `form:"-"`
+	Status    int `form:"status"`
+}
+
+func GetChannelKeysList(params *ChannelKeysListParams) (*DataResult[ChannelKey], error) {
+	ChannelKeyPoolInstance.FlushStats()
+
+	var keys []*ChannelKey
+	db := DB.Where("channel_id = ?", params.ChannelId)
+	if params.Status != 0 {
+		db = db.Where("status = ?", params.Status)
+	}
+
+	result, err := PaginateAndOrder(db, &params.PaginationParams, &keys, allowedChannelKeyOrderFields)
+	if err != nil {
+		return nil, err
+	}
+	// 列表中只展示脱敏后的密钥，完整密钥通过导出接口获取
+	for _, key := range keys {
+		key.Key = maskChannelKey(key.Key)
+	}
+	return result, nil
+}
+
+func maskChannelKey(key string) string {
+	runes := []rune(key)
+	if len(runes) <= 12 {
+		return "******"
+	}
+	return string(runes[:4]) + "******" + string(runes[len(runes)-4:])
+}
+
+func GetChannelKeys(channelId int) ([]*ChannelKey, error) {
+	var keys []*ChannelKey
+	err := DB.Where("channel_id = ?", channelId).Order("id").Find(&keys).Error
+	return keys, err
+}
+
+func GetChannelKey(channelId, keyId int) (*ChannelKey, error) {
+	var key ChannelKey
+	err := DB.Where("channel_id = ? AND id = ?", channelId, keyId).First(&key).Error
+	return &key, err
+}
+
+// ImportChannelKeys 批量导入密钥，每行一个，跳过空行和已存在的密钥，返回导入的数量
+func ImportChannelKeys(channelId int, keys []string) (int, error) {
+	existing, err := GetChannelKeys(channelId)
+	if err != nil {
+		return 0, err
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, key := range existing {
+		exists[key.Key] = true
+	}
+
+	now := utils.GetTimestamp()
+	newKeys := make([]*ChannelKey, 0, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || exists[key] {
+			continue
+		}
+		exists[key] = true
+		newKeys = append(newKeys, &ChannelKey{
+			ChannelId:   channelId,
+			Key:         key,
+			Status:      config.ChannelStatusEnabled,
+			CreatedTime: now,
+		})
+	}
+	if len(newKeys) == 0 {
+		return 0, nil
+	}
+
+	if err := DB.CreateInBatches(newKeys, 100).Error; err != nil {
+		return 0, err
+	}
+	ChannelKeyPoolInstance.Load()
+	return len(newKeys), nil
+}
+
+// UpdateChannelKeyStatus 修改密钥状态，启用时清除禁用原因
+func UpdateChannelKeyStatus(channelId, keyId, status int, reason string) error {
+	if status == config.ChannelStatusEnabled {
+		reason = ""
+	}
+	result := DB.Model(&ChannelKey{}).Where("channel_id = ? AND id = ?", channelId, keyId).Updates(map[string]any{
+		"status":          status,
+		"disabled_reason": truncateDisabledReason(reason),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("密钥不存在")
+	}
+
+	ChannelKeyPoolInstance.Load()
+	return nil
+}
+
+func DeleteChannelKeys(channelId int, ids []int) (int64, error) {
+	result := DB.Where("channel_id = ? AND id IN ?", channelId, ids).Delete(&ChannelKey{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	ChannelKeyPoolInstance.Load()
+	return result.RowsAffected, nil
+}
+
+func truncateDisabledReason(reason string) string {
+	if runes := []rune(reason); len(runes) > 255 {
+		return string(runes[:255])
+	}
+	return reason
+}
+
+type poolKey struct {
+	id  int
+	key string
+	// 以下为尚未写入数据库的统计
+	requests      atomic.Int64
+	errors        atomic.Int64
+	lastUsedTime  atomic.Int64
+	lastErrorTime atomic.Int64
+	// 触发频率限制后的冷却截止时间
+	cooldownUntil atomic.Int64
+}
+
+type ChannelKeyPool struct {
+	sync.RWMutex
+	// 渠道 ID 对应的启用中的密钥
+	keys    map[int][]*poolKey
+	cursors sync.Map
+}
+
+var ChannelKeyPoolInstance = &ChannelKeyPool{}
+
+// InitChannelKeyPool 加载密钥池并定时写入密钥使用统计
+func InitChannelKeyPool() {
+	ChannelKeyPoolInstance.Load()
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			ChannelKeyPoolInstance.FlushStats()
+		}
+	}()
+}
+
+func (p *ChannelKeyPool) Load() {
+	// 重新加载前先写入统计，避免丢失
+	p.FlushStats()
+
+	var keys []*ChannelKey
+	if err := DB.Where("status = ?", config.ChannelStatusEnabled).Order("id").Find(&keys).Error; err != nil {
+		logger.SysError("failed to load channel keys: " + err.Error())
+		return
+	}
+
+	// 重新加载时保留密钥的冷却状态
+	cooldowns := make(map[int]int64)
+	p.RLock()
+	for _, channelKeys := range p.keys {
+		for _, key := range channelKeys {
+			if until := key.cooldownUntil.Load(); until > 0 {
+				cooldowns[key.id] = until
+			}
+		}
+	}
+	p.RUnlock()
+
+	newKeys := make(map[int][]*poolKey)
+	for _, key := range keys {
+		item := &poolKey{id: key.Id, key: key.Key}
+		item.lastErrorTime.Store(key.LastErrorTime)
+		item.cooldownUntil.Store(cooldowns[key.Id])
+		newKeys[key.ChannelId] = append(newKeys[key.ChannelId], item)
+	}
+
+	p.Lock()
+	p.keys = newKeys
+	p.Unlock()
+}
+
+// Pick 按渠道的策略选择一个密钥，返回带有该密钥的渠道副本；渠道没有可用密钥时返回原渠道
+// 冷却中的密钥不参与选择，全部冷却时不做排除
+func (p *ChannelKeyPool) Pick(channel *Channel) *Channel {
+	p.RLock()
+	keys := p.keys[channel.Id]
+	p.RUnlock()
+	if len(keys) == 0 {
+		return channel
+	}
+	keys = availableKeys(keys)
+
+	var key *poolKey
+	switch channel.KeyStrategy {
+	case ChannelKeyStrategyRandom:
+		key = keys[rand.Intn(len(keys))]
+	case ChannelKeyStrategyLeastErrored:
+		key = p.leastErrored(channel.Id, keys)
+	default:
+		key = keys[p.next(channel.Id, len(keys))]
+	}
+
+	key.requests.Add(1)
+	key.lastUsedTime.Store(utils.GetTimestamp())
+
+	picked := *channel
+	picked.Key = key.key
+	picked.KeyId = key.id
+	return &picked
+}
+
+func availableKeys(keys []*poolKey) []*poolKey {
+	now := time.Now().Unix()
+	available := make([]*poolKey, 0, len(keys))
+	for _, key := range keys {
+		if key.cooldownUntil.Load() <= now {
+			available = append(available, key)
+		}
+	}
+	if len(available) == 0 {
+		return keys
+	}
+	return available
+}
+
+func (p *ChannelKeyPool) next(channelId, size int) int {
+	cursor, _ := p.cursors.LoadOrStore(channelId, &atomic.Uint64{})
+	return int(cursor.(*atomic.Uint64).Add(1)-1) % size
+}
+
+// leastErrored 优先选择从未出错或最早出错的密钥，相同时轮询
+func (p *ChannelKeyPool) leastErrored(channelId int, keys []*poolKey) *poolKey {
+	start := p.next(channelId, len(keys))
+	best := keys[start]
+	for i := 1; i < len(keys); i++ {
+		key := keys[(start+i)%len(keys)]
+		if key.lastErrorTime.Load() < best.lastErrorTime.Load() {
+			best = key
+		}
+	}
+	return best
+}
+
+func (p *ChannelKeyPool) find(channelId, keyId int) *poolKey {
+	p.RLock()
+	defer p.RUnlock()
+	for _, key := range p.keys[channelId] {
+		if key.id == keyId {
+			return key
+		}
+	}
+	return nil
+}
+
+// RecordError 记录密钥出错，用于统计和 least_errored 策略
+func (p *ChannelKeyPool) RecordError(channelId, keyId int) {
+	if key := p.find(channelId, keyId); key != nil {
+		key.errors.Add(1)
+		key.lastErrorTime.Store(utils.GetTimestamp())
+	}
+}
+
+// Cooldown 密钥触发频率限制时暂停使用，冷却时间与渠道的重试冷却时间一致
+func (p *ChannelKeyPool) Cooldown(channelId, keyId int) bool {
+	if config.RetryCooldownSeconds == 0 {
+		return false
+	}
+	key := p.find(channelId, keyId)
+	if key == nil {
+		return false
+	}
+
+	key.cooldownUntil.Store(time.Now().Unix() + int64(config.RetryCooldownSeconds))
+	return true
+}
+
+// Disable 自动禁用密钥，返回渠道是否还有可用的密钥
+func (p *ChannelKeyPool) Disable(channelId, keyId int, reason string) bool {
+	// 并发请求可能同时触发禁用，只更新仍为启用状态的密钥
+	result := DB.Model(&ChannelKey{}).Where("id = ? AND status = ?", keyId, config.ChannelStatusEnabled).Updates(map[string]any{
+		"status":          config.ChannelStatusAutoDisabled,
+		"disabled_reason": truncateDisabledReason(reason),
+	})
+	if result.Error != nil {
+		logger.SysError(fmt.Sprintf("failed to disable channel key #%d: %s", keyId, result.Error.Error()))
+	} else if result.RowsAffected > 0 {
+		p.Load()
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+	return len(p.keys[channelId]) > 0
+}
+
+// FlushStats 将内存中的使用统计累加到数据库
+func (p *ChannelKeyPool) FlushStats() {
+	p.RLock()
+	var pending []*poolKey
+	for _, keys := range p.keys {
+		pending = append(pending, keys...)
+	}
+	p.RUnlock()
+
+	for _, key := range pending {
+		requests := key.requests.Swap(0)
+		errorCount := key.errors.Swap(0)
+		if requests == 0 && errorCount == 0 {
+			continue
+		}
+
+		updates := map[string]any{
+			"request_count":  gorm.Expr("request_count + ?", requests),
+			"error_count":    gorm.Expr("error_count + ?", errorCount),
+			"last_used_time": key.lastUsedTime.Load(),
+		}
+		if errorCount > 0 {
+			updates["last_error_time"] = key.lastErrorTime.Load()
+		}
+		if err := DB.Model(&ChannelKey{}).Where("id = ?", key.id).Updates(updates).Error; err != nil {
+			logger.SysError("failed to update channel key stats: " + err.Error())
+			// 写入失败的计数放回，下次一起写入
+			key.requests.Add(requests)
+			key.errors.Add(errorCount)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"testing"
+
+	"one-api/common/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupChannelKeyTestDB(t *testing.T, keys ...string) *ChannelKeyPool {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存数据库每个连接独立，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&ChannelKey{}))
+
+	originDB := DB
+	DB = db
+	t.Cleanup(func() {
+		DB = originDB
+	})
+
+	for _, key := range keys {
+		require.NoError(t, db.Create(&ChannelKey{ChannelId: 1, Key: key, Status: config.ChannelStatusEnabled}).Error)
+	}
+
+	pool := &ChannelKeyPool{}
+	pool.Load()
+	return pool
+}
+
+func pickKeys(pool *ChannelKeyPool, channel *Channel, times int) []string {
+	picked := make([]string, 0, times)
+	for i := 0; i < times; i++ {
+		picked = append(picked, pool.Pick(channel).Key)
+	}
+	return picked
+}
+
+func TestChannelKeyPoolPick(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		prepare  func(pool *ChannelKeyPool)
+		want     []string
+	}{
+		{
+			name:     "round robin",
+			strategy: ChannelKeyStrategyRoundRobin,
+			want:     []string{"k1", "k2", "k3", "k1", "k2", "k3"},
+		},
+		{
+			name:     "default strategy is round robin",
+			strategy: "",
+			want:     []string{"k1", "k2", "k3", "k1"},
+		},
+		{
+			name:     "least errored prefers keys without errors",
+			strategy: ChannelKeyStrategyLeastErrored,
+			prepare: func(pool *ChannelKeyPool) {
+				pool.find(1, 1).lastErrorTime.Store(100)
+				pool.find(1, 3).lastErrorTime.Store(200)
+			},
+			want: []string{"k2", "k2", "k2"},
+		},
+		{
+			name:     "least errored picks the earliest error",
+			strategy: ChannelKeyStrategyLeastErrored,
+			prepare: func(pool *ChannelKeyPool) {
+				pool.find(1, 1).lastErrorTime.Store(300)
+				pool.find(1, 2).lastErrorTime.Store(100)
+				pool.find(1, 3).lastErrorTime.Store(200)
+			},
+			want: []string{"k2", "k2"},
+		},
+		{
+			name:     "cooling down key is skipped",
+			strategy: ChannelKeyStrategyRoundRobin,
+			prepare: func(pool *ChannelKeyPool) {
+				assert.True(t, pool.Cooldown(1, 2))
+			},
+			want: []string{"k1", "k3", "k1", "k3"},
+		},
+		{
+			name:     "all keys cooling down are still used",
+			strategy: ChannelKeyStrategyRoundRobin,
+			prepare: func(pool *ChannelKeyPool) {
+				for id := 1; id <= 3; id++ {
+					pool.Cooldown(1, id)
+				}
+			},
+			want: []string{"k1", "k2", "k3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := setupChannelKeyTestDB(t, "k1", "k2", "k3")
+			if tt.prepare != nil {
+				tt.prepare(pool)
+			}
+			channel := &Channel{Id: 1, Key: "channel-key", KeyStrategy: tt.strategy}
+			assert.Equal(t, tt.want, pickKeys(pool, channel, len(tt.want)))
+			assert.Equal(t, "channel-key", channel.Key, "pick must not modify the cached channel")
+		})
+	}
+}
+
+func TestChannelKeyPoolPickRandom(t *testing.T) {
+	pool := setupChannelKeyTestDB(t, "k1", "k2", "k3")
+	channel := &Channel{Id: 1, KeyStrategy: ChannelKeyStrategyRandom}
+
+	for i := 0; i < 30; i++ {
+		picked := pool.Pick(channel)
+		assert.Contains(t, []string{"k1", "k2", "k3"}, picked.Key)
+		assert.NotZero(t, picked.KeyId)
+	}
+}
+
+func TestChannelKeyPoolPickWithoutKeys(t *testing.T) {
+	pool := setupChannelKeyTestDB(t)
+	channel := &Channel{Id: 1, Key: "channel-key"}
+
+	picked := pool.Pick(channel)
+	assert.Same(t, channel, picked)
+	assert.Zero(t, picked.KeyId)
+}
+
+func TestChannelKeyPoolDisable(t *testing.T) {
+	pool := setupChannelKeyTestDB(t, "k1", "k2")
+	channel := &Channel{Id: 1}
+
+	assert.True(t, pool.Disable(1, 1, "invalid key"))
+	assert.Equal(t, []string{"k2", "k2"}, pickKeys(pool, channel, 2))
+
+	key, err := GetChannelKey(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, config.ChannelStatusAutoDisabled, key.Status)
+	assert.Equal(t, "invalid key", key.DisabledReason)
+
+	// 重复禁用不会覆盖原因
+	assert.True(t, pool.Disable(1, 1, "again"))
+	key, err = GetChannelKey(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "invalid key", key.DisabledReason)
+
+	assert.False(t, pool.Disable(1, 2, "invalid key"))
+	picked := pool.Pick(channel)
+	assert.Zero(t, picked.KeyId)
+}
+
+func TestChannelKeyPoolCooldownSurvivesReload(t *testing.T) {
+	pool := setupChannelKeyTestDB(t, "k1", "k2")
+	assert.True(t, pool.Cooldown(1, 1))
+
+	pool.Load()
+	channel := &Channel{Id: 1}
+	assert.Equal(t, []string{"k2", "k2"}, pickKeys(pool, channel, 2))
+}
+
+func TestChannelKeyPoolCooldownDisabled(t *testing.T) {
+	pool := setupChannelKeyTestDB(t, "k1", "k2")
+	origin := config.RetryCooldownSeconds
+	config.RetryCooldownSeconds = 0
+	t.Cleanup(func() {
+		config.RetryCooldownSeconds = origin
+	})
+
+	assert.False(t, pool.Cooldown(1, 1))
+	assert.False(t, pool.Cooldown(1, 99))
+}
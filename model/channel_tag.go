@@ -157,7 +157,13 @@ func UpdateChannelsTag(tag string, channel *Channel) error {
 			OnlyChat:           channel.OnlyChat,
 			Plugin:             channel.Plugin,
 			PreCost:            channel.PreCost,
+			BalanceDisable:     channel.BalanceDisable,
+			BalanceThreshold:   channel.BalanceThreshold,
+			ModelSync:          channel.ModelSync,
+			ModelSyncPatterns:  channel.ModelSyncPatterns,
+			ModelSyncRemove:    channel.ModelSyncRemove,
 			DisabledStream:     channel.DisabledStream,
+			RealtimeModels:     channel.RealtimeModels,
 			CompatibleResponse: channel.CompatibleResponse,
 		}).Error
 
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-api/common/encryption"
+	"one-api/common/logger"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// EncryptedSerializer 字段写入数据库前加密，读取后解密，字段类型为 string，
+// 使用 gorm:"serializer:encrypted" 声明
+type EncryptedSerializer struct{}
+
+// 实现该接口的模型可以按行决定是否加密，例如只加密密钥类的配置项
+type encryptCondition interface {
+	shouldEncrypt() bool
+}
+
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	plaintext, err := encryption.Decrypt(value)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt field %s: %w", field.Name, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue any) (any, error) {
+	value, _ := fieldValue.(string)
+	if dst = reflect.Indirect(dst); dst.IsValid() && dst.CanInterface() {
+		if condition, ok := dst.Interface().(encryptCondition); ok && !condition.shouldEncrypt() {
+			return value, nil
+		}
+	}
+	return encryption.Encrypt(value)
+}
+
+// EncryptExistingData 加密存量数据，已加密但不是使用当前主密钥加密的数据会重新加密，
+// 轮换主密钥后再次执行即可
+func EncryptExistingData() error {
+	if !encryption.Enabled() {
+		return errors.New("encryption is not enabled")
+	}
+
+	var channels []*Channel
+	if err := DB.Unscoped().Select("id", "key").Find(&channels).Error; err != nil {
+		return err
+	}
+	channelCount := 0
+	for _, channel := range channels {
+		// 这里读取到的是解密后的值，需要查询原始值判断是否需要重新加密
+		raw, err := rawColumn(&Channel{}, "id", channel.Id, "key")
+		if err != nil {
+			return err
+		}
+		if !encryption.NeedsRotation(raw) {
+			continue
+		}
+		if err := DB.Unscoped().Model(channel).Select("key").Updates(channel).Error; err != nil {
+			return err
+		}
+		channelCount++
+	}
+
+	var channelKeys []*ChannelKey
+	if err := DB.Select("id", "key").Find(&channelKeys).Error; err != nil {
+		return err
+	}
+	channelKeyCount := 0
+	for _, channelKey := range channelKeys {
+		raw, err := rawColumn(&ChannelKey{}, "id", channelKey.Id, "key")
+		if err != nil {
+			return err
+		}
+		if !encryption.NeedsRotation(raw) {
+			continue
+		}
+		if err := DB.Model(channelKey).Select("key").Updates(channelKey).Error; err != nil {
+			return err
+		}
+		channelKeyCount++
+	}
+
+	var payments []*Payment
+	if err := DB.Unscoped().Select("id", "config").Find(&payments).Error; err != nil {
+		return err
+	}
+	paymentCount := 0
+	for _, payment := range payments {
+		raw, err := rawColumn(&Payment{}, "id", payment.ID, "config")
+		if err != nil {
+			return err
+		}
+		if !encryption.NeedsRotation(raw) {
+			continue
+		}
+		if err := DB.Unscoped().Model(payment).Select("config").Updates(payment).Error; err != nil {
+			return err
+		}
+		paymentCount++
+	}
+
+	options, err := AllOption()
+	if err != nil {
+		return err
+	}
+	optionCount := 0
+	for _, option := range options {
+		if !option.shouldEncrypt() {
+			continue
+		}
+		raw, err := rawColumn(&Option{}, "key", option.Key, "value")
+		if err != nil {
+			return err
+		}
+		if !encryption.NeedsRotation(raw) {
+			continue
+		}
+		if err := DB.Save(option).Error; err != nil {
+			return err
+		}
+		optionCount++
+	}
+
+	logger.SysLog(fmt.Sprintf("encrypted data: %d channels, %d channel keys, %d payments, %d options", channelCount, channelKeyCount, paymentCount, optionCount))
+	return nil
+}
+
+// rawColumn 读取未经解密的原始值
+func rawColumn(model any, pk string, id any, column string) (string, error) {
+	var values []string
+	err := DB.Model(model).Unscoped().Where(map[string]any{pk: id}).Pluck(column, &values).Error
+	if err != nil || len(values) == 0 {
+		return "", err
+	}
+	return values[0], nil
+}
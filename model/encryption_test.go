@@ -0,0 +1,210 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"one-api/common/encryption"
+	"one-api/common/logger"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	testOldMasterKey = "old-master-key-0123456789abcdefghij"
+	testNewMasterKey = "new-master-key-0123456789abcdefghij"
+)
+
+func setupEncryptionTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&Channel{}, &ChannelKey{}, &Payment{}, &Option{}))
+
+	originDB := DB
+	DB = db
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	t.Cleanup(func() {
+		DB = originDB
+	})
+}
+
+// initTestEncryption 第一个为主密钥，其余为历史密钥；加密开启后无法在进程内关闭，
+// 加密对读写透明，不影响同包的其他测试
+func initTestEncryption(t *testing.T, keys ...string) {
+	t.Helper()
+	viper.Set("encryption.enable", true)
+	viper.Set("encryption.kms", "local")
+	viper.Set("encryption.local.master_key", keys[0])
+	viper.Set("encryption.local.previous_keys", keys[1:])
+	encryption.InitEncryption()
+	require.True(t, encryption.Enabled())
+}
+
+func encryptedKeyId(value string) string {
+	parts := strings.SplitN(value, ":", 4)
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[2]
+}
+
+type encryptedColumnCase struct {
+	name          string
+	create        func(t *testing.T) (model any, pk string, id any)
+	column        string
+	plaintext     string
+	load          func(t *testing.T, id any) string
+	wantEncrypted bool
+}
+
+func encryptedColumnCases() []encryptedColumnCase {
+	return []encryptedColumnCase{
+		{
+			name: "channel key",
+			create: func(t *testing.T) (any, string, any) {
+				channel := &Channel{Name: "test", Key: "sk-channel"}
+				require.NoError(t, DB.Create(channel).Error)
+				return &Channel{}, "id", channel.Id
+			},
+			column:    "key",
+			plaintext: "sk-channel",
+			load: func(t *testing.T, id any) string {
+				var channel Channel
+				require.NoError(t, DB.First(&channel, id).Error)
+				return channel.Key
+			},
+			wantEncrypted: true,
+		},
+		{
+			name: "channel pool key",
+			create: func(t *testing.T) (any, string, any) {
+				channelKey := &ChannelKey{ChannelId: 1, Key: "sk-pool"}
+				require.NoError(t, DB.Create(channelKey).Error)
+				return &ChannelKey{}, "id", channelKey.Id
+			},
+			column:    "key",
+			plaintext: "sk-pool",
+			load: func(t *testing.T, id any) string {
+				var channelKey ChannelKey
+				require.NoError(t, DB.First(&channelKey, id).Error)
+				return channelKey.Key
+			},
+			wantEncrypted: true,
+		},
+		{
+			name: "payment config",
+			create: func(t *testing.T) (any, string, any) {
+				payment := &Payment{UUID: "payment", Config: `{"secret":"abc"}`}
+				require.NoError(t, DB.Create(payment).Error)
+				return &Payment{}, "id", payment.ID
+			},
+			column:    "config",
+			plaintext: `{"secret":"abc"}`,
+			load: func(t *testing.T, id any) string {
+				var payment Payment
+				require.NoError(t, DB.First(&payment, id).Error)
+				return payment.Config
+			},
+			wantEncrypted: true,
+		},
+		{
+			name: "secret option",
+			create: func(t *testing.T) (any, string, any) {
+				require.NoError(t, DB.Create(&Option{Key: "GitHubClientSecret", Value: "github-secret"}).Error)
+				return &Option{}, "key", "GitHubClientSecret"
+			},
+			column:    "value",
+			plaintext: "github-secret",
+			load: func(t *testing.T, id any) string {
+				var option Option
+				require.NoError(t, DB.Where("key = ?", id).First(&option).Error)
+				return option.Value
+			},
+			wantEncrypted: true,
+		},
+		{
+			name: "plain option",
+			create: func(t *testing.T) (any, string, any) {
+				require.NoError(t, DB.Create(&Option{Key: "SystemName", Value: "One Hub"}).Error)
+				return &Option{}, "key", "SystemName"
+			},
+			column:    "value",
+			plaintext: "One Hub",
+			load: func(t *testing.T, id any) string {
+				var option Option
+				require.NoError(t, DB.Where("key = ?", id).First(&option).Error)
+				return option.Value
+			},
+		},
+	}
+}
+
+func TestEncryptedSerializer(t *testing.T) {
+	for _, tt := range encryptedColumnCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			setupEncryptionTestDB(t)
+			initTestEncryption(t, testOldMasterKey)
+
+			model, pk, id := tt.create(t)
+			raw, err := rawColumn(model, pk, id, tt.column)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEncrypted, encryption.IsEncrypted(raw))
+			if tt.wantEncrypted {
+				assert.NotContains(t, raw, tt.plaintext)
+			}
+
+			assert.Equal(t, tt.plaintext, tt.load(t, id))
+		})
+	}
+}
+
+func TestEncryptExistingDataRotation(t *testing.T) {
+	for _, tt := range encryptedColumnCases() {
+		for _, legacy := range []bool{false, true} {
+			name := tt.name
+			if legacy {
+				name += " written before encryption"
+			}
+			t.Run(name, func(t *testing.T) {
+				setupEncryptionTestDB(t)
+				initTestEncryption(t, testOldMasterKey)
+				model, pk, id := tt.create(t)
+				if legacy {
+					require.NoError(t, DB.Model(model).Where(map[string]any{pk: id}).UpdateColumn(tt.column, gorm.Expr("?", tt.plaintext)).Error)
+				}
+				oldRaw, err := rawColumn(model, pk, id, tt.column)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantEncrypted && !legacy, encryption.IsEncrypted(oldRaw))
+
+				// 轮换主密钥，旧主密钥作为历史密钥保留
+				initTestEncryption(t, testNewMasterKey, testOldMasterKey)
+				assert.Equal(t, tt.plaintext, tt.load(t, id))
+				require.NoError(t, EncryptExistingData())
+
+				newRaw, err := rawColumn(model, pk, id, tt.column)
+				require.NoError(t, err)
+				if !tt.wantEncrypted {
+					assert.Equal(t, tt.plaintext, newRaw)
+					return
+				}
+				assert.True(t, encryption.IsEncrypted(newRaw))
+				assert.False(t, encryption.NeedsRotation(newRaw))
+				assert.NotEqual(t, encryptedKeyId(oldRaw), encryptedKeyId(newRaw))
+
+				// 移除旧主密钥后仍能读取
+				initTestEncryption(t, testNewMasterKey)
+				assert.Equal(t, tt.plaintext, tt.load(t, id))
+			})
+		}
+	}
+}
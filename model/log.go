@@ -2,10 +2,13 @@ package model
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"one-api/common/config"
 	"one-api/common/logger"
+	"one-api/common/logsink"
 	"one-api/common/utils"
+	"strconv"
 
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
@@ -121,10 +124,35 @@ func RecordConsumeLog(
 		log.Metadata = datatypes.NewJSONType(metadata)
 	}
 
-	err := DB.Create(log).Error
+	if config.LogConsumeSQLDisabled {
+		accumulateStatistics(log)
+	} else {
+		err := DB.Create(log).Error
+		if err != nil {
+			logger.LogError(ctx, "failed to record log: "+err.Error())
+		}
+	}
+
+	pushLogSink(log)
+}
+
+// pushLogSink 将日志发送到配置的外部存储
+func pushLogSink(log *Log) {
+	if !logsink.Enabled() {
+		return
+	}
+
+	data, err := json.Marshal(log)
 	if err != nil {
-		logger.LogError(ctx, "failed to record log: "+err.Error())
+		logger.SysError("failed to marshal log: " + err.Error())
+		return
 	}
+
+	logsink.Push(&logsink.Entry{
+		CreatedAt: log.CreatedAt,
+		Key:       strconv.Itoa(log.UserId),
+		Data:      data,
+	})
 }
 
 type LogsListParams struct {
@@ -209,6 +237,18 @@ func GetUserLogsList(userId int, params *LogsListParams) (*DataResult[Log], erro
 	return PaginateAndOrder[Log](tx, &params.PaginationParams, &logs, allowedLogsOrderFields)
 }
 
+func GetLogById(id int) (*Log, error) {
+	log := &Log{}
+	err := DB.Preload("Channel", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, name")
+	}).First(log, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
 func SearchAllLogs(keyword string) (logs []*Log, err error) {
 	err = DB.Where("type = ? or content LIKE ?", keyword, keyword+"%").Order("id desc").Limit(config.MaxRecentItems).Find(&logs).Error
 	return logs, err
@@ -0,0 +1,303 @@
+package model
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/storage"
+	"one-api/common/utils"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	LogArchiveKindConsume = "consume"
+	LogArchiveKindSystem  = "system"
+
+	logRetentionBatchSize = 1000
+	// 分批删除之间的间隔，避免长时间锁表
+	logRetentionBatchInterval = 100 * time.Millisecond
+)
+
+// LogArchive 日志归档记录，每个文件保存一天的日志
+type LogArchive struct {
+	Id        int    `json:"id"`
+	Kind      string `json:"kind" gorm:"type:varchar(32);index"`
+	StartAt   int64  `json:"start_at" gorm:"bigint;index"`
+	EndAt     int64  `json:"end_at" gorm:"bigint;index"`
+	Count     int64  `json:"count"`
+	Drive     string `json:"drive" gorm:"type:varchar(32)"`
+	FileKey   string `json:"file_key" gorm:"type:varchar(255)"`
+	CreatedAt int64  `json:"created_at" gorm:"bigint"`
+}
+
+var allowedLogArchiveOrderFields = map[string]bool{
+	"id":       true,
+	"start_at": true,
+}
+
+type LogArchivesListParams struct {
+	PaginationParams
+	Kind string `form:"kind"`
+}
+
+func GetLogArchivesList(params *LogArchivesListParams) (*DataResult[LogArchive], error) {
+	var archives []*LogArchive
+	db := DB
+	if params.Kind != "" {
+		db = db.Where("kind = ?", params.Kind)
+	}
+
+	return PaginateAndOrder[LogArchive](db, &params.PaginationParams, &archives, allowedLogArchiveOrderFields)
+}
+
+func startOfDay(timestamp int64) int64 {
+	t := time.Unix(timestamp, 0)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Unix()
+}
+
+// CleanExpiredLogs 按保留天数清理日志，开启归档时先归档再删除
+func CleanExpiredLogs() {
+	cleanExpiredLogs(LogArchiveKindConsume, []int{LogTypeConsume}, config.LogRetentionConsumeDays)
+	cleanExpiredLogs(LogArchiveKindSystem, []int{LogTypeSystem, LogTypeManage}, config.LogRetentionSystemDays)
+}
+
+func cleanExpiredLogs(kind string, logTypes []int, retentionDays int) {
+	if retentionDays <= 0 {
+		return
+	}
+
+	if config.LogArchiveEnabled && !storage.ArchiveEnabled() {
+		logger.SysError("log archive is enabled but no archive storage is configured, skip cleaning " + kind + " logs")
+		return
+	}
+
+	before := startOfDay(utils.GetTimestamp()) - int64(retentionDays)*24*60*60
+
+	var from int64
+	for {
+		var oldest Log
+		err := DB.Select("created_at").Where("type IN ? AND created_at >= ? AND created_at < ?", logTypes, from, before).Order("created_at").First(&oldest).Error
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.SysError("failed to find expired logs: " + err.Error())
+			}
+			return
+		}
+
+		// 按天处理，控制每个归档文件和每次删除的大小
+		dayStart := startOfDay(oldest.CreatedAt)
+		dayEnd := min(dayStart+24*60*60, before)
+		from = dayEnd
+
+		// 已有归档的日期中的日志是从归档恢复的，保留到管理员手动删除，避免重复归档
+		archived, err := hasLogArchive(kind, dayStart)
+		if err != nil {
+			logger.SysError("failed to find log archives: " + err.Error())
+			return
+		}
+		if archived {
+			continue
+		}
+
+		if config.LogArchiveEnabled {
+			if err := archiveLogs(kind, logTypes, dayStart, dayEnd); err != nil {
+				logger.SysError(fmt.Sprintf("failed to archive %s logs: %s", kind, err.Error()))
+				return
+			}
+		}
+
+		count, err := deleteLogsInBatches(logTypes, dayStart, dayEnd)
+		if err != nil {
+			logger.SysError(fmt.Sprintf("failed to delete %s logs: %s", kind, err.Error()))
+			return
+		}
+		logger.SysLog(fmt.Sprintf("cleaned %d %s logs of %s", count, kind, time.Unix(dayStart, 0).Format("2006-01-02")))
+	}
+}
+
+func hasLogArchive(kind string, dayStart int64) (bool, error) {
+	var count int64
+	err := DB.Model(&LogArchive{}).Where("kind = ? AND start_at = ?", kind, dayStart).Count(&count).Error
+	return count > 0, err
+}
+
+// archiveLogs 将时间范围内的日志压缩为 JSONL 保存到归档存储，压缩内容先写入临时文件，避免占用内存
+func archiveLogs(kind string, logTypes []int, startAt, endAt int64) error {
+	file, err := os.CreateTemp("", "log-archive-*.jsonl.gz")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	writer := gzip.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+
+	var count int64
+	lastId := 0
+	for {
+		var logs []*Log
+		err := DB.Where("type IN ? AND created_at >= ? AND created_at < ? AND id > ?", logTypes, startAt, endAt, lastId).
+			Order("id").Limit(logRetentionBatchSize).Find(&logs).Error
+		if err != nil {
+			return err
+		}
+
+		for _, log := range logs {
+			if err := encoder.Encode(log); err != nil {
+				return err
+			}
+			lastId = log.Id
+		}
+		count += int64(len(logs))
+
+		if len(logs) < logRetentionBatchSize {
+			break
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	date := time.Unix(startAt, 0).Format("2006-01-02")
+	fileKey := fmt.Sprintf("logs/%s/%s-%d.jsonl.gz", kind, date, time.Now().Unix())
+	drive, err := storage.Archive(fileKey, file)
+	if err != nil {
+		return err
+	}
+
+	return DB.Create(&LogArchive{
+		Kind:      kind,
+		StartAt:   startAt,
+		EndAt:     endAt,
+		Count:     count,
+		Drive:     drive,
+		FileKey:   fileKey,
+		CreatedAt: utils.GetTimestamp(),
+	}).Error
+}
+
+func deleteLogsInBatches(logTypes []int, startAt, endAt int64) (int64, error) {
+	var total int64
+	for {
+		var ids []int
+		err := DB.Model(&Log{}).Where("type IN ? AND created_at >= ? AND created_at < ?", logTypes, startAt, endAt).
+			Limit(logRetentionBatchSize).Pluck("id", &ids).Error
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		result := DB.Where("id IN ?", ids).Delete(&Log{})
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+
+		time.Sleep(logRetentionBatchInterval)
+	}
+}
+
+// RestoreLogArchives 将时间范围内的归档日志恢复到数据库，已存在的日志会被跳过
+// 恢复的日志所在日期已有归档，定时清理时会跳过，不会重复归档
+func RestoreLogArchives(startAt, endAt int64) (int64, error) {
+	if startAt >= endAt {
+		return 0, errors.New("结束时间必须大于开始时间")
+	}
+
+	var archives []*LogArchive
+	err := DB.Where("start_at < ? AND end_at > ?", endAt, startAt).Order("start_at").Find(&archives).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(archives) == 0 {
+		return 0, errors.New("该时间范围内没有归档")
+	}
+
+	var total int64
+	for _, archive := range archives {
+		count, err := restoreLogArchive(archive, startAt, endAt)
+		total += count
+		if err != nil {
+			return total, fmt.Errorf("恢复归档 %s 失败: %w", archive.FileKey, err)
+		}
+	}
+
+	return total, nil
+}
+
+func restoreLogArchive(archive *LogArchive, startAt, endAt int64) (int64, error) {
+	file, err := storage.ReadArchive(archive.Drive, archive.FileKey)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	var total int64
+	logs := make([]*Log, 0, logRetentionBatchSize)
+	insert := func() error {
+		if len(logs) == 0 {
+			return nil
+		}
+		result := DB.Omit("Channel").Clauses(clause.OnConflict{DoNothing: true}).Create(&logs)
+		if result.Error != nil {
+			return result.Error
+		}
+		total += result.RowsAffected
+		logs = logs[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		log := &Log{}
+		if err := json.Unmarshal(scanner.Bytes(), log); err != nil {
+			return total, err
+		}
+		if log.CreatedAt < startAt || log.CreatedAt >= endAt {
+			continue
+		}
+		log.Channel = nil
+
+		logs = append(logs, log)
+		if len(logs) >= logRetentionBatchSize {
+			if err := insert(); err != nil {
+				return total, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return total, err
+	}
+
+	return total, insert()
+}
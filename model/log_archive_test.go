@@ -0,0 +1,115 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/storage"
+	"one-api/common/storage/drives"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestLogArchiveRoundTrip(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&Channel{}, &Log{}, &LogArchive{}))
+
+	originDB := DB
+	originEnabled := config.LogArchiveEnabled
+	DB = db
+	config.LogArchiveEnabled = true
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	t.Cleanup(func() {
+		DB = originDB
+		config.LogArchiveEnabled = originEnabled
+	})
+	storage.AddArchiveDrive(drives.NewLocalArchive(t.TempDir()))
+
+	today := startOfDay(time.Now().Unix())
+	day := int64(24 * 60 * 60)
+	logs := []*Log{
+		{Type: LogTypeConsume, Content: "expired 1", ModelName: "gpt-4o", Quota: 10, CreatedAt: today - 5*day + 10},
+		{Type: LogTypeConsume, Content: "expired 2", ModelName: "gpt-4o", Quota: 20, CreatedAt: today - 5*day + 20},
+		{Type: LogTypeConsume, Content: "expired 3", ModelName: "gpt-4o-mini", Quota: 30, CreatedAt: today - 4*day + 10},
+		{Type: LogTypeSystem, Content: "other kind", CreatedAt: today - 5*day + 30},
+		{Type: LogTypeConsume, Content: "kept", CreatedAt: today - day + 10},
+	}
+	require.NoError(t, db.Create(&logs).Error)
+
+	cleanExpiredLogs(LogArchiveKindConsume, []int{LogTypeConsume}, 3)
+
+	var archives []*LogArchive
+	require.NoError(t, db.Order("start_at").Find(&archives).Error)
+	require.Len(t, archives, 2)
+	assert.Equal(t, today-5*day, archives[0].StartAt)
+	assert.EqualValues(t, 2, archives[0].Count)
+	assert.Equal(t, today-4*day, archives[1].StartAt)
+	assert.EqualValues(t, 1, archives[1].Count)
+
+	var remaining []*Log
+	require.NoError(t, db.Order("id").Find(&remaining).Error)
+	require.Len(t, remaining, 2)
+	assert.Equal(t, "other kind", remaining[0].Content)
+	assert.Equal(t, "kept", remaining[1].Content)
+
+	tests := []struct {
+		name    string
+		startAt int64
+		endAt   int64
+		want    []string
+		wantErr bool
+	}{
+		{name: "part of a day", startAt: today - 5*day + 15, endAt: today - 5*day + 25, want: []string{"expired 2"}},
+		{name: "existing rows are skipped", startAt: today - 6*day, endAt: today - 4*day, want: []string{"expired 1", "expired 2"}},
+		{name: "several archives", startAt: today - 6*day, endAt: today - 3*day, want: []string{"expired 1", "expired 2", "expired 3"}},
+		{name: "no archive", startAt: today - 10*day, endAt: today - 9*day, wantErr: true},
+		{name: "invalid range", startAt: today, endAt: today - day, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RestoreLogArchives(tt.startAt, tt.endAt)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var restored []*Log
+			require.NoError(t, db.Where("type = ? AND created_at < ?", LogTypeConsume, today-3*day).Order("id").Find(&restored).Error)
+			contents := make([]string, 0, len(restored))
+			for _, log := range restored {
+				contents = append(contents, log.Content)
+			}
+			assert.Equal(t, tt.want, contents)
+		})
+	}
+
+	// 恢复的日志保留原始数据
+	var restored Log
+	require.NoError(t, db.Where("content = ?", "expired 1").First(&restored).Error)
+	assert.Equal(t, logs[0].Id, restored.Id)
+	assert.Equal(t, logs[0].CreatedAt, restored.CreatedAt)
+	assert.Equal(t, logs[0].Quota, restored.Quota)
+	assert.Equal(t, logs[0].ModelName, restored.ModelName)
+
+	// 恢复的日志不会被再次归档和删除
+	cleanExpiredLogs(LogArchiveKindConsume, []int{LogTypeConsume}, 3)
+	var count int64
+	require.NoError(t, db.Model(&LogArchive{}).Count(&count).Error)
+	assert.EqualValues(t, 2, count)
+	require.NoError(t, db.Model(&Log{}).Where("type = ?", LogTypeConsume).Count(&count).Error)
+	assert.EqualValues(t, 4, count)
+}
@@ -26,7 +26,9 @@ func SetupDB() {
 		logger.FatalLog("failed to initialize database: " + err.Error())
 	}
 	ChannelGroup.Load()
+	VirtualModelsInstance.Load()
 	GlobalUserGroupRatio.Load()
+	AdminRolesInstance.Load()
 	config.RootUserEmail = GetRootUserEmail()
 	NewModelOwnedBys()
 
@@ -194,6 +196,46 @@ func InitDB() (err error) {
 			return err
 		}
 
+		err = db.AutoMigrate(&VirtualModel{})
+		if err != nil {
+			return err
+		}
+
+		err = db.AutoMigrate(&SafetyEvent{})
+		if err != nil {
+			return err
+		}
+
+		err = db.AutoMigrate(&PayloadCapture{})
+		if err != nil {
+			return err
+		}
+
+		err = db.AutoMigrate(&LogArchive{})
+		if err != nil {
+			return err
+		}
+
+		err = db.AutoMigrate(&AdminRole{})
+		if err != nil {
+			return err
+		}
+
+		err = db.AutoMigrate(&AuditLog{})
+		if err != nil {
+			return err
+		}
+
+		err = db.AutoMigrate(&ChannelKey{})
+		if err != nil {
+			return err
+		}
+
+		err = db.AutoMigrate(&ChannelBalanceHistory{})
+		if err != nil {
+			return err
+		}
+
 		if config.UserInvoiceMonth {
 			err = db.AutoMigrate(&StatisticsMonthGeneratedHistory{})
 			if err != nil {
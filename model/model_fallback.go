@@ -0,0 +1,126 @@
+package model
+
+import (
+	"encoding/json"
+	"one-api/common/logger"
+	"strings"
+	"sync"
+)
+
+// 触发模型回退的错误类型
+const (
+	FallbackOnRateLimit     = "429"
+	FallbackOnServerError   = "5xx"
+	FallbackOnContextLength = "context_length"
+)
+
+var defaultFallbackOn = []string{FallbackOnRateLimit, FallbackOnServerError, FallbackOnContextLength}
+
+// ModelFallbackStep 回退链中的一个步骤
+type ModelFallbackStep struct {
+	Model string `json:"model"`
+	// 触发该步骤的错误类型，为空时使用全部默认类型
+	On []string `json:"on,omitempty"`
+}
+
+func (s *ModelFallbackStep) Match(errorClass string) bool {
+	if errorClass == "" {
+		return false
+	}
+
+	on := s.On
+	if len(on) == 0 {
+		on = defaultFallbackOn
+	}
+
+	for _, class := range on {
+		if strings.EqualFold(strings.TrimSpace(class), errorClass) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ModelFallbackChains 原始模型 -> 回退步骤
+type ModelFallbackChains map[string][]ModelFallbackStep
+
+func (chains ModelFallbackChains) Get(modelName string) []ModelFallbackStep {
+	if chains == nil {
+		return nil
+	}
+
+	return chains[modelName]
+}
+
+type ModelFallback struct {
+	sync.RWMutex
+	chains ModelFallbackChains
+}
+
+var ModelFallbackInstance = &ModelFallback{
+	chains: make(ModelFallbackChains),
+}
+
+func ParseModelFallbackChains(value string) (ModelFallbackChains, error) {
+	chains := make(ModelFallbackChains)
+	if strings.TrimSpace(value) == "" {
+		return chains, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &chains); err != nil {
+		return nil, err
+	}
+
+	for modelName, steps := range chains {
+		validSteps := make([]ModelFallbackStep, 0, len(steps))
+		for _, step := range steps {
+			step.Model = strings.TrimSpace(step.Model)
+			if step.Model == "" || step.Model == modelName {
+				continue
+			}
+			validSteps = append(validSteps, step)
+		}
+		chains[modelName] = validSteps
+	}
+
+	return chains, nil
+}
+
+func (mf *ModelFallback) Load(value string) error {
+	chains, err := ParseModelFallbackChains(value)
+	if err != nil {
+		logger.SysError("failed to load model fallback chains: " + err.Error())
+		return err
+	}
+
+	mf.Lock()
+	defer mf.Unlock()
+	mf.chains = chains
+
+	return nil
+}
+
+func (mf *ModelFallback) String() string {
+	mf.RLock()
+	defer mf.RUnlock()
+
+	if len(mf.chains) == 0 {
+		return ""
+	}
+
+	jsonBytes, err := json.Marshal(mf.chains)
+	if err != nil {
+		logger.SysError("error marshalling model fallback chains: " + err.Error())
+		return ""
+	}
+
+	return string(jsonBytes)
+}
+
+func (mf *ModelFallback) GetChain(modelName string) []ModelFallbackStep {
+	mf.RLock()
+	defer mf.RUnlock()
+
+	return mf.chains.Get(modelName)
+}
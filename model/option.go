@@ -10,7 +10,13 @@ import (
 
 type Option struct {
 	Key   string `json:"key" gorm:"primaryKey"`
-	Value string `json:"value"`
+	Value string `json:"value" gorm:"serializer:encrypted"`
+}
+
+// shouldEncrypt 开启加密后，密钥类配置项加密保存
+func (o Option) shouldEncrypt() bool {
+	return strings.HasSuffix(o.Key, "Secret") || strings.HasSuffix(o.Key, "Token") ||
+		strings.HasSuffix(o.Key, "SecretKey") || o.Key == "CFWorkerImageKey"
 }
 
 func AllOption() ([]*Option, error) {
@@ -39,6 +45,25 @@ func InitOptionMap() {
 	config.GlobalOption.RegisterBool("AutomaticEnableChannelEnabled", &config.AutomaticEnableChannelEnabled)
 	config.GlobalOption.RegisterBool("ApproximateTokenEnabled", &config.ApproximateTokenEnabled)
 	config.GlobalOption.RegisterBool("LogConsumeEnabled", &config.LogConsumeEnabled)
+	config.GlobalOption.RegisterInt("LogRetentionConsumeDays", &config.LogRetentionConsumeDays)
+	config.GlobalOption.RegisterInt("LogRetentionSystemDays", &config.LogRetentionSystemDays)
+	config.GlobalOption.RegisterBool("LogArchiveEnabled", &config.LogArchiveEnabled)
+	config.GlobalOption.RegisterBool("PayloadCaptureEnabled", &config.PayloadCaptureEnabled)
+	config.GlobalOption.RegisterCustom("PayloadCaptureUserIds", func() string {
+		return joinIds(config.PayloadCaptureUserIds)
+	}, func(value string) error {
+		config.PayloadCaptureUserIds = splitIds(value)
+		return nil
+	}, "")
+	config.GlobalOption.RegisterCustom("PayloadCaptureTokenIds", func() string {
+		return joinIds(config.PayloadCaptureTokenIds)
+	}, func(value string) error {
+		config.PayloadCaptureTokenIds = splitIds(value)
+		return nil
+	}, "")
+	config.GlobalOption.RegisterFloat("PayloadCaptureSampleRate", &config.PayloadCaptureSampleRate)
+	config.GlobalOption.RegisterInt("PayloadCaptureMaxBytes", &config.PayloadCaptureMaxBytes)
+	config.GlobalOption.RegisterInt("PayloadCaptureRetentionDays", &config.PayloadCaptureRetentionDays)
 	config.GlobalOption.RegisterBool("DisplayInCurrencyEnabled", &config.DisplayInCurrencyEnabled)
 	config.GlobalOption.RegisterFloat("ChannelDisableThreshold", &config.ChannelDisableThreshold)
 	config.GlobalOption.RegisterBool("EmailDomainRestrictionEnabled", &config.EmailDomainRestrictionEnabled)
@@ -120,6 +145,12 @@ func InitOptionMap() {
 
 	config.GlobalOption.RegisterInt("RetryTimeOut", &config.RetryTimeOut)
 
+	config.GlobalOption.RegisterCustom("ModelFallback", func() string {
+		return ModelFallbackInstance.String()
+	}, func(value string) error {
+		return ModelFallbackInstance.Load(value)
+	}, "")
+
 	config.GlobalOption.RegisterBool("EnableSafe", &config.EnableSafe)
 	config.GlobalOption.RegisterString("SafeToolName", &config.SafeToolName)
 	config.GlobalOption.RegisterCustom("SafeKeyWords", func() string {
@@ -128,6 +159,27 @@ func InitOptionMap() {
 		config.SafeKeyWords = strings.Split(value, "\n")
 		return nil
 	}, "")
+	config.GlobalOption.RegisterCustom("SafeRegexRules", func() string {
+		return strings.Join(config.SafeRegexRules, "\n")
+	}, func(value string) error {
+		config.SafeRegexRules = strings.Split(value, "\n")
+		return nil
+	}, "")
+	config.GlobalOption.RegisterInt("SafeModerationChannelId", &config.SafeModerationChannelId)
+	config.GlobalOption.RegisterString("SafeModerationModel", &config.SafeModerationModel)
+	config.GlobalOption.RegisterInt("SafeLLMJudgeChannelId", &config.SafeLLMJudgeChannelId)
+	config.GlobalOption.RegisterString("SafeLLMJudgeModel", &config.SafeLLMJudgeModel)
+	config.GlobalOption.RegisterString("SafeLLMJudgePrompt", &config.SafeLLMJudgePrompt)
+	config.GlobalOption.RegisterCustom("PIIRedaction", func() string {
+		return PIIRedactionInstance.String()
+	}, func(value string) error {
+		return PIIRedactionInstance.Load(value)
+	}, "")
+	config.GlobalOption.RegisterCustom("SafetyGroupPolicy", func() string {
+		return SafetyPolicyInstance.String()
+	}, func(value string) error {
+		return SafetyPolicyInstance.Load(value)
+	}, "")
 
 	loadOptionsFromDatabase()
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
@@ -9,10 +10,11 @@ import (
 type OrderStatus string
 
 const (
-	OrderStatusPending OrderStatus = "pending"
-	OrderStatusSuccess OrderStatus = "success"
-	OrderStatusFailed  OrderStatus = "failed"
-	OrderStatusClosed  OrderStatus = "closed"
+	OrderStatusPending  OrderStatus = "pending"
+	OrderStatusSuccess  OrderStatus = "success"
+	OrderStatusFailed   OrderStatus = "failed"
+	OrderStatusClosed   OrderStatus = "closed"
+	OrderStatusRefunded OrderStatus = "refunded"
 )
 
 type Order struct {
@@ -52,6 +54,36 @@ func GetUserOrder(userId int, tradeNo string) (*Order, error) {
 	return &order, err
 }
 
+// RefundOrder 将支付成功的订单标记为已退款，并扣回到帐的额度
+// 只处理站内记录，款项需在支付平台后台退回
+func RefundOrder(id int) (*Order, error) {
+	var order Order
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&order, id).Error; err != nil {
+			return err
+		}
+		if order.Status != OrderStatusSuccess {
+			return errors.New("只能退款支付成功的订单")
+		}
+
+		result := tx.Model(&Order{}).Where("id = ? AND status = ?", id, OrderStatusSuccess).Update("status", OrderStatusRefunded)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("订单状态已变更，请刷新后重试")
+		}
+		order.Status = OrderStatusRefunded
+
+		return tx.Model(&User{}).Where("id = ?", order.UserId).Update("quota", gorm.Expr("quota - ?", order.Quota)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
 func (o *Order) Insert() error {
 	return DB.Create(o).Error
 }
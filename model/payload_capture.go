@@ -0,0 +1,134 @@
+package model
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"one-api/common/logger"
+	"one-api/common/utils"
+	"strconv"
+	"strings"
+)
+
+// PayloadCapture 请求内容记录，内容使用 gzip 压缩存储
+type PayloadCapture struct {
+	Id               int    `json:"id"`
+	RequestId        string `json:"request_id" gorm:"type:varchar(64);index"`
+	UserId           int    `json:"user_id" gorm:"index"`
+	TokenId          int    `json:"token_id"`
+	ChannelId        int    `json:"channel_id"`
+	ModelName        string `json:"model_name" gorm:"type:varchar(100)"`
+	Path             string `json:"path" gorm:"type:varchar(255)"`
+	StatusCode       int    `json:"status_code"`
+	UpstreamURL      string `json:"upstream_url" gorm:"type:text"`
+	RequestBody      []byte `json:"-"`
+	UpstreamRequest  []byte `json:"-"`
+	UpstreamResponse []byte `json:"-"`
+	ClientResponse   []byte `json:"-"`
+	CreatedAt        int64  `json:"created_at" gorm:"bigint;index"`
+}
+
+// PayloadCaptureDetail 解压后的请求内容
+type PayloadCaptureDetail struct {
+	*PayloadCapture
+	RequestBody      string `json:"request_body"`
+	UpstreamRequest  string `json:"upstream_request"`
+	UpstreamResponse string `json:"upstream_response"`
+	ClientResponse   string `json:"client_response"`
+}
+
+func compressPayload(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil
+	}
+	if err := writer.Close(); err != nil {
+		return nil
+	}
+
+	return buf.Bytes()
+}
+
+func decompressPayload(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return ""
+	}
+
+	return string(content)
+}
+
+// RecordPayloadCapture 压缩并保存请求内容
+func RecordPayloadCapture(capture *PayloadCapture) {
+	capture.RequestBody = compressPayload(capture.RequestBody)
+	capture.UpstreamRequest = compressPayload(capture.UpstreamRequest)
+	capture.UpstreamResponse = compressPayload(capture.UpstreamResponse)
+	capture.ClientResponse = compressPayload(capture.ClientResponse)
+	capture.CreatedAt = utils.GetTimestamp()
+
+	if err := DB.Create(capture).Error; err != nil {
+		logger.SysError("failed to record payload capture: " + err.Error())
+	}
+}
+
+func GetPayloadCaptureByRequestId(requestId string) (*PayloadCaptureDetail, error) {
+	capture := &PayloadCapture{}
+	err := DB.Where("request_id = ?", requestId).Order("id desc").First(capture).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &PayloadCaptureDetail{
+		PayloadCapture:   capture,
+		RequestBody:      decompressPayload(capture.RequestBody),
+		UpstreamRequest:  decompressPayload(capture.UpstreamRequest),
+		UpstreamResponse: decompressPayload(capture.UpstreamResponse),
+		ClientResponse:   decompressPayload(capture.ClientResponse),
+	}, nil
+}
+
+// DeleteExpiredPayloadCaptures 删除过期的记录
+func DeleteExpiredPayloadCaptures(retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+
+	targetTimestamp := utils.GetTimestamp() - int64(retentionDays)*24*60*60
+	result := DB.Where("created_at < ?", targetTimestamp).Delete(&PayloadCapture{})
+
+	return result.RowsAffected, result.Error
+}
+
+func joinIds(ids []int) string {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.Itoa(id))
+	}
+	return strings.Join(values, ",")
+}
+
+func splitIds(value string) []int {
+	ids := make([]int, 0)
+	for _, item := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(item))
+		if err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"one-api/common/logger"
+	"one-api/common/utils"
+	"one-api/safty/pii"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// PIIRedactionConfig 请求脱敏的系统设置
+type PIIRedactionConfig struct {
+	// Groups 启用脱敏的分组
+	Groups []string `json:"groups,omitempty"`
+	// Detectors 使用的内置检测规则，为空时使用全部内置规则
+	Detectors []string `json:"detectors,omitempty"`
+	// Custom 自定义检测规则，名称 -> 正则表达式
+	Custom map[string]string `json:"custom,omitempty"`
+}
+
+type PIIRedaction struct {
+	sync.RWMutex
+	redactionConfig *PIIRedactionConfig
+	custom          []*pii.Detector
+	detectors       []*pii.Detector
+}
+
+var PIIRedactionInstance = &PIIRedaction{
+	redactionConfig: &PIIRedactionConfig{},
+}
+
+func ParsePIIRedactionConfig(value string) (*PIIRedactionConfig, []*pii.Detector, error) {
+	redactionConfig := &PIIRedactionConfig{}
+	if strings.TrimSpace(value) == "" {
+		return redactionConfig, nil, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), redactionConfig); err != nil {
+		return nil, nil, err
+	}
+
+	for _, name := range redactionConfig.Detectors {
+		if pii.GetBuiltinDetector(name) == nil {
+			return nil, nil, fmt.Errorf("未知的检测规则 %s", name)
+		}
+	}
+
+	custom := make([]*pii.Detector, 0, len(redactionConfig.Custom))
+	for name, expr := range redactionConfig.Custom {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("自定义规则 %s 错误: %w", name, err)
+		}
+		custom = append(custom, &pii.Detector{Name: name, Pattern: pattern})
+	}
+
+	return redactionConfig, custom, nil
+}
+
+func (p *PIIRedaction) Load(value string) error {
+	redactionConfig, custom, err := ParsePIIRedactionConfig(value)
+	if err != nil {
+		logger.SysError("failed to load pii redaction config: " + err.Error())
+		return err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	p.redactionConfig = redactionConfig
+	p.custom = custom
+	p.detectors = p.buildDetectors(redactionConfig.Detectors)
+
+	return nil
+}
+
+func (p *PIIRedaction) String() string {
+	p.RLock()
+	defer p.RUnlock()
+
+	if len(p.redactionConfig.Groups) == 0 && len(p.redactionConfig.Detectors) == 0 && len(p.redactionConfig.Custom) == 0 {
+		return ""
+	}
+
+	jsonBytes, err := json.Marshal(p.redactionConfig)
+	if err != nil {
+		logger.SysError("error marshalling pii redaction config: " + err.Error())
+		return ""
+	}
+
+	return string(jsonBytes)
+}
+
+// buildDetectors 按名称组合内置规则和自定义规则
+func (p *PIIRedaction) buildDetectors(names []string) []*pii.Detector {
+	var detectors []*pii.Detector
+	if len(names) == 0 {
+		detectors = append(detectors, pii.BuiltinDetectors()...)
+	} else {
+		for _, name := range names {
+			if detector := pii.GetBuiltinDetector(name); detector != nil {
+				detectors = append(detectors, detector)
+			}
+		}
+	}
+
+	return append(detectors, p.custom...)
+}
+
+// GetDetectors 获取请求使用的检测规则，令牌设置优先于分组设置，未启用时返回 nil
+func (p *PIIRedaction) GetDetectors(group string, setting *TokenSetting) []*pii.Detector {
+	p.RLock()
+	defer p.RUnlock()
+
+	if setting != nil && setting.PII.Enabled {
+		return p.buildDetectors(setting.PII.Detectors)
+	}
+
+	if utils.Contains(group, p.redactionConfig.Groups) {
+		if p.detectors == nil {
+			return p.buildDetectors(p.redactionConfig.Detectors)
+		}
+		return p.detectors
+	}
+
+	return nil
+}
@@ -296,6 +296,11 @@ func GetDefaultPrice() []*Price {
 		"gemini-1.5-flash":        {[]float64{0.175, 0.265}, config.ChannelTypeGemini},
 		"gemini-1.5-flash-latest": {[]float64{0.175, 0.265}, config.ChannelTypeGemini},
 		"gemini-ultra":            {[]float64{1, 1}, config.ChannelTypeGemini},
+		// 语音合成按字符计费，按每字符约 1.5 个音频 token 折算
+		// $15 / 1 million characters
+		"gemini-2.5-flash-preview-tts": {[]float64{7.5, 7.5}, config.ChannelTypeGemini},
+		// $30 / 1 million characters
+		"gemini-2.5-pro-preview-tts": {[]float64{15, 15}, config.ChannelTypeGemini},
 
 		// ￥0.005 / 1k tokens
 		"glm-3-turbo": {[]float64{0.3572, 0.3572}, config.ChannelTypeZhipu},
@@ -319,6 +324,13 @@ func GetDefaultPrice() []*Price {
 		"qwen-vl-plus": {[]float64{0.5715, 0.5715}, config.ChannelTypeAli},
 		// ￥0.0007 / 1k tokens
 		"text-embedding-v1": {[]float64{0.05, 0.05}, config.ChannelTypeAli},
+		// ￥2 / 1万字符
+		"cosyvoice-v1": {[]float64{14.2857, 14.2857}, config.ChannelTypeAli},
+		"cosyvoice-v2": {[]float64{14.2857, 14.2857}, config.ChannelTypeAli},
+		// ￥1 / 1万字符
+		"sambert-zhichu-v1":   {[]float64{7.1429, 7.1429}, config.ChannelTypeAli},
+		"sambert-zhiqi-v1":    {[]float64{7.1429, 7.1429}, config.ChannelTypeAli},
+		"sambert-zhixiang-v1": {[]float64{7.1429, 7.1429}, config.ChannelTypeAli},
 
 		// ￥0.018 / 1k tokens
 		"SparkDesk":      {[]float64{1.2858, 1.2858}, config.ChannelTypeXunfei},
@@ -374,6 +386,13 @@ func GetDefaultPrice() []*Price {
 		"gemma-7b-it":    {[]float64{0.05, 0.05}, config.ChannelTypeGroq},
 		// $0.27/$0.27 /1M Tokens 0.00027$ / 1k tokens
 		"mixtral-8x7b-32768": {[]float64{0.135, 0.135}, config.ChannelTypeGroq},
+		// 语音识别按时长折算为 200 tokens / 分钟
+		// $0.111 / hour -> $0.00185 / minute -> 0.00925$ / 1k tokens
+		"whisper-large-v3": {[]float64{4.625, 4.625}, config.ChannelTypeGroq},
+		// $0.04 / hour -> 0.00333$ / 1k tokens
+		"whisper-large-v3-turbo": {[]float64{1.667, 1.667}, config.ChannelTypeGroq},
+		// $0.02 / hour -> 0.00167$ / 1k tokens
+		"distil-whisper-large-v3-en": {[]float64{0.833, 0.833}, config.ChannelTypeGroq},
 
 		// 2.5 元 / 1M tokens 0.0025 / 1k tokens
 		"yi-34b-chat-0205": {[]float64{0.1786, 0.1786}, config.ChannelTypeLingyi},
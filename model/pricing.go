@@ -141,6 +141,19 @@ func (p *Pricing) GetPrice(modelName string) *Price {
 	}
 }
 
+// GetModelPrice 返回模型自身配置价格的副本，不匹配通配符和默认价格，未配置时返回 nil
+func (p *Pricing) GetModelPrice(modelName string) *Price {
+	p.RLock()
+	defer p.RUnlock()
+
+	price, ok := p.Prices[modelName]
+	if !ok {
+		return nil
+	}
+	priceCopy := *price
+	return &priceCopy
+}
+
 func (p *Pricing) GetAllPrices() map[string]*Price {
 	return p.Prices
 }
@@ -344,6 +357,50 @@ func GetPriceByPriceService() ([]*Price, error) {
 	return prices, nil
 }
 
+// AddMissingPricesByPriceService 从价格服务中为尚未配置价格的模型新增价格，返回新增了价格的模型
+func (p *Pricing) AddMissingPricesByPriceService(models []string) ([]string, error) {
+	missing := make(map[string]bool)
+	p.RLock()
+	for _, modelName := range models {
+		if _, ok := p.Prices[modelName]; ok {
+			continue
+		}
+		if utils.GetModelsWithMatch(&p.Match, modelName) != "" {
+			continue
+		}
+		missing[modelName] = true
+	}
+	p.RUnlock()
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	prices, err := GetPriceByPriceService()
+	if err != nil {
+		return nil, err
+	}
+
+	var newPrices []*Price
+	var added []string
+	for _, price := range prices {
+		if missing[price.Model] {
+			newPrices = append(newPrices, price)
+			added = append(added, price.Model)
+		}
+	}
+
+	if len(newPrices) == 0 {
+		return nil, nil
+	}
+
+	if err := p.SyncPriceWithoutOverwrite(newPrices); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
 // SyncPriceWithOverwrite 删除系统所有数据并插入所有查询到的新数据 不含lock的数据
 func (p *Pricing) SyncPriceWithOverwrite(pricing []*Price) error {
 	tx := DB.Begin()
@@ -0,0 +1,212 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"one-api/common/logger"
+	"one-api/common/utils"
+	saftyTypes "one-api/safty/types"
+	"strings"
+	"sync"
+
+	"gorm.io/datatypes"
+)
+
+// 未单独配置的分组使用该策略
+const SafetyDefaultPolicyKey = "default"
+
+type SafetyPolicies map[string]*saftyTypes.Policy
+
+type SafetyPolicy struct {
+	sync.RWMutex
+	policies SafetyPolicies
+}
+
+var SafetyPolicyInstance = &SafetyPolicy{
+	policies: make(SafetyPolicies),
+}
+
+// ParseSafetyPolicies 保存时校验，检查器名称必须是已注册的检查器
+func ParseSafetyPolicies(value string) (SafetyPolicies, error) {
+	policies, err := parseSafetyPolicies(value)
+	if err != nil {
+		return nil, err
+	}
+
+	for group, policy := range policies {
+		for _, tool := range policy.Tools {
+			if !utils.Contains(tool, saftyTypes.ToolNames) {
+				return nil, fmt.Errorf("分组 %s 的检查器 %s 不存在", group, tool)
+			}
+		}
+	}
+
+	return policies, nil
+}
+
+func parseSafetyPolicies(value string) (SafetyPolicies, error) {
+	policies := make(SafetyPolicies)
+	if strings.TrimSpace(value) == "" {
+		return policies, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &policies); err != nil {
+		return nil, err
+	}
+
+	for group, policy := range policies {
+		if policy == nil {
+			delete(policies, group)
+			continue
+		}
+
+		if policy.Action == "" {
+			policy.Action = saftyTypes.ActionBlock
+		}
+
+		switch policy.Action {
+		case saftyTypes.ActionBlock, saftyTypes.ActionFlag, saftyTypes.ActionRedact:
+		default:
+			return nil, fmt.Errorf("分组 %s 的处理方式 %s 无效", group, policy.Action)
+		}
+
+		if policy.StreamChunkSize < 0 {
+			return nil, fmt.Errorf("分组 %s 的 stream_chunk_size 无效", group)
+		}
+	}
+
+	return policies, nil
+}
+
+// Load 加载已保存的策略，不校验检查器名称，不存在的检查器在检查时按策略拦截
+func (sp *SafetyPolicy) Load(value string) error {
+	policies, err := parseSafetyPolicies(value)
+	if err != nil {
+		logger.SysError("failed to load safety policies: " + err.Error())
+		return err
+	}
+
+	sp.Lock()
+	defer sp.Unlock()
+	sp.policies = policies
+
+	return nil
+}
+
+func (sp *SafetyPolicy) String() string {
+	sp.RLock()
+	defer sp.RUnlock()
+
+	if len(sp.policies) == 0 {
+		return ""
+	}
+
+	jsonBytes, err := json.Marshal(sp.policies)
+	if err != nil {
+		logger.SysError("error marshalling safety policies: " + err.Error())
+		return ""
+	}
+
+	return string(jsonBytes)
+}
+
+// GetPolicy 获取分组的审查策略，未配置时返回 nil
+func (sp *SafetyPolicy) GetPolicy(group string) *saftyTypes.Policy {
+	sp.RLock()
+	defer sp.RUnlock()
+
+	if policy, ok := sp.policies[group]; ok {
+		return policy
+	}
+
+	return sp.policies[SafetyDefaultPolicyKey]
+}
+
+// SafetyEvent 内容审查命中记录，供管理员复核
+type SafetyEvent struct {
+	Id        int                         `json:"id"`
+	UserId    int                         `json:"user_id" gorm:"index"`
+	TokenId   int                         `json:"token_id"`
+	ChannelId int                         `json:"channel_id"`
+	Group     string                      `json:"group" gorm:"type:varchar(64)"`
+	ModelName string                      `json:"model_name" gorm:"type:varchar(100)"`
+	Stage     string                      `json:"stage" gorm:"type:varchar(16);index"`
+	Tool      string                      `json:"tool" gorm:"type:varchar(64)"`
+	Action    string                      `json:"action" gorm:"type:varchar(16);index"`
+	Code      string                      `json:"code" gorm:"type:varchar(64)"`
+	Reason    string                      `json:"reason" gorm:"type:text"`
+	Details   datatypes.JSONSlice[string] `json:"details" gorm:"type:json"`
+	// Matches 命中位置，不保存命中的原文
+	Matches datatypes.JSONSlice[saftyTypes.Match] `json:"matches" gorm:"type:json"`
+	// Content 仅脱敏处理时保存脱敏后的内容
+	Content string `json:"content" gorm:"type:text"`
+	// ContentLength 被检查内容的字符数
+	ContentLength int    `json:"content_length"`
+	RequestId     string `json:"request_id" gorm:"type:varchar(64)"`
+	Reviewed      bool   `json:"reviewed" gorm:"default:false;index"`
+	CreatedAt     int64  `json:"created_at" gorm:"bigint;index"`
+}
+
+// 记录中保存的内容最大长度
+const safetyEventContentLimit = 2000
+
+type SafetyEventsListParams struct {
+	PaginationParams
+	UserId   int    `form:"user_id"`
+	Stage    string `form:"stage"`
+	Action   string `form:"action"`
+	Reviewed *bool  `form:"reviewed"`
+}
+
+var allowedSafetyEventOrderFields = map[string]bool{
+	"id":         true,
+	"user_id":    true,
+	"created_at": true,
+}
+
+func RecordSafetyEvent(event *SafetyEvent) {
+	if runes := []rune(event.Content); len(runes) > safetyEventContentLimit {
+		event.Content = string(runes[:safetyEventContentLimit])
+	}
+	event.CreatedAt = utils.GetTimestamp()
+
+	if err := DB.Create(event).Error; err != nil {
+		logger.SysError("failed to record safety event: " + err.Error())
+	}
+}
+
+func GetSafetyEventsList(params *SafetyEventsListParams) (*DataResult[SafetyEvent], error) {
+	var events []*SafetyEvent
+	db := DB
+	if params.UserId != 0 {
+		db = db.Where("user_id = ?", params.UserId)
+	}
+	if params.Stage != "" {
+		db = db.Where("stage = ?", params.Stage)
+	}
+	if params.Action != "" {
+		db = db.Where("action = ?", params.Action)
+	}
+	if params.Reviewed != nil {
+		db = db.Where("reviewed = ?", *params.Reviewed)
+	}
+
+	return PaginateAndOrder[SafetyEvent](db, &params.PaginationParams, &events, allowedSafetyEventOrderFields)
+}
+
+func ReviewSafetyEvent(id int) error {
+	if id == 0 {
+		return errors.New("id 为空！")
+	}
+
+	result := DB.Model(&SafetyEvent{}).Where("id = ?", id).Update("reviewed", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("记录不存在")
+	}
+
+	return nil
+}
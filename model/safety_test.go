@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSafetyPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "empty", value: ""},
+		{name: "default tools", value: `{"default":{"input":true}}`},
+		{name: "registered tools", value: `{"default":{"tools":["Keyword","Regex","Moderation","LLMJudge"],"input":true}}`},
+		{name: "unknown tool", value: `{"vip":{"tools":["Regexp"],"input":true}}`, wantErr: true},
+		{name: "unknown action", value: `{"default":{"action":"drop"}}`, wantErr: true},
+		{name: "negative chunk size", value: `{"default":{"stream_chunk_size":-1}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSafetyPolicies(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSafetyPolicyLoadKeepsUnknownTools(t *testing.T) {
+	policy := &SafetyPolicy{}
+	assert.NoError(t, policy.Load(`{"default":{"tools":["Regexp"],"input":true}}`))
+	assert.Equal(t, []string{"Regexp"}, policy.GetPolicy("default").Tools)
+}
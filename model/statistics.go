@@ -3,7 +3,10 @@ package model
 import (
 	"fmt"
 	"one-api/common"
+	"one-api/common/config"
+	"one-api/common/logger"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -239,6 +242,11 @@ const (
 )
 
 func UpdateStatistics(updateType StatisticsUpdateType) error {
+	// 消费日志不写入数据库时统计数据由 accumulateStatistics 实时累加
+	if config.LogConsumeSQLDisabled {
+		return nil
+	}
+
 	sql := `
 	%s statistics (date, user_id, channel_id, model_name, request_count, quota, prompt_tokens, completion_tokens, request_time)
 	SELECT 
@@ -301,3 +309,123 @@ func UpdateStatistics(updateType StatisticsUpdateType) error {
 	err := DB.Exec(fmt.Sprintf(sql, sqlPrefix, sqlDate, sqlWhere, sqlSuffix)).Error
 	return err
 }
+
+type statisticsKey struct {
+	date      string
+	userId    int
+	channelId int
+	modelName string
+}
+
+var pendingStatistics = struct {
+	sync.Mutex
+	data map[statisticsKey]*Statistics
+}{data: make(map[statisticsKey]*Statistics)}
+
+// accumulateStatistics 在内存中累加消费日志，由 FlushStatistics 定期写入数据库
+func accumulateStatistics(log *Log) {
+	key := statisticsKey{
+		date:      time.Unix(log.CreatedAt, 0).Format("2006-01-02"),
+		userId:    log.UserId,
+		channelId: log.ChannelId,
+		modelName: log.ModelName,
+	}
+
+	pendingStatistics.Lock()
+	defer pendingStatistics.Unlock()
+
+	item, ok := pendingStatistics.data[key]
+	if !ok {
+		item = &Statistics{
+			UserId:    key.userId,
+			ChannelId: key.channelId,
+			ModelName: key.modelName,
+		}
+		pendingStatistics.data[key] = item
+	}
+
+	item.RequestCount++
+	item.Quota += log.Quota
+	item.PromptTokens += log.PromptTokens
+	item.CompletionTokens += log.CompletionTokens
+	item.RequestTime += log.RequestTime
+}
+
+func restoreStatistics(key statisticsKey, item *Statistics) {
+	pendingStatistics.Lock()
+	defer pendingStatistics.Unlock()
+
+	pending, ok := pendingStatistics.data[key]
+	if !ok {
+		pendingStatistics.data[key] = item
+		return
+	}
+
+	pending.RequestCount += item.RequestCount
+	pending.Quota += item.Quota
+	pending.PromptTokens += item.PromptTokens
+	pending.CompletionTokens += item.CompletionTokens
+	pending.RequestTime += item.RequestTime
+}
+
+// FlushStatistics 将累加的统计数据写入数据库
+func FlushStatistics() error {
+	pendingStatistics.Lock()
+	data := pendingStatistics.data
+	pendingStatistics.data = make(map[statisticsKey]*Statistics)
+	pendingStatistics.Unlock()
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	sql := `INSERT INTO statistics (date, user_id, channel_id, model_name, request_count, quota, prompt_tokens, completion_tokens, request_time)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) %s`
+
+	sqlSuffix := ""
+	if common.UsingSQLite || common.UsingPostgreSQL {
+		sqlSuffix = `ON CONFLICT (date, user_id, channel_id, model_name) DO UPDATE SET
+		request_count = statistics.request_count + EXCLUDED.request_count,
+		quota = statistics.quota + EXCLUDED.quota,
+		prompt_tokens = statistics.prompt_tokens + EXCLUDED.prompt_tokens,
+		completion_tokens = statistics.completion_tokens + EXCLUDED.completion_tokens,
+		request_time = statistics.request_time + EXCLUDED.request_time`
+	} else {
+		sqlSuffix = `ON DUPLICATE KEY UPDATE
+		request_count = request_count + VALUES(request_count),
+		quota = quota + VALUES(quota),
+		prompt_tokens = prompt_tokens + VALUES(prompt_tokens),
+		completion_tokens = completion_tokens + VALUES(completion_tokens),
+		request_time = request_time + VALUES(request_time)`
+	}
+	sql = fmt.Sprintf(sql, sqlSuffix)
+
+	var lastErr error
+	for key, item := range data {
+		err := DB.Exec(sql, key.date, item.UserId, item.ChannelId, item.ModelName, item.RequestCount, item.Quota, item.PromptTokens, item.CompletionTokens, item.RequestTime).Error
+		if err != nil {
+			lastErr = err
+			logger.SysError("failed to flush statistics: " + err.Error())
+			// 写入失败的数据放回，下次一起写入
+			restoreStatistics(key, item)
+		}
+	}
+
+	return lastErr
+}
+
+// InitStatisticsAccumulator 消费日志不写入数据库时，定期写入累加的统计数据
+func InitStatisticsAccumulator() {
+	if !config.LogConsumeSQLDisabled {
+		return
+	}
+
+	logger.SysLog("consume logs are not written to database, statistics are accumulated in memory")
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			FlushStatistics()
+		}
+	}()
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"one-api/common"
+	"one-api/common/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestFlushStatisticsRestoresFailedData(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	originDB := DB
+	originSQLite := common.UsingSQLite
+	DB = db
+	common.UsingSQLite = true
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	t.Cleanup(func() {
+		DB = originDB
+		common.UsingSQLite = originSQLite
+	})
+
+	now := time.Now().Unix()
+	accumulateStatistics(&Log{UserId: 1, ChannelId: 2, ModelName: "gpt-4o", Quota: 10, PromptTokens: 3, CompletionTokens: 4, RequestTime: 5, CreatedAt: now})
+
+	// 统计表不存在，写入失败
+	assert.Error(t, FlushStatistics())
+
+	// 写入失败后新产生的数据与未写入的数据合并
+	accumulateStatistics(&Log{UserId: 1, ChannelId: 2, ModelName: "gpt-4o", Quota: 20, PromptTokens: 1, CompletionTokens: 1, RequestTime: 1, CreatedAt: now})
+	require.NoError(t, db.AutoMigrate(&Statistics{}))
+	require.NoError(t, FlushStatistics())
+
+	var rows []Statistics
+	require.NoError(t, db.Find(&rows).Error)
+	require.Len(t, rows, 1)
+	assert.Equal(t, 2, rows[0].RequestCount)
+	assert.Equal(t, 30, rows[0].Quota)
+	assert.Equal(t, 4, rows[0].PromptTokens)
+	assert.Equal(t, 5, rows[0].CompletionTokens)
+	assert.Equal(t, 6, rows[0].RequestTime)
+
+	// 已写入的数据不会重复写入
+	require.NoError(t, FlushStatistics())
+	require.NoError(t, db.Find(&rows).Error)
+	assert.Equal(t, 2, rows[0].RequestCount)
+}
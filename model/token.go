@@ -66,6 +66,40 @@ func (token *Token) AfterCreate(tx *gorm.DB) (err error) {
 type TokenSetting struct {
 	Heartbeat HeartbeatSetting `json:"heartbeat,omitempty"`
 	Limits    LimitsConfig     `json:"limits,omitempty"`
+	Fallback  FallbackSetting  `json:"fallback,omitempty"`
+	PII       PIISetting       `json:"pii,omitempty"`
+	// PromptCache 请求 Claude 时自动插入提示词缓存断点
+	PromptCache PromptCacheSetting `json:"prompt_cache,omitempty"`
+	// Scopes 令牌可以调用的接口范围，为空时可以调用全部中转接口
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// HasScope 未设置范围时兼容旧令牌，允许除需显式授予外的全部范围
+func (s *TokenSetting) HasScope(scope string) bool {
+	if len(s.Scopes) == 0 {
+		return !utils.Contains(scope, config.ExplicitTokenScopes)
+	}
+	return utils.Contains(scope, s.Scopes)
+}
+
+// PIISetting 令牌级别的请求脱敏设置
+type PIISetting struct {
+	Enabled bool `json:"enabled"`
+	// Detectors 使用的内置检测规则，为空时使用全部内置规则
+	Detectors []string `json:"detectors,omitempty"`
+}
+
+// PromptCacheSetting 令牌级别的提示词缓存设置，渠道插件未开启时生效
+type PromptCacheSetting struct {
+	Enabled bool `json:"enabled"`
+	// MinTokens 可缓存前缀的最小 token 数，为 0 时使用默认值
+	MinTokens int `json:"min_tokens,omitempty"`
+}
+
+// FallbackSetting 令牌级别的模型回退链，优先于系统设置
+type FallbackSetting struct {
+	Enabled bool                `json:"enabled"`
+	Chains  ModelFallbackChains `json:"chains,omitempty"`
 }
 
 type HeartbeatSetting struct {
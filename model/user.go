@@ -32,12 +32,15 @@ type User struct {
 	WeChatId         string         `json:"wechat_id" gorm:"column:wechat_id;index"`
 	TelegramId       int64          `json:"telegram_id" gorm:"bigint,column:telegram_id;default:0;"`
 	LarkId           string         `json:"lark_id" gorm:"column:lark_id;index"`
+	SamlId           string         `json:"saml_id" gorm:"column:saml_id;index"`
+	ScimId           string         `json:"scim_id" gorm:"column:scim_id;index"`                               // SCIM externalId
 	VerificationCode string         `json:"verification_code" gorm:"-:all"`                                    // this field is only for Email verification, don't save it to database!
 	AccessToken      string         `json:"access_token" gorm:"type:char(32);column:access_token;uniqueIndex"` // this token is for system management
 	Quota            int            `json:"quota" gorm:"type:int;default:0"`
 	UsedQuota        int            `json:"used_quota" gorm:"type:int;default:0;column:used_quota"` // used quota
 	RequestCount     int            `json:"request_count" gorm:"type:int;default:0;"`               // request number
 	Group            string         `json:"group" gorm:"type:varchar(32);default:'default'"`
+	AdminRoleId      int            `json:"admin_role_id" gorm:"type:int;default:0;index"` // 管理角色，只能通过角色分配接口修改
 	AffCode          string         `json:"aff_code" gorm:"type:varchar(32);column:aff_code;uniqueIndex"`
 	AffCount         int            `json:"aff_count" gorm:"type:int;default:0;column:aff_count"`
 	AffQuota         int            `json:"aff_quota" gorm:"type:int;default:0;column:aff_quota"`
@@ -160,7 +163,7 @@ func (user *User) Insert(inviterId int) error {
 
 func (user *User) Update(updatePassword bool) error {
 	var err error
-	omitFields := []string{"quota", "used_quota", "request_count", "aff_count", "aff_quota", "aff_history"}
+	omitFields := []string{"quota", "used_quota", "request_count", "aff_count", "aff_quota", "aff_history", "admin_role_id"}
 
 	if updatePassword {
 		user.Password, err = common.Password2Hash(user.Password)
@@ -303,6 +306,28 @@ func (user *User) FillUserByOidcId() error {
 	return nil
 }
 
+func (user *User) FillUserBySamlId() error {
+	if user.SamlId == "" {
+		return errors.New("SAML ID 为空！")
+	}
+	err := DB.Where(User{SamlId: user.SamlId}).First(user)
+	if err != nil {
+		return err.Error
+	}
+	return nil
+}
+
+func (user *User) FillUserByScimId() error {
+	if user.ScimId == "" {
+		return errors.New("SCIM ID 为空！")
+	}
+	err := DB.Where(User{ScimId: user.ScimId}).First(user)
+	if err != nil {
+		return err.Error
+	}
+	return nil
+}
+
 func (user *User) FillUserByUsername() error {
 	if user.Username == "" {
 		return errors.New("username 为空！")
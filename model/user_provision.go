@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"one-api/common/config"
+	"one-api/common/redis"
+
+	"gorm.io/gorm"
+)
+
+// IsProvisioned 由身份源管理的用户，管理员和本地创建的用户不允许通过 SCIM 修改
+func (user *User) IsProvisioned() bool {
+	return user.ScimId != "" && user.Role < config.RoleAdminUser
+}
+
+func provisionedUsers() *gorm.DB {
+	return DB.Model(&User{}).Where("scim_id <> '' AND role < ?", config.RoleAdminUser)
+}
+
+// DeprovisionUser 禁用用户并吊销其全部令牌，用于 SCIM 取消分配或删除员工
+func DeprovisionUser(user *User) error {
+	if !user.IsProvisioned() {
+		return errors.New("只能禁用由身份源管理的普通用户")
+	}
+
+	var keys []string
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&User{}).Where("id = ?", user.Id).Update("status", config.UserStatusDisabled).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&Token{}).Where("user_id = ?", user.Id).Pluck("key", &keys).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&Token{}).Where("user_id = ? AND status = ?", user.Id, config.TokenStatusEnabled).
+			Update("status", config.TokenStatusDisabled).Error
+	})
+	if err != nil {
+		return err
+	}
+	user.Status = config.UserStatusDisabled
+
+	if config.RedisEnabled {
+		redis.RedisDel(fmt.Sprintf(UserEnabledCacheKey, user.Id))
+		redis.RedisDel(fmt.Sprintf(UserGroupCacheKey, user.Id))
+		for _, key := range keys {
+			redis.RedisDel(fmt.Sprintf(UserTokensKey, key))
+		}
+	}
+
+	RecordLog(user.Id, LogTypeManage, "用户已被身份源停用，令牌已全部禁用")
+	return nil
+}
+
+// EnableUser 重新启用用户，已吊销的令牌需要用户自行启用
+func EnableUser(user *User) error {
+	err := DB.Model(&User{}).Where("id = ?", user.Id).Update("status", config.UserStatusEnabled).Error
+	if err != nil {
+		return err
+	}
+	user.Status = config.UserStatusEnabled
+
+	if config.RedisEnabled {
+		redis.RedisDel(fmt.Sprintf(UserEnabledCacheKey, user.Id))
+	}
+
+	return nil
+}
+
+// SetUserGroup 修改用户分组并清除缓存
+func SetUserGroup(userId int, group string) error {
+	err := DB.Model(&User{}).Where("id = ?", userId).Update("group", group).Error
+	if err != nil {
+		return err
+	}
+
+	if config.RedisEnabled {
+		redis.RedisDel(fmt.Sprintf(UserGroupCacheKey, userId))
+	}
+
+	return nil
+}
+
+// GetProvisionedUsers 按字段过滤并分页查询用户，offset 从 0 开始
+func GetProvisionedUsers(field, value string, offset, limit int) ([]*User, int64, error) {
+	var users []*User
+	var total int64
+
+	db := provisionedUsers().Omit("password")
+	if field != "" {
+		db = db.Where(fmt.Sprintf("%s = ?", field), value)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("id").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+func GetUserGroupBySymbol(symbol string) (*UserGroup, error) {
+	var userGroup UserGroup
+	err := DB.Where("symbol = ?", symbol).First(&userGroup).Error
+	return &userGroup, err
+}
+
+// GetProvisionedUserById 只查询由身份源管理的用户
+func GetProvisionedUserById(id int) (*User, error) {
+	var user User
+	err := provisionedUsers().Omit("password").Where("id = ?", id).First(&user).Error
+	return &user, err
+}
+
+// IsScimIdTaken 判断 externalId 是否已被其他用户使用，避免关联到其他用户的 SAML 身份
+func IsScimIdTaken(scimId string, excludeId int) bool {
+	var count int64
+	DB.Model(&User{}).Where("scim_id = ? AND id <> ?", scimId, excludeId).Count(&count)
+	return count > 0
+}
+
+func GetProvisionedUserIdsByGroup(group string) ([]int, error) {
+	var ids []int
+	err := provisionedUsers().Where(quotePostgresField("group")+" = ?", group).Pluck("id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,288 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"one-api/common/logger"
+	"one-api/common/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// VirtualModel 虚拟模型，请求时根据规则解析为具体模型
+type VirtualModel struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name" gorm:"type:varchar(100);uniqueIndex"`
+	Description string `json:"description" gorm:"type:text"`
+	// 可使用该虚拟模型的分组，逗号分隔，为空表示所有分组
+	Groups string `json:"groups" gorm:"type:varchar(255);default:''"`
+	// 所有规则均不匹配时使用的模型
+	DefaultModel string `json:"default_model" gorm:"type:varchar(100)"`
+	// 是否按虚拟模型自身的价格计费，否则按实际使用的模型计费
+	FixedPrice bool                                  `json:"fixed_price" gorm:"default:false"`
+	Rules      datatypes.JSONSlice[VirtualModelRule] `json:"rules" gorm:"type:json"`
+	Enabled    *bool                                 `json:"enabled" gorm:"default:true"`
+	CreatedAt  int64                                 `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  int64                                 `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// VirtualModelRule 按顺序匹配，第一个满足全部条件的规则生效
+type VirtualModelRule struct {
+	Model      string                `json:"model"`
+	Conditions VirtualModelCondition `json:"conditions"`
+}
+
+// VirtualModelCondition 未设置的条件视为满足
+type VirtualModelCondition struct {
+	MinPromptTokens int      `json:"min_prompt_tokens,omitempty"`
+	MaxPromptTokens int      `json:"max_prompt_tokens,omitempty"`
+	HasImages       *bool    `json:"has_images,omitempty"`
+	HasTools        *bool    `json:"has_tools,omitempty"`
+	ResponseFormat  []string `json:"response_format,omitempty"`
+	Groups          []string `json:"groups,omitempty"`
+	// 生效时间段，格式 HH:MM-HH:MM，支持跨零点，使用服务器本地时间
+	TimeRange string `json:"time_range,omitempty"`
+}
+
+// VirtualModelRequest 用于规则匹配的请求特征
+type VirtualModelRequest struct {
+	PromptTokens   int
+	HasImages      bool
+	HasTools       bool
+	ResponseFormat string
+	Group          string
+	Now            time.Time
+}
+
+func (c *VirtualModelCondition) Match(req *VirtualModelRequest) bool {
+	if c.MinPromptTokens > 0 && req.PromptTokens < c.MinPromptTokens {
+		return false
+	}
+
+	if c.MaxPromptTokens > 0 && req.PromptTokens > c.MaxPromptTokens {
+		return false
+	}
+
+	if c.HasImages != nil && *c.HasImages != req.HasImages {
+		return false
+	}
+
+	if c.HasTools != nil && *c.HasTools != req.HasTools {
+		return false
+	}
+
+	if len(c.ResponseFormat) > 0 {
+		format := req.ResponseFormat
+		if format == "" {
+			format = "text"
+		}
+		if !utils.Contains(format, c.ResponseFormat) {
+			return false
+		}
+	}
+
+	if len(c.Groups) > 0 && !utils.Contains(req.Group, c.Groups) {
+		return false
+	}
+
+	if c.TimeRange != "" {
+		inRange, err := inTimeRange(c.TimeRange, req.Now)
+		if err != nil || !inRange {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func inTimeRange(timeRange string, now time.Time) (bool, error) {
+	parts := strings.Split(timeRange, "-")
+	if len(parts) != 2 {
+		return false, fmt.Errorf("invalid time range: %s", timeRange)
+	}
+
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return false, err
+	}
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return false, err
+	}
+
+	current := now.Hour()*60 + now.Minute()
+	if start <= end {
+		return current >= start && current < end, nil
+	}
+
+	// 跨零点，例如 22:00-06:00
+	return current >= start || current < end, nil
+}
+
+func (v *VirtualModel) IsEnabled() bool {
+	return v.Enabled == nil || *v.Enabled
+}
+
+func (v *VirtualModel) AllowGroup(group string) bool {
+	if strings.TrimSpace(v.Groups) == "" {
+		return true
+	}
+
+	for _, g := range strings.Split(v.Groups, ",") {
+		if strings.TrimSpace(g) == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve 根据请求特征解析出具体模型
+func (v *VirtualModel) Resolve(req *VirtualModelRequest) (string, error) {
+	for _, rule := range v.Rules {
+		if rule.Model == "" {
+			continue
+		}
+		if rule.Conditions.Match(req) {
+			return rule.Model, nil
+		}
+	}
+
+	if v.DefaultModel == "" {
+		return "", fmt.Errorf("虚拟模型 %s 没有匹配的规则", v.Name)
+	}
+
+	return v.DefaultModel, nil
+}
+
+func (v *VirtualModel) Validate() error {
+	v.Name = strings.TrimSpace(v.Name)
+	if v.Name == "" {
+		return errors.New("虚拟模型名称不能为空")
+	}
+
+	if v.DefaultModel == v.Name {
+		return errors.New("默认模型不能是虚拟模型自身")
+	}
+
+	for _, rule := range v.Rules {
+		if rule.Model == "" || rule.Model == v.Name {
+			return errors.New("规则的目标模型无效")
+		}
+		if rule.Conditions.TimeRange != "" {
+			if _, err := inTimeRange(rule.Conditions.TimeRange, time.Now()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func GetAllVirtualModels() ([]*VirtualModel, error) {
+	var virtualModels []*VirtualModel
+	err := DB.Order("id desc").Find(&virtualModels).Error
+	return virtualModels, err
+}
+
+func GetVirtualModel(id int) (*VirtualModel, error) {
+	virtualModel := &VirtualModel{}
+	err := DB.Where("id = ?", id).First(virtualModel).Error
+	if err != nil {
+		return nil, err
+	}
+	return virtualModel, nil
+}
+
+func (v *VirtualModel) Insert() error {
+	err := DB.Create(v).Error
+	if err == nil {
+		VirtualModelsInstance.Load()
+	}
+	return err
+}
+
+func (v *VirtualModel) Update() error {
+	err := DB.Omit("id", "created_at").Save(v).Error
+	if err == nil {
+		VirtualModelsInstance.Load()
+	}
+	return err
+}
+
+func DeleteVirtualModel(id int) error {
+	err := DB.Delete(&VirtualModel{}, id).Error
+	if err == nil {
+		VirtualModelsInstance.Load()
+	}
+	return err
+}
+
+type VirtualModels struct {
+	sync.RWMutex
+	models map[string]*VirtualModel
+}
+
+var VirtualModelsInstance = &VirtualModels{
+	models: make(map[string]*VirtualModel),
+}
+
+func (vm *VirtualModels) Load() {
+	virtualModels, err := GetAllVirtualModels()
+	if err != nil {
+		logger.SysError("failed to load virtual models: " + err.Error())
+		return
+	}
+
+	newModels := make(map[string]*VirtualModel, len(virtualModels))
+	for _, virtualModel := range virtualModels {
+		if !virtualModel.IsEnabled() {
+			continue
+		}
+		newModels[virtualModel.Name] = virtualModel
+	}
+
+	vm.Lock()
+	defer vm.Unlock()
+	vm.models = newModels
+}
+
+func (vm *VirtualModels) Get(name string) *VirtualModel {
+	vm.RLock()
+	defer vm.RUnlock()
+
+	return vm.models[name]
+}
+
+// GetGroupModels 获取分组可用的虚拟模型名称
+func (vm *VirtualModels) GetGroupModels(group string) []string {
+	vm.RLock()
+	defer vm.RUnlock()
+
+	models := make([]string, 0, len(vm.models))
+	for name, virtualModel := range vm.models {
+		if virtualModel.AllowGroup(group) {
+			models = append(models, name)
+		}
+	}
+
+	return models
+}
+
+func (vm *VirtualModels) GetAll() map[string]*VirtualModel {
+	vm.RLock()
+	defer vm.RUnlock()
+
+	return vm.models
+}
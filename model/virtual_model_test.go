@@ -0,0 +1,148 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clockAt(hour, minute int) time.Time {
+	return time.Date(2024, 1, 1, hour, minute, 0, 0, time.Local)
+}
+
+func TestInTimeRange(t *testing.T) {
+	tests := []struct {
+		timeRange string
+		now       time.Time
+		want      bool
+		wantErr   bool
+	}{
+		{timeRange: "09:00-18:00", now: clockAt(9, 0), want: true},
+		{timeRange: "09:00-18:00", now: clockAt(17, 59), want: true},
+		{timeRange: "09:00-18:00", now: clockAt(18, 0), want: false},
+		{timeRange: "09:00-18:00", now: clockAt(8, 59), want: false},
+		{timeRange: " 09:00 - 18:00 ", now: clockAt(12, 0), want: true},
+		{timeRange: "22:00-06:00", now: clockAt(23, 30), want: true},
+		{timeRange: "22:00-06:00", now: clockAt(0, 0), want: true},
+		{timeRange: "22:00-06:00", now: clockAt(5, 59), want: true},
+		{timeRange: "22:00-06:00", now: clockAt(6, 0), want: false},
+		{timeRange: "22:00-06:00", now: clockAt(12, 0), want: false},
+		{timeRange: "10:00-10:00", now: clockAt(10, 0), want: false},
+		{timeRange: "09:00", wantErr: true},
+		{timeRange: "09:00-18:00-20:00", wantErr: true},
+		{timeRange: "9am-6pm", wantErr: true},
+		{timeRange: "25:00-26:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timeRange+" "+tt.now.Format("15:04"), func(t *testing.T) {
+			got, err := inTimeRange(tt.timeRange, tt.now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVirtualModelConditionMatch(t *testing.T) {
+	yes, no := true, false
+	req := VirtualModelRequest{
+		PromptTokens: 1000,
+		HasImages:    true,
+		HasTools:     false,
+		Group:        "vip",
+		Now:          clockAt(12, 0),
+	}
+
+	tests := []struct {
+		name      string
+		condition VirtualModelCondition
+		modify    func(req *VirtualModelRequest)
+		want      bool
+	}{
+		{name: "no conditions", want: true},
+		{name: "min prompt tokens met", condition: VirtualModelCondition{MinPromptTokens: 1000}, want: true},
+		{name: "min prompt tokens not met", condition: VirtualModelCondition{MinPromptTokens: 1001}, want: false},
+		{name: "max prompt tokens met", condition: VirtualModelCondition{MaxPromptTokens: 1000}, want: true},
+		{name: "max prompt tokens exceeded", condition: VirtualModelCondition{MaxPromptTokens: 999}, want: false},
+		{name: "has images", condition: VirtualModelCondition{HasImages: &yes}, want: true},
+		{name: "without images", condition: VirtualModelCondition{HasImages: &no}, want: false},
+		{name: "without tools", condition: VirtualModelCondition{HasTools: &no}, want: true},
+		{name: "has tools", condition: VirtualModelCondition{HasTools: &yes}, want: false},
+		{name: "empty response format is text", condition: VirtualModelCondition{ResponseFormat: []string{"text"}}, want: true},
+		{
+			name:      "response format matched",
+			condition: VirtualModelCondition{ResponseFormat: []string{"json_object", "json_schema"}},
+			modify:    func(req *VirtualModelRequest) { req.ResponseFormat = "json_schema" },
+			want:      true,
+		},
+		{name: "response format not matched", condition: VirtualModelCondition{ResponseFormat: []string{"json_object"}}, want: false},
+		{name: "group matched", condition: VirtualModelCondition{Groups: []string{"default", "vip"}}, want: true},
+		{name: "group not matched", condition: VirtualModelCondition{Groups: []string{"default"}}, want: false},
+		{name: "in time range", condition: VirtualModelCondition{TimeRange: "09:00-18:00"}, want: true},
+		{name: "out of time range", condition: VirtualModelCondition{TimeRange: "22:00-06:00"}, want: false},
+		{name: "invalid time range never matches", condition: VirtualModelCondition{TimeRange: "invalid"}, want: false},
+		{
+			name:      "all conditions met",
+			condition: VirtualModelCondition{MinPromptTokens: 500, MaxPromptTokens: 2000, HasImages: &yes, Groups: []string{"vip"}, TimeRange: "09:00-18:00"},
+			want:      true,
+		},
+		{
+			name:      "one condition not met",
+			condition: VirtualModelCondition{MinPromptTokens: 500, MaxPromptTokens: 2000, HasImages: &yes, HasTools: &yes},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := req
+			if tt.modify != nil {
+				tt.modify(&req)
+			}
+			assert.Equal(t, tt.want, tt.condition.Match(&req))
+		})
+	}
+}
+
+func TestVirtualModelResolve(t *testing.T) {
+	yes := true
+	virtualModel := &VirtualModel{
+		Name:         "auto",
+		DefaultModel: "gpt-4o-mini",
+		Rules: []VirtualModelRule{
+			{Model: "", Conditions: VirtualModelCondition{}},
+			{Model: "gpt-4o", Conditions: VirtualModelCondition{HasImages: &yes}},
+			{Model: "gpt-4.1", Conditions: VirtualModelCondition{MinPromptTokens: 1000}},
+			{Model: "o3", Conditions: VirtualModelCondition{MinPromptTokens: 500}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		req  VirtualModelRequest
+		want string
+	}{
+		{name: "first matched rule wins", req: VirtualModelRequest{HasImages: true, PromptTokens: 2000}, want: "gpt-4o"},
+		{name: "later rule", req: VirtualModelRequest{PromptTokens: 2000}, want: "gpt-4.1"},
+		{name: "last rule", req: VirtualModelRequest{PromptTokens: 600}, want: "o3"},
+		{name: "default model", req: VirtualModelRequest{PromptTokens: 10}, want: "gpt-4o-mini"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := virtualModel.Resolve(&tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	virtualModel.DefaultModel = ""
+	_, err := virtualModel.Resolve(&VirtualModelRequest{})
+	assert.Error(t, err)
+}
@@ -11,6 +11,7 @@ import (
 	"one-api/payment/types"
 
 	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
 	"github.com/wechatpay-apiv3/wechatpay-go/core"
 	"github.com/wechatpay-apiv3/wechatpay-go/core/auth/verifiers"
 	"github.com/wechatpay-apiv3/wechatpay-go/core/downloader"
@@ -104,7 +105,7 @@ func (w *WeChatPay) HandleCallback(c *gin.Context, gatewayConfig string) (*types
 	}
 	if *transaction.TradeState != "SUCCESS" {
 		c.Status(http.StatusNoContent)
-		return nil, fmt.Errorf("tradeNo: %s, TransactionId: %s,  err: %v", transaction.OutTradeNo, transaction.TransactionId, err)
+		return nil, fmt.Errorf("tradeNo: %s, TransactionId: %s,  err: %v", lo.FromPtr(transaction.OutTradeNo), lo.FromPtr(transaction.TransactionId), err)
 	}
 
 	payNotify := &types.PayNotify{
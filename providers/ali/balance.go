@@ -0,0 +1,106 @@
+package ali
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"one-api/common/config"
+	"one-api/common/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DashScope 的 API Key 无法查询余额，需要在插件中配置阿里云 AccessKey 调用费用中心接口
+const balanceURL = "https://business.aliyuncs.com/"
+
+type BalanceResponse struct {
+	Code    string       `json:"Code"`
+	Message string       `json:"Message"`
+	Success bool         `json:"Success"`
+	Data    *BalanceData `json:"Data"`
+}
+
+type BalanceData struct {
+	AvailableAmount string `json:"AvailableAmount"`
+	Currency        string `json:"Currency"`
+}
+
+func (p *AliProvider) Balance() (float64, error) {
+	accessKeyId := p.GetPluginString("balance", "access_key_id")
+	accessKeySecret := p.GetPluginString("balance", "access_key_secret")
+	if accessKeyId == "" || accessKeySecret == "" {
+		return 0, errors.New("请在插件中配置阿里云 AccessKey")
+	}
+
+	params := map[string]string{
+		"Action":           "QueryAccountBalance",
+		"Version":          "2017-12-14",
+		"Format":           "JSON",
+		"AccessKeyId":      accessKeyId,
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureVersion": "1.0",
+		"SignatureNonce":   utils.GetUUID(),
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	query := signRPCRequest(params, accessKeySecret)
+
+	req, err := p.Requester.NewRequest("GET", balanceURL+"?"+query)
+	if err != nil {
+		return 0, err
+	}
+
+	// 发送请求
+	var info BalanceResponse
+	_, errWithCode := p.Requester.SendRequest(req, &info, false)
+	if errWithCode != nil {
+		return 0, errors.New(errWithCode.OpenAIError.Message)
+	}
+
+	if !info.Success || info.Data == nil {
+		return 0, errors.New("获取余额失败: " + info.Message)
+	}
+
+	balance, err := strconv.ParseFloat(strings.ReplaceAll(info.Data.AvailableAmount, ",", ""), 64)
+	if err != nil {
+		return 0, err
+	}
+	if info.Data.Currency == "CNY" {
+		balance = balance / config.PaymentUSDRate
+	}
+
+	p.Channel.UpdateBalance(balance)
+	return balance, nil
+}
+
+// signRPCRequest 阿里云 RPC 风格接口签名，返回带签名的查询字符串
+func signRPCRequest(params map[string]string, accessKeySecret string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, percentEncode(key)+"="+percentEncode(params[key]))
+	}
+	canonicalized := strings.Join(pairs, "&")
+
+	stringToSign := "GET&" + percentEncode("/") + "&" + percentEncode(canonicalized)
+	mac := hmac.New(sha1.New, []byte(accessKeySecret+"&"))
+	mac.Write([]byte(stringToSign))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return "Signature=" + percentEncode(signature) + "&" + canonicalized
+}
+
+func percentEncode(value string) string {
+	encoded := url.QueryEscape(value)
+	encoded = strings.ReplaceAll(encoded, "+", "%20")
+	encoded = strings.ReplaceAll(encoded, "*", "%2A")
+	return strings.ReplaceAll(encoded, "%7E", "~")
+}
@@ -25,6 +25,7 @@ type AliProvider struct {
 	openai.OpenAIProvider
 
 	UseOpenaiAPI bool
+	wsRequester  *requester.WSRequester
 }
 
 // 创建 AliProvider
@@ -52,6 +53,7 @@ func (f AliProviderFactory) Create(channel *model.Channel) base.ProviderInterfac
 			SupportStreamOptions: true,
 		},
 		UseOpenaiAPI: useOpenaiAPI,
+		wsRequester:  requester.NewWSRequester(*channel.Proxy),
 	}
 
 	if useOpenaiAPI {
@@ -0,0 +1,199 @@
+package ali
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"one-api/common"
+	"one-api/common/audio"
+	"one-api/common/utils"
+	"one-api/types"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	speechWSPath      = "/api-ws/v1/inference"
+	speechReadTimeout = 60 * time.Second
+)
+
+func (p *AliProvider) GetVoiceMap() map[string][]string {
+	defaultVoiceMapping := map[string][]string{
+		"alloy":   {"longxiaochun"},
+		"echo":    {"longcheng"},
+		"fable":   {"longshuo"},
+		"onyx":    {"longshu"},
+		"nova":    {"longwan"},
+		"shimmer": {"longxiaoxia"},
+	}
+
+	return p.BaseProvider.GetVoiceMap(defaultVoiceMapping)
+}
+
+// CreateSpeech 通过 WebSocket 调用 CosyVoice / Sambert 语音合成
+// Sambert 的音色由模型名决定，忽略 voice 参数
+func (p *AliProvider) CreateSpeech(request *types.SpeechAudioRequest) (*http.Response, *types.OpenAIErrorWithStatusCode) {
+	format := audio.NormalizeSpeechFormat(request.ResponseFormat)
+	if !audio.IsSpeechFormat(format) {
+		return nil, common.StringErrorWrapperLocal("unsupported response_format: "+format, "invalid_request_error", http.StatusBadRequest)
+	}
+
+	isSambert := strings.HasPrefix(request.Model, "sambert")
+
+	// 上游只支持 mp3、wav、pcm，其余格式先合成 wav 再转码
+	upstreamFormat := format
+	sampleRate := audio.DefaultPCMSampleRate
+	if isSambert {
+		// Sambert 多数音色只支持 16kHz，pcm 需要重采样
+		sampleRate = 16000
+		if format == "pcm" {
+			upstreamFormat = "wav"
+		}
+	}
+	if upstreamFormat != "mp3" && upstreamFormat != "wav" && upstreamFormat != "pcm" {
+		upstreamFormat = "wav"
+	}
+
+	// 上游语速范围为 0.5 ~ 2，超出部分由服务端转码
+	rate := 1.0
+	if request.Speed > 0 {
+		rate = min(max(request.Speed, 0.5), 2)
+	}
+
+	parameters := &AliSpeechParameters{
+		TextType:   "PlainText",
+		Format:     upstreamFormat,
+		SampleRate: sampleRate,
+		Rate:       rate,
+	}
+	if !isSambert {
+		parameters.Voice = request.Voice
+		if voices := p.GetVoiceMap()[request.Voice]; voices != nil {
+			parameters.Voice = voices[0]
+		}
+		// cosyvoice-v2 的系统音色带 _v2 后缀
+		if strings.HasPrefix(request.Model, "cosyvoice-v2") && strings.HasPrefix(parameters.Voice, "long") && !strings.HasSuffix(parameters.Voice, "_v2") {
+			parameters.Voice += "_v2"
+		}
+	}
+
+	data, characters, errWithCode := p.synthesize(request.Model, request.Input, parameters, isSambert)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	data, err := audio.Transcode(data, audio.TranscodeOptions{
+		From:       upstreamFormat,
+		To:         format,
+		SampleRate: sampleRate,
+		Speed:      request.Speed / rate,
+	})
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "transcode_audio_failed", http.StatusInternalServerError)
+	}
+
+	if characters > 0 {
+		p.Usage.PromptTokens = characters
+	}
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+
+	return audio.NewSpeechResponse(data, format), nil
+}
+
+func (p *AliProvider) getSpeechURL() string {
+	baseURL := strings.TrimSuffix(p.GetBaseURL(), "/")
+	baseURL = strings.TrimSuffix(baseURL, "/compatible-mode")
+	baseURL = strings.Replace(baseURL, "https://", "wss://", 1)
+	baseURL = strings.Replace(baseURL, "http://", "ws://", 1)
+
+	return baseURL + speechWSPath
+}
+
+// synthesize 返回合成的音频和计费字符数
+// CosyVoice 使用 duplex 模式，文本通过 continue-task 发送；Sambert 在 run-task 中直接携带文本
+func (p *AliProvider) synthesize(model, text string, parameters *AliSpeechParameters, isSambert bool) ([]byte, int, *types.OpenAIErrorWithStatusCode) {
+	headers := map[string]string{
+		"Authorization": "bearer " + p.Channel.Key,
+	}
+	conn, err := p.wsRequester.NewRequest(p.getSpeechURL(), p.wsRequester.WithHeader(headers))
+	if err != nil {
+		return nil, 0, common.ErrorWrapper(err, "ws_request_failed", http.StatusInternalServerError)
+	}
+	defer conn.Close()
+
+	taskId := strings.ReplaceAll(utils.GetUUID(), "-", "")
+	header := AliSpeechHeader{
+		Action:    "run-task",
+		TaskId:    taskId,
+		Streaming: "duplex",
+	}
+	runTask := &AliSpeechRequest{
+		Header: header,
+		Payload: AliSpeechPayload{
+			TaskGroup:  "audio",
+			Task:       "tts",
+			Function:   "SpeechSynthesizer",
+			Model:      model,
+			Parameters: parameters,
+		},
+	}
+	if isSambert {
+		runTask.Header.Streaming = "out"
+		runTask.Payload.Input.Text = text
+	}
+
+	if err := conn.WriteJSON(runTask); err != nil {
+		return nil, 0, common.ErrorWrapper(err, "ws_request_failed", http.StatusInternalServerError)
+	}
+
+	var (
+		buffer     bytes.Buffer
+		characters int
+	)
+	for {
+		conn.SetReadDeadline(time.Now().Add(speechReadTimeout))
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			return nil, 0, common.ErrorWrapper(err, "ws_read_failed", http.StatusInternalServerError)
+		}
+
+		if messageType == websocket.BinaryMessage {
+			buffer.Write(message)
+			continue
+		}
+
+		event := &AliSpeechEvent{}
+		if err := json.Unmarshal(message, event); err != nil {
+			return nil, 0, common.ErrorWrapper(err, "ws_read_failed", http.StatusInternalServerError)
+		}
+		if event.Payload.Usage.Characters > 0 {
+			characters = event.Payload.Usage.Characters
+		}
+
+		switch event.Header.Event {
+		case "task-started":
+			if isSambert {
+				continue
+			}
+			continueHeader := header
+			continueHeader.Action = "continue-task"
+			finishHeader := header
+			finishHeader.Action = "finish-task"
+			if err := conn.WriteJSON(&AliSpeechRequest{Header: continueHeader, Payload: AliSpeechPayload{Input: AliSpeechInput{Text: text}}}); err != nil {
+				return nil, 0, common.ErrorWrapper(err, "ws_request_failed", http.StatusInternalServerError)
+			}
+			if err := conn.WriteJSON(&AliSpeechRequest{Header: finishHeader}); err != nil {
+				return nil, 0, common.ErrorWrapper(err, "ws_request_failed", http.StatusInternalServerError)
+			}
+		case "task-finished":
+			return buffer.Bytes(), characters, nil
+		case "task-failed":
+			errWithCode := common.ErrorWrapper(errors.New(event.Header.ErrorMessage), "speech_error", http.StatusInternalServerError)
+			errWithCode.OpenAIError.Code = event.Header.ErrorCode
+			return nil, 0, errWithCode
+		}
+	}
+}
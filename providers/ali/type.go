@@ -97,3 +97,47 @@ type AliEmbeddingResponse struct {
 	Usage AliUsage `json:"usage"`
 	AliError
 }
+
+type AliSpeechHeader struct {
+	Action       string `json:"action,omitempty"`
+	TaskId       string `json:"task_id"`
+	Streaming    string `json:"streaming,omitempty"`
+	Event        string `json:"event,omitempty"`
+	ErrorCode    string `json:"error_code,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+type AliSpeechRequest struct {
+	Header  AliSpeechHeader  `json:"header"`
+	Payload AliSpeechPayload `json:"payload"`
+}
+
+type AliSpeechPayload struct {
+	TaskGroup  string               `json:"task_group,omitempty"`
+	Task       string               `json:"task,omitempty"`
+	Function   string               `json:"function,omitempty"`
+	Model      string               `json:"model,omitempty"`
+	Parameters *AliSpeechParameters `json:"parameters,omitempty"`
+	Input      AliSpeechInput       `json:"input"`
+}
+
+type AliSpeechParameters struct {
+	TextType   string  `json:"text_type"`
+	Voice      string  `json:"voice,omitempty"`
+	Format     string  `json:"format"`
+	SampleRate int     `json:"sample_rate"`
+	Rate       float64 `json:"rate,omitempty"`
+}
+
+type AliSpeechInput struct {
+	Text string `json:"text,omitempty"`
+}
+
+type AliSpeechEvent struct {
+	Header  AliSpeechHeader `json:"header"`
+	Payload struct {
+		Usage struct {
+			Characters int `json:"characters"`
+		} `json:"usage"`
+	} `json:"payload"`
+}
@@ -0,0 +1,37 @@
+package azure
+
+import (
+	"fmt"
+	"net/url"
+	"one-api/common/config"
+	"one-api/common/requester"
+	"one-api/types"
+
+	"github.com/gorilla/websocket"
+)
+
+// realtime 接口最低支持的 api-version
+const realtimeMinAPIVersion = "2024-10-01-preview"
+
+// CreateChatRealtime Azure 的 realtime 部署名不一定包含 realtime，因此单独拼接地址
+// wss://my-resource.openai.azure.com/openai/realtime?api-version=2024-10-01-preview&deployment=gpt-4o-realtime-preview
+func (p *AzureProvider) CreateChatRealtime(modelName string) (*websocket.Conn, requester.MessageHandler, *types.OpenAIErrorWithStatusCode) {
+	uri, errWithCode := p.GetSupportedAPIUri(config.RelayModeChatRealtime)
+	if errWithCode != nil {
+		return nil, nil, errWithCode
+	}
+
+	apiVersion := p.Channel.Other
+	if apiVersion < "2024-10-01" {
+		apiVersion = realtimeMinAPIVersion
+	}
+
+	fullRequestURL := fmt.Sprintf("%s/openai%s?api-version=%s&deployment=%s", p.GetWSBaseURL(), uri, url.QueryEscape(apiVersion), url.QueryEscape(modelName))
+
+	wsConn, errWithCode := p.DialRealtime(fullRequestURL)
+	if errWithCode != nil {
+		return nil, nil, errWithCode
+	}
+
+	return wsConn, p.HandleMessage, nil
+}
@@ -1,10 +1,13 @@
 package azureSpeech
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"one-api/common/requester"
 	"one-api/model"
 	"one-api/providers/base"
+	"one-api/types"
 	"strings"
 )
 
@@ -19,7 +22,7 @@ func (f AzureSpeechProviderFactory) Create(channel *model.Channel) base.Provider
 				AudioSpeech: "/cognitiveservices/v1",
 			},
 			Channel:   channel,
-			Requester: requester.NewHTTPRequester(*channel.Proxy, nil),
+			Requester: requester.NewHTTPRequester(*channel.Proxy, requestErrorHandle),
 		},
 	}
 }
@@ -49,3 +52,24 @@ func (p *AzureSpeechProvider) GetRequestHeaders() (headers map[string]string) {
 
 	return headers
 }
+
+// 请求错误处理，语音识别接口返回 {"error": {...}} 或 {"code": "...", "message": "..."}
+func requestErrorHandle(resp *http.Response) *types.OpenAIError {
+	azureError := &AzureSpeechError{}
+	if err := json.NewDecoder(resp.Body).Decode(azureError); err != nil {
+		return nil
+	}
+
+	code, message := azureError.Code, azureError.Message
+	if azureError.Error != nil {
+		code, message = azureError.Error.Code, azureError.Error.Message
+	}
+	if message == "" {
+		return nil
+	}
+	return &types.OpenAIError{
+		Message: message,
+		Type:    "azure_speech_error",
+		Code:    code,
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"one-api/common"
 	"one-api/common/config"
 	"one-api/types"
-	"strings"
 )
 
 var outputFormatMap = map[string]string{
@@ -42,28 +41,7 @@ func (p *AzureSpeechProvider) GetVoiceMap() map[string][]string {
 		"shimmer": {"zh-CN-XiaohanNeural"},
 	}
 
-	if p.Channel.Plugin == nil {
-		return defaultVoiceMapping
-	}
-
-	customVoiceMapping, ok := p.Channel.Plugin.Data()["voice"]
-	if !ok {
-		return defaultVoiceMapping
-	}
-
-	for key, value := range customVoiceMapping {
-		if _, exists := defaultVoiceMapping[key]; !exists {
-			continue
-		}
-		customVoiceValue, isString := value.(string)
-		if !isString || customVoiceValue == "" {
-			continue
-		}
-		customizeVoice := strings.Split(customVoiceValue, "|")
-		defaultVoiceMapping[key] = customizeVoice
-	}
-
-	return defaultVoiceMapping
+	return p.BaseProvider.GetVoiceMap(defaultVoiceMapping)
 }
 
 func (p *AzureSpeechProvider) getRequestBody(request *types.SpeechAudioRequest) *bytes.Buffer {
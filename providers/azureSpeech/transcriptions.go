@@ -0,0 +1,306 @@
+package azureSpeech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"one-api/common"
+	"one-api/common/storage"
+	"one-api/common/utils"
+	"one-api/types"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	fastTranscriptionURL  = "/speechtotext/transcriptions:transcribe?api-version=2024-11-15"
+	batchTranscriptionURL = "/speechtotext/v3.2/transcriptions"
+
+	batchPollInterval = 3 * time.Second
+	batchTimeout      = 10 * time.Minute
+	// 批量转写的时间单位为 100 纳秒
+	ticksPerSecond = 10000000
+)
+
+// 常用语言代码对应的默认区域，Azure 需要完整的区域代码
+var localeMap = map[string]string{
+	"en": "en-US",
+	"zh": "zh-CN",
+	"ja": "ja-JP",
+	"ko": "ko-KR",
+	"fr": "fr-FR",
+	"de": "de-DE",
+	"es": "es-ES",
+	"it": "it-IT",
+	"pt": "pt-BR",
+	"ru": "ru-RU",
+}
+
+// CreateTranscriptions 默认使用快速转写，模型名包含 batch 时使用批量转写，
+// 批量转写需要先将音频上传到已配置的存储
+func (p *AzureSpeechProvider) CreateTranscriptions(request *types.AudioRequest) (*types.AudioResponseWrapper, *types.OpenAIErrorWithStatusCode) {
+	baseURL := p.getSpeechToTextBaseURL()
+	if baseURL == "" {
+		return nil, common.StringErrorWrapperLocal("region or base url is required", "invalid_azure_speech_config", http.StatusInternalServerError)
+	}
+
+	var (
+		result      *transcriptionResult
+		errWithCode *types.OpenAIErrorWithStatusCode
+	)
+	if strings.Contains(request.Model, "batch") {
+		result, errWithCode = p.batchTranscription(baseURL, request)
+	} else {
+		result, errWithCode = p.fastTranscription(baseURL, request)
+	}
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	p.Usage.CompletionTokens = common.CountTokenText(result.text, request.Model)
+	p.Usage.TotalTokens = p.Usage.PromptTokens + p.Usage.CompletionTokens
+
+	response, err := request.NewAudioResponse("transcribe", result.language, result.duration, result.text, result.segments)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "marshal_response_body_failed", http.StatusInternalServerError)
+	}
+	return response, nil
+}
+
+type transcriptionResult struct {
+	language string
+	duration float64
+	text     string
+	segments []types.AudioSegment
+}
+
+func (p *AzureSpeechProvider) getSpeechToTextBaseURL() string {
+	if p.Channel.Other != "" {
+		return fmt.Sprintf("https://%s.api.cognitive.microsoft.com", p.Channel.Other)
+	}
+	return strings.TrimSuffix(p.GetBaseURL(), "/")
+}
+
+func (p *AzureSpeechProvider) getSpeechToTextHeaders() map[string]string {
+	return map[string]string{
+		"Ocp-Apim-Subscription-Key": p.Channel.Key,
+		"Content-Type":              "application/json",
+		"User-Agent":                "OneAPI",
+	}
+}
+
+func getLocale(language string) string {
+	if locale, ok := localeMap[strings.ToLower(language)]; ok {
+		return locale
+	}
+	return language
+}
+
+func (p *AzureSpeechProvider) fastTranscription(baseURL string, request *types.AudioRequest) (*transcriptionResult, *types.OpenAIErrorWithStatusCode) {
+	// 未指定语言时由服务自动识别
+	definition := &FastTranscriptionDefinition{}
+	if request.Language != "" {
+		definition.Locales = []string{getLocale(request.Language)}
+	}
+	definitionJSON, _ := json.Marshal(definition)
+
+	var formBody bytes.Buffer
+	builder := p.Requester.CreateFormBuilder(&formBody)
+	if err := builder.CreateFormFile("audio", request.File); err != nil {
+		return nil, common.ErrorWrapper(err, "create_form_builder_failed", http.StatusInternalServerError)
+	}
+	if err := builder.WriteField("definition", string(definitionJSON)); err != nil {
+		return nil, common.ErrorWrapper(err, "create_form_builder_failed", http.StatusInternalServerError)
+	}
+	if err := builder.Close(); err != nil {
+		return nil, common.ErrorWrapper(err, "create_form_builder_failed", http.StatusInternalServerError)
+	}
+
+	req, err := p.Requester.NewRequest(
+		http.MethodPost,
+		baseURL+fastTranscriptionURL,
+		p.Requester.WithBody(&formBody),
+		p.Requester.WithHeader(p.getSpeechToTextHeaders()),
+		p.Requester.WithContentType(builder.FormDataContentType()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	req.ContentLength = int64(formBody.Len())
+	defer req.Body.Close()
+
+	fastResponse := &FastTranscriptionResponse{}
+	_, errWithCode := p.Requester.SendRequest(req, fastResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	result := &transcriptionResult{
+		duration: float64(fastResponse.DurationMilliseconds) / 1000,
+		segments: make([]types.AudioSegment, 0, len(fastResponse.Phrases)),
+	}
+	texts := make([]string, 0, len(fastResponse.CombinedPhrases))
+	for _, phrase := range fastResponse.CombinedPhrases {
+		texts = append(texts, phrase.Text)
+	}
+	result.text = strings.Join(texts, " ")
+
+	for i, phrase := range fastResponse.Phrases {
+		if result.language == "" {
+			result.language = phrase.Locale
+		}
+		start := float64(phrase.OffsetMilliseconds) / 1000
+		result.segments = append(result.segments, types.AudioSegment{
+			Id:    i,
+			Start: start,
+			End:   start + float64(phrase.DurationMilliseconds)/1000,
+			Text:  " " + phrase.Text,
+		})
+	}
+
+	return result, nil
+}
+
+func (p *AzureSpeechProvider) batchTranscription(baseURL string, request *types.AudioRequest) (*transcriptionResult, *types.OpenAIErrorWithStatusCode) {
+	contentUrl, err := uploadAudio(request)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "upload_audio_failed", http.StatusInternalServerError)
+	}
+
+	locale := getLocale(request.Language)
+	if locale == "" {
+		locale = "en-US"
+	}
+	batchRequest := &BatchTranscriptionRequest{
+		ContentUrls: []string{contentUrl},
+		Locale:      locale,
+		DisplayName: "one-hub-" + utils.GetUUID(),
+		Properties: BatchTranscriptionProperties{
+			TimeToLive: "PT6H",
+		},
+	}
+
+	headers := p.getSpeechToTextHeaders()
+	job := &BatchTranscription{}
+	if errWithCode := p.sendSpeechToTextRequest(http.MethodPost, baseURL+batchTranscriptionURL, headers, batchRequest, job); errWithCode != nil {
+		return nil, errWithCode
+	}
+	if job.Self == "" {
+		return nil, common.StringErrorWrapper("batch transcription not created", "azure_speech_error", http.StatusInternalServerError)
+	}
+	// 转写完成后删除任务，忽略删除失败
+	defer p.sendSpeechToTextRequest(http.MethodDelete, job.Self, headers, nil, nil)
+
+	ctx, cancel := context.WithTimeout(p.Context.Request.Context(), batchTimeout)
+	defer cancel()
+	for job.Status != "Succeeded" {
+		if job.Status == "Failed" {
+			message := "batch transcription failed"
+			if job.Properties.Error != nil {
+				message = job.Properties.Error.Message
+			}
+			return nil, common.StringErrorWrapper(message, "azure_speech_error", http.StatusInternalServerError)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, common.StringErrorWrapper("batch transcription timeout", "azure_speech_error", http.StatusGatewayTimeout)
+		case <-time.After(batchPollInterval):
+		}
+
+		if errWithCode := p.sendSpeechToTextRequest(http.MethodGet, job.Self, headers, nil, job); errWithCode != nil {
+			return nil, errWithCode
+		}
+	}
+
+	files := &BatchTranscriptionFiles{}
+	if errWithCode := p.sendSpeechToTextRequest(http.MethodGet, job.Links.Files, headers, nil, files); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	for _, file := range files.Values {
+		if file.Kind != "Transcription" {
+			continue
+		}
+		// 结果文件使用 SAS 地址，不需要密钥
+		content := &BatchTranscriptionContent{}
+		if errWithCode := p.sendSpeechToTextRequest(http.MethodGet, file.Links.ContentUrl, nil, nil, content); errWithCode != nil {
+			return nil, errWithCode
+		}
+		return content.toResult(locale), nil
+	}
+
+	return nil, common.StringErrorWrapper("batch transcription result not found", "azure_speech_error", http.StatusInternalServerError)
+}
+
+func (c *BatchTranscriptionContent) toResult(locale string) *transcriptionResult {
+	result := &transcriptionResult{
+		language: locale,
+		duration: float64(c.DurationInTicks) / ticksPerSecond,
+		segments: make([]types.AudioSegment, 0, len(c.RecognizedPhrases)),
+	}
+
+	texts := make([]string, 0, len(c.CombinedRecognizedPhrases))
+	for _, phrase := range c.CombinedRecognizedPhrases {
+		texts = append(texts, phrase.Display)
+	}
+	result.text = strings.Join(texts, " ")
+
+	for i, phrase := range c.RecognizedPhrases {
+		if len(phrase.NBest) == 0 {
+			continue
+		}
+		start := float64(phrase.OffsetInTicks) / ticksPerSecond
+		result.segments = append(result.segments, types.AudioSegment{
+			Id:    i,
+			Start: start,
+			End:   start + float64(phrase.DurationInTicks)/ticksPerSecond,
+			Text:  " " + phrase.NBest[0].Display,
+		})
+	}
+
+	return result
+}
+
+func (p *AzureSpeechProvider) sendSpeechToTextRequest(method, url string, headers map[string]string, body any, response any) *types.OpenAIErrorWithStatusCode {
+	req, err := p.Requester.NewRequest(method, url, p.Requester.WithBody(body), p.Requester.WithHeader(headers))
+	if err != nil {
+		return common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+
+	if response == nil {
+		resp, errWithCode := p.Requester.SendRequestRaw(req)
+		if errWithCode != nil {
+			return errWithCode
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	_, errWithCode := p.Requester.SendRequest(req, response, false)
+	return errWithCode
+}
+
+// uploadAudio 批量转写只支持通过 URL 读取音频
+func uploadAudio(request *types.AudioRequest) (string, error) {
+	file, err := request.File.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	url := storage.Upload(data, utils.GetUUID()+filepath.Ext(request.File.Filename))
+	if url == "" {
+		return "", errors.New("batch transcription requires a configured storage to upload audio")
+	}
+	return url, nil
+}
@@ -0,0 +1,75 @@
+package azureSpeech
+
+type AzureSpeechError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	Error   *struct {
+		Code    string `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	} `json:"error,omitempty"`
+}
+
+type FastTranscriptionDefinition struct {
+	Locales []string `json:"locales,omitempty"`
+}
+
+type FastTranscriptionResponse struct {
+	DurationMilliseconds int64 `json:"durationMilliseconds"`
+	CombinedPhrases      []struct {
+		Text string `json:"text"`
+	} `json:"combinedPhrases"`
+	Phrases []struct {
+		OffsetMilliseconds   int64   `json:"offsetMilliseconds"`
+		DurationMilliseconds int64   `json:"durationMilliseconds"`
+		Text                 string  `json:"text"`
+		Locale               string  `json:"locale"`
+		Confidence           float64 `json:"confidence"`
+	} `json:"phrases"`
+}
+
+type BatchTranscriptionRequest struct {
+	ContentUrls []string                     `json:"contentUrls"`
+	Locale      string                       `json:"locale"`
+	DisplayName string                       `json:"displayName"`
+	Properties  BatchTranscriptionProperties `json:"properties"`
+}
+
+type BatchTranscriptionProperties struct {
+	TimeToLive string `json:"timeToLive,omitempty"`
+	Error      *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type BatchTranscription struct {
+	Self   string `json:"self"`
+	Status string `json:"status"`
+	Links  struct {
+		Files string `json:"files"`
+	} `json:"links"`
+	Properties BatchTranscriptionProperties `json:"properties"`
+}
+
+type BatchTranscriptionFiles struct {
+	Values []struct {
+		Kind  string `json:"kind"`
+		Links struct {
+			ContentUrl string `json:"contentUrl"`
+		} `json:"links"`
+	} `json:"values"`
+}
+
+type BatchTranscriptionContent struct {
+	DurationInTicks           int64 `json:"durationInTicks"`
+	CombinedRecognizedPhrases []struct {
+		Display string `json:"display"`
+	} `json:"combinedRecognizedPhrases"`
+	RecognizedPhrases []struct {
+		OffsetInTicks   int64 `json:"offsetInTicks"`
+		DurationInTicks int64 `json:"durationInTicks"`
+		NBest           []struct {
+			Display string `json:"display"`
+		} `json:"nBest"`
+	} `json:"recognizedPhrases"`
+}
@@ -0,0 +1,29 @@
+package azure_v1
+
+import (
+	"fmt"
+	"net/url"
+	"one-api/common/config"
+	"one-api/common/requester"
+	"one-api/types"
+
+	"github.com/gorilla/websocket"
+)
+
+// CreateChatRealtime v1 接口不需要 api-version
+// wss://my-resource.openai.azure.com/openai/v1/realtime?model=gpt-realtime
+func (p *AzureV1Provider) CreateChatRealtime(modelName string) (*websocket.Conn, requester.MessageHandler, *types.OpenAIErrorWithStatusCode) {
+	uri, errWithCode := p.GetSupportedAPIUri(config.RelayModeChatRealtime)
+	if errWithCode != nil {
+		return nil, nil, errWithCode
+	}
+
+	fullRequestURL := fmt.Sprintf("%s/openai%s?model=%s", p.GetWSBaseURL(), uri, url.QueryEscape(modelName))
+
+	wsConn, errWithCode := p.DialRealtime(fullRequestURL)
+	if errWithCode != nil {
+		return nil, nil, errWithCode
+	}
+
+	return wsConn, p.HandleMessage, nil
+}
@@ -0,0 +1,88 @@
+package baidu
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"one-api/common/config"
+	"strings"
+	"time"
+)
+
+// 千帆的 API Key 无法查询余额，需要在插件中配置百度智能云 AccessKey 调用财务接口
+const (
+	balanceHost = "billing.baidubce.com"
+	balancePath = "/v1/finance/cash/balance"
+)
+
+type BalanceResponse struct {
+	AccountId   string  `json:"accountId"`
+	CashBalance float64 `json:"cashBalance"`
+}
+
+func (p *BaiduProvider) Balance() (float64, error) {
+	accessKey := p.GetPluginString("balance", "access_key")
+	secretKey := p.GetPluginString("balance", "secret_key")
+	if accessKey == "" || secretKey == "" {
+		return 0, errors.New("请在插件中配置百度智能云 AccessKey")
+	}
+
+	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		"x-bce-date":    timestamp,
+		"Authorization": signBCERequest("POST", balancePath, timestamp, accessKey, secretKey),
+	}
+
+	req, err := p.Requester.NewRequest("POST", "https://"+balanceHost+balancePath, p.Requester.WithBody(map[string]any{}), p.Requester.WithHeader(headers))
+	if err != nil {
+		return 0, err
+	}
+
+	// 发送请求
+	var info BalanceResponse
+	_, errWithCode := p.Requester.SendRequest(req, &info, false)
+	if errWithCode != nil {
+		return 0, errors.New(errWithCode.OpenAIError.Message)
+	}
+
+	if info.AccountId == "" {
+		return 0, errors.New("获取余额失败")
+	}
+
+	balance := info.CashBalance / config.PaymentUSDRate //RMB TO USD
+	p.Channel.UpdateBalance(balance)
+	return balance, nil
+}
+
+// signBCERequest 百度智能云 bce-auth-v1 签名，签名 host 和 x-bce-date 两个请求头
+func signBCERequest(method, path, timestamp, accessKey, secretKey string) string {
+	authStringPrefix := fmt.Sprintf("bce-auth-v1/%s/%s/%d", accessKey, timestamp, 1800)
+	signingKey := hmacSHA256Hex(secretKey, authStringPrefix)
+
+	canonicalHeaders := strings.Join([]string{
+		"host:" + bceEncode(balanceHost),
+		"x-bce-date:" + bceEncode(timestamp),
+	}, "\n")
+	canonicalURI := strings.ReplaceAll(bceEncode(path), "%2F", "/")
+	canonicalRequest := strings.Join([]string{method, canonicalURI, "", canonicalHeaders}, "\n")
+
+	signature := hmacSHA256Hex(signingKey, canonicalRequest)
+
+	return fmt.Sprintf("%s/host;x-bce-date/%s", authStringPrefix, signature)
+}
+
+func hmacSHA256Hex(key, data string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func bceEncode(value string) string {
+	encoded := url.QueryEscape(value)
+	encoded = strings.ReplaceAll(encoded, "+", "%20")
+	return strings.ReplaceAll(encoded, "%7E", "~")
+}
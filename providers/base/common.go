@@ -1,12 +1,15 @@
 package base
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"one-api/common"
 	"one-api/common/config"
+	"one-api/common/image"
 	"one-api/common/requester"
+	"one-api/common/storage"
 	"one-api/common/utils"
 	"one-api/model"
 	"one-api/types"
@@ -247,3 +250,110 @@ func (p *BaseProvider) GetRawBody() ([]byte, bool) {
 	}
 	return nil, false
 }
+
+// GetVoiceMap 使用渠道插件中的 voice 配置覆盖默认的声音映射，多个参数用 | 隔开
+func (p *BaseProvider) GetVoiceMap(defaultVoiceMapping map[string][]string) map[string][]string {
+	if p.Channel.Plugin == nil {
+		return defaultVoiceMapping
+	}
+
+	customVoiceMapping, ok := p.Channel.Plugin.Data()["voice"]
+	if !ok {
+		return defaultVoiceMapping
+	}
+
+	for key, value := range customVoiceMapping {
+		if _, exists := defaultVoiceMapping[key]; !exists {
+			continue
+		}
+		customVoiceValue, isString := value.(string)
+		if !isString || customVoiceValue == "" {
+			continue
+		}
+		defaultVoiceMapping[key] = strings.Split(customVoiceValue, "|")
+	}
+
+	return defaultVoiceMapping
+}
+
+// GetPluginString 获取渠道插件中的字符串参数，未设置时返回空字符串
+func (p *BaseProvider) GetPluginString(pluginName, param string) string {
+	if p.Channel.Plugin == nil {
+		return ""
+	}
+
+	plugin, ok := p.Channel.Plugin.Data()[pluginName]
+	if !ok {
+		return ""
+	}
+
+	value, _ := plugin[param].(string)
+	return strings.TrimSpace(value)
+}
+
+// 提示词缓存前缀默认的最小 token 数，与 Claude 大部分模型的最小缓存长度一致
+const defaultPromptCacheMinTokens = 1024
+
+// GetPromptCachePolicy 渠道开启 prompt_cache 插件或令牌开启提示词缓存时返回缓存策略，渠道设置优先
+func (p *BaseProvider) GetPromptCachePolicy() *types.PromptCachePolicy {
+	if p.Channel != nil && p.Channel.Plugin != nil {
+		if plugin, ok := p.Channel.Plugin.Data()["prompt_cache"]; ok {
+			if enable, ok := plugin["enable"].(bool); ok && enable {
+				minTokens := utils.String2Int(p.GetPluginString("prompt_cache", "min_tokens"))
+				if minTokens <= 0 {
+					minTokens = defaultPromptCacheMinTokens
+				}
+				return &types.PromptCachePolicy{MinTokens: minTokens}
+			}
+		}
+	}
+
+	if p.Context == nil {
+		return nil
+	}
+	tokenSetting, ok := utils.GetGinValue[*model.TokenSetting](p.Context, "token_setting")
+	if !ok || tokenSetting == nil || !tokenSetting.PromptCache.Enabled {
+		return nil
+	}
+	minTokens := tokenSetting.PromptCache.MinTokens
+	if minTokens <= 0 {
+		minTokens = defaultPromptCacheMinTokens
+	}
+
+	return &types.PromptCachePolicy{MinTokens: minTokens}
+}
+
+// NewImageResponseData 按 response_format 返回图片，url 格式需要上传到存储，未配置存储时返回 b64_json
+// mimeType 为空时根据图片内容识别
+func NewImageResponseData(b64 string, mimeType string, responseFormat string) types.ImageResponseDataInner {
+	if responseFormat == "b64_json" {
+		return types.ImageResponseDataInner{B64JSON: b64}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return types.ImageResponseDataInner{B64JSON: b64}
+	}
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	ext := strings.TrimPrefix(mimeType, "image/")
+	if ext == mimeType {
+		ext = "png"
+	}
+	if url := storage.Upload(data, utils.GetUUID()+"."+ext); url != "" {
+		return types.ImageResponseDataInner{URL: url}
+	}
+
+	return types.ImageResponseDataInner{B64JSON: b64}
+}
+
+// RehostImageURL 上游返回的图片地址通常有时效，下载后按 response_format 重新返回
+func RehostImageURL(url string, responseFormat string) types.ImageResponseDataInner {
+	mimeType, b64, err := image.GetImageFromUrl(url)
+	if err != nil {
+		return types.ImageResponseDataInner{URL: url}
+	}
+
+	return NewImageResponseData(b64, mimeType, responseFormat)
+}
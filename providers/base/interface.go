@@ -133,6 +133,8 @@ type RerankInterface interface {
 type RealtimeInterface interface {
 	ProviderInterface
 	CreateChatRealtime(modelName string) (*websocket.Conn, requester.MessageHandler, *types.OpenAIErrorWithStatusCode)
+	// IsRealtimeModel 根据上游的模型命名判断是否原生支持 realtime
+	IsRealtimeModel(modelName string) bool
 }
 
 type ResponsesInterface interface {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"one-api/common"
 	"one-api/common/requester"
 	"one-api/model"
 	"one-api/providers/base"
@@ -51,6 +52,7 @@ func getConfig() base.ProviderConfig {
 	return base.ProviderConfig{
 		BaseURL:         "https://bedrock-runtime.%s.amazonaws.com",
 		ChatCompletions: "/model/%s/invoke",
+		Embeddings:      "/model/%s/invoke",
 	}
 }
 
@@ -135,3 +137,19 @@ func (p *BedrockProvider) Sign(req *http.Request) error {
 
 	return nil
 }
+
+// sendJSONRequest 签名后发送请求并解析 JSON 响应
+func (p *BedrockProvider) sendJSONRequest(fullRequestURL string, body any, response any) *types.OpenAIErrorWithStatusCode {
+	req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(body), p.Requester.WithHeader(p.GetRequestHeaders()))
+	if err != nil {
+		return common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	defer req.Body.Close()
+
+	if err := p.Sign(req); err != nil {
+		return common.ErrorWrapper(err, "sign_request_failed", http.StatusInternalServerError)
+	}
+
+	_, errWithCode := p.Requester.SendRequest(req, response, false)
+	return errWithCode
+}
@@ -86,3 +86,14 @@ type ChatCompletionConvert func(*types.ChatCompletionRequest) (any, *types.OpenA
 type ChatCompletionResponse func(base.ProviderInterface, *http.Response, *types.ChatCompletionRequest) (*types.ChatCompletionResponse, *types.OpenAIErrorWithStatusCode)
 
 type ChatCompletionStreamResponse func(base.ProviderInterface, *types.ChatCompletionRequest) requester.HandlerPrefix[string]
+
+// GetBaseModelName 将 Bedrock 的模型ID转换为渠道中使用的模型名称，没有映射时原样返回
+func GetBaseModelName(modelId string) string {
+	for baseName, bedrockName := range bedrockMap {
+		if bedrockName == modelId {
+			return baseName
+		}
+	}
+
+	return modelId
+}
@@ -11,6 +11,7 @@ import (
 
 func (p *BedrockProvider) CreateChatCompletion(request *types.ChatCompletionRequest) (*types.ChatCompletionResponse, *types.OpenAIErrorWithStatusCode) {
 	request.OneOtherArg = p.GetOtherArg()
+	request.OnePromptCache = p.GetPromptCachePolicy()
 	// 发送请求
 	response, errWithCode := p.Send(request)
 	if errWithCode != nil {
@@ -24,6 +25,7 @@ func (p *BedrockProvider) CreateChatCompletion(request *types.ChatCompletionRequ
 
 func (p *BedrockProvider) CreateChatCompletionStream(request *types.ChatCompletionRequest) (requester.StreamReaderInterface[string], *types.OpenAIErrorWithStatusCode) {
 	request.OneOtherArg = p.GetOtherArg()
+	request.OnePromptCache = p.GetPromptCachePolicy()
 	// 发送请求
 	response, errWithCode := p.Send(request)
 	if errWithCode != nil {
@@ -0,0 +1,117 @@
+package bedrock
+
+import (
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/common/utils"
+	"one-api/types"
+	"strings"
+)
+
+// Cohere Embed 单次最多 96 条输入
+const cohereEmbeddingBatchSize = 96
+
+type TitanEmbeddingRequest struct {
+	InputText  string `json:"inputText"`
+	Dimensions int    `json:"dimensions,omitempty"`
+	Normalize  bool   `json:"normalize"`
+}
+
+type TitanEmbeddingResponse struct {
+	Embedding           []float64 `json:"embedding"`
+	InputTextTokenCount int       `json:"inputTextTokenCount"`
+}
+
+type CohereEmbeddingRequest struct {
+	Texts     []string `json:"texts"`
+	InputType string   `json:"input_type"`
+	Truncate  string   `json:"truncate,omitempty"`
+}
+
+type CohereEmbeddingResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+func (p *BedrockProvider) CreateEmbeddings(request *types.EmbeddingRequest) (*types.EmbeddingResponse, *types.OpenAIErrorWithStatusCode) {
+	input := request.ParseInput()
+	if len(input) == 0 {
+		return nil, common.StringErrorWrapperLocal("input is required", "invalid_request_error", http.StatusBadRequest)
+	}
+
+	url, errWithCode := p.GetSupportedAPIUri(config.RelayModeEmbeddings)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	fullRequestURL := p.GetFullRequestURL(url, request.Model)
+
+	switch {
+	case strings.Contains(request.Model, "amazon.titan-embed"):
+		return p.createTitanEmbeddings(fullRequestURL, input, request)
+	case strings.Contains(request.Model, "cohere.embed"):
+		return p.createCohereEmbeddings(fullRequestURL, input, request)
+	}
+
+	return nil, common.StringErrorWrapperLocal("bedrock embedding model not supported", "bedrock_err", http.StatusBadRequest)
+}
+
+// Titan 每次请求只支持一条输入
+func (p *BedrockProvider) createTitanEmbeddings(fullRequestURL string, input []string, request *types.EmbeddingRequest) (*types.EmbeddingResponse, *types.OpenAIErrorWithStatusCode) {
+	vectors := make([][]float64, 0, len(input))
+	tokenCount := 0
+	for _, text := range input {
+		titanRequest := &TitanEmbeddingRequest{
+			InputText:  text,
+			Dimensions: request.Dimensions,
+			Normalize:  true,
+		}
+
+		titanResponse := &TitanEmbeddingResponse{}
+		if errWithCode := p.sendJSONRequest(fullRequestURL, titanRequest, titanResponse); errWithCode != nil {
+			return nil, errWithCode
+		}
+
+		vectors = append(vectors, titanResponse.Embedding)
+		tokenCount += titanResponse.InputTextTokenCount
+	}
+
+	usage := p.GetUsage()
+	if tokenCount > 0 {
+		usage.PromptTokens = tokenCount
+	}
+	usage.TotalTokens = usage.PromptTokens
+
+	return request.NewEmbeddingResponse(vectors, usage), nil
+}
+
+func (p *BedrockProvider) createCohereEmbeddings(fullRequestURL string, input []string, request *types.EmbeddingRequest) (*types.EmbeddingResponse, *types.OpenAIErrorWithStatusCode) {
+	inputType := request.InputType
+	if inputType == "" {
+		inputType = "search_document"
+	}
+
+	vectors := make([][]float64, 0, len(input))
+	for _, batch := range utils.ChunkSlice(input, cohereEmbeddingBatchSize) {
+		cohereRequest := &CohereEmbeddingRequest{
+			Texts:     batch,
+			InputType: inputType,
+			Truncate:  "END",
+		}
+
+		cohereResponse := &CohereEmbeddingResponse{}
+		if errWithCode := p.sendJSONRequest(fullRequestURL, cohereRequest, cohereResponse); errWithCode != nil {
+			return nil, errWithCode
+		}
+		if len(cohereResponse.Embeddings) != len(batch) {
+			return nil, common.StringErrorWrapper("embeddings count mismatch", "bedrock_err", http.StatusInternalServerError)
+		}
+
+		vectors = append(vectors, cohereResponse.Embeddings...)
+	}
+
+	// 接口不返回用量，使用本地计算的输入 tokens
+	usage := p.GetUsage()
+	usage.TotalTokens = usage.PromptTokens
+
+	return request.NewEmbeddingResponse(vectors, usage), nil
+}
@@ -0,0 +1,55 @@
+package bedrock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"one-api/providers/bedrock/category"
+	"strings"
+)
+
+type FoundationModelsResponse struct {
+	ModelSummaries []FoundationModelSummary `json:"modelSummaries"`
+}
+
+type FoundationModelSummary struct {
+	ModelId        string `json:"modelId"`
+	ModelLifecycle struct {
+		Status string `json:"status"`
+	} `json:"modelLifecycle"`
+}
+
+// GetModelList 查询 Bedrock 控制面的基础模型列表，只返回仍处于 ACTIVE 状态的模型
+func (p *BedrockProvider) GetModelList() ([]string, error) {
+	if p.Region == "" {
+		return nil, errors.New("invalid bedrock key config")
+	}
+
+	baseURL := strings.Replace(strings.TrimSuffix(p.GetBaseURL(), "/"), "bedrock-runtime", "bedrock", 1)
+	fullRequestURL := fmt.Sprintf(baseURL, p.Region) + "/foundation-models"
+
+	req, err := p.Requester.NewRequest(http.MethodGet, fullRequestURL, p.Requester.WithHeader(p.GetRequestHeaders()))
+	if err != nil {
+		return nil, errors.New("new_request_failed")
+	}
+
+	if err := p.Sign(req); err != nil {
+		return nil, err
+	}
+
+	response := &FoundationModelsResponse{}
+	_, errWithCode := p.Requester.SendRequest(req, response, false)
+	if errWithCode != nil {
+		return nil, errors.New(errWithCode.Message)
+	}
+
+	var modelList []string
+	for _, summary := range response.ModelSummaries {
+		if summary.ModelLifecycle.Status != "" && summary.ModelLifecycle.Status != "ACTIVE" {
+			continue
+		}
+		modelList = append(modelList, category.GetBaseModelName(summary.ModelId))
+	}
+
+	return modelList, nil
+}
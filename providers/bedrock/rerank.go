@@ -0,0 +1,128 @@
+package bedrock
+
+import (
+	"fmt"
+	"net/http"
+	"one-api/common"
+	"one-api/types"
+	"strings"
+)
+
+// Bedrock 的 Rerank 接口位于 bedrock-agent-runtime，支持 amazon.rerank 和 cohere.rerank 模型
+const rerankURL = "https://bedrock-agent-runtime.%s.amazonaws.com/rerank"
+
+type RerankRequest struct {
+	Queries                []RerankQuery          `json:"queries"`
+	Sources                []RerankSource         `json:"sources"`
+	RerankingConfiguration RerankingConfiguration `json:"rerankingConfiguration"`
+}
+
+type RerankQuery struct {
+	Type      string     `json:"type"`
+	TextQuery RerankText `json:"textQuery"`
+}
+
+type RerankText struct {
+	Text string `json:"text"`
+}
+
+type RerankSource struct {
+	Type                 string               `json:"type"`
+	InlineDocumentSource RerankInlineDocument `json:"inlineDocumentSource"`
+}
+
+type RerankInlineDocument struct {
+	Type         string     `json:"type"`
+	TextDocument RerankText `json:"textDocument"`
+}
+
+type RerankingConfiguration struct {
+	Type                          string                        `json:"type"`
+	BedrockRerankingConfiguration BedrockRerankingConfiguration `json:"bedrockRerankingConfiguration"`
+}
+
+type BedrockRerankingConfiguration struct {
+	NumberOfResults    int                      `json:"numberOfResults,omitempty"`
+	ModelConfiguration RerankModelConfiguration `json:"modelConfiguration"`
+}
+
+type RerankModelConfiguration struct {
+	ModelArn string `json:"modelArn"`
+}
+
+type RerankResponse struct {
+	Results []RerankResult `json:"results"`
+}
+
+type RerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevanceScore"`
+}
+
+func (p *BedrockProvider) CreateRerank(request *types.RerankRequest) (*types.RerankResponse, *types.OpenAIErrorWithStatusCode) {
+	documents, err := request.GetDocumentsList()
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "invalid_documents", http.StatusBadRequest)
+	}
+
+	bedrockRequest := &RerankRequest{
+		Queries: []RerankQuery{{
+			Type:      "TEXT",
+			TextQuery: RerankText{Text: request.Query},
+		}},
+		Sources: make([]RerankSource, 0, len(documents)),
+		RerankingConfiguration: RerankingConfiguration{
+			Type: "BEDROCK_RERANKING_MODEL",
+			BedrockRerankingConfiguration: BedrockRerankingConfiguration{
+				NumberOfResults: request.GetTopN(),
+				ModelConfiguration: RerankModelConfiguration{
+					ModelArn: p.getModelArn(request.Model),
+				},
+			},
+		},
+	}
+	for _, document := range documents {
+		bedrockRequest.Sources = append(bedrockRequest.Sources, RerankSource{
+			Type: "INLINE",
+			InlineDocumentSource: RerankInlineDocument{
+				Type:         "TEXT",
+				TextDocument: RerankText{Text: document},
+			},
+		})
+	}
+
+	bedrockResponse := &RerankResponse{}
+	if errWithCode := p.sendJSONRequest(fmt.Sprintf(rerankURL, p.Region), bedrockRequest, bedrockResponse); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	rerank := &types.RerankResponse{
+		Model:   request.Model,
+		Results: make([]types.RerankResult, 0, len(bedrockResponse.Results)),
+	}
+	returnDocuments := request.ShouldReturnDocuments()
+	for _, result := range bedrockResponse.Results {
+		rerankResult := types.RerankResult{
+			Index:          result.Index,
+			RelevanceScore: result.RelevanceScore,
+		}
+		if returnDocuments && result.Index >= 0 && result.Index < len(documents) {
+			rerankResult.Document = &types.RerankResultDocument{Text: documents[result.Index]}
+		}
+		rerank.Results = append(rerank.Results, rerankResult)
+	}
+
+	// 接口不返回用量，使用本地计算的 tokens
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+	rerank.Usage = p.Usage
+
+	return rerank, nil
+}
+
+// 模型填写 ARN 时直接使用，否则按基础模型拼接
+func (p *BedrockProvider) getModelArn(modelName string) string {
+	if strings.HasPrefix(modelName, "arn:") {
+		return modelName
+	}
+	return fmt.Sprintf("arn:aws:bedrock:%s::foundation-model/%s", p.Region, modelName)
+}
@@ -0,0 +1,132 @@
+package claude
+
+import (
+	"encoding/json"
+	"one-api/common"
+	"one-api/types"
+	"strings"
+)
+
+// 单次请求最多允许的缓存断点数
+const maxCacheBreakpoints = 4
+
+// applyPromptCache 按 tools、system、历史对话的顺序累计前缀长度，在达到最小长度的位置插入缓存断点
+// 客户端已经自行设置 cache_control 时不做处理
+func applyPromptCache(request *ClaudeRequest, policy *types.PromptCachePolicy) {
+	if policy == nil || countCacheBreakpoints(request) > 0 {
+		return
+	}
+
+	breakpoints := 0
+	prefixTokens := 0
+
+	if len(request.Tools) > 0 {
+		toolsJson, _ := json.Marshal(request.Tools)
+		prefixTokens += common.CountTokenText(string(toolsJson), request.Model)
+		if prefixTokens >= policy.MinTokens {
+			request.Tools[len(request.Tools)-1].CacheControl = &CacheControl{Type: "ephemeral"}
+			breakpoints++
+		}
+	}
+
+	switch system := request.System.(type) {
+	case string:
+		if system != "" {
+			prefixTokens += common.CountTokenText(system, request.Model)
+			if prefixTokens >= policy.MinTokens {
+				request.System = []MessageContent{{
+					Type:         ContentTypeText,
+					Text:         system,
+					CacheControl: &CacheControl{Type: "ephemeral"},
+				}}
+				breakpoints++
+			}
+		}
+	case nil:
+	default:
+		systemJson, _ := json.Marshal(system)
+		prefixTokens += common.CountTokenText(string(systemJson), request.Model)
+	}
+
+	// 最后一条用户消息之前的对话在后续请求中保持不变，在其末尾插入断点
+	lastUser := -1
+	for index := len(request.Messages) - 1; index >= 0; index-- {
+		if request.Messages[index].Role == types.ChatMessageRoleUser {
+			lastUser = index
+			break
+		}
+	}
+	if lastUser <= 0 || breakpoints >= maxCacheBreakpoints {
+		return
+	}
+
+	for _, message := range request.Messages[:lastUser] {
+		contents, _ := message.Content.([]MessageContent)
+		for _, content := range contents {
+			prefixTokens += estimateContentTokens(&content, request.Model)
+		}
+	}
+	if prefixTokens < policy.MinTokens {
+		return
+	}
+
+	contents, ok := request.Messages[lastUser-1].Content.([]MessageContent)
+	if !ok {
+		return
+	}
+	// 空文本块不能设置缓存断点
+	for index := len(contents) - 1; index >= 0; index-- {
+		if contents[index].Type == ContentTypeText && contents[index].Text == "" {
+			continue
+		}
+		contents[index].CacheControl = &CacheControl{Type: "ephemeral"}
+		return
+	}
+}
+
+// countCacheBreakpoints 统计请求中已有的缓存断点数
+func countCacheBreakpoints(request *ClaudeRequest) int {
+	count := 0
+	for _, tool := range request.Tools {
+		if tool.CacheControl != nil {
+			count++
+		}
+	}
+
+	if _, ok := request.System.(string); !ok && request.System != nil {
+		systemJson, _ := json.Marshal(request.System)
+		count += strings.Count(string(systemJson), `"cache_control":`)
+	}
+
+	for _, message := range request.Messages {
+		contents, _ := message.Content.([]MessageContent)
+		for _, content := range contents {
+			if content.CacheControl != nil {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// estimateContentTokens 估算内容块的 token 数，图片和文档按 base64 长度粗略估算
+func estimateContentTokens(content *MessageContent, model string) int {
+	switch content.Type {
+	case ContentTypeText:
+		return common.CountTokenText(content.Text, model)
+	case ContentTypeToolUes:
+		inputJson, _ := json.Marshal(content.Input)
+		return common.CountTokenText(string(inputJson), model)
+	case ContentTypeToolResult:
+		if text, ok := content.Content.(string); ok {
+			return common.CountTokenText(text, model)
+		}
+	case ContentTypeImage, ContentTypeDocument:
+		if content.Source != nil {
+			return len(content.Source.Data) / 4
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,167 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"one-api/common/config"
+	"one-api/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cacheBreakpoints 返回请求中设置了缓存断点的位置
+func cacheBreakpoints(request *ClaudeRequest) []string {
+	var positions []string
+	for index, tool := range request.Tools {
+		if tool.CacheControl != nil {
+			positions = append(positions, fmt.Sprintf("tools.%d", index))
+		}
+	}
+	if system, ok := request.System.([]MessageContent); ok {
+		for index, content := range system {
+			if content.CacheControl != nil {
+				positions = append(positions, fmt.Sprintf("system.%d", index))
+			}
+		}
+	}
+	for index, message := range request.Messages {
+		contents, _ := message.Content.([]MessageContent)
+		for contentIndex, content := range contents {
+			if content.CacheControl != nil {
+				positions = append(positions, fmt.Sprintf("messages.%d.%d", index, contentIndex))
+			}
+		}
+	}
+	return positions
+}
+
+func textMessage(role string, texts ...string) Message {
+	contents := make([]MessageContent, 0, len(texts))
+	for _, text := range texts {
+		contents = append(contents, MessageContent{Type: ContentTypeText, Text: text})
+	}
+	return Message{Role: role, Content: contents}
+}
+
+func TestApplyPromptCache(t *testing.T) {
+	originDisable := config.DisableTokenEncoders
+	// 按文本长度估算 token 数，结果与编码器无关
+	config.DisableTokenEncoders = true
+	t.Cleanup(func() {
+		config.DisableTokenEncoders = originDisable
+	})
+
+	long := strings.Repeat("a", 1000)
+	short := "hi"
+	policy := &types.PromptCachePolicy{MinTokens: 300}
+
+	tests := []struct {
+		name    string
+		policy  *types.PromptCachePolicy
+		request ClaudeRequest
+		want    []string
+	}{
+		{
+			name:    "no policy",
+			request: ClaudeRequest{System: long, Messages: []Message{textMessage("user", long)}},
+			want:    nil,
+		},
+		{
+			name:    "short prefix",
+			policy:  policy,
+			request: ClaudeRequest{System: short, Messages: []Message{textMessage("user", short), textMessage("assistant", short), textMessage("user", long)}},
+			want:    nil,
+		},
+		{
+			name:    "long tools",
+			policy:  policy,
+			request: ClaudeRequest{Tools: []Tools{{Name: "a"}, {Name: "b", Description: long}}, Messages: []Message{textMessage("user", short)}},
+			want:    []string{"tools.1"},
+		},
+		{
+			name:    "long system",
+			policy:  policy,
+			request: ClaudeRequest{System: long, Messages: []Message{textMessage("user", short)}},
+			want:    []string{"system.0"},
+		},
+		{
+			name:    "tools and system add up",
+			policy:  &types.PromptCachePolicy{MinTokens: 500},
+			request: ClaudeRequest{Tools: []Tools{{Name: "a", Description: long}}, System: long, Messages: []Message{textMessage("user", short)}},
+			want:    []string{"system.0"},
+		},
+		{
+			name:   "history before the last user message",
+			policy: policy,
+			request: ClaudeRequest{Messages: []Message{
+				textMessage("user", long),
+				textMessage("assistant", short, short),
+				textMessage("user", short),
+			}},
+			want: []string{"messages.1.1"},
+		},
+		{
+			name:   "every part of the prefix",
+			policy: policy,
+			request: ClaudeRequest{
+				Tools:  []Tools{{Name: "a", Description: long}},
+				System: long,
+				Messages: []Message{
+					textMessage("user", long),
+					textMessage("assistant", long),
+					textMessage("user", short),
+				},
+			},
+			want: []string{"tools.0", "system.0", "messages.1.0"},
+		},
+		{
+			name:   "empty text block is skipped",
+			policy: policy,
+			request: ClaudeRequest{Messages: []Message{
+				textMessage("user", long),
+				textMessage("assistant", short, ""),
+				textMessage("user", short),
+			}},
+			want: []string{"messages.1.0"},
+		},
+		{
+			name:    "single user message",
+			policy:  policy,
+			request: ClaudeRequest{Messages: []Message{textMessage("user", long)}},
+			want:    nil,
+		},
+		{
+			name:   "client breakpoints are kept",
+			policy: policy,
+			request: ClaudeRequest{
+				System: long,
+				Messages: []Message{
+					{Role: "user", Content: []MessageContent{{Type: ContentTypeText, Text: long, CacheControl: &CacheControl{Type: "ephemeral"}}}},
+					textMessage("assistant", long),
+					textMessage("user", short),
+				},
+			},
+			want: []string{"messages.0.0"},
+		},
+		{
+			name:   "client system breakpoint",
+			policy: policy,
+			request: ClaudeRequest{
+				System:   []MessageContent{{Type: ContentTypeText, Text: long, CacheControl: &CacheControl{Type: "ephemeral"}}},
+				Messages: []Message{textMessage("user", long), textMessage("assistant", long), textMessage("user", short)},
+			},
+			want: []string{"system.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := tt.request
+			request.Model = "claude-sonnet-4"
+			applyPromptCache(&request, tt.policy)
+			assert.Equal(t, tt.want, cacheBreakpoints(&request))
+		})
+	}
+}
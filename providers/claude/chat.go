@@ -1,9 +1,12 @@
 package claude
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"one-api/common"
 	"one-api/common/config"
@@ -12,7 +15,9 @@ import (
 	"one-api/common/utils"
 	"one-api/providers/base"
 	"one-api/types"
+	"path"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
 )
@@ -28,10 +33,16 @@ type ClaudeStreamHandler struct {
 	Request     *types.ChatCompletionRequest
 	StreamTolls int
 	Prefix      string
+
+	// 已输出的文本长度和当前文本块的起始位置，用于计算引用标注的位置
+	contentLength int
+	blockStart    int
+	citations     []Citation
 }
 
 func (p *ClaudeProvider) CreateChatCompletion(request *types.ChatCompletionRequest) (*types.ChatCompletionResponse, *types.OpenAIErrorWithStatusCode) {
 	request.OneOtherArg = p.GetOtherArg()
+	request.OnePromptCache = p.GetPromptCachePolicy()
 	claudeRequest, errWithCode := ConvertFromChatOpenai(request)
 	if errWithCode != nil {
 		return nil, errWithCode
@@ -55,6 +66,7 @@ func (p *ClaudeProvider) CreateChatCompletion(request *types.ChatCompletionReque
 
 func (p *ClaudeProvider) CreateChatCompletionStream(request *types.ChatCompletionRequest) (requester.StreamReaderInterface[string], *types.OpenAIErrorWithStatusCode) {
 	request.OneOtherArg = p.GetOtherArg()
+	request.OnePromptCache = p.GetPromptCachePolicy()
 	claudeRequest, errWithCode := ConvertFromChatOpenai(request)
 	if errWithCode != nil {
 		return nil, errWithCode
@@ -208,6 +220,8 @@ func ConvertFromChatOpenai(request *types.ChatCompletionRequest) (*ClaudeRequest
 		claudeRequest.TopP = nil
 	}
 
+	applyPromptCache(&claudeRequest, request.OnePromptCache)
+
 	return &claudeRequest, nil
 }
 
@@ -335,6 +349,14 @@ func convertMessageContent(msg *types.ChatCompletionMessage) (*Message, error) {
 					Data:      data,
 				},
 			})
+			continue
+		}
+		if part.Type == types.ContentTypeFile {
+			document, err := convertFileContent(part.File)
+			if err != nil {
+				return nil, common.ErrorWrapper(err, "file_invalid", http.StatusBadRequest)
+			}
+			content = append(content, *document)
 		}
 	}
 
@@ -343,6 +365,59 @@ func convertMessageContent(msg *types.ChatCompletionMessage) (*Message, error) {
 	return &message, nil
 }
 
+// convertFileContent 将 OpenAI 的 file 内容转换为 document，PDF 按 base64 传递，文本文件按纯文本传递，并开启引用
+func convertFileContent(file *types.ChatMessageFile) (*MessageContent, error) {
+	if file == nil || file.FileData == "" {
+		return nil, errors.New("file content requires file_data")
+	}
+
+	mimeType := ""
+	data := file.FileData
+	if strings.HasPrefix(data, "data:") {
+		var err error
+		mimeType, data, err = image.ParseBase64File(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = mime.TypeByExtension(path.Ext(file.Filename))
+	}
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	if mimeType == "" {
+		mimeType = "application/pdf"
+	}
+
+	document := &MessageContent{
+		Type:      ContentTypeDocument,
+		Title:     file.Filename,
+		Citations: &CitationsConfig{Enabled: true},
+	}
+
+	switch {
+	case mimeType == "application/pdf":
+		document.Source = &ContentSource{
+			Type:      "base64",
+			MediaType: mimeType,
+			Data:      data,
+		}
+	case strings.HasPrefix(mimeType, "text/"):
+		text, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, err
+		}
+		document.Source = &ContentSource{
+			Type:      "text",
+			MediaType: "text/plain",
+			Data:      string(text),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file type: %s", mimeType)
+	}
+
+	return document, nil
+}
+
 func ConvertToChatOpenai(provider base.ProviderInterface, response *ClaudeResponse, request *types.ChatCompletionRequest) (openaiResponse *types.ChatCompletionResponse, errWithCode *types.OpenAIErrorWithStatusCode) {
 	aiError := errorHandle(response.Error)
 	if aiError != nil {
@@ -353,69 +428,38 @@ func ConvertToChatOpenai(provider base.ProviderInterface, response *ClaudeRespon
 		return
 	}
 
-	choices := make([]types.ChatCompletionChoice, 0)
-	isThinking := false
-	thinkingContent := ""
+	// 多个文本块合并为一条回复，引用按合并后的位置转换为标注
+	choice := types.ChatCompletionChoice{
+		Index: 0,
+		Message: types.ChatCompletionMessage{
+			Role: response.Role,
+		},
+		FinishReason: stopReasonClaude2OpenAI(response.StopReason),
+	}
+	var text strings.Builder
+	var annotations []types.ChatMessageAnnotation
 
 	for _, content := range response.Content {
 		switch content.Type {
 		case ContentTypeToolUes:
-			if len(choices) == 0 {
-				choice := types.ChatCompletionChoice{
-					Index: 0,
-					Message: types.ChatCompletionMessage{
-						Role:    response.Role,
-						Content: "",
-					},
-				}
-				choices = append(choices, choice)
-			}
-
-			index := len(choices) - 1
-			lastChoice := choices[index]
-
-			if lastChoice.Message.ToolCalls == nil {
-				lastChoice.Message.ToolCalls = make([]*types.ChatCompletionToolCalls, 0)
-			}
-			lastChoice.Message.ToolCalls = append(lastChoice.Message.ToolCalls, content.ToOpenAITool())
-			lastChoice.FinishReason = types.FinishReasonToolCalls
-			choices[index] = lastChoice
-		case ContentTypeThinking, ContentTypeRedactedThinking:
-			if content.Type == ContentTypeRedactedThinking {
-				continue
-			}
-			isThinking = true
-			thinkingContent = content.Thinking
+			choice.Message.ToolCalls = append(choice.Message.ToolCalls, content.ToOpenAITool())
+			choice.FinishReason = types.FinishReasonToolCalls
+		case ContentTypeThinking:
+			choice.Message.ReasoningContent += content.Thinking
+		case ContentTypeRedactedThinking:
 		default:
-			choice := types.ChatCompletionChoice{
-				Index: 0,
-				Message: types.ChatCompletionMessage{
-					Role:    response.Role,
-					Content: content.Text,
-				},
-				FinishReason: stopReasonClaude2OpenAI(response.StopReason),
-			}
-
-			if isThinking {
-				choice.Message.ReasoningContent = thinkingContent
-			}
-
-			choices = append(choices, choice)
+			start := utf8.RuneCountInString(text.String())
+			text.WriteString(content.Text)
+			citations := parseCitations(content.Citations)
+			annotations = append(annotations, convertCitations(citations, start, start+utf8.RuneCountInString(content.Text))...)
 		}
-
 	}
 
-	if len(choices) == 0 {
-		// 如果没有内容，则返回一个空的响应
-		choices = append(choices, types.ChatCompletionChoice{
-			Index: 0,
-			Message: types.ChatCompletionMessage{
-				Role:    response.Role,
-				Content: "",
-			},
-			FinishReason: stopReasonClaude2OpenAI(response.StopReason),
-		})
+	choice.Message.Content = text.String()
+	if len(annotations) > 0 {
+		choice.Message.Annotations = annotations
 	}
+	choices := []types.ChatCompletionChoice{choice}
 
 	openaiResponse = &types.ChatCompletionResponse{
 		ID:      response.Id,
@@ -442,6 +486,61 @@ func ConvertToChatOpenai(provider base.ProviderInterface, response *ClaudeRespon
 	return openaiResponse, nil
 }
 
+// parseCitations 解析文本块中的引用
+func parseCitations(raw any) []Citation {
+	if raw == nil {
+		return nil
+	}
+
+	var citations []Citation
+	citationsJson, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(citationsJson, &citations); err != nil {
+		return nil
+	}
+
+	return citations
+}
+
+// convertCitations 将引用转换为 OpenAI 的标注，start、end 为被引用文本在回复中的字符位置
+func convertCitations(citations []Citation, start, end int) []types.ChatMessageAnnotation {
+	annotations := make([]types.ChatMessageAnnotation, 0, len(citations))
+	for _, citation := range citations {
+		if citation.Type == CitationTypeWebSearchResult {
+			annotations = append(annotations, types.ChatMessageAnnotation{
+				Type: "url_citation",
+				URLCitation: &types.ChatURLCitation{
+					StartIndex: start,
+					EndIndex:   end,
+					URL:        citation.Url,
+					Title:      citation.Title,
+					CitedText:  citation.CitedText,
+				},
+			})
+			continue
+		}
+
+		annotations = append(annotations, types.ChatMessageAnnotation{
+			Type: "file_citation",
+			FileCitation: &types.ChatFileCitation{
+				StartIndex:      start,
+				EndIndex:        end,
+				FileIndex:       citation.DocumentIndex,
+				Filename:        citation.DocumentTitle,
+				CitedText:       citation.CitedText,
+				StartPageNumber: citation.StartPageNumber,
+				EndPageNumber:   citation.EndPageNumber,
+				StartCharIndex:  citation.StartCharIndex,
+				EndCharIndex:    citation.EndCharIndex,
+			},
+		})
+	}
+
+	return annotations
+}
+
 // 转换为OpenAI聊天流式请求体
 func (h *ClaudeStreamHandler) HandlerStream(rawLine *[]byte, dataChan chan string, errChan chan error) {
 	// 如果rawLine 前缀不为data:，则直接返回
@@ -477,7 +576,11 @@ func (h *ClaudeStreamHandler) HandlerStream(rawLine *[]byte, dataChan chan strin
 	switch claudeResponse.Type {
 	case "message_start":
 		h.convertToOpenaiStream(&claudeResponse, dataChan)
-		h.Usage.PromptTokens = claudeResponse.Message.Usage.InputTokens
+		// 缓存写入和读取计入输入，并按 UsageExtraCachedWrite、UsageExtraCachedRead 单独计费
+		usage := claudeResponse.Message.Usage
+		h.Usage.PromptTokensDetails.CachedWriteTokens = usage.CacheCreationInputTokens
+		h.Usage.PromptTokensDetails.CachedReadTokens = usage.CacheReadInputTokens
+		h.Usage.PromptTokens = usage.InputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens
 
 	case "message_delta":
 		h.convertToOpenaiStream(&claudeResponse, dataChan)
@@ -485,11 +588,33 @@ func (h *ClaudeStreamHandler) HandlerStream(rawLine *[]byte, dataChan chan strin
 		h.Usage.TotalTokens = h.Usage.PromptTokens + h.Usage.CompletionTokens
 
 	case "content_block_delta":
+		if claudeResponse.Delta.Type == ContentStreamTypeCitationsDelta {
+			if claudeResponse.Delta.Citation != nil {
+				h.citations = append(h.citations, *claudeResponse.Delta.Citation)
+			}
+			return
+		}
 		h.convertToOpenaiStream(&claudeResponse, dataChan)
 		h.Usage.TextBuilder.WriteString(claudeResponse.Delta.Text)
+		h.contentLength += utf8.RuneCountInString(claudeResponse.Delta.Text)
 	case "content_block_start":
+		h.blockStart = h.contentLength
+		h.contentLength += utf8.RuneCountInString(claudeResponse.ContentBlock.Text)
 		h.convertToOpenaiStream(&claudeResponse, dataChan)
 
+	case "content_block_stop":
+		// 文本块结束后再输出引用，此时才能确定被引用文本的位置
+		if len(h.citations) == 0 {
+			return
+		}
+		h.sendChunk(types.ChatCompletionStreamChoice{
+			Index: claudeResponse.Index,
+			Delta: types.ChatCompletionStreamChoiceDelta{
+				Annotations: convertCitations(h.citations, h.blockStart, h.contentLength),
+			},
+		}, dataChan)
+		h.citations = nil
+
 	default:
 		return
 	}
@@ -562,6 +687,11 @@ func (h *ClaudeStreamHandler) convertToOpenaiStream(claudeResponse *ClaudeStream
 	if finishReason != "" {
 		choice.FinishReason = &finishReason
 	}
+
+	h.sendChunk(choice, dataChan)
+}
+
+func (h *ClaudeStreamHandler) sendChunk(choice types.ChatCompletionStreamChoice, dataChan chan string) {
 	chatCompletion := types.ChatCompletionStreamResponse{
 		ID:      fmt.Sprintf("chatcmpl-%s", utils.GetUUID()),
 		Object:  "chat.completion.chunk",
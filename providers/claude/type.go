@@ -13,6 +13,7 @@ const (
 const (
 	ContentTypeText             = "text"
 	ContentTypeImage            = "image"
+	ContentTypeDocument         = "document"
 	ContentTypeToolUes          = "tool_use"
 	ContentTypeToolResult       = "tool_result"
 	ContentTypeThinking         = "thinking"
@@ -21,6 +22,14 @@ const (
 	ContentStreamTypeThinking       = "thinking_delta"
 	ContentStreamTypeSignatureDelta = "signature_delta"
 	ContentStreamTypeInputJsonDelta = "input_json_delta"
+	ContentStreamTypeCitationsDelta = "citations_delta"
+)
+
+const (
+	CitationTypeCharLocation         = "char_location"
+	CitationTypePageLocation         = "page_location"
+	CitationTypeContentBlockLocation = "content_block_location"
+	CitationTypeWebSearchResult      = "web_search_result_location"
 )
 
 type ClaudeError struct {
@@ -99,16 +108,43 @@ type ContentSource struct {
 }
 
 type MessageContent struct {
-	Type         string         `json:"type"`
-	Text         string         `json:"text,omitempty"`
-	Source       *ContentSource `json:"source,omitempty"`
-	Id           string         `json:"id,omitempty"`
-	Name         string         `json:"name,omitempty"`
-	Input        any            `json:"input,omitempty"`
-	Content      any            `json:"content,omitempty"`
-	IsError      *bool          `json:"is_error,omitempty"`
-	ToolUseId    string         `json:"tool_use_id,omitempty"`
-	CacheControl any            `json:"cache_control,omitempty"`
+	Type         string           `json:"type"`
+	Text         string           `json:"text,omitempty"`
+	Source       *ContentSource   `json:"source,omitempty"`
+	Id           string           `json:"id,omitempty"`
+	Name         string           `json:"name,omitempty"`
+	Input        any              `json:"input,omitempty"`
+	Content      any              `json:"content,omitempty"`
+	IsError      *bool            `json:"is_error,omitempty"`
+	ToolUseId    string           `json:"tool_use_id,omitempty"`
+	CacheControl any              `json:"cache_control,omitempty"`
+	Title        string           `json:"title,omitempty"`
+	Citations    *CitationsConfig `json:"citations,omitempty"`
+}
+
+type CacheControl struct {
+	Type string `json:"type"`
+}
+
+type CitationsConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Citation 回复文本中的引用，文档引用和网页搜索引用共用
+type Citation struct {
+	Type            string `json:"type"`
+	CitedText       string `json:"cited_text,omitempty"`
+	DocumentIndex   int    `json:"document_index,omitempty"`
+	DocumentTitle   string `json:"document_title,omitempty"`
+	StartCharIndex  int    `json:"start_char_index,omitempty"`
+	EndCharIndex    int    `json:"end_char_index,omitempty"`
+	StartPageNumber int    `json:"start_page_number,omitempty"`
+	EndPageNumber   int    `json:"end_page_number,omitempty"`
+	StartBlockIndex int    `json:"start_block_index,omitempty"`
+	EndBlockIndex   int    `json:"end_block_index,omitempty"`
+	Url             string `json:"url,omitempty"`
+	Title           string `json:"title,omitempty"`
+	EncryptedIndex  string `json:"encrypted_index,omitempty"`
 }
 
 type Message struct {
@@ -182,14 +218,15 @@ type ClaudeResponse struct {
 }
 
 type Delta struct {
-	Type         string `json:"type,omitempty"`
-	Text         string `json:"text,omitempty"`
-	PartialJson  string `json:"partial_json,omitempty"`
-	StopReason   string `json:"stop_reason,omitempty"`
-	StopSequence string `json:"stop_sequence,omitempty"`
-	Thinking     string `json:"thinking,omitempty"`
-	Signature    string `json:"signature,omitempty"`
-	Citations    any    `json:"citations,omitempty"`
+	Type         string    `json:"type,omitempty"`
+	Text         string    `json:"text,omitempty"`
+	PartialJson  string    `json:"partial_json,omitempty"`
+	StopReason   string    `json:"stop_reason,omitempty"`
+	StopSequence string    `json:"stop_sequence,omitempty"`
+	Thinking     string    `json:"thinking,omitempty"`
+	Signature    string    `json:"signature,omitempty"`
+	Citations    any       `json:"citations,omitempty"`
+	Citation     *Citation `json:"citation,omitempty"`
 }
 
 type ClaudeStreamResponse struct {
@@ -0,0 +1,72 @@
+package cohere
+
+import (
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/common/utils"
+	"one-api/types"
+)
+
+// 单次最多 96 条输入
+const embeddingBatchSize = 96
+
+func (p *CohereProvider) CreateEmbeddings(request *types.EmbeddingRequest) (*types.EmbeddingResponse, *types.OpenAIErrorWithStatusCode) {
+	input := request.ParseInput()
+	if len(input) == 0 {
+		return nil, common.StringErrorWrapperLocal("input is required", "invalid_request_error", http.StatusBadRequest)
+	}
+
+	url, errWithCode := p.GetSupportedAPIUri(config.RelayModeEmbeddings)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	fullRequestURL := p.GetFullRequestURL(url)
+	headers := p.GetRequestHeaders()
+
+	inputType := request.InputType
+	if inputType == "" {
+		inputType = "search_document"
+	}
+
+	vectors := make([][]float64, 0, len(input))
+	tokenCount := 0
+	for _, batch := range utils.ChunkSlice(input, embeddingBatchSize) {
+		embedRequest := &EmbedRequest{
+			Model:           request.Model,
+			Texts:           batch,
+			InputType:       inputType,
+			EmbeddingTypes:  []string{"float"},
+			OutputDimension: request.Dimensions,
+		}
+
+		req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(embedRequest), p.Requester.WithHeader(headers))
+		if err != nil {
+			return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+		}
+
+		embedResponse := &EmbedResponse{}
+		_, errWithCode = p.Requester.SendRequest(req, embedResponse, false)
+		req.Body.Close()
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		if len(embedResponse.Embeddings.Float) != len(batch) {
+			return nil, common.StringErrorWrapper("embeddings count mismatch", "cohere_error", http.StatusInternalServerError)
+		}
+
+		vectors = append(vectors, embedResponse.Embeddings.Float...)
+		if embedResponse.Meta != nil && embedResponse.Meta.BilledUnits != nil {
+			tokenCount += embedResponse.Meta.BilledUnits.InputTokens
+		}
+	}
+
+	usage := p.GetUsage()
+	if tokenCount > 0 {
+		usage.PromptTokens = tokenCount
+	}
+	usage.TotalTokens = usage.PromptTokens
+
+	return request.NewEmbeddingResponse(vectors, usage), nil
+}
@@ -52,7 +52,7 @@ func getRerankRequest(request *types.RerankRequest, documents []string) *RerankR
 		Model:           request.Model,
 		Query:           request.Query,
 		TopN:            request.TopN,
-		ReturnDocuments: true,
+		ReturnDocuments: request.ShouldReturnDocuments(),
 		Documents:       documents,
 	}
 }
@@ -71,9 +71,11 @@ func (p *CohereProvider) ConvertToRerank(response *RerankResponse, request *type
 		rerankResult := types.RerankResult{
 			Index:          result.Index,
 			RelevanceScore: result.RelevanceScore,
-			Document: types.RerankResultDocument{
+		}
+		if result.Document != nil {
+			rerankResult.Document = &types.RerankResultDocument{
 				Text: result.Document.Text,
-			},
+			}
 		}
 		rerank.Results = append(rerank.Results, rerankResult)
 	}
@@ -136,3 +136,22 @@ type RerankResponseResultsItem struct {
 	Index          int                      `json:"index"`
 	RelevanceScore float64                  `json:"relevance_score"`
 }
+
+type EmbedRequest struct {
+	Model           string   `json:"model"`
+	Texts           []string `json:"texts"`
+	InputType       string   `json:"input_type"`
+	EmbeddingTypes  []string `json:"embedding_types,omitempty"`
+	OutputDimension int      `json:"output_dimension,omitempty"`
+	Truncate        string   `json:"truncate,omitempty"`
+}
+
+type EmbedResponse struct {
+	Id         string         `json:"id"`
+	Embeddings EmbedEmbedding `json:"embeddings"`
+	Meta       *Usage         `json:"meta,omitempty"`
+}
+
+type EmbedEmbedding struct {
+	Float [][]float64 `json:"float"`
+}
@@ -0,0 +1,137 @@
+package declarative
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"one-api/common"
+	"one-api/common/requester"
+	"one-api/model"
+	"one-api/providers/base"
+	"one-api/types"
+	"strings"
+)
+
+// 声明式渠道，用于接入接口与 OpenAI 相近、只有鉴权方式、地址或字段名不同的服务，
+// 接口描述填写在渠道的其他参数中，无需编写代码
+type DeclarativeProviderFactory struct{}
+
+// 创建 DeclarativeProvider
+func (f DeclarativeProviderFactory) Create(channel *model.Channel) base.ProviderInterface {
+	definition, err := getChannelDefinition(channel.Id, channel.Other)
+	if err != nil {
+		definition = &Definition{}
+	}
+
+	return &DeclarativeProvider{
+		BaseProvider: base.BaseProvider{
+			Config:    getConfig(definition),
+			Channel:   channel,
+			Requester: requester.NewHTTPRequester(*channel.Proxy, requestErrorHandle(definition.Error)),
+		},
+		Definition:    definition,
+		DefinitionErr: err,
+	}
+}
+
+type DeclarativeProvider struct {
+	base.BaseProvider
+	Definition *Definition
+	// DefinitionErr 接口描述解析失败时的错误，请求时返回给调用方
+	DefinitionErr error
+}
+
+func getConfig(definition *Definition) base.ProviderConfig {
+	config := base.ProviderConfig{
+		BaseURL: definition.BaseURL,
+	}
+	if definition.Chat != nil {
+		config.ChatCompletions = definition.Chat.URL
+	}
+	if definition.Embeddings != nil {
+		config.Embeddings = definition.Embeddings.URL
+	}
+	if definition.Models != nil {
+		config.ModelList = definition.Models.URL
+	}
+
+	return config
+}
+
+// 获取请求头
+func (p *DeclarativeProvider) GetRequestHeaders() (headers map[string]string) {
+	headers = make(map[string]string)
+	p.CommonRequestHeaders(headers)
+
+	for key, value := range p.Definition.Headers {
+		headers[key] = value
+	}
+
+	auth := p.Definition.Auth
+	switch strings.ToLower(auth.Type) {
+	case "none", "query":
+	case "header":
+		headers[auth.Name] = auth.Prefix + p.Channel.Key
+	case "basic":
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(p.Channel.Key))
+	default:
+		headers["Authorization"] = "Bearer " + p.Channel.Key
+	}
+
+	return headers
+}
+
+// getEndpointURL 生成请求地址，替换 {model} 占位符，query 鉴权时在地址中附加密钥
+func (p *DeclarativeProvider) getEndpointURL(endpoint *Endpoint, modelName string, stream bool) string {
+	requestURL := endpoint.URL
+	if stream && endpoint.StreamURL != "" {
+		requestURL = endpoint.StreamURL
+	}
+	requestURL = strings.ReplaceAll(requestURL, "{model}", url.PathEscape(modelName))
+
+	if !strings.HasPrefix(requestURL, "http://") && !strings.HasPrefix(requestURL, "https://") {
+		requestURL = p.GetFullRequestURL(requestURL, modelName)
+	}
+
+	if strings.ToLower(p.Definition.Auth.Type) == "query" {
+		separator := "?"
+		if strings.Contains(requestURL, "?") {
+			separator = "&"
+		}
+		requestURL = fmt.Sprintf("%s%s%s=%s", requestURL, separator, url.QueryEscape(p.Definition.Auth.Name), url.QueryEscape(p.Channel.Key))
+	}
+
+	return requestURL
+}
+
+// getRequest 按接口描述构造请求
+func (p *DeclarativeProvider) getRequest(endpoint *Endpoint, modelName string, request any, stream bool) (*http.Request, *types.OpenAIErrorWithStatusCode) {
+	if p.DefinitionErr != nil {
+		return nil, common.ErrorWrapperLocal(p.DefinitionErr, "invalid_declarative_definition", http.StatusInternalServerError)
+	}
+	if endpoint == nil {
+		return nil, common.StringErrorWrapperLocal("The API interface is not supported", "unsupported_api", http.StatusNotImplemented)
+	}
+
+	method := strings.ToUpper(endpoint.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var body any
+	if method != http.MethodGet {
+		requestBody, err := buildRequestBody(endpoint.Request, request)
+		if err != nil {
+			return nil, common.ErrorWrapperLocal(err, "build_request_failed", http.StatusInternalServerError)
+		}
+		body = requestBody
+	}
+
+	req, err := p.Requester.NewRequest(method, p.getEndpointURL(endpoint, modelName, stream), p.Requester.WithBody(body), p.Requester.WithHeader(p.GetRequestHeaders()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+
+	return req, nil
+}
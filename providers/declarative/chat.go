@@ -0,0 +1,261 @@
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"one-api/common"
+	"one-api/common/requester"
+	"one-api/common/utils"
+	"one-api/types"
+	"strings"
+)
+
+type declarativeStreamHandler struct {
+	Usage    *types.Usage
+	Request  *types.ChatCompletionRequest
+	Stream   *StreamConfig
+	Endpoint *Endpoint
+	Error    ErrorMapping
+
+	Id        string
+	toolIndex int
+}
+
+func (p *DeclarativeProvider) CreateChatCompletion(request *types.ChatCompletionRequest) (*types.ChatCompletionResponse, *types.OpenAIErrorWithStatusCode) {
+	endpoint := p.Definition.Chat
+	req, errWithCode := p.getRequest(endpoint, request.Model, request, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	defer req.Body.Close()
+
+	resp, errWithCode := p.Requester.SendRequestRaw(req)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "read_response_failed", http.StatusInternalServerError)
+	}
+
+	if aiError := extractError(data, p.Definition.Error); aiError != nil {
+		return nil, &types.OpenAIErrorWithStatusCode{
+			OpenAIError: *aiError,
+			StatusCode:  http.StatusBadRequest,
+		}
+	}
+
+	response, err := convertChatResponse(data, endpoint, request)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "decode_response_failed", http.StatusInternalServerError)
+	}
+
+	usage := extractUsage(data, endpoint.Usage)
+	if usage == nil || usage.CompletionTokens == 0 {
+		usage = &types.Usage{
+			PromptTokens:     p.Usage.PromptTokens,
+			CompletionTokens: common.CountTokenText(response.GetContent(), request.Model),
+		}
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	response.Usage = usage
+	*p.Usage = *usage
+
+	return response, nil
+}
+
+func (p *DeclarativeProvider) CreateChatCompletionStream(request *types.ChatCompletionRequest) (requester.StreamReaderInterface[string], *types.OpenAIErrorWithStatusCode) {
+	endpoint := p.Definition.Chat
+	req, errWithCode := p.getRequest(endpoint, request.Model, request, true)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	defer req.Body.Close()
+
+	resp, errWithCode := p.Requester.SendRequestRaw(req)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	stream := endpoint.Stream
+	if stream == nil {
+		stream = &StreamConfig{}
+	}
+
+	chatHandler := &declarativeStreamHandler{
+		Usage:    p.Usage,
+		Request:  request,
+		Stream:   stream,
+		Endpoint: endpoint,
+		Error:    p.Definition.Error,
+		Id:       fmt.Sprintf("chatcmpl-%s", utils.GetUUID()),
+	}
+
+	return requester.RequestStream(p.Requester, resp, chatHandler.handlerStream)
+}
+
+// convertChatResponse 按响应映射转换为 OpenAI 格式，未配置映射时直接按 OpenAI 格式解析
+func convertChatResponse(data []byte, endpoint *Endpoint, request *types.ChatCompletionRequest) (*types.ChatCompletionResponse, error) {
+	if len(endpoint.Response) == 0 {
+		response := &types.ChatCompletionResponse{}
+		if err := json.Unmarshal(data, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	mapping := endpoint.Response
+	message := types.ChatCompletionMessage{
+		Role:             types.ChatMessageRoleAssistant,
+		Content:          lookupString(data, mapping[FieldContent]),
+		ReasoningContent: lookupString(data, mapping[FieldReasoningContent]),
+		ToolCalls:        extractToolCalls(data, mapping[FieldToolCalls]),
+	}
+
+	finishReason := lookupString(data, mapping[FieldFinishReason])
+	if finishReason == "" {
+		finishReason = types.FinishReasonStop
+		if len(message.ToolCalls) > 0 {
+			finishReason = types.FinishReasonToolCalls
+		}
+	}
+
+	id := lookupString(data, mapping[FieldId])
+	if id == "" {
+		id = fmt.Sprintf("chatcmpl-%s", utils.GetUUID())
+	}
+
+	return &types.ChatCompletionResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: utils.GetTimestamp(),
+		Model:   request.Model,
+		Choices: []types.ChatCompletionChoice{{
+			Index:        0,
+			Message:      message,
+			FinishReason: finishReason,
+		}},
+	}, nil
+}
+
+// lookupString 按路径取字符串，路径为空时返回空字符串
+func lookupString(data []byte, path string) string {
+	if path == "" {
+		return ""
+	}
+	return lookup(data, path).String()
+}
+
+// 转换为OpenAI聊天流式请求体
+func (h *declarativeStreamHandler) handlerStream(rawLine *[]byte, dataChan chan string, errChan chan error) {
+	line := *rawLine
+	if strings.ToLower(h.Stream.Format) == StreamFormatNDJSON {
+		if !bytes.HasPrefix(line, []byte("{")) {
+			*rawLine = nil
+			return
+		}
+	} else {
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			*rawLine = nil
+			return
+		}
+		line = bytes.TrimSpace(line[5:])
+	}
+
+	done := h.Stream.Done
+	if done == "" && strings.ToLower(h.Stream.Format) != StreamFormatNDJSON {
+		done = "[DONE]"
+	}
+	if done != "" && string(line) == done {
+		errChan <- io.EOF
+		*rawLine = requester.StreamClosed
+		return
+	}
+
+	if aiError := extractError(line, h.Error); aiError != nil {
+		errChan <- aiError
+		return
+	}
+
+	if usage := extractUsage(line, h.Endpoint.Usage); usage != nil && usage.CompletionTokens > 0 {
+		*h.Usage = *usage
+	}
+
+	if len(h.Stream.Response) == 0 {
+		h.passthroughChunk(line, rawLine, dataChan)
+		return
+	}
+
+	mapping := h.Stream.Response
+	delta := types.ChatCompletionStreamChoiceDelta{
+		Role:             types.ChatMessageRoleAssistant,
+		Content:          lookupString(line, mapping[FieldContent]),
+		ReasoningContent: lookupString(line, mapping[FieldReasoningContent]),
+		ToolCalls:        extractToolCalls(line, mapping[FieldToolCalls]),
+	}
+	// 上游每次返回完整的工具调用，需要按顺序编号
+	for _, toolCall := range delta.ToolCalls {
+		toolCall.Index = h.toolIndex
+		h.toolIndex++
+	}
+
+	finishReason := lookupString(line, mapping[FieldFinishReason])
+	if delta.Content == "" && delta.ReasoningContent == "" && len(delta.ToolCalls) == 0 && finishReason == "" {
+		*rawLine = nil
+		return
+	}
+
+	choice := types.ChatCompletionStreamChoice{
+		Index: 0,
+		Delta: delta,
+	}
+	if finishReason != "" {
+		choice.FinishReason = finishReason
+	}
+
+	if h.Usage.CompletionTokens == 0 {
+		h.Usage.TextBuilder.WriteString(delta.Content)
+	}
+
+	chatCompletion := types.ChatCompletionStreamResponse{
+		ID:      h.Id,
+		Object:  "chat.completion.chunk",
+		Created: utils.GetTimestamp(),
+		Model:   h.Request.Model,
+		Choices: []types.ChatCompletionStreamChoice{choice},
+	}
+
+	responseBody, _ := json.Marshal(chatCompletion)
+	dataChan <- string(responseBody)
+}
+
+// passthroughChunk 上游分块已经是 OpenAI 格式时原样转发
+// include_usage 的最后一个分块没有 choices，只记录用量，由 relay 按客户端的 stream_options 返回
+func (h *declarativeStreamHandler) passthroughChunk(line []byte, rawLine *[]byte, dataChan chan string) {
+	var chunk types.ChatCompletionStreamResponse
+	if err := json.Unmarshal(line, &chunk); err != nil {
+		*rawLine = nil
+		return
+	}
+
+	// 自定义的用量路径可能与 OpenAI 格式的分块不一致
+	if chunk.Usage != nil && chunk.Usage.CompletionTokens > 0 && h.Usage.CompletionTokens == 0 {
+		*h.Usage = *chunk.Usage
+	}
+
+	if len(chunk.Choices) == 0 {
+		*rawLine = nil
+		return
+	}
+
+	if h.Usage.CompletionTokens == 0 {
+		h.Usage.TextBuilder.WriteString(chunk.GetResponseText())
+	}
+
+	dataChan <- string(line)
+}
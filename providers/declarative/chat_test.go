@@ -0,0 +1,105 @@
+package declarative
+
+import (
+	"testing"
+
+	"one-api/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassthroughChunk(t *testing.T) {
+	tests := []struct {
+		name           string
+		line           string
+		wantForward    bool
+		wantCompletion int
+	}{
+		{
+			name:        "content chunk",
+			line:        `{"id":"1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"hi"}}]}`,
+			wantForward: true,
+		},
+		{
+			name:           "usage chunk without choices",
+			line:           `{"id":"1","object":"chat.completion.chunk","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":7,"total_tokens":12}}`,
+			wantCompletion: 7,
+		},
+		{
+			name:           "usage on the last content chunk",
+			line:           `{"id":"1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":3,"total_tokens":8}}`,
+			wantForward:    true,
+			wantCompletion: 3,
+		},
+		{
+			name: "chunk without choices and usage",
+			line: `{"id":"1","object":"chat.completion.chunk","choices":[]}`,
+		},
+		{
+			name: "invalid json",
+			line: `{"id":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &declarativeStreamHandler{
+				Usage:   &types.Usage{PromptTokens: 5},
+				Request: &types.ChatCompletionRequest{Model: "test"},
+			}
+			line := []byte(tt.line)
+			rawLine := line
+			dataChan := make(chan string, 1)
+
+			handler.passthroughChunk(line, &rawLine, dataChan)
+
+			if tt.wantForward {
+				require.Len(t, dataChan, 1)
+				assert.Equal(t, tt.line, <-dataChan)
+			} else {
+				assert.Empty(t, dataChan)
+				assert.Nil(t, rawLine)
+			}
+			assert.Equal(t, tt.wantCompletion, handler.Usage.CompletionTokens)
+		})
+	}
+}
+
+func TestGetChannelDefinition(t *testing.T) {
+	first := "base_url: https://a.example.com\nchat:\n  url: /v1/chat\n"
+	second := "base_url: https://b.example.com\nchat:\n  url: /v1/chat\n"
+	t.Cleanup(func() {
+		definitionCache.Delete(1)
+		definitionCache.Delete(2)
+	})
+
+	definition, err := getChannelDefinition(1, first)
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.example.com", definition.BaseURL)
+
+	cached, err := getChannelDefinition(1, first)
+	require.NoError(t, err)
+	assert.Same(t, definition, cached)
+
+	// 更新后替换同一渠道的缓存
+	updated, err := getChannelDefinition(1, second)
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.example.com", updated.BaseURL)
+
+	_, err = getChannelDefinition(1, "chat: {}")
+	assert.Error(t, err)
+	_, ok := definitionCache.Load(1)
+	assert.False(t, ok)
+
+	other, err := getChannelDefinition(2, first)
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.example.com", other.BaseURL)
+
+	count := 0
+	definitionCache.Range(func(key, value any) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 1, count)
+}
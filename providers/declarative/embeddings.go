@@ -0,0 +1,72 @@
+package declarative
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"one-api/common"
+	"one-api/types"
+)
+
+func (p *DeclarativeProvider) CreateEmbeddings(request *types.EmbeddingRequest) (*types.EmbeddingResponse, *types.OpenAIErrorWithStatusCode) {
+	endpoint := p.Definition.Embeddings
+	req, errWithCode := p.getRequest(endpoint, request.Model, request, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	defer req.Body.Close()
+
+	resp, errWithCode := p.Requester.SendRequestRaw(req)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "read_response_failed", http.StatusInternalServerError)
+	}
+
+	if aiError := extractError(data, p.Definition.Error); aiError != nil {
+		return nil, &types.OpenAIErrorWithStatusCode{
+			OpenAIError: *aiError,
+			StatusCode:  http.StatusBadRequest,
+		}
+	}
+
+	response := &types.EmbeddingResponse{}
+	if path := endpoint.Response[FieldEmbedding]; path != "" {
+		response.Object = "list"
+		response.Model = request.Model
+		// 路径需要指向向量数组，例如 $.data[*].embedding
+		for index, embedding := range lookup(data, path).Array() {
+			var vector any
+			if err := json.Unmarshal([]byte(embedding.Raw), &vector); err != nil {
+				return nil, common.ErrorWrapper(err, "decode_response_failed", http.StatusInternalServerError)
+			}
+			response.Data = append(response.Data, types.Embedding{
+				Object:    "embedding",
+				Embedding: vector,
+				Index:     index,
+			})
+		}
+	} else if err := json.Unmarshal(data, response); err != nil {
+		return nil, common.ErrorWrapper(err, "decode_response_failed", http.StatusInternalServerError)
+	}
+
+	if len(response.Data) == 0 {
+		return nil, common.StringErrorWrapper("no embedding returned", "empty_response", http.StatusInternalServerError)
+	}
+
+	usage := extractUsage(data, endpoint.Usage)
+	if usage == nil {
+		usage = &types.Usage{
+			PromptTokens: p.Usage.PromptTokens,
+			TotalTokens:  p.Usage.PromptTokens,
+		}
+	}
+	response.Usage = usage
+	*p.Usage = *usage
+
+	return response, nil
+}
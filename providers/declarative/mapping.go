@@ -0,0 +1,247 @@
+package declarative
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"one-api/common/requester"
+	"one-api/common/utils"
+	"one-api/types"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+	"gopkg.in/yaml.v3"
+)
+
+// 解析后的定义缓存，key 为渠道 ID，渠道的接口描述变化时替换
+var definitionCache sync.Map
+
+type cachedDefinition struct {
+	raw        string
+	definition *Definition
+}
+
+var indexPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// ParseDefinition 解析渠道中填写的接口描述，以 { 开头时按 JSON 解析，否则按 YAML 解析
+func ParseDefinition(raw string) (*Definition, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("declarative provider definition is empty")
+	}
+	data := []byte(raw)
+	if !strings.HasPrefix(raw, "{") {
+		var node any
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, fmt.Errorf("invalid definition yaml: %w", err)
+		}
+		var err error
+		if data, err = json.Marshal(node); err != nil {
+			return nil, fmt.Errorf("invalid definition yaml: %w", err)
+		}
+	}
+
+	definition := &Definition{}
+	if err := json.Unmarshal(data, definition); err != nil {
+		return nil, fmt.Errorf("invalid definition json: %w", err)
+	}
+
+	if definition.Chat == nil && definition.Embeddings == nil {
+		return nil, errors.New("definition must contain chat or embeddings endpoint")
+	}
+	authType := strings.ToLower(definition.Auth.Type)
+	if (authType == "header" || authType == "query") && definition.Auth.Name == "" {
+		return nil, fmt.Errorf("auth name is required for %s auth", authType)
+	}
+	for name, endpoint := range map[string]*Endpoint{"chat": definition.Chat, "embeddings": definition.Embeddings, "models": definition.Models} {
+		if endpoint != nil && endpoint.URL == "" {
+			return nil, fmt.Errorf("%s endpoint url is required", name)
+		}
+	}
+
+	return definition, nil
+}
+
+// getChannelDefinition 获取渠道的接口描述，同一渠道只保留最新的解析结果
+func getChannelDefinition(channelId int, raw string) (*Definition, error) {
+	if cached, ok := definitionCache.Load(channelId); ok && cached.(*cachedDefinition).raw == raw {
+		return cached.(*cachedDefinition).definition, nil
+	}
+
+	definition, err := ParseDefinition(raw)
+	if err != nil {
+		definitionCache.Delete(channelId)
+		return nil, err
+	}
+
+	definitionCache.Store(channelId, &cachedDefinition{raw: raw, definition: definition})
+	return definition, nil
+}
+
+// toGJSONPath 将 $.a.b[0].c、$.data[*].id 形式的路径转换为 gjson 路径
+func toGJSONPath(path string) string {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.ReplaceAll(path, "[*]", ".#")
+	path = indexPattern.ReplaceAllString(path, ".$1")
+	return strings.TrimPrefix(path, ".")
+}
+
+// lookup 按路径取值，路径为 $ 时返回整个文档
+func lookup(data []byte, path string) gjson.Result {
+	gjsonPath := toGJSONPath(path)
+	if gjsonPath == "" {
+		return gjson.ParseBytes(data)
+	}
+	return gjson.GetBytes(data, gjsonPath)
+}
+
+// buildRequestBody 按请求映射将 OpenAI 请求转换为上游请求体
+func buildRequestBody(mapping *RequestMapping, request any) ([]byte, error) {
+	source, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	if mapping == nil {
+		return source, nil
+	}
+
+	body := []byte("{}")
+	if mapping.Passthrough {
+		body = source
+	}
+
+	// 按路径排序，保证嵌套字段的设置顺序稳定
+	targets := make([]string, 0, len(mapping.Body))
+	for target := range mapping.Body {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	for _, target := range targets {
+		value := mapping.Body[target]
+		if expr, ok := value.(string); ok && strings.HasPrefix(expr, "$") {
+			result := lookup(source, expr)
+			if !result.Exists() {
+				continue
+			}
+			body, err = sjson.SetRawBytes(body, toGJSONPath(target), []byte(result.Raw))
+		} else {
+			body, err = sjson.SetBytes(body, toGJSONPath(target), value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("set request field %s failed: %w", target, err)
+		}
+	}
+
+	for _, path := range mapping.Remove {
+		if body, err = sjson.DeleteBytes(body, toGJSONPath(path)); err != nil {
+			return nil, fmt.Errorf("remove request field %s failed: %w", path, err)
+		}
+	}
+
+	return body, nil
+}
+
+// extractUsage 提取用量，未配置路径时按 OpenAI 格式提取，没有用量时返回 nil
+func extractUsage(data []byte, mapping UsageMapping) *types.Usage {
+	promptPath := mapping.PromptTokens
+	if promptPath == "" {
+		promptPath = "$.usage.prompt_tokens"
+	}
+	completionPath := mapping.CompletionTokens
+	if completionPath == "" {
+		completionPath = "$.usage.completion_tokens"
+	}
+
+	usage := &types.Usage{
+		PromptTokens:     int(lookup(data, promptPath).Int()),
+		CompletionTokens: int(lookup(data, completionPath).Int()),
+	}
+	if mapping.TotalTokens != "" {
+		usage.TotalTokens = int(lookup(data, mapping.TotalTokens).Int())
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	if usage.TotalTokens == 0 {
+		return nil
+	}
+
+	return usage
+}
+
+// extractError 按错误映射提取错误信息，未配置路径时按 OpenAI 格式提取
+func extractError(data []byte, mapping ErrorMapping) *types.OpenAIError {
+	messagePath := mapping.Message
+	if messagePath == "" {
+		messagePath = "$.error.message"
+	}
+	message := lookup(data, messagePath).String()
+	if message == "" {
+		return nil
+	}
+
+	typePath := mapping.Type
+	if typePath == "" {
+		typePath = "$.error.type"
+	}
+	errType := lookup(data, typePath).String()
+	if errType == "" {
+		errType = "declarative_error"
+	}
+
+	codePath := mapping.Code
+	if codePath == "" {
+		codePath = "$.error.code"
+	}
+
+	return &types.OpenAIError{
+		Message: message,
+		Type:    errType,
+		Code:    lookup(data, codePath).Value(),
+	}
+}
+
+// 请求错误处理
+func requestErrorHandle(mapping ErrorMapping) requester.HttpErrorHandler {
+	return func(resp *http.Response) *types.OpenAIError {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil
+		}
+		return extractError(data, mapping)
+	}
+}
+
+// extractToolCalls 将上游的工具调用转换为 OpenAI 格式，上游需要返回与 OpenAI 相同结构的数组
+func extractToolCalls(data []byte, path string) []*types.ChatCompletionToolCalls {
+	if path == "" {
+		return nil
+	}
+	result := lookup(data, path)
+	if !result.IsArray() {
+		return nil
+	}
+
+	var toolCalls []*types.ChatCompletionToolCalls
+	if err := json.Unmarshal([]byte(result.Raw), &toolCalls); err != nil {
+		return nil
+	}
+	for index, toolCall := range toolCalls {
+		if toolCall.Id == "" {
+			toolCall.Id = fmt.Sprintf("call_%s", utils.GetUUID())
+		}
+		if toolCall.Type == "" {
+			toolCall.Type = types.ChatMessageRoleFunction
+		}
+		toolCall.Index = index
+	}
+
+	return toolCalls
+}
@@ -0,0 +1,52 @@
+package declarative
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// GetModelList 按接口描述中的 models 获取模型列表，未配置映射时按 OpenAI 格式提取 $.data[*].id
+func (p *DeclarativeProvider) GetModelList() ([]string, error) {
+	if p.DefinitionErr != nil {
+		return nil, p.DefinitionErr
+	}
+	if p.Definition.Models == nil {
+		return nil, errors.New("models endpoint is not defined")
+	}
+
+	endpoint := *p.Definition.Models
+	if endpoint.Method == "" {
+		endpoint.Method = http.MethodGet
+	}
+
+	req, errWithCode := p.getRequest(&endpoint, "", nil, false)
+	if errWithCode != nil {
+		return nil, errors.New(errWithCode.Message)
+	}
+
+	resp, errWithCode := p.Requester.SendRequestRaw(req)
+	if errWithCode != nil {
+		return nil, errors.New(errWithCode.Message)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	path := endpoint.Response[FieldId]
+	if path == "" {
+		path = "$.data[*].id"
+	}
+
+	var modelList []string
+	for _, id := range lookup(data, path).Array() {
+		if id.String() != "" {
+			modelList = append(modelList, id.String())
+		}
+	}
+
+	return modelList, nil
+}
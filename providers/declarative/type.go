@@ -0,0 +1,84 @@
+package declarative
+
+// Definition 声明式渠道的接口描述，填写在渠道的其他参数中，支持 JSON 和 YAML
+type Definition struct {
+	// BaseURL 渠道未填写 API 地址时使用
+	BaseURL    string            `json:"base_url"`
+	Auth       AuthConfig        `json:"auth"`
+	Headers    map[string]string `json:"headers"`
+	Chat       *Endpoint         `json:"chat"`
+	Embeddings *Endpoint         `json:"embeddings"`
+	Models     *Endpoint         `json:"models"`
+	Error      ErrorMapping      `json:"error"`
+}
+
+// AuthConfig 鉴权方式，密钥使用渠道的密钥
+type AuthConfig struct {
+	// Type 支持 bearer（默认）、header、query、basic、none
+	Type string `json:"type"`
+	// Name header 或 query 鉴权时的参数名
+	Name string `json:"name"`
+	// Prefix header 鉴权时密钥的前缀
+	Prefix string `json:"prefix"`
+}
+
+type Endpoint struct {
+	// URL 请求地址，可以使用 {model} 占位符，以 / 开头时拼接在 API 地址之后
+	URL string `json:"url"`
+	// StreamURL 流式请求的地址，为空时使用 URL
+	StreamURL string `json:"stream_url"`
+	// Method 请求方法，默认 POST，模型列表默认 GET
+	Method  string          `json:"method"`
+	Request *RequestMapping `json:"request"`
+	// Response OpenAI 字段到上游响应路径的映射，为空时按 OpenAI 格式解析
+	Response map[string]string `json:"response"`
+	Stream   *StreamConfig     `json:"stream"`
+	Usage    UsageMapping      `json:"usage"`
+}
+
+// RequestMapping 请求体的构造方式，未配置时原样发送 OpenAI 请求
+type RequestMapping struct {
+	// Passthrough 以 OpenAI 请求为基础，再设置 Body 中的字段
+	Passthrough bool `json:"passthrough"`
+	// Body 上游字段路径到取值的映射，以 $ 开头的字符串从 OpenAI 请求中取值，其余作为常量
+	Body map[string]any `json:"body"`
+	// Remove 需要删除的字段路径
+	Remove []string `json:"remove"`
+}
+
+type StreamConfig struct {
+	// Format 支持 sse（默认）、ndjson
+	Format string `json:"format"`
+	// Done 结束标记，sse 默认为 [DONE]
+	Done string `json:"done"`
+	// Response 流式分块的字段映射，为空时按 OpenAI 格式透传
+	Response map[string]string `json:"response"`
+}
+
+type UsageMapping struct {
+	PromptTokens     string `json:"prompt_tokens"`
+	CompletionTokens string `json:"completion_tokens"`
+	TotalTokens      string `json:"total_tokens"`
+}
+
+// ErrorMapping 从错误响应中提取错误信息的路径，未配置时按 OpenAI 格式提取
+type ErrorMapping struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+}
+
+// 响应映射中支持的字段
+const (
+	FieldId               = "id"
+	FieldContent          = "content"
+	FieldReasoningContent = "reasoning_content"
+	FieldFinishReason     = "finish_reason"
+	FieldToolCalls        = "tool_calls"
+	FieldEmbedding        = "embedding"
+)
+
+const (
+	StreamFormatSSE    = "sse"
+	StreamFormatNDJSON = "ndjson"
+)
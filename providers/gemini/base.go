@@ -67,6 +67,8 @@ func getConfig(version string) base.ProviderConfig {
 		ChatCompletions:   fmt.Sprintf("/%s/chat/completions", version),
 		ModelList:         "/models",
 		ImagesGenerations: "1",
+		Embeddings:        fmt.Sprintf("/%s/embeddings", version),
+		ChatRealtime:      fmt.Sprintf("/ws/google.ai.generativelanguage.%s.GenerativeService.BidiGenerateContent", version),
 	}
 }
 
@@ -0,0 +1,80 @@
+package gemini
+
+import (
+	"net/http"
+	"one-api/common"
+	"one-api/common/utils"
+	"one-api/types"
+)
+
+// 单次 batchEmbedContents 最多 100 条输入
+const embeddingBatchSize = 100
+
+func (p *GeminiProvider) CreateEmbeddings(request *types.EmbeddingRequest) (*types.EmbeddingResponse, *types.OpenAIErrorWithStatusCode) {
+	if p.UseOpenaiAPI {
+		return p.OpenAIProvider.CreateEmbeddings(request)
+	}
+
+	input := request.ParseInput()
+	if len(input) == 0 {
+		return nil, common.StringErrorWrapperLocal("input is required", "invalid_request_error", http.StatusBadRequest)
+	}
+
+	fullRequestURL := p.GetFullRequestURL("batchEmbedContents", request.Model)
+	headers := p.GetRequestHeaders()
+
+	vectors := make([][]float64, 0, len(input))
+	for _, batch := range utils.ChunkSlice(input, embeddingBatchSize) {
+		geminiRequest := &GeminiEmbeddingRequest{
+			Requests: make([]GeminiEmbeddingContentRequest, 0, len(batch)),
+		}
+		for _, text := range batch {
+			geminiRequest.Requests = append(geminiRequest.Requests, GeminiEmbeddingContentRequest{
+				Model:                "models/" + request.Model,
+				Content:              GeminiChatContent{Parts: []GeminiPart{{Text: text}}},
+				TaskType:             ConvertEmbeddingTaskType(request.InputType),
+				OutputDimensionality: request.Dimensions,
+			})
+		}
+
+		req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(geminiRequest), p.Requester.WithHeader(headers))
+		if err != nil {
+			return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+		}
+
+		geminiResponse := &GeminiEmbeddingResponse{}
+		_, errWithCode := p.Requester.SendRequest(req, geminiResponse, false)
+		req.Body.Close()
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		if len(geminiResponse.Embeddings) != len(batch) {
+			return nil, common.StringErrorWrapper("embeddings count mismatch", "gemini_error", http.StatusInternalServerError)
+		}
+
+		for _, embedding := range geminiResponse.Embeddings {
+			vectors = append(vectors, embedding.Values)
+		}
+	}
+
+	// 接口不返回用量，使用本地计算的输入 tokens
+	usage := p.GetUsage()
+	usage.TotalTokens = usage.PromptTokens
+
+	return request.NewEmbeddingResponse(vectors, usage), nil
+}
+
+// ConvertEmbeddingTaskType 将 input_type 转换为 Gemini/Vertex AI 的 task_type
+func ConvertEmbeddingTaskType(inputType string) string {
+	switch inputType {
+	case "search_document":
+		return "RETRIEVAL_DOCUMENT"
+	case "search_query":
+		return "RETRIEVAL_QUERY"
+	case "classification":
+		return "CLASSIFICATION"
+	case "clustering":
+		return "CLUSTERING"
+	}
+	return ""
+}
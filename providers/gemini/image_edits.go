@@ -0,0 +1,97 @@
+package gemini
+
+import (
+	"encoding/base64"
+	"net/http"
+	"one-api/common"
+	"one-api/common/image"
+	"one-api/common/utils"
+	"one-api/providers/base"
+	"one-api/types"
+	"strings"
+)
+
+const maskEditPrompt = "The last image is a mask. Only modify the white area of the mask in the first image and keep everything else unchanged."
+
+// CreateImageEdits 使用支持图片输出的 Gemini 模型编辑图片，Gemini 不支持蒙版，通过提示词说明蒙版的含义
+func (p *GeminiProvider) CreateImageEdits(request *types.ImageEditRequest) (*types.ImageResponse, *types.OpenAIErrorWithStatusCode) {
+	if strings.HasPrefix(request.Model, "imagen") {
+		return nil, common.StringErrorWrapperLocal("imagen models do not support image edits", "invalid_request_error", http.StatusBadRequest)
+	}
+
+	images := request.GetImages()
+	if len(images) == 0 {
+		return nil, common.StringErrorWrapperLocal("image is required", "invalid_request_error", http.StatusBadRequest)
+	}
+
+	parts := make([]GeminiPart, 0, len(images)+2)
+	var firstImage []byte
+	for _, file := range images {
+		data, mimeType, err := image.ReadImageFile(file)
+		if err != nil {
+			return nil, common.ErrorWrapperLocal(err, "read_image_failed", http.StatusBadRequest)
+		}
+		if firstImage == nil {
+			firstImage = data
+		}
+		parts = append(parts, GeminiPart{InlineData: &GeminiInlineData{MimeType: mimeType, Data: base64.StdEncoding.EncodeToString(data)}})
+	}
+
+	prompt := request.Prompt
+	mask, err := image.GetEditMask(firstImage, request.Mask)
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "invalid_mask", http.StatusBadRequest)
+	}
+	if mask != nil {
+		parts = append(parts, GeminiPart{InlineData: &GeminiInlineData{MimeType: "image/png", Data: base64.StdEncoding.EncodeToString(mask)}})
+		prompt = maskEditPrompt + "\n" + prompt
+	}
+	parts = append([]GeminiPart{{Text: prompt}}, parts...)
+
+	geminiRequest := &GeminiChatRequest{
+		Contents: []GeminiChatContent{{
+			Role:  "user",
+			Parts: parts,
+		}},
+		GenerationConfig: GeminiChatGenerationConfig{
+			ResponseModalities: []string{"TEXT", "IMAGE"},
+		},
+	}
+
+	fullRequestURL := p.GetFullRequestURL("generateContent", request.Model)
+	req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(geminiRequest), p.Requester.WithHeader(p.GetRequestHeaders()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	defer req.Body.Close()
+
+	geminiResponse := &GeminiChatResponse{}
+	_, errWithCode := p.Requester.SendRequest(req, geminiResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	openaiResponse := &types.ImageResponse{
+		Created: utils.GetTimestamp(),
+	}
+	for _, candidate := range geminiResponse.Candidates {
+		for _, part := range candidate.Content.Parts {
+			if part.InlineData == nil || !strings.HasPrefix(part.InlineData.MimeType, "image/") {
+				continue
+			}
+			openaiResponse.Data = append(openaiResponse.Data, base.NewImageResponseData(part.InlineData.Data, part.InlineData.MimeType, request.ResponseFormat))
+		}
+	}
+
+	if len(openaiResponse.Data) == 0 {
+		return nil, common.StringErrorWrapper("no image generated", "no_image_generated", http.StatusInternalServerError)
+	}
+
+	if geminiResponse.UsageMetadata != nil {
+		*p.Usage = ConvertOpenAIUsage(geminiResponse.UsageMetadata)
+	} else {
+		p.Usage.TotalTokens = p.Usage.PromptTokens
+	}
+
+	return openaiResponse, nil
+}
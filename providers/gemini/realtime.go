@@ -0,0 +1,364 @@
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/common/requester"
+	"one-api/types"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Live API 输入输出均为 24kHz 的 16 位 PCM，与 OpenAI 的 pcm16 一致
+const liveAudioMimeType = "audio/pcm;rate=24000"
+
+// IsRealtimeModel Live API 只支持 live 和 native-audio 系列模型
+func (p *GeminiProvider) IsRealtimeModel(modelName string) bool {
+	modelName = strings.ToLower(modelName)
+	return strings.Contains(modelName, "live") || strings.Contains(modelName, "native-audio")
+}
+
+// CreateChatRealtime 连接 Gemini Live API，并在 OpenAI realtime 事件和 Live 消息之间转换
+// Live 的会话配置只能在 setup 中设置，连接后立即发送，之后 session.update 中的 instructions 作为上下文发送
+func (p *GeminiProvider) CreateChatRealtime(modelName string) (*websocket.Conn, requester.MessageHandler, *types.OpenAIErrorWithStatusCode) {
+	uri, errWithCode := p.GetSupportedAPIUri(config.RelayModeChatRealtime)
+	if errWithCode != nil {
+		return nil, nil, errWithCode
+	}
+
+	httpHeaders := make(http.Header)
+	httpHeaders.Set("x-goog-api-key", p.Channel.Key)
+
+	wsRequester := requester.NewWSRequester(*p.Channel.Proxy)
+	wsConn, err := wsRequester.NewRequest(p.GetWSBaseURL()+uri, httpHeaders)
+	if err != nil {
+		return nil, nil, common.ErrorWrapper(err, "ws_request_failed", http.StatusInternalServerError)
+	}
+
+	setup := &GeminiLiveClientMessage{
+		Setup: &GeminiLiveSetup{
+			Model: "models/" + modelName,
+			GenerationConfig: &GeminiChatGenerationConfig{
+				ResponseModalities: []string{"AUDIO"},
+			},
+			InputAudioTranscription:  &struct{}{},
+			OutputAudioTranscription: &struct{}{},
+		},
+	}
+	if err := wsConn.WriteJSON(setup); err != nil {
+		wsConn.Close()
+		return nil, nil, common.ErrorWrapper(err, "ws_request_failed", http.StatusInternalServerError)
+	}
+
+	session := &geminiRealtimeSession{
+		session: types.RealtimeSession{
+			Id:                types.NewRealtimeId("sess"),
+			Object:            "realtime.session",
+			Model:             modelName,
+			Modalities:        []string{"text", "audio"},
+			InputAudioFormat:  "pcm16",
+			OutputAudioFormat: "pcm16",
+		},
+	}
+
+	return wsConn, session.HandleMessage, nil
+}
+
+// geminiRealtimeSession 保存单个连接的转换状态，两个方向的消息在不同的协程中处理
+type geminiRealtimeSession struct {
+	sync.Mutex
+	session      types.RealtimeSession
+	pendingTurns []GeminiChatContent
+
+	inputItemId string
+	response    *types.ResponseEvent
+	item        *types.RealtimeItem
+	partType    string
+	content     strings.Builder
+	usage       *GeminiLiveUsageMetadata
+	turnDone    bool
+}
+
+func (s *geminiRealtimeSession) HandleMessage(source requester.MessageSource, messageType int, message []byte) (bool, *types.UsageEvent, []requester.ProxyMessage, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if source == requester.UserMessage {
+		return s.handleUserMessage(message)
+	}
+
+	return s.handleSupplierMessage(message)
+}
+
+func (s *geminiRealtimeSession) handleUserMessage(message []byte) (bool, *types.UsageEvent, []requester.ProxyMessage, error) {
+	event := &types.RealtimeEvent{}
+	if err := json.Unmarshal(message, event); err != nil {
+		return true, nil, nil, types.NewErrorEvent("", "json_unmarshal_failed", "invalid_event", err.Error())
+	}
+
+	messages := make([]requester.ProxyMessage, 0, 2)
+	switch event.Type {
+	case types.EventTypeSessionUpdate:
+		if event.Session != nil && len(event.Session.Tools) > 0 {
+			messages = append(messages, replyError(event.EventId, "tools are not supported by Gemini Live sessions"))
+			event.Session.Tools = nil
+		}
+		if event.Session != nil && event.Session.Instructions != "" && event.Session.Instructions != s.session.Instructions {
+			messages = append(messages, sendLiveMessage(&GeminiLiveClientMessage{
+				ClientContent: &GeminiLiveClientContent{
+					Turns: []GeminiChatContent{{Role: "user", Parts: []GeminiPart{{Text: event.Session.Instructions}}}},
+				},
+			}))
+		}
+		s.session.Merge(event.Session)
+		updated := types.NewRealtimeEvent(types.EventTypeSessionUpdated)
+		updated.Session = &s.session
+		messages = append(messages, requester.ProxyMessage{Data: updated.Marshal(), Reply: true})
+
+	case types.EventTypeInputAudioBufferAppend:
+		messages = append(messages, sendLiveMessage(&GeminiLiveClientMessage{
+			RealtimeInput: &GeminiLiveRealtimeInput{
+				Audio: &GeminiInlineData{MimeType: liveAudioMimeType, Data: event.Audio},
+			},
+		}))
+
+	case types.EventTypeInputAudioBufferCommit:
+		messages = append(messages, sendLiveMessage(&GeminiLiveClientMessage{
+			RealtimeInput: &GeminiLiveRealtimeInput{AudioStreamEnd: true},
+		}))
+		committed := types.NewRealtimeEvent(types.EventTypeInputAudioBufferCommitted)
+		committed.ItemId = s.getInputItemId()
+		messages = append(messages, requester.ProxyMessage{Data: committed.Marshal(), Reply: true})
+
+	case types.EventTypeInputAudioBufferClear:
+		cleared := types.NewRealtimeEvent(types.EventTypeInputAudioBufferCleared)
+		messages = append(messages, requester.ProxyMessage{Data: cleared.Marshal(), Reply: true})
+
+	case types.EventTypeConversationItemCreate:
+		item := event.Item
+		if item == nil || item.Type != types.RealtimeItemTypeMessage {
+			messages = append(messages, replyError(event.EventId, "only message items are supported by Gemini Live sessions"))
+			break
+		}
+		role := "user"
+		if item.Role == "assistant" {
+			role = "model"
+		}
+		s.pendingTurns = append(s.pendingTurns, GeminiChatContent{Role: role, Parts: []GeminiPart{{Text: item.GetText()}}})
+
+		if item.Id == "" {
+			item.Id = types.NewRealtimeId("item")
+		}
+		item.Object = "realtime.item"
+		item.Status = "completed"
+		created := types.NewRealtimeEvent(types.EventTypeConversationItemCreated)
+		created.PreviousItemId = event.PreviousItemId
+		created.Item = item
+		messages = append(messages, requester.ProxyMessage{Data: created.Marshal(), Reply: true})
+
+	case types.EventTypeResponseCreate:
+		// 语音输入由 Live 的语音检测自动触发回复，只有文本输入需要结束当前轮次
+		if len(s.pendingTurns) > 0 {
+			messages = append(messages, sendLiveMessage(&GeminiLiveClientMessage{
+				ClientContent: &GeminiLiveClientContent{Turns: s.pendingTurns, TurnComplete: true},
+			}))
+			s.pendingTurns = nil
+		}
+	}
+
+	return true, nil, messages, nil
+}
+
+func (s *geminiRealtimeSession) handleSupplierMessage(message []byte) (bool, *types.UsageEvent, []requester.ProxyMessage, error) {
+	liveMessage := &GeminiLiveServerMessage{}
+	if err := json.Unmarshal(message, liveMessage); err != nil {
+		return true, nil, nil, types.NewErrorEvent("", "json_unmarshal_failed", "invalid_event", err.Error())
+	}
+
+	events := make([]*types.RealtimeEvent, 0, 4)
+	if liveMessage.SetupComplete != nil {
+		created := types.NewRealtimeEvent(types.EventTypeSessionCreated)
+		created.Session = &s.session
+		events = append(events, created)
+	}
+
+	if liveMessage.UsageMetadata != nil {
+		s.usage = liveMessage.UsageMetadata
+	}
+
+	if content := liveMessage.ServerContent; content != nil {
+		if content.InputTranscription != nil && content.InputTranscription.Text != "" {
+			delta := types.NewRealtimeEvent(types.EventTypeInputTranscriptionDelta)
+			delta.ItemId = s.getInputItemId()
+			delta.Delta = content.InputTranscription.Text
+			events = append(events, delta)
+		}
+
+		if content.Interrupted {
+			speech := types.NewRealtimeEvent(types.EventTypeInputAudioBufferSpeechStart)
+			speech.ItemId = s.getInputItemId()
+			events = append(events, speech)
+		}
+
+		if content.ModelTurn != nil {
+			for _, part := range content.ModelTurn.Parts {
+				if part.InlineData != nil && strings.HasPrefix(part.InlineData.MimeType, "audio/") {
+					events = s.startResponse(events, "audio")
+					delta := s.newResponseEvent(types.EventTypeResponseAudioDelta)
+					delta.Delta = part.InlineData.Data
+					events = append(events, delta)
+				} else if part.Text != "" && !part.Thought {
+					events = s.startResponse(events, "text")
+					if s.partType == "text" {
+						s.content.WriteString(part.Text)
+						delta := s.newResponseEvent(types.EventTypeResponseTextDelta)
+						delta.Delta = part.Text
+						events = append(events, delta)
+					}
+				}
+			}
+		}
+
+		if content.OutputTranscription != nil && content.OutputTranscription.Text != "" {
+			events = s.startResponse(events, "audio")
+			if s.partType == "audio" {
+				s.content.WriteString(content.OutputTranscription.Text)
+				delta := s.newResponseEvent(types.EventTypeResponseTranscriptDelta)
+				delta.Delta = content.OutputTranscription.Text
+				events = append(events, delta)
+			}
+		}
+
+		if content.TurnComplete {
+			events = s.finishResponse(events)
+			s.turnDone = true
+		}
+	}
+
+	// 用量可能和 turnComplete 一起返回，也可能单独返回，每轮只计费一次
+	var usage *types.UsageEvent
+	if s.turnDone && s.usage != nil {
+		usage = s.usage.ToUsageEvent()
+		s.usage = nil
+		s.turnDone = false
+		if len(events) > 0 && events[len(events)-1].Type == types.EventTypeResponseDone {
+			events[len(events)-1].Response.Usage = usage
+		}
+	}
+
+	messages := make([]requester.ProxyMessage, 0, len(events))
+	for _, event := range events {
+		messages = append(messages, requester.ProxyMessage{Data: event.Marshal()})
+	}
+
+	return true, usage, messages, nil
+}
+
+func (s *geminiRealtimeSession) getInputItemId() string {
+	if s.inputItemId == "" {
+		s.inputItemId = types.NewRealtimeId("item")
+	}
+	return s.inputItemId
+}
+
+func (s *geminiRealtimeSession) newResponseEvent(eventType string) *types.RealtimeEvent {
+	event := types.NewRealtimeEvent(eventType)
+	event.ResponseId = s.response.ID
+	event.ItemId = s.item.Id
+	return event
+}
+
+// startResponse 收到第一段输出时补发 response.created 等事件
+func (s *geminiRealtimeSession) startResponse(events []*types.RealtimeEvent, partType string) []*types.RealtimeEvent {
+	if s.response != nil {
+		return events
+	}
+
+	s.turnDone = false
+	s.inputItemId = ""
+	s.partType = partType
+	s.content.Reset()
+	s.response = &types.ResponseEvent{
+		ID:     types.NewRealtimeId("resp"),
+		Object: "realtime.response",
+		Status: "in_progress",
+	}
+	s.item = &types.RealtimeItem{
+		Id:     types.NewRealtimeId("item"),
+		Object: "realtime.item",
+		Type:   types.RealtimeItemTypeMessage,
+		Status: "in_progress",
+		Role:   "assistant",
+	}
+
+	created := types.NewRealtimeEvent(types.EventTypeResponseCreated)
+	created.Response = s.response
+	itemAdded := types.NewRealtimeEvent(types.EventTypeResponseOutputItemAdded)
+	itemAdded.ResponseId = s.response.ID
+	itemAdded.Item = s.item
+	partAdded := s.newResponseEvent(types.EventTypeResponseContentPartAdded)
+	partAdded.Part = &types.RealtimeContent{Type: partType}
+
+	return append(events, created, itemAdded, partAdded)
+}
+
+func (s *geminiRealtimeSession) finishResponse(events []*types.RealtimeEvent) []*types.RealtimeEvent {
+	if s.response == nil {
+		return events
+	}
+
+	part := types.RealtimeContent{Type: s.partType}
+	if s.partType == "audio" {
+		events = append(events, s.newResponseEvent(types.EventTypeResponseAudioDone))
+		transcriptDone := s.newResponseEvent(types.EventTypeResponseTranscriptDone)
+		transcriptDone.Transcript = s.content.String()
+		events = append(events, transcriptDone)
+		part.Transcript = s.content.String()
+	} else {
+		textDone := s.newResponseEvent(types.EventTypeResponseTextDone)
+		textDone.Text = s.content.String()
+		events = append(events, textDone)
+		part.Text = s.content.String()
+	}
+
+	partDone := s.newResponseEvent(types.EventTypeResponseContentPartDone)
+	partDone.Part = &part
+
+	item := *s.item
+	item.Status = "completed"
+	item.Content = []types.RealtimeContent{part}
+	itemDone := types.NewRealtimeEvent(types.EventTypeResponseOutputItemDone)
+	itemDone.ResponseId = s.response.ID
+	itemDone.Item = &item
+
+	response := *s.response
+	response.Status = "completed"
+	response.Output = []types.RealtimeItem{item}
+	responseDone := types.NewRealtimeEvent(types.EventTypeResponseDone)
+	responseDone.Response = &response
+
+	s.response = nil
+	s.item = nil
+
+	return append(events, partDone, itemDone, responseDone)
+}
+
+func sendLiveMessage(message *GeminiLiveClientMessage) requester.ProxyMessage {
+	data, err := json.Marshal(message)
+	if err != nil {
+		logger.SysError(fmt.Sprintf("marshal gemini live message failed: %s", err.Error()))
+	}
+
+	return requester.ProxyMessage{Data: data}
+}
+
+func replyError(eventId, message string) requester.ProxyMessage {
+	errEvent := types.NewErrorEvent(eventId, "invalid_request_error", "unsupported_event", message)
+	return requester.ProxyMessage{Data: []byte(errEvent.Error()), Reply: true}
+}
@@ -0,0 +1,107 @@
+package gemini
+
+import (
+	"encoding/base64"
+	"errors"
+	"mime"
+	"net/http"
+	"one-api/common"
+	"one-api/common/audio"
+	"one-api/common/utils"
+	"one-api/types"
+)
+
+func (p *GeminiProvider) GetVoiceMap() map[string][]string {
+	defaultVoiceMapping := map[string][]string{
+		"alloy":   {"Zephyr"},
+		"echo":    {"Puck"},
+		"fable":   {"Fenrir"},
+		"onyx":    {"Charon"},
+		"nova":    {"Kore"},
+		"shimmer": {"Aoede"},
+	}
+
+	return p.BaseProvider.GetVoiceMap(defaultVoiceMapping)
+}
+
+// CreateSpeech Gemini TTS 模型只输出 24kHz PCM，其他格式和语速由服务端转码
+func (p *GeminiProvider) CreateSpeech(request *types.SpeechAudioRequest) (*http.Response, *types.OpenAIErrorWithStatusCode) {
+	format := audio.NormalizeSpeechFormat(request.ResponseFormat)
+	if !audio.IsSpeechFormat(format) {
+		return nil, common.StringErrorWrapperLocal("unsupported response_format: "+format, "invalid_request_error", http.StatusBadRequest)
+	}
+
+	voice := request.Voice
+	if voices := p.GetVoiceMap()[request.Voice]; voices != nil {
+		voice = voices[0]
+	}
+
+	geminiRequest := &GeminiChatRequest{
+		Contents: []GeminiChatContent{{
+			Role:  "user",
+			Parts: []GeminiPart{{Text: request.Input}},
+		}},
+		GenerationConfig: GeminiChatGenerationConfig{
+			ResponseModalities: []string{"AUDIO"},
+			SpeechConfig: &SpeechConfig{
+				VoiceConfig: &VoiceConfig{
+					PrebuiltVoiceConfig: &PrebuiltVoiceConfig{VoiceName: voice},
+				},
+			},
+		},
+	}
+
+	fullRequestURL := p.GetFullRequestURL("generateContent", request.Model)
+	req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(geminiRequest), p.Requester.WithHeader(p.GetRequestHeaders()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	defer req.Body.Close()
+
+	geminiResponse := &GeminiChatResponse{}
+	_, errWithCode := p.Requester.SendRequest(req, geminiResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	inlineData := getAudioInlineData(geminiResponse)
+	if inlineData == nil {
+		return nil, common.ErrorWrapper(errors.New("no audio in response"), "speech_error", http.StatusInternalServerError)
+	}
+
+	pcm, err := base64.StdEncoding.DecodeString(inlineData.Data)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "decode_audio_data_failed", http.StatusInternalServerError)
+	}
+
+	// audio/L16;codec=pcm;rate=24000
+	sampleRate := audio.DefaultPCMSampleRate
+	if _, params, err := mime.ParseMediaType(inlineData.MimeType); err == nil && params["rate"] != "" {
+		sampleRate = utils.String2Int(params["rate"])
+	}
+
+	data, err := audio.Transcode(pcm, audio.TranscodeOptions{
+		From:       "pcm",
+		To:         format,
+		SampleRate: sampleRate,
+		Speed:      request.Speed,
+	})
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "transcode_audio_failed", http.StatusInternalServerError)
+	}
+
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+
+	return audio.NewSpeechResponse(data, format), nil
+}
+
+func getAudioInlineData(response *GeminiChatResponse) *GeminiInlineData {
+	for _, candidate := range response.Candidates {
+		for _, part := range candidate.Content.Parts {
+			if part.InlineData != nil && part.InlineData.Data != "" {
+				return part.InlineData
+			}
+		}
+	}
+	return nil
+}
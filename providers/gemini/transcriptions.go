@@ -0,0 +1,162 @@
+package gemini
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"one-api/common"
+	"one-api/types"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	transcriptionPrompt = "Transcribe the speech in this audio verbatim in its original language. " +
+		"Split the transcript into segments of at most about 30 seconds at natural pauses, " +
+		"with start and end times in seconds from the beginning of the audio. " +
+		"Set language to the ISO-639-1 code of the spoken language."
+	translationPrompt = "Translate the speech in this audio into English. " +
+		"Split the translation into segments of at most about 30 seconds at natural pauses, " +
+		"with start and end times in seconds from the beginning of the audio. " +
+		"Set language to the ISO-639-1 code of the spoken language."
+)
+
+// 要求模型按 verbose_json 的分段格式输出
+var transcriptionSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"language": map[string]any{"type": "string"},
+		"segments": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"start": map[string]any{"type": "number"},
+					"end":   map[string]any{"type": "number"},
+					"text":  map[string]any{"type": "string"},
+				},
+				"required": []string{"start", "end", "text"},
+			},
+		},
+	},
+	"required": []string{"language", "segments"},
+}
+
+type GeminiTranscription struct {
+	Language string `json:"language"`
+	Segments []struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	} `json:"segments"`
+}
+
+func (p *GeminiProvider) CreateTranscriptions(request *types.AudioRequest) (*types.AudioResponseWrapper, *types.OpenAIErrorWithStatusCode) {
+	return p.understandAudio(request, "transcribe", transcriptionPrompt)
+}
+
+func (p *GeminiProvider) CreateTranslation(request *types.AudioRequest) (*types.AudioResponseWrapper, *types.OpenAIErrorWithStatusCode) {
+	return p.understandAudio(request, "translate", translationPrompt)
+}
+
+// understandAudio 使用音频理解能力模拟语音识别接口
+func (p *GeminiProvider) understandAudio(request *types.AudioRequest, task, prompt string) (*types.AudioResponseWrapper, *types.OpenAIErrorWithStatusCode) {
+	mimeType, data, err := readAudioFile(request)
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "read_audio_failed", http.StatusBadRequest)
+	}
+
+	if request.Language != "" {
+		prompt += " The spoken language is " + request.Language + "."
+	}
+	if request.Prompt != "" {
+		prompt += " Context: " + request.Prompt
+	}
+
+	geminiRequest := &GeminiChatRequest{
+		Model: request.Model,
+		Contents: []GeminiChatContent{{
+			Role: "user",
+			Parts: []GeminiPart{
+				{Text: prompt},
+				{InlineData: &GeminiInlineData{MimeType: mimeType, Data: data}},
+			},
+		}},
+		GenerationConfig: GeminiChatGenerationConfig{
+			ResponseMimeType: "application/json",
+			ResponseSchema:   transcriptionSchema,
+		},
+	}
+	if request.Temperature != 0 {
+		temperature := float64(request.Temperature)
+		geminiRequest.GenerationConfig.Temperature = &temperature
+	}
+
+	req, errWithCode := p.getChatRequest(geminiRequest, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	defer req.Body.Close()
+
+	geminiResponse := &GeminiChatResponse{}
+	_, errWithCode = p.Requester.SendRequest(req, geminiResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	transcription := &GeminiTranscription{}
+	if err := json.Unmarshal([]byte(geminiResponse.GetResponseText()), transcription); err != nil {
+		return nil, common.ErrorWrapper(err, "decode_transcription_failed", http.StatusInternalServerError)
+	}
+
+	segments := make([]types.AudioSegment, 0, len(transcription.Segments))
+	texts := make([]string, 0, len(transcription.Segments))
+	var duration float64
+	for i, segment := range transcription.Segments {
+		text := strings.TrimSpace(segment.Text)
+		segments = append(segments, types.AudioSegment{
+			Id:    i,
+			Start: segment.Start,
+			End:   segment.End,
+			Text:  " " + text,
+		})
+		texts = append(texts, text)
+		duration = max(duration, segment.End)
+	}
+
+	if geminiResponse.UsageMetadata != nil {
+		*p.Usage = ConvertOpenAIUsage(geminiResponse.UsageMetadata)
+	}
+
+	response, err := request.NewAudioResponse(task, transcription.Language, duration, strings.Join(texts, " "), segments)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "marshal_response_body_failed", http.StatusInternalServerError)
+	}
+	return response, nil
+}
+
+// readAudioFile 读取上传的音频，返回 MIME 类型和 base64 内容
+func readAudioFile(request *types.AudioRequest) (string, string, error) {
+	file, err := request.File.Open()
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", "", err
+	}
+
+	mimeType := request.File.Header.Get("Content-Type")
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = mime.TypeByExtension(strings.ToLower(filepath.Ext(request.File.Filename)))
+	}
+	if mimeType == "" {
+		mimeType = http.DetectContentType(content)
+	}
+
+	return mimeType, base64.StdEncoding.EncodeToString(content), nil
+}
@@ -330,6 +330,20 @@ type GeminiChatGenerationConfig struct {
 	ResponseSchema     any             `json:"responseSchema,omitempty"`
 	ResponseModalities []string        `json:"responseModalities,omitempty"`
 	ThinkingConfig     *ThinkingConfig `json:"thinkingConfig,omitempty"`
+	SpeechConfig       *SpeechConfig   `json:"speechConfig,omitempty"`
+}
+
+type SpeechConfig struct {
+	VoiceConfig  *VoiceConfig `json:"voiceConfig,omitempty"`
+	LanguageCode string       `json:"languageCode,omitempty"`
+}
+
+type VoiceConfig struct {
+	PrebuiltVoiceConfig *PrebuiltVoiceConfig `json:"prebuiltVoiceConfig,omitempty"`
+}
+
+type PrebuiltVoiceConfig struct {
+	VoiceName string `json:"voiceName"`
 }
 
 type ThinkingConfig struct {
@@ -714,3 +728,107 @@ func formatGroundingMetadataAsMarkdown(metadata *GeminiGroundingMetadata) string
 	}
 	return result.String()
 }
+
+type GeminiEmbeddingRequest struct {
+	Requests []GeminiEmbeddingContentRequest `json:"requests"`
+}
+
+type GeminiEmbeddingContentRequest struct {
+	Model                string            `json:"model"`
+	Content              GeminiChatContent `json:"content"`
+	TaskType             string            `json:"taskType,omitempty"`
+	OutputDimensionality int               `json:"outputDimensionality,omitempty"`
+}
+
+type GeminiEmbeddingResponse struct {
+	Embeddings []GeminiEmbeddingValues `json:"embeddings"`
+}
+
+type GeminiEmbeddingValues struct {
+	Values []float64 `json:"values"`
+}
+
+// Gemini Live (BidiGenerateContent) 的客户端消息，每条消息只包含一个字段
+type GeminiLiveClientMessage struct {
+	Setup         *GeminiLiveSetup         `json:"setup,omitempty"`
+	ClientContent *GeminiLiveClientContent `json:"clientContent,omitempty"`
+	RealtimeInput *GeminiLiveRealtimeInput `json:"realtimeInput,omitempty"`
+}
+
+type GeminiLiveSetup struct {
+	Model                    string                      `json:"model"`
+	GenerationConfig         *GeminiChatGenerationConfig `json:"generationConfig,omitempty"`
+	InputAudioTranscription  *struct{}                   `json:"inputAudioTranscription,omitempty"`
+	OutputAudioTranscription *struct{}                   `json:"outputAudioTranscription,omitempty"`
+}
+
+type GeminiLiveClientContent struct {
+	Turns        []GeminiChatContent `json:"turns,omitempty"`
+	TurnComplete bool                `json:"turnComplete"`
+}
+
+type GeminiLiveRealtimeInput struct {
+	Audio          *GeminiInlineData `json:"audio,omitempty"`
+	AudioStreamEnd bool              `json:"audioStreamEnd,omitempty"`
+}
+
+type GeminiLiveServerMessage struct {
+	SetupComplete *struct{}                `json:"setupComplete,omitempty"`
+	ServerContent *GeminiLiveServerContent `json:"serverContent,omitempty"`
+	UsageMetadata *GeminiLiveUsageMetadata `json:"usageMetadata,omitempty"`
+}
+
+type GeminiLiveServerContent struct {
+	ModelTurn           *GeminiChatContent       `json:"modelTurn,omitempty"`
+	TurnComplete        bool                     `json:"turnComplete,omitempty"`
+	Interrupted         bool                     `json:"interrupted,omitempty"`
+	InputTranscription  *GeminiLiveTranscription `json:"inputTranscription,omitempty"`
+	OutputTranscription *GeminiLiveTranscription `json:"outputTranscription,omitempty"`
+}
+
+type GeminiLiveTranscription struct {
+	Text string `json:"text"`
+}
+
+type GeminiLiveUsageMetadata struct {
+	PromptTokenCount        int                          `json:"promptTokenCount"`
+	ResponseTokenCount      int                          `json:"responseTokenCount"`
+	TotalTokenCount         int                          `json:"totalTokenCount"`
+	CachedContentTokenCount int                          `json:"cachedContentTokenCount,omitempty"`
+	ThoughtsTokenCount      int                          `json:"thoughtsTokenCount,omitempty"`
+	PromptTokensDetails     []GeminiUsageMetadataDetails `json:"promptTokensDetails,omitempty"`
+	ResponseTokensDetails   []GeminiUsageMetadataDetails `json:"responseTokensDetails,omitempty"`
+}
+
+func (u *GeminiLiveUsageMetadata) ToUsageEvent() *types.UsageEvent {
+	usage := &types.UsageEvent{
+		InputTokens:  u.PromptTokenCount,
+		OutputTokens: u.ResponseTokenCount + u.ThoughtsTokenCount,
+		TotalTokens:  u.TotalTokenCount,
+	}
+	usage.InputTokenDetails.CachedTokens = u.CachedContentTokenCount
+	usage.OutputTokenDetails.ReasoningTokens = u.ThoughtsTokenCount
+
+	for _, detail := range u.PromptTokensDetails {
+		switch detail.Modality {
+		case "AUDIO":
+			usage.InputTokenDetails.AudioTokens += detail.TokenCount
+		case "TEXT":
+			usage.InputTokenDetails.TextTokens += detail.TokenCount
+		}
+	}
+	for _, detail := range u.ResponseTokensDetails {
+		switch detail.Modality {
+		case "AUDIO":
+			usage.OutputTokenDetails.AudioTokens += detail.TokenCount
+		case "TEXT":
+			usage.OutputTokenDetails.TextTokens += detail.TokenCount
+		}
+	}
+
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.InputTokens + usage.OutputTokens
+	}
+
+	return usage
+}
@@ -0,0 +1,40 @@
+package groq
+
+import (
+	"errors"
+	"net/http"
+)
+
+type ModelListResponse struct {
+	Data []struct {
+		Id     string `json:"id"`
+		Active *bool  `json:"active"`
+	} `json:"data"`
+}
+
+// GetModelList Groq 的模型列表会保留已下线的模型，需要过滤掉 active 为 false 的
+func (p *GroqProvider) GetModelList() ([]string, error) {
+	fullRequestURL := p.GetFullRequestURL(p.Config.ModelList, "")
+	headers := p.GetRequestHeaders()
+
+	req, err := p.Requester.NewRequest(http.MethodGet, fullRequestURL, p.Requester.WithHeader(headers))
+	if err != nil {
+		return nil, errors.New("new_request_failed")
+	}
+
+	response := &ModelListResponse{}
+	_, errWithCode := p.Requester.SendRequest(req, response, false)
+	if errWithCode != nil {
+		return nil, errors.New(errWithCode.Message)
+	}
+
+	var modelList []string
+	for _, model := range response.Data {
+		if model.Active != nil && !*model.Active {
+			continue
+		}
+		modelList = append(modelList, model.Id)
+	}
+
+	return modelList, nil
+}
@@ -24,28 +24,7 @@ func (p *MiniMaxProvider) GetVoiceMap() map[string][]string {
 		"shimmer": {"audiobook_female_1"},
 	}
 
-	if p.Channel.Plugin == nil {
-		return defaultVoiceMapping
-	}
-
-	customVoiceMapping, ok := p.Channel.Plugin.Data()["voice"]
-	if !ok {
-		return defaultVoiceMapping
-	}
-
-	for key, value := range customVoiceMapping {
-		if _, exists := defaultVoiceMapping[key]; !exists {
-			continue
-		}
-		customVoiceValue, isString := value.(string)
-		if !isString || customVoiceValue == "" {
-			continue
-		}
-		customizeVoice := strings.Split(customVoiceValue, "|")
-		defaultVoiceMapping[key] = customizeVoice
-	}
-
-	return defaultVoiceMapping
+	return p.BaseProvider.GetVoiceMap(defaultVoiceMapping)
 }
 
 func (p *MiniMaxProvider) getRequestBody(request *types.SpeechAudioRequest) *SpeechRequest {
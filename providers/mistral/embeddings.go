@@ -0,0 +1,46 @@
+package mistral
+
+import (
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/types"
+)
+
+// CreateEmbeddings Mistral 使用 output_dimension 指定维度，且不支持 base64 编码，需要在本地转换
+func (p *MistralProvider) CreateEmbeddings(request *types.EmbeddingRequest) (*types.EmbeddingResponse, *types.OpenAIErrorWithStatusCode) {
+	mistralRequest := &EmbeddingRequest{
+		Model:           request.Model,
+		Input:           request.Input,
+		OutputDimension: request.Dimensions,
+		EncodingFormat:  "float",
+	}
+
+	req, errWithCode := p.GetRequestTextBody(config.RelayModeEmbeddings, request.Model, mistralRequest)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	defer req.Body.Close()
+
+	mistralResponse := &EmbeddingResponse{}
+	_, errWithCode = p.Requester.SendRequest(req, mistralResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	vectors := make([][]float64, len(mistralResponse.Data))
+	for _, item := range mistralResponse.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, common.StringErrorWrapper("invalid embedding index", "mistral_error", http.StatusInternalServerError)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+
+	usage := p.GetUsage()
+	if mistralResponse.Usage != nil && mistralResponse.Usage.PromptTokens > 0 {
+		usage.PromptTokens = mistralResponse.Usage.PromptTokens
+	}
+	usage.TotalTokens = usage.PromptTokens
+
+	return request.NewEmbeddingResponse(vectors, usage), nil
+}
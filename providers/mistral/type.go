@@ -2,6 +2,7 @@ package mistral
 
 import (
 	"encoding/json"
+	"one-api/types"
 )
 
 type MistralError struct {
@@ -45,3 +46,18 @@ type ModelDetails struct {
 	Created int64  `json:"created"`
 	OwnedBy string `json:"owned_by"`
 }
+
+type EmbeddingRequest struct {
+	Model           string `json:"model"`
+	Input           any    `json:"input"`
+	OutputDimension int    `json:"output_dimension,omitempty"`
+	EncodingFormat  string `json:"encoding_format,omitempty"`
+}
+
+type EmbeddingResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+	Usage *types.Usage `json:"usage,omitempty"`
+}
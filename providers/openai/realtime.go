@@ -9,6 +9,7 @@ import (
 	"one-api/common/logger"
 	"one-api/common/requester"
 	"one-api/types"
+	"strings"
 
 	"github.com/gorilla/websocket"
 )
@@ -21,6 +22,21 @@ func (p *OpenAIProvider) CreateChatRealtime(modelName string) (*websocket.Conn,
 	// 获取请求地址
 	fullRequestURL := p.GetFullRequestURL(url, modelName)
 
+	wsConn, errWithCode := p.DialRealtime(fullRequestURL)
+	if errWithCode != nil {
+		return nil, nil, errWithCode
+	}
+
+	return wsConn, p.HandleMessage, nil
+}
+
+// IsRealtimeModel OpenAI 的 realtime 模型名称均包含 realtime
+func (p *OpenAIProvider) IsRealtimeModel(modelName string) bool {
+	return strings.Contains(strings.ToLower(modelName), "realtime")
+}
+
+// DialRealtime 使用渠道的认证方式连接 realtime 接口
+func (p *OpenAIProvider) DialRealtime(fullRequestURL string) (*websocket.Conn, *types.OpenAIErrorWithStatusCode) {
 	// 获取请求头
 	httpHeaders := make(http.Header)
 	if p.IsAzure {
@@ -34,13 +50,23 @@ func (p *OpenAIProvider) CreateChatRealtime(modelName string) (*websocket.Conn,
 
 	wsConn, err := wsRequester.NewRequest(fullRequestURL, httpHeaders)
 	if err != nil {
-		return nil, nil, common.ErrorWrapper(err, "ws_request_failed", http.StatusInternalServerError)
+		return nil, common.ErrorWrapper(err, "ws_request_failed", http.StatusInternalServerError)
 	}
 
-	return wsConn, p.HandleMessage, nil
+	return wsConn, nil
+}
+
+// GetWSBaseURL 将渠道地址转换为 websocket 地址
+func (p *OpenAIProvider) GetWSBaseURL() string {
+	baseURL := strings.TrimSuffix(p.GetBaseURL(), "/")
+	if strings.HasPrefix(baseURL, "https://") {
+		return strings.Replace(baseURL, "https://", "wss://", 1)
+	}
+
+	return strings.Replace(baseURL, "http://", "ws://", 1)
 }
 
-func (p *OpenAIProvider) HandleMessage(source requester.MessageSource, messageType int, message []byte) (bool, *types.UsageEvent, []byte, error) {
+func (p *OpenAIProvider) HandleMessage(source requester.MessageSource, messageType int, message []byte) (bool, *types.UsageEvent, []requester.ProxyMessage, error) {
 	// 处理用户消息
 	if source == requester.UserMessage {
 		return true, nil, nil, nil
@@ -0,0 +1,39 @@
+package openrouter
+
+import (
+	"errors"
+)
+
+type CreditsResponse struct {
+	Data *CreditsData `json:"data"`
+}
+
+type CreditsData struct {
+	TotalCredits float64 `json:"total_credits"`
+	TotalUsage   float64 `json:"total_usage"`
+}
+
+func (p *OpenRouterProvider) Balance() (float64, error) {
+	fullRequestURL := p.GetFullRequestURL("/v1/credits", "")
+	headers := p.GetRequestHeaders()
+
+	req, err := p.Requester.NewRequest("GET", fullRequestURL, p.Requester.WithHeader(headers))
+	if err != nil {
+		return 0, err
+	}
+
+	// 发送请求
+	var info CreditsResponse
+	_, errWithCode := p.Requester.SendRequest(req, &info, false)
+	if errWithCode != nil {
+		return 0, errors.New(errWithCode.OpenAIError.Message)
+	}
+
+	if info.Data == nil {
+		return 0, errors.New("获取余额失败")
+	}
+
+	balance := info.Data.TotalCredits - info.Data.TotalUsage
+	p.Channel.UpdateBalance(balance)
+	return balance, nil
+}
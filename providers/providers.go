@@ -16,6 +16,7 @@ import (
 	"one-api/providers/cloudflareAI"
 	"one-api/providers/cohere"
 	"one-api/providers/coze"
+	"one-api/providers/declarative"
 	"one-api/providers/deepseek"
 	"one-api/providers/gemini"
 	"one-api/providers/github"
@@ -33,11 +34,14 @@ import (
 	"one-api/providers/palm"
 	"one-api/providers/recraftAI"
 	"one-api/providers/replicate"
+	"one-api/providers/rerank"
 	"one-api/providers/siliconflow"
 	"one-api/providers/stabilityAI"
 	"one-api/providers/suno"
 	"one-api/providers/tencent"
+	"one-api/providers/tts"
 	"one-api/providers/vertexai"
+	"one-api/providers/whisper"
 	"one-api/providers/xAI"
 	"one-api/providers/xunfei"
 	"one-api/providers/zhipu"
@@ -86,6 +90,7 @@ func init() {
 		config.ChannelTypeVertexAI:        vertexai.VertexAIProviderFactory{},
 		config.ChannelTypeSiliconflow:     siliconflow.SiliconflowProviderFactory{},
 		config.ChannelTypeJina:            jina.JinaProviderFactory{},
+		config.ChannelTypeRerank:          rerank.RerankProviderFactory{},
 		config.ChannelTypeGithub:          github.GithubProviderFactory{},
 		config.ChannelTypeRecraft:         recraftAI.RecraftProviderFactory{},
 		config.ChannelTypeReplicate:       replicate.ReplicateProviderFactory{},
@@ -93,11 +98,17 @@ func init() {
 		config.ChannelTypeAzureDatabricks: azuredatabricks.AzureDatabricksProviderFactory{},
 		config.ChannelTypeAzureV1:         azure_v1.AzureV1ProviderFactory{},
 		config.ChannelTypeXAI:             xAI.XAIProviderFactory{},
+		config.ChannelTypeWhisper:         whisper.WhisperProviderFactory{},
+		config.ChannelTypeTTS:             tts.TTSProviderFactory{},
+		config.ChannelTypeDeclarative:     declarative.DeclarativeProviderFactory{},
 	}
 }
 
 // GetProvider 获取供应商
 func GetProvider(channel *model.Channel, c *gin.Context) base.ProviderInterface {
+	// 使用密钥池时每次请求选择一个密钥
+	channel = model.ChannelKeyPoolInstance.Pick(channel)
+
 	factory, ok := providerFactories[channel.Type]
 	var provider base.ProviderInterface
 	if !ok {
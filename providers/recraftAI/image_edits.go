@@ -0,0 +1,78 @@
+package recraftAI
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/common/image"
+	"one-api/providers/base"
+	"one-api/types"
+)
+
+// CreateImageEdits 有蒙版时使用 inpaint，否则使用 imageToImage
+func (p *RecraftProvider) CreateImageEdits(request *types.ImageEditRequest) (*types.ImageResponse, *types.OpenAIErrorWithStatusCode) {
+	url, errWithCode := p.GetSupportedAPIUri(config.RelayModeImagesEdits)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	images := request.GetImages()
+	if len(images) == 0 {
+		return nil, common.StringErrorWrapperLocal("image is required", "invalid_request_error", http.StatusBadRequest)
+	}
+	imageData, _, err := image.ReadImageFile(images[0])
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "read_image_failed", http.StatusBadRequest)
+	}
+	mask, err := image.GetEditMask(imageData, request.Mask)
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "invalid_mask", http.StatusBadRequest)
+	}
+
+	var formBody bytes.Buffer
+	builder := p.Requester.CreateFormBuilder(&formBody)
+	builder.CreateFormFileReader("image", bytes.NewReader(imageData), images[0].Filename)
+	builder.WriteField("prompt", request.Prompt)
+	builder.WriteField("model", request.Model)
+	// 统一取回 base64，再按 response_format 转存
+	builder.WriteField("response_format", "b64_json")
+	if request.N > 0 {
+		builder.WriteField("n", fmt.Sprintf("%d", request.N))
+	}
+	if mask != nil {
+		builder.CreateFormFileReader("mask", bytes.NewReader(mask), "mask.png")
+	} else {
+		url = p.ImageToImageUrl
+		builder.WriteField("strength", "0.5")
+	}
+	builder.Close()
+
+	req, err := p.Requester.NewRequest(
+		http.MethodPost,
+		p.GetFullRequestURL(url),
+		p.Requester.WithBody(&formBody),
+		p.Requester.WithHeader(p.GetRequestHeaders()),
+		p.Requester.WithContentType(builder.FormDataContentType()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	req.ContentLength = int64(formBody.Len())
+
+	recraftResponse := &types.ImageResponse{}
+	_, errWithCode = p.Requester.SendRequest(req, recraftResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	for i, data := range recraftResponse.Data {
+		if data.B64JSON != "" {
+			recraftResponse.Data[i] = base.NewImageResponseData(data.B64JSON, "", request.ResponseFormat)
+		}
+	}
+
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+
+	return recraftResponse, nil
+}
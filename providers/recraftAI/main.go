@@ -22,6 +22,7 @@ func (f RecraftProviderFactory) Create(channel *model.Channel) base.ProviderInte
 			Requester: requester.NewHTTPRequester(*channel.Proxy, requestErrorHandle),
 		},
 		StylesUrl:            "/v1/styles",
+		ImageToImageUrl:      "/v1/images/imageToImage",
 		VectorizeUrl:         "/v1/images/vectorize",
 		RemoveBackgroundUrl:  "/v1/images/removeBackground",
 		ClarityUpscaleUrl:    "/v1/images/clarityUpscale",
@@ -32,6 +33,7 @@ func (f RecraftProviderFactory) Create(channel *model.Channel) base.ProviderInte
 type RecraftProvider struct {
 	base.BaseProvider
 	StylesUrl            string
+	ImageToImageUrl      string
 	VectorizeUrl         string
 	RemoveBackgroundUrl  string
 	ClarityUpscaleUrl    string
@@ -42,6 +44,7 @@ func getConfig() base.ProviderConfig {
 	return base.ProviderConfig{
 		BaseURL:           "https://external.api.recraft.ai",
 		ImagesGenerations: "/v1/images/generations",
+		ImagesEdit:        "/v1/images/inpaint",
 	}
 }
 
@@ -0,0 +1,88 @@
+package replicate
+
+import (
+	"encoding/base64"
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/common/image"
+	"one-api/providers/base"
+	"one-api/types"
+	"strings"
+	"time"
+)
+
+func (p *ReplicateProvider) CreateImageEdits(request *types.ImageEditRequest) (*types.ImageResponse, *types.OpenAIErrorWithStatusCode) {
+	url, errWithCode := p.GetSupportedAPIUri(config.RelayModeImagesEdits)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	replicateRequest, errWithCode := convertFromImageEditOpenai(request)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	fullRequestURL := p.GetFullRequestURL(url, request.Model)
+	req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(replicateRequest), p.Requester.WithHeader(p.GetRequestHeaders()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+
+	replicateResponse := &ReplicateResponse[string]{}
+	_, errWithCode = p.Requester.SendRequest(req, replicateResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	replicateResponse, err = getPrediction(p, replicateResponse)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "prediction_failed", http.StatusInternalServerError)
+	}
+	if replicateResponse.Output == "" {
+		return nil, common.StringErrorWrapper("no image generated", "no_image_generated", http.StatusInternalServerError)
+	}
+
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+
+	// 预测结果的地址一小时后失效，需要转存
+	return &types.ImageResponse{
+		Created: time.Now().Unix(),
+		Data:    []types.ImageResponseDataInner{base.RehostImageURL(replicateResponse.Output, request.ResponseFormat)},
+	}, nil
+}
+
+func convertFromImageEditOpenai(request *types.ImageEditRequest) (*ReplicateRequest[ReplicateImageEditRequest], *types.OpenAIErrorWithStatusCode) {
+	images := request.GetImages()
+	if len(images) == 0 {
+		return nil, common.StringErrorWrapperLocal("image is required", "invalid_request_error", http.StatusBadRequest)
+	}
+	imageData, mimeType, err := image.ReadImageFile(images[0])
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "read_image_failed", http.StatusBadRequest)
+	}
+	mask, err := image.GetEditMask(imageData, request.Mask)
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "invalid_mask", http.StatusBadRequest)
+	}
+
+	imageURI := "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(imageData)
+	replicateRequest := &ReplicateRequest[ReplicateImageEditRequest]{
+		Input: ReplicateImageEditRequest{
+			Prompt:       request.Prompt,
+			OutputFormat: "png",
+		},
+	}
+
+	switch {
+	case mask != nil:
+		replicateRequest.Input.Image = imageURI
+		replicateRequest.Input.Mask = "data:image/png;base64," + base64.StdEncoding.EncodeToString(mask)
+	case strings.Contains(request.Model, "kontext"):
+		replicateRequest.Input.InputImage = imageURI
+	default:
+		replicateRequest.Input.Image = imageURI
+	}
+
+	return replicateRequest, nil
+}
@@ -36,6 +36,7 @@ func getConfig() base.ProviderConfig {
 	return base.ProviderConfig{
 		BaseURL:           "https://api.replicate.com",
 		ImagesGenerations: "/v1/models/%s/predictions",
+		ImagesEdit:        "/v1/models/%s/predictions",
 		ChatCompletions:   "/v1/models/%s/predictions",
 	}
 }
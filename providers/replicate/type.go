@@ -21,6 +21,15 @@ type ReplicateImageRequest struct {
 	Size             string  `json:"size,omitempty"`
 }
 
+// 图片编辑请求，flux-fill 等局部重绘模型使用 image 和 mask，flux-kontext 等指令编辑模型使用 input_image
+type ReplicateImageEditRequest struct {
+	Prompt       string `json:"prompt"`
+	Image        string `json:"image,omitempty"`
+	InputImage   string `json:"input_image,omitempty"`
+	Mask         string `json:"mask,omitempty"`
+	OutputFormat string `json:"output_format,omitempty"`
+}
+
 type ReplicateChatRequest struct {
 	TopK             float64  `json:"top_k,omitempty"`
 	TopP             *float64 `json:"top_p,omitempty"`
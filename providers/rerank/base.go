@@ -0,0 +1,91 @@
+package rerank
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"one-api/common/requester"
+	"one-api/model"
+	"one-api/providers/base"
+	"one-api/types"
+	"strings"
+)
+
+// 兼容 Jina/Cohere 接口格式的自建重排序服务，例如 infinity、vLLM、Xinference，
+// 渠道的其他参数填写 tei 时使用 text-embeddings-inference 的接口格式
+type RerankProviderFactory struct{}
+
+// 创建 RerankProvider
+func (f RerankProviderFactory) Create(channel *model.Channel) base.ProviderInterface {
+	return &RerankProvider{
+		BaseProvider: base.BaseProvider{
+			Config:    getConfig(),
+			Channel:   channel,
+			Requester: requester.NewHTTPRequester(*channel.Proxy, requestErrorHandle),
+		},
+	}
+}
+
+type RerankProvider struct {
+	base.BaseProvider
+}
+
+const formatTEI = "tei"
+
+func (p *RerankProvider) isTEI() bool {
+	return strings.EqualFold(strings.TrimSpace(p.Channel.Other), formatTEI)
+}
+
+func getConfig() base.ProviderConfig {
+	return base.ProviderConfig{
+		BaseURL: "",
+		Rerank:  "/v1/rerank",
+	}
+}
+
+// 请求错误处理，兼容 {"detail": "..."}、{"error": "..."} 和 OpenAI 格式
+func requestErrorHandle(resp *http.Response) *types.OpenAIError {
+	rerankError := &RerankError{}
+	err := json.NewDecoder(resp.Body).Decode(rerankError)
+	if err != nil {
+		return nil
+	}
+
+	return errorHandle(rerankError)
+}
+
+// 错误处理
+func errorHandle(rerankError *RerankError) *types.OpenAIError {
+	message := rerankError.Message()
+	if message == "" {
+		return nil
+	}
+	return &types.OpenAIError{
+		Message: message,
+		Type:    "rerank_error",
+	}
+}
+
+// 获取请求头
+func (p *RerankProvider) GetRequestHeaders() (headers map[string]string) {
+	headers = make(map[string]string)
+	p.CommonRequestHeaders(headers)
+	if p.Channel.Key != "" {
+		headers["Authorization"] = fmt.Sprintf("Bearer %s", p.Channel.Key)
+	}
+
+	return headers
+}
+
+// 获取完整请求 URL，渠道地址已包含重排序路径时直接使用，例如 http://127.0.0.1:8080/rerank
+func (p *RerankProvider) GetFullRequestURL(requestURL string) string {
+	baseURL := strings.TrimSuffix(p.GetBaseURL(), "/")
+	if strings.HasSuffix(baseURL, "/rerank") {
+		return baseURL
+	}
+	if p.isTEI() {
+		return baseURL + "/rerank"
+	}
+
+	return fmt.Sprintf("%s%s", baseURL, requestURL)
+}
@@ -0,0 +1,154 @@
+package rerank
+
+import (
+	"encoding/json"
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/types"
+)
+
+func (p *RerankProvider) CreateRerank(request *types.RerankRequest) (*types.RerankResponse, *types.OpenAIErrorWithStatusCode) {
+	documents, err := request.GetDocumentsList()
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "invalid_documents", http.StatusBadRequest)
+	}
+
+	url, errWithCode := p.GetSupportedAPIUri(config.RelayModeRerank)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// 获取请求地址
+	fullRequestURL := p.GetFullRequestURL(url)
+	if fullRequestURL == "" {
+		return nil, common.ErrorWrapper(nil, "invalid_rerank_config", http.StatusInternalServerError)
+	}
+
+	// 获取请求头
+	headers := p.GetRequestHeaders()
+
+	var rerankResponse *RerankResponse
+	if p.isTEI() {
+		rerankResponse, errWithCode = p.sendTEIRerank(fullRequestURL, headers, request, documents)
+	} else {
+		rerankResponse, errWithCode = p.sendRerank(fullRequestURL, headers, request, documents)
+	}
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.convertToRerank(rerankResponse, request, documents), nil
+}
+
+func (p *RerankProvider) sendRerank(fullRequestURL string, headers map[string]string, request *types.RerankRequest, documents []string) (*RerankResponse, *types.OpenAIErrorWithStatusCode) {
+	rerankReq := &RerankRequest{
+		Model:           request.Model,
+		Query:           request.Query,
+		Documents:       documents,
+		TopN:            request.TopN,
+		ReturnDocuments: request.ShouldReturnDocuments(),
+	}
+
+	// 创建请求
+	req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(rerankReq), p.Requester.WithHeader(headers))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	defer req.Body.Close()
+
+	rerankResponse := &RerankResponse{}
+
+	// 发送请求
+	_, errWithCode := p.Requester.SendRequest(req, rerankResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return rerankResponse, nil
+}
+
+// TEI 不支持 top_n，且按分数降序返回全部文档
+func (p *RerankProvider) sendTEIRerank(fullRequestURL string, headers map[string]string, request *types.RerankRequest, documents []string) (*RerankResponse, *types.OpenAIErrorWithStatusCode) {
+	teiReq := &TEIRerankRequest{
+		Query:      request.Query,
+		Texts:      documents,
+		ReturnText: request.ShouldReturnDocuments(),
+		Truncate:   true,
+	}
+
+	req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(teiReq), p.Requester.WithHeader(headers))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	defer req.Body.Close()
+
+	var teiResponse []TEIRerankResult
+	_, errWithCode := p.Requester.SendRequest(req, &teiResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	rerankResponse := &RerankResponse{
+		Results: make([]RerankResult, 0, len(teiResponse)),
+	}
+	for _, result := range teiResponse {
+		document, _ := json.Marshal(result.Text)
+		rerankResponse.Results = append(rerankResponse.Results, RerankResult{
+			Index:          result.Index,
+			RelevanceScore: result.Score,
+			Document:       document,
+		})
+	}
+
+	return rerankResponse, nil
+}
+
+func (p *RerankProvider) convertToRerank(response *RerankResponse, request *types.RerankRequest, documents []string) *types.RerankResponse {
+	rerank := &types.RerankResponse{
+		Model:   request.Model,
+		Results: make([]types.RerankResult, 0, len(response.Results)),
+	}
+
+	returnDocuments := request.ShouldReturnDocuments()
+	for _, result := range response.Results {
+		rerankResult := types.RerankResult{
+			Index:          result.Index,
+			RelevanceScore: result.RelevanceScore,
+		}
+		if returnDocuments {
+			// 部分服务不返回文档内容，按索引补齐
+			text := result.DocumentText()
+			if text == "" && result.Index >= 0 && result.Index < len(documents) {
+				text = documents[result.Index]
+			}
+			rerankResult.Document = &types.RerankResultDocument{Text: text}
+		}
+		rerank.Results = append(rerank.Results, rerankResult)
+	}
+
+	if request.TopN > 0 && len(rerank.Results) > request.TopN {
+		rerank.Results = rerank.Results[:request.TopN]
+	}
+
+	// 上游未返回用量时使用本地计算的 tokens
+	promptTokens := 0
+	switch {
+	case response.Usage != nil:
+		promptTokens = response.Usage.PromptTokens
+		if promptTokens == 0 {
+			promptTokens = response.Usage.TotalTokens
+		}
+	case response.Meta != nil && response.Meta.BilledUnits != nil:
+		promptTokens = response.Meta.BilledUnits.InputTokens
+	case response.Meta != nil && response.Meta.Tokens != nil:
+		promptTokens = response.Meta.Tokens.InputTokens
+	}
+	if promptTokens > 0 {
+		p.Usage.PromptTokens = promptTokens
+	}
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+	rerank.Usage = p.Usage
+
+	return rerank
+}
@@ -0,0 +1,93 @@
+package rerank
+
+import (
+	"encoding/json"
+	"one-api/types"
+)
+
+type RerankError struct {
+	Detail any `json:"detail,omitempty"`
+	Error  any `json:"error,omitempty"`
+}
+
+func (e *RerankError) Message() string {
+	for _, value := range []any{e.Detail, e.Error} {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			if v != "" {
+				return v
+			}
+		case map[string]any:
+			if message, ok := v["message"].(string); ok && message != "" {
+				return message
+			}
+		default:
+			bytes, _ := json.Marshal(v)
+			return string(bytes)
+		}
+	}
+	return ""
+}
+
+type RerankRequest struct {
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            int      `json:"top_n,omitempty"`
+	ReturnDocuments bool     `json:"return_documents"`
+}
+
+type RerankResponse struct {
+	Results []RerankResult `json:"results"`
+	Usage   *types.Usage   `json:"usage,omitempty"`
+	Meta    *RerankMeta    `json:"meta,omitempty"`
+}
+
+type RerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+	// 不同服务返回的文档可能是字符串或 {"text": "..."}
+	Document json.RawMessage `json:"document,omitempty"`
+}
+
+// Cohere 格式的用量
+type RerankMeta struct {
+	BilledUnits *struct {
+		InputTokens int `json:"input_tokens,omitempty"`
+		SearchUnits int `json:"search_units,omitempty"`
+	} `json:"billed_units,omitempty"`
+	Tokens *struct {
+		InputTokens int `json:"input_tokens,omitempty"`
+	} `json:"tokens,omitempty"`
+}
+
+type TEIRerankRequest struct {
+	Query      string   `json:"query"`
+	Texts      []string `json:"texts"`
+	ReturnText bool     `json:"return_text"`
+	Truncate   bool     `json:"truncate"`
+}
+
+type TEIRerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"score"`
+	Text  string  `json:"text,omitempty"`
+}
+
+func (r *RerankResult) DocumentText() string {
+	if len(r.Document) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(r.Document, &text); err == nil {
+		return text
+	}
+
+	document := &types.RerankResultDocument{}
+	if err := json.Unmarshal(r.Document, document); err == nil {
+		return document.Text
+	}
+	return ""
+}
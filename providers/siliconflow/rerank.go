@@ -52,7 +52,7 @@ func getRerankRequest(request *types.RerankRequest, documents []string) *RerankR
 		Model:           request.Model,
 		Query:           request.Query,
 		TopN:            request.TopN,
-		ReturnDocuments: true,
+		ReturnDocuments: request.ShouldReturnDocuments(),
 		Documents:       documents,
 	}
 }
@@ -32,6 +32,7 @@ func getConfig() base.ProviderConfig {
 	return base.ProviderConfig{
 		BaseURL:           "https://api.stability.ai/v2beta",
 		ImagesGenerations: "/stable-image/generate",
+		ImagesEdit:        "/stable-image/edit",
 	}
 }
 
@@ -0,0 +1,83 @@
+package stabilityAI
+
+import (
+	"bytes"
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/common/image"
+	"one-api/providers/base"
+	"one-api/types"
+	"strings"
+	"time"
+)
+
+// CreateImageEdits 有蒙版时使用 inpaint，否则使用 sd3 的图生图
+func (p *StabilityAIProvider) CreateImageEdits(request *types.ImageEditRequest) (*types.ImageResponse, *types.OpenAIErrorWithStatusCode) {
+	url, errWithCode := p.GetSupportedAPIUri(config.RelayModeImagesEdits)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	images := request.GetImages()
+	if len(images) == 0 {
+		return nil, common.StringErrorWrapperLocal("image is required", "invalid_request_error", http.StatusBadRequest)
+	}
+	imageData, _, err := image.ReadImageFile(images[0])
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "read_image_failed", http.StatusBadRequest)
+	}
+	mask, err := image.GetEditMask(imageData, request.Mask)
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "invalid_mask", http.StatusBadRequest)
+	}
+
+	var formBody bytes.Buffer
+	builder := p.Requester.CreateFormBuilder(&formBody)
+	builder.CreateFormFileReader("image", bytes.NewReader(imageData), images[0].Filename)
+	builder.WriteField("prompt", request.Prompt)
+	builder.WriteField("output_format", "png")
+
+	var fullRequestURL string
+	if mask != nil {
+		fullRequestURL = p.GetFullRequestURL(url, "inpaint")
+		builder.CreateFormFileReader("mask", bytes.NewReader(mask), "mask.png")
+	} else {
+		fullRequestURL = p.GetFullRequestURL(p.Config.ImagesGenerations, "sd3")
+		builder.WriteField("mode", "image-to-image")
+		builder.WriteField("strength", "0.7")
+		if strings.HasPrefix(request.Model, "sd3") {
+			builder.WriteField("model", request.Model)
+		}
+	}
+	builder.Close()
+
+	headers := p.GetRequestHeaders()
+	headers["Accept"] = "application/json; type=image/png"
+
+	req, err := p.Requester.NewRequest(
+		http.MethodPost,
+		fullRequestURL,
+		p.Requester.WithBody(&formBody),
+		p.Requester.WithHeader(headers),
+		p.Requester.WithContentType(builder.FormDataContentType()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	req.ContentLength = int64(formBody.Len())
+
+	stabilityAIResponse := &generateResponse{}
+	_, errWithCode = p.Requester.SendRequest(req, stabilityAIResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	openaiResponse := &types.ImageResponse{
+		Created: time.Now().Unix(),
+		Data:    []types.ImageResponseDataInner{base.NewImageResponseData(stabilityAIResponse.Image, "image/png", request.ResponseFormat)},
+	}
+
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+
+	return openaiResponse, nil
+}
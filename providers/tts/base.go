@@ -0,0 +1,62 @@
+package tts
+
+import (
+	"fmt"
+	"one-api/common/requester"
+	"one-api/model"
+	"one-api/providers/base"
+	"one-api/providers/openai"
+	"strings"
+)
+
+// 自建的语音合成服务，使用 OpenAI 兼容的 /v1/audio/speech 接口（Kokoro-FastAPI、openedai-speech 等），
+// 渠道的其他参数填写服务支持的输出格式，多个用英文逗号隔开，留空表示支持全部格式
+type TTSProviderFactory struct{}
+
+// 创建 TTSProvider
+func (f TTSProviderFactory) Create(channel *model.Channel) base.ProviderInterface {
+	return &TTSProvider{
+		OpenAIProvider: openai.OpenAIProvider{
+			BaseProvider: base.BaseProvider{
+				Config:    getConfig(),
+				Channel:   channel,
+				Requester: requester.NewHTTPRequester(*channel.Proxy, openai.RequestErrorHandle),
+			},
+		},
+	}
+}
+
+type TTSProvider struct {
+	openai.OpenAIProvider
+}
+
+func getConfig() base.ProviderConfig {
+	return base.ProviderConfig{
+		BaseURL:     "",
+		AudioSpeech: "/v1/audio/speech",
+		ModelList:   "/v1/models",
+	}
+}
+
+// 获取请求头，服务未开启鉴权时不发送 Authorization
+func (p *TTSProvider) GetRequestHeaders() (headers map[string]string) {
+	headers = make(map[string]string)
+	p.CommonRequestHeaders(headers)
+	if p.Channel.Key != "" {
+		headers["Authorization"] = fmt.Sprintf("Bearer %s", p.Channel.Key)
+	}
+
+	return headers
+}
+
+// getSupportedFormats 返回服务支持的输出格式，未配置时返回 nil
+func (p *TTSProvider) getSupportedFormats() []string {
+	var formats []string
+	for _, format := range strings.Split(p.Channel.Other, ",") {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format != "" {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
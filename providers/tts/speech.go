@@ -0,0 +1,74 @@
+package tts
+
+import (
+	"io"
+	"net/http"
+	"one-api/common"
+	"one-api/common/audio"
+	"one-api/common/config"
+	"one-api/common/requester"
+	"one-api/types"
+	"slices"
+)
+
+func (p *TTSProvider) CreateSpeech(request *types.SpeechAudioRequest) (*http.Response, *types.OpenAIErrorWithStatusCode) {
+	url, errWithCode := p.GetSupportedAPIUri(config.RelayModeAudioSpeech)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	format := audio.NormalizeSpeechFormat(request.ResponseFormat)
+	if !audio.IsSpeechFormat(format) {
+		return nil, common.StringErrorWrapperLocal("unsupported response_format: "+format, "invalid_request_error", http.StatusBadRequest)
+	}
+
+	// 服务不支持请求的格式时，优先合成 wav 再转码
+	upstreamFormat := format
+	if formats := p.getSupportedFormats(); len(formats) > 0 && !slices.Contains(formats, format) {
+		upstreamFormat = formats[0]
+		if slices.Contains(formats, "wav") {
+			upstreamFormat = "wav"
+		}
+	}
+
+	speechRequest := *request
+	speechRequest.ResponseFormat = upstreamFormat
+
+	fullRequestURL := p.GetFullRequestURL(url, request.Model)
+	req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(&speechRequest), p.Requester.WithHeader(p.GetRequestHeaders()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	defer req.Body.Close()
+
+	resp, errWithCode := p.Requester.SendRequestRaw(req)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if resp.Header.Get("Content-Type") == "application/json" {
+		return nil, requester.HandleErrorResp(resp, p.Requester.ErrorHandler, p.Requester.IsOpenAI)
+	}
+
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+
+	if upstreamFormat == format {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "read_response_body_failed", http.StatusInternalServerError)
+	}
+
+	data, err = audio.Transcode(data, audio.TranscodeOptions{
+		From: upstreamFormat,
+		To:   format,
+	})
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "transcode_audio_failed", http.StatusInternalServerError)
+	}
+
+	return audio.NewSpeechResponse(data, format), nil
+}
@@ -8,6 +8,7 @@ import (
 	"one-api/providers/base"
 	"one-api/providers/claude"
 	"one-api/types"
+	"strings"
 )
 
 const AnthropicVersion = "vertex-2023-10-16"
@@ -92,3 +93,15 @@ func getClaudeOtherUrl(stream bool) string {
 	}
 	return "rawPredict"
 }
+
+// GetClaudeBaseModelNames 返回 Vertex AI 上的 Claude 模型对应的渠道模型名称
+func GetClaudeBaseModelNames(vertexModelName string) []string {
+	var names []string
+	for baseName, vertexName := range claudeMap {
+		if strings.Split(vertexName, "@")[0] == vertexModelName {
+			names = append(names, baseName)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,108 @@
+package vertexai
+
+import (
+	"net/http"
+	"one-api/common"
+	"one-api/common/utils"
+	"one-api/providers/gemini"
+	"one-api/types"
+	"strings"
+)
+
+// text-embedding 系列单次最多 250 条输入，gemini-embedding 系列单次只支持 1 条
+const embeddingBatchSize = 250
+
+type VertexAIEmbeddingRequest struct {
+	Instances  []VertexAIEmbeddingInstance  `json:"instances"`
+	Parameters *VertexAIEmbeddingParameters `json:"parameters,omitempty"`
+}
+
+type VertexAIEmbeddingInstance struct {
+	Content  string `json:"content"`
+	TaskType string `json:"task_type,omitempty"`
+}
+
+type VertexAIEmbeddingParameters struct {
+	OutputDimensionality int `json:"outputDimensionality,omitempty"`
+}
+
+type VertexAIEmbeddingResponse struct {
+	Predictions []VertexAIEmbeddingPrediction `json:"predictions"`
+}
+
+type VertexAIEmbeddingPrediction struct {
+	Embeddings struct {
+		Values     []float64 `json:"values"`
+		Statistics struct {
+			TokenCount float64 `json:"token_count"`
+			Truncated  bool    `json:"truncated"`
+		} `json:"statistics"`
+	} `json:"embeddings"`
+}
+
+func (p *VertexAIProvider) CreateEmbeddings(request *types.EmbeddingRequest) (*types.EmbeddingResponse, *types.OpenAIErrorWithStatusCode) {
+	input := request.ParseInput()
+	if len(input) == 0 {
+		return nil, common.StringErrorWrapperLocal("input is required", "invalid_request_error", http.StatusBadRequest)
+	}
+
+	fullRequestURL := p.GetFullRequestURL(request.Model, "predict")
+	if fullRequestURL == "" {
+		return nil, common.ErrorWrapper(nil, "invalid_vertex_ai_config", http.StatusInternalServerError)
+	}
+	headers := p.GetRequestHeaders()
+
+	var parameters *VertexAIEmbeddingParameters
+	if request.Dimensions > 0 {
+		parameters = &VertexAIEmbeddingParameters{OutputDimensionality: request.Dimensions}
+	}
+	taskType := gemini.ConvertEmbeddingTaskType(request.InputType)
+
+	batchSize := embeddingBatchSize
+	if strings.HasPrefix(request.Model, "gemini-embedding") {
+		batchSize = 1
+	}
+
+	vectors := make([][]float64, 0, len(input))
+	tokenCount := 0
+	for _, batch := range utils.ChunkSlice(input, batchSize) {
+		vertexRequest := &VertexAIEmbeddingRequest{
+			Instances:  make([]VertexAIEmbeddingInstance, 0, len(batch)),
+			Parameters: parameters,
+		}
+		for _, text := range batch {
+			vertexRequest.Instances = append(vertexRequest.Instances, VertexAIEmbeddingInstance{
+				Content:  text,
+				TaskType: taskType,
+			})
+		}
+
+		req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(vertexRequest), p.Requester.WithHeader(headers))
+		if err != nil {
+			return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+		}
+
+		vertexResponse := &VertexAIEmbeddingResponse{}
+		_, errWithCode := p.Requester.SendRequest(req, vertexResponse, false)
+		req.Body.Close()
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		if len(vertexResponse.Predictions) != len(batch) {
+			return nil, common.StringErrorWrapper("embeddings count mismatch", "vertex_ai_error", http.StatusInternalServerError)
+		}
+
+		for _, prediction := range vertexResponse.Predictions {
+			vectors = append(vectors, prediction.Embeddings.Values)
+			tokenCount += int(prediction.Embeddings.Statistics.TokenCount)
+		}
+	}
+
+	usage := p.GetUsage()
+	if tokenCount > 0 {
+		usage.PromptTokens = tokenCount
+	}
+	usage.TotalTokens = usage.PromptTokens
+
+	return request.NewEmbeddingResponse(vectors, usage), nil
+}
@@ -0,0 +1,129 @@
+package vertexai
+
+import (
+	"encoding/base64"
+	"net/http"
+	"one-api/common"
+	"one-api/common/image"
+	"one-api/providers/base"
+	"one-api/types"
+	"time"
+)
+
+type VertexAIImageEditRequest struct {
+	Instances  []VertexAIImageEditInstance `json:"instances"`
+	Parameters VertexAIImageEditParameters `json:"parameters"`
+}
+
+type VertexAIImageEditInstance struct {
+	Prompt          string                   `json:"prompt"`
+	ReferenceImages []VertexAIReferenceImage `json:"referenceImages"`
+}
+
+type VertexAIReferenceImage struct {
+	ReferenceType   string                   `json:"referenceType"`
+	ReferenceId     int                      `json:"referenceId"`
+	ReferenceImage  VertexAIImageBytes       `json:"referenceImage"`
+	MaskImageConfig *VertexAIMaskImageConfig `json:"maskImageConfig,omitempty"`
+}
+
+type VertexAIImageBytes struct {
+	BytesBase64Encoded string `json:"bytesBase64Encoded"`
+}
+
+type VertexAIMaskImageConfig struct {
+	MaskMode string  `json:"maskMode"`
+	Dilation float64 `json:"dilation,omitempty"`
+}
+
+type VertexAIImageEditParameters struct {
+	EditMode         string                 `json:"editMode"`
+	SampleCount      int                    `json:"sampleCount"`
+	PersonGeneration string                 `json:"personGeneration,omitempty"`
+	OutputOptions    *VertexAIOutputOptions `json:"outputOptions,omitempty"`
+}
+
+// CreateImageEdits 使用 Imagen 的蒙版局部重绘，蒙版白色为编辑区域
+func (p *VertexAIProvider) CreateImageEdits(request *types.ImageEditRequest) (*types.ImageResponse, *types.OpenAIErrorWithStatusCode) {
+	images := request.GetImages()
+	if len(images) == 0 {
+		return nil, common.StringErrorWrapperLocal("image is required", "invalid_request_error", http.StatusBadRequest)
+	}
+	imageData, _, err := image.ReadImageFile(images[0])
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "read_image_failed", http.StatusBadRequest)
+	}
+	mask, err := image.GetEditMask(imageData, request.Mask)
+	if err != nil {
+		return nil, common.ErrorWrapperLocal(err, "invalid_mask", http.StatusBadRequest)
+	}
+	if mask == nil {
+		return nil, common.StringErrorWrapperLocal("mask or image with transparent area is required", "invalid_request_error", http.StatusBadRequest)
+	}
+
+	sampleCount := request.N
+	if sampleCount == 0 {
+		sampleCount = 1
+	}
+
+	vertexRequest := &VertexAIImageEditRequest{
+		Instances: []VertexAIImageEditInstance{{
+			Prompt: request.Prompt,
+			ReferenceImages: []VertexAIReferenceImage{
+				{
+					ReferenceType:  "REFERENCE_TYPE_RAW",
+					ReferenceId:    1,
+					ReferenceImage: VertexAIImageBytes{BytesBase64Encoded: base64.StdEncoding.EncodeToString(imageData)},
+				},
+				{
+					ReferenceType:   "REFERENCE_TYPE_MASK",
+					ReferenceId:     2,
+					ReferenceImage:  VertexAIImageBytes{BytesBase64Encoded: base64.StdEncoding.EncodeToString(mask)},
+					MaskImageConfig: &VertexAIMaskImageConfig{MaskMode: "MASK_MODE_USER_PROVIDED", Dilation: 0.01},
+				},
+			},
+		}},
+		Parameters: VertexAIImageEditParameters{
+			EditMode:         "EDIT_MODE_INPAINT_INSERTION",
+			SampleCount:      sampleCount,
+			PersonGeneration: "allow_adult",
+			OutputOptions:    &VertexAIOutputOptions{MimeType: "image/png"},
+		},
+	}
+
+	fullRequestURL := p.GetFullRequestURL(request.Model, "predict")
+	if fullRequestURL == "" {
+		return nil, common.ErrorWrapper(nil, "invalid_vertex_ai_config", http.StatusInternalServerError)
+	}
+
+	req, err := p.Requester.NewRequest(http.MethodPost, fullRequestURL, p.Requester.WithBody(vertexRequest), p.Requester.WithHeader(p.GetRequestHeaders()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+
+	vertexResponse := &VertexAIImageResponse{}
+	_, errWithCode := p.Requester.SendRequest(req, vertexResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	openaiResponse := &types.ImageResponse{
+		Created: time.Now().Unix(),
+		Data:    make([]types.ImageResponseDataInner, 0, len(vertexResponse.Predictions)),
+	}
+	for _, prediction := range vertexResponse.Predictions {
+		if prediction.BytesBase64Encoded == "" {
+			continue
+		}
+		openaiResponse.Data = append(openaiResponse.Data, base.NewImageResponseData(prediction.BytesBase64Encoded, prediction.MimeType, request.ResponseFormat))
+	}
+
+	if len(openaiResponse.Data) == 0 {
+		return nil, common.StringErrorWrapper("no image generated", "no_image_generated", http.StatusInternalServerError)
+	}
+
+	p.Usage.PromptTokens = len(openaiResponse.Data) * 258
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+
+	return openaiResponse, nil
+}
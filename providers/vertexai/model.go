@@ -0,0 +1,94 @@
+package vertexai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"one-api/providers/vertexai/category"
+	"strings"
+)
+
+type PublisherModelListResponse struct {
+	PublisherModels []PublisherModel `json:"publisherModels"`
+	NextPageToken   string           `json:"nextPageToken"`
+}
+
+type PublisherModel struct {
+	Name string `json:"name"`
+}
+
+// 需要查询的 Model Garden 发布者
+var modelListPublishers = []string{"google", "anthropic"}
+
+func (p *VertexAIProvider) GetModelList() ([]string, error) {
+	if p.Region == "" || p.ProjectID == "" {
+		return nil, errors.New("invalid vertex ai config")
+	}
+
+	var modelList []string
+	for _, publisher := range modelListPublishers {
+		models, err := p.getPublisherModels(publisher)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range models {
+			if publisher == "anthropic" {
+				if baseNames := category.GetClaudeBaseModelNames(name); len(baseNames) > 0 {
+					modelList = append(modelList, baseNames...)
+					continue
+				}
+			}
+			modelList = append(modelList, name)
+		}
+	}
+
+	return modelList, nil
+}
+
+func (p *VertexAIProvider) getPublisherModels(publisher string) ([]string, error) {
+	host := "aiplatform.googleapis.com"
+	if p.Region != "global" {
+		host = p.Region + "-" + host
+	}
+
+	headers := p.GetRequestHeaders()
+	if headers == nil {
+		return nil, errors.New("failed to get vertex ai token")
+	}
+	headers["x-goog-user-project"] = p.ProjectID
+
+	var models []string
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Add("pageSize", "1000")
+		if pageToken != "" {
+			params.Add("pageToken", pageToken)
+		}
+		fullRequestURL := fmt.Sprintf("https://%s/v1beta1/publishers/%s/models?%s", host, publisher, params.Encode())
+
+		req, err := p.Requester.NewRequest(http.MethodGet, fullRequestURL, p.Requester.WithHeader(headers))
+		if err != nil {
+			return nil, errors.New("new_request_failed")
+		}
+
+		response := &PublisherModelListResponse{}
+		_, errWithCode := p.Requester.SendRequest(req, response, false)
+		if errWithCode != nil {
+			return nil, errors.New(errWithCode.Message)
+		}
+
+		for _, model := range response.PublisherModels {
+			models = append(models, model.Name[strings.LastIndex(model.Name, "/")+1:])
+		}
+
+		if response.NextPageToken == "" {
+			break
+		}
+		pageToken = response.NextPageToken
+	}
+
+	return models, nil
+}
@@ -0,0 +1,93 @@
+package vertexai
+
+import (
+	"fmt"
+	"net/http"
+	"one-api/common"
+	"one-api/types"
+	"strconv"
+)
+
+// Vertex AI 的重排序由 Discovery Engine 的 Ranking API 提供，模型例如 semantic-ranker-default@latest
+const rankingURL = "https://discoveryengine.googleapis.com/v1/projects/%s/locations/global/rankingConfigs/default_ranking_config:rank"
+
+type VertexAIRankRequest struct {
+	Model                         string               `json:"model"`
+	Query                         string               `json:"query"`
+	Records                       []VertexAIRankRecord `json:"records"`
+	TopN                          int                  `json:"topN,omitempty"`
+	IgnoreRecordDetailsInResponse bool                 `json:"ignoreRecordDetailsInResponse,omitempty"`
+}
+
+type VertexAIRankRecord struct {
+	Id      string  `json:"id"`
+	Content string  `json:"content,omitempty"`
+	Score   float64 `json:"score,omitempty"`
+}
+
+type VertexAIRankResponse struct {
+	Records []VertexAIRankRecord `json:"records"`
+}
+
+func (p *VertexAIProvider) CreateRerank(request *types.RerankRequest) (*types.RerankResponse, *types.OpenAIErrorWithStatusCode) {
+	documents, err := request.GetDocumentsList()
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "invalid_documents", http.StatusBadRequest)
+	}
+	if p.ProjectID == "" {
+		return nil, common.ErrorWrapper(nil, "invalid_vertex_ai_config", http.StatusInternalServerError)
+	}
+
+	// 记录 ID 使用文档索引，用于还原 index
+	rankRequest := &VertexAIRankRequest{
+		Model:                         request.Model,
+		Query:                         request.Query,
+		Records:                       make([]VertexAIRankRecord, 0, len(documents)),
+		TopN:                          request.GetTopN(),
+		IgnoreRecordDetailsInResponse: !request.ShouldReturnDocuments(),
+	}
+	for i, document := range documents {
+		rankRequest.Records = append(rankRequest.Records, VertexAIRankRecord{
+			Id:      strconv.Itoa(i),
+			Content: document,
+		})
+	}
+
+	headers := p.GetRequestHeaders()
+	req, err := p.Requester.NewRequest(http.MethodPost, fmt.Sprintf(rankingURL, p.ProjectID), p.Requester.WithBody(rankRequest), p.Requester.WithHeader(headers))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	defer req.Body.Close()
+
+	rankResponse := &VertexAIRankResponse{}
+	_, errWithCode := p.Requester.SendRequest(req, rankResponse, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	rerank := &types.RerankResponse{
+		Model:   request.Model,
+		Results: make([]types.RerankResult, 0, len(rankResponse.Records)),
+	}
+	for _, record := range rankResponse.Records {
+		index, err := strconv.Atoi(record.Id)
+		if err != nil {
+			continue
+		}
+		rerankResult := types.RerankResult{
+			Index:          index,
+			RelevanceScore: record.Score,
+		}
+		if request.ShouldReturnDocuments() {
+			rerankResult.Document = &types.RerankResultDocument{Text: record.Content}
+		}
+		rerank.Results = append(rerank.Results, rerankResult)
+	}
+
+	// 接口不返回用量，使用本地计算的 tokens
+	p.Usage.TotalTokens = p.Usage.PromptTokens
+	rerank.Usage = p.Usage
+
+	return rerank, nil
+}
@@ -0,0 +1,57 @@
+package whisper
+
+import (
+	"fmt"
+	"one-api/common/requester"
+	"one-api/model"
+	"one-api/providers/base"
+	"one-api/providers/openai"
+	"strings"
+)
+
+// 自建的语音识别服务，默认使用 OpenAI 兼容接口（faster-whisper-server、speaches、LocalAI 等），
+// 渠道的其他参数填写 whisper.cpp 时使用 whisper.cpp server 的 /inference 接口
+type WhisperProviderFactory struct{}
+
+// 创建 WhisperProvider
+func (f WhisperProviderFactory) Create(channel *model.Channel) base.ProviderInterface {
+	return &WhisperProvider{
+		OpenAIProvider: openai.OpenAIProvider{
+			BaseProvider: base.BaseProvider{
+				Config:    getConfig(),
+				Channel:   channel,
+				Requester: requester.NewHTTPRequester(*channel.Proxy, openai.RequestErrorHandle),
+			},
+		},
+	}
+}
+
+type WhisperProvider struct {
+	openai.OpenAIProvider
+}
+
+const formatWhisperCpp = "whisper.cpp"
+
+func getConfig() base.ProviderConfig {
+	return base.ProviderConfig{
+		BaseURL:             "",
+		AudioTranscriptions: "/v1/audio/transcriptions",
+		AudioTranslations:   "/v1/audio/translations",
+		ModelList:           "/v1/models",
+	}
+}
+
+func (p *WhisperProvider) isWhisperCpp() bool {
+	return strings.EqualFold(strings.TrimSpace(p.Channel.Other), formatWhisperCpp)
+}
+
+// 获取请求头，服务未开启鉴权时不发送 Authorization
+func (p *WhisperProvider) GetRequestHeaders() (headers map[string]string) {
+	headers = make(map[string]string)
+	p.CommonRequestHeaders(headers)
+	if p.Channel.Key != "" {
+		headers["Authorization"] = fmt.Sprintf("Bearer %s", p.Channel.Key)
+	}
+
+	return headers
+}
@@ -0,0 +1,117 @@
+package whisper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"one-api/common"
+	"one-api/types"
+	"strings"
+)
+
+func (p *WhisperProvider) CreateTranscriptions(request *types.AudioRequest) (*types.AudioResponseWrapper, *types.OpenAIErrorWithStatusCode) {
+	if p.isWhisperCpp() {
+		return p.inference(request, false)
+	}
+	return p.OpenAIProvider.CreateTranscriptions(request)
+}
+
+func (p *WhisperProvider) CreateTranslation(request *types.AudioRequest) (*types.AudioResponseWrapper, *types.OpenAIErrorWithStatusCode) {
+	if p.isWhisperCpp() {
+		return p.inference(request, true)
+	}
+	return p.OpenAIProvider.CreateTranslation(request)
+}
+
+// inference whisper.cpp server 的识别接口，translate 为 true 时翻译为英文，返回格式与 OpenAI 一致
+func (p *WhisperProvider) inference(request *types.AudioRequest, translate bool) (*types.AudioResponseWrapper, *types.OpenAIErrorWithStatusCode) {
+	baseURL := strings.TrimSuffix(p.GetBaseURL(), "/")
+	if baseURL == "" {
+		return nil, common.StringErrorWrapperLocal("base url is required", "invalid_whisper_config", http.StatusInternalServerError)
+	}
+	fullRequestURL := baseURL + "/inference"
+
+	responseFormat := request.ResponseFormat
+	if responseFormat == "" {
+		responseFormat = "json"
+	}
+
+	var formBody bytes.Buffer
+	builder := p.Requester.CreateFormBuilder(&formBody)
+	fields := map[string]string{
+		"response_format": responseFormat,
+		"language":        request.Language,
+		"prompt":          request.Prompt,
+	}
+	if request.Temperature != 0 {
+		fields["temperature"] = fmt.Sprintf("%.2f", request.Temperature)
+	}
+	if translate {
+		fields["translate"] = "true"
+	}
+	if err := builder.CreateFormFile("file", request.File); err != nil {
+		return nil, common.ErrorWrapper(err, "create_form_builder_failed", http.StatusInternalServerError)
+	}
+	for key, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := builder.WriteField(key, value); err != nil {
+			return nil, common.ErrorWrapper(err, "create_form_builder_failed", http.StatusInternalServerError)
+		}
+	}
+	if err := builder.Close(); err != nil {
+		return nil, common.ErrorWrapper(err, "create_form_builder_failed", http.StatusInternalServerError)
+	}
+
+	req, err := p.Requester.NewRequest(
+		http.MethodPost,
+		fullRequestURL,
+		p.Requester.WithBody(&formBody),
+		p.Requester.WithHeader(p.GetRequestHeaders()),
+		p.Requester.WithContentType(builder.FormDataContentType()))
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "new_request_failed", http.StatusInternalServerError)
+	}
+	req.ContentLength = int64(formBody.Len())
+	defer req.Body.Close()
+
+	resp, errWithCode := p.Requester.SendRequestRaw(req)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, common.ErrorWrapper(err, "read_response_body_failed", http.StatusInternalServerError)
+	}
+
+	// whisper.cpp 出错时同样返回 200 和 {"error": "..."}
+	text := string(body)
+	if responseFormat == "json" || responseFormat == "verbose_json" {
+		inferenceResponse := &InferenceResponse{}
+		if err := json.Unmarshal(body, inferenceResponse); err != nil {
+			return nil, common.ErrorWrapper(err, "decode_response_failed", http.StatusInternalServerError)
+		}
+		if inferenceResponse.Error != "" {
+			return nil, common.StringErrorWrapper(inferenceResponse.Error, "whisper_error", http.StatusInternalServerError)
+		}
+		text = inferenceResponse.Text
+	}
+
+	p.Usage.CompletionTokens = common.CountTokenText(text, request.Model)
+	p.Usage.TotalTokens = p.Usage.PromptTokens + p.Usage.CompletionTokens
+
+	return &types.AudioResponseWrapper{
+		Headers: map[string]string{"Content-Type": resp.Header.Get("Content-Type")},
+		Body:    body,
+	}, nil
+}
+
+type InferenceResponse struct {
+	Text  string `json:"text"`
+	Error string `json:"error,omitempty"`
+}
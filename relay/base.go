@@ -33,6 +33,7 @@ type RelayBaseInterface interface {
 	setProvider(modelName string) error
 	getProvider() providersBase.ProviderInterface
 	getOriginalModel() string
+	setOriginalModel(modelName string)
 	getModelName() string
 	getContext() *gin.Context
 	IsStream() bool
@@ -92,6 +93,11 @@ func (r *relayBase) getOriginalModel() string {
 }
 
 func (r *relayBase) getModelName() string {
+	// 虚拟模型按自身价格计费
+	if r.c.GetBool("billing_virtual_model") {
+		return r.c.GetString("virtual_model")
+	}
+
 	billingOriginalModel := r.c.GetBool("billing_original_model")
 
 	if billingOriginalModel {
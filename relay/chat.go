@@ -7,11 +7,9 @@ import (
 	"math"
 	"net/http"
 	"one-api/common"
-	"one-api/common/config"
 	"one-api/common/requester"
 	"one-api/common/utils"
 	providersBase "one-api/providers/base"
-	"one-api/safty"
 	"one-api/types"
 	"time"
 
@@ -95,6 +93,22 @@ var need2Response = map[string]bool{
 }
 
 func (r *relayChat) send() (err *types.OpenAIErrorWithStatusCode, done bool) {
+	// 内容审查
+	err = checkInputSafetyOnce(r.c, func() *types.OpenAIErrorWithStatusCode {
+		for i := range r.chatRequest.Messages {
+			content, errWithCode := checkInputSafety(r.c, r.chatRequest.Messages[i].Content)
+			if errWithCode != nil {
+				return errWithCode
+			}
+			r.chatRequest.Messages[i].Content = content
+		}
+		return nil
+	})
+	if err != nil {
+		done = true
+		return
+	}
+
 	if need2Response[r.modelName] {
 		resProvider, ok := r.provider.(providersBase.ResponsesInterface)
 		if ok {
@@ -110,19 +124,6 @@ func (r *relayChat) send() (err *types.OpenAIErrorWithStatusCode, done bool) {
 	}
 
 	r.chatRequest.Model = r.modelName
-	// 内容审查
-	if config.EnableSafe {
-		for _, message := range r.chatRequest.Messages {
-			if message.Content != nil {
-				CheckResult, _ := safty.CheckContent(message.Content)
-				if !CheckResult.IsSafe {
-					err = common.StringErrorWrapperLocal(CheckResult.Reason, CheckResult.Code, http.StatusBadRequest)
-					done = true
-					return
-				}
-			}
-		}
-	}
 
 	if r.chatRequest.Stream {
 		var response requester.StreamReaderInterface[string]
@@ -153,6 +154,13 @@ func (r *relayChat) send() (err *types.OpenAIErrorWithStatusCode, done bool) {
 			r.heartbeat.Stop()
 		}
 
+		restoreChatResponsePII(r.c, response)
+		err = checkChatResponseSafety(r.c, response)
+		if err != nil {
+			done = true
+			return
+		}
+
 		err = responseJsonClient(r.c, response)
 
 	}
@@ -218,7 +226,15 @@ func (r *relayChat) compatibleSend(resProvider providersBase.ResponsesInterface)
 		if r.heartbeat != nil {
 			r.heartbeat.Stop()
 		}
-		err = responseJsonClient(r.c, response.ToChat())
+
+		chatResponse := response.ToChat()
+		restoreChatResponsePII(r.c, chatResponse)
+		err = checkChatResponseSafety(r.c, chatResponse)
+		if err != nil {
+			done = true
+			return
+		}
+		err = responseJsonClient(r.c, chatResponse)
 	}
 
 	if err != nil {
@@ -7,7 +7,6 @@ import (
 	"one-api/common/config"
 	"one-api/common/requester"
 	"one-api/providers/claude"
-	"one-api/safty"
 	"one-api/types"
 	"strings"
 
@@ -65,17 +64,19 @@ func (r *relayClaudeOnly) send() (err *types.OpenAIErrorWithStatusCode, done boo
 
 	r.claudeRequest.Model = r.modelName
 	// 内容审查
-	if config.EnableSafe {
-		for _, message := range r.claudeRequest.Messages {
-			if message.Content != nil {
-				CheckResult, _ := safty.CheckContent(message.Content)
-				if !CheckResult.IsSafe {
-					err = common.StringErrorWrapperLocal(CheckResult.Reason, CheckResult.Code, http.StatusBadRequest)
-					done = true
-					return
-				}
+	err = checkInputSafetyOnce(r.c, func() *types.OpenAIErrorWithStatusCode {
+		for i := range r.claudeRequest.Messages {
+			content, errWithCode := checkInputSafety(r.c, r.claudeRequest.Messages[i].Content)
+			if errWithCode != nil {
+				return errWithCode
 			}
+			r.claudeRequest.Messages[i].Content = content
 		}
+		return nil
+	})
+	if err != nil {
+		done = true
+		return
 	}
 
 	if r.claudeRequest.Stream {
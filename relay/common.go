@@ -11,6 +11,7 @@ import (
 	"one-api/common/config"
 	"one-api/common/logger"
 	"one-api/common/requester"
+	"one-api/common/tracing"
 	"one-api/common/utils"
 	"one-api/controller"
 	"one-api/metrics"
@@ -23,6 +24,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func Path2Relay(c *gin.Context, path string) RelayBaseInterface {
@@ -87,9 +89,12 @@ func checkLimitModel(c *gin.Context, modelName string) (error error) {
 		return errors.New("No available models configured for current token")
 	}
 
+	// 通过虚拟模型解析出的具体模型，以虚拟模型名称进行判定
+	virtualModel := c.GetString("virtual_model")
+
 	// Check if modelName is in the allowed models list
 	for _, allowedModel := range setting.Limits.LimitModelSetting.Models {
-		if allowedModel == modelName {
+		if allowedModel == modelName || (virtualModel != "" && allowedModel == virtualModel) {
 			// Found matching model, allow usage
 			return nil
 		}
@@ -122,6 +127,12 @@ func GetProvider(c *gin.Context, modelName string) (provider providersBase.Provi
 	provider.SetOriginalModel(modelName)
 	c.Set("original_model", modelName)
 
+	if recorder, ok := utils.GetGinValue[*requester.PayloadRecorder](c, "payload_recorder"); ok {
+		if httpRequester := provider.GetRequester(); httpRequester != nil {
+			httpRequester.Context = requester.WithPayloadRecorder(httpRequester.Context, recorder)
+		}
+	}
+
 	newModelName, fail = provider.ModelMappingHandler(modelName)
 	if fail != nil {
 		return
@@ -141,6 +152,16 @@ func GetProvider(c *gin.Context, modelName string) (provider providersBase.Provi
 }
 
 func fetchChannel(c *gin.Context, modelName string) (channel *model.Channel, fail error) {
+	span := tracing.StartGin(c, "select_channel", attribute.String("model", modelName))
+	defer func() {
+		if fail != nil {
+			tracing.SetError(span, fail.Error())
+		} else if channel != nil {
+			span.SetAttributes(attribute.Int("channel.id", channel.Id))
+		}
+		span.End()
+	}()
+
 	channelId := c.GetInt("specific_channel_id")
 	ignore := c.GetBool("specific_channel_id_ignore")
 	if channelId > 0 && !ignore {
@@ -297,6 +318,11 @@ func responseStreamClient(c *gin.Context, stream requester.StreamReaderInterface
 	defer stream.Close()
 
 	var isFirstResponse bool
+	// 还原请求脱敏时替换的占位符
+	piiRestorer := newPIIStreamRestorer(c)
+	// 输出内容审查，拦截后关闭上游并丢弃剩余数据
+	safetyFilter := newSafetyStreamFilter(c)
+	var safetyBlocked bool
 
 	// 在新的goroutine中处理stream数据
 	go func() {
@@ -308,6 +334,26 @@ func responseStreamClient(c *gin.Context, stream requester.StreamReaderInterface
 				if !ok {
 					return
 				}
+
+				if safetyBlocked {
+					continue
+				}
+
+				// 先审查脱敏后的内容，再还原占位符，避免用户的原始敏感信息进入输出审查
+				if safetyFilter != nil {
+					var safetyErr *types.OpenAIErrorWithStatusCode
+					data, safetyErr = safetyFilter.Filter(data)
+					if safetyErr != nil {
+						writeSafetyStreamError(c, safetyErr)
+						safetyBlocked = true
+						stream.Close()
+						continue
+					}
+				}
+
+				if piiRestorer != nil {
+					data = piiRestorer.Restore(data)
+				}
 				streamData := "data: " + data + "\n\n"
 
 				if !isFirstResponse {
@@ -326,6 +372,10 @@ func responseStreamClient(c *gin.Context, stream requester.StreamReaderInterface
 				}
 
 			case err := <-errChan:
+				if safetyBlocked {
+					return
+				}
+
 				if !errors.Is(err, io.EOF) {
 					// 处理错误情况
 					errMsg := "data: " + err.Error() + "\n\n"
@@ -341,6 +391,36 @@ func responseStreamClient(c *gin.Context, stream requester.StreamReaderInterface
 					finalErr = common.StringErrorWrapper(err.Error(), "stream_error", 900)
 					logger.LogError(c.Request.Context(), "Stream err:"+err.Error())
 				} else {
+					// 审查暂存的内容同样需要还原占位符，之后再输出还原时暂存的内容
+					var tail []string
+					if safetyFilter != nil {
+						streamData, safetyErr := safetyFilter.Flush()
+						if safetyErr != nil {
+							writeSafetyStreamError(c, safetyErr)
+							return
+						}
+						if streamData != "" {
+							tail = append(tail, streamData)
+						}
+					}
+					if piiRestorer != nil {
+						for i := range tail {
+							tail[i] = piiRestorer.Restore(tail[i])
+						}
+						if streamData := piiRestorer.Flush(); streamData != "" {
+							tail = append(tail, streamData)
+						}
+					}
+					for _, streamData := range tail {
+						select {
+						case <-c.Request.Context().Done():
+							// 客户端已断开，不执行任何操作，直接跳过
+						default:
+							c.Writer.Write([]byte("data: " + streamData + "\n\n"))
+							c.Writer.Flush()
+						}
+					}
+
 					// 正常结束，处理endHandler
 					if finalErr == nil && endHandler != nil {
 						streamData := endHandler()
@@ -544,10 +624,18 @@ func shouldRetryBadRequest(channelType int, apiErr *types.OpenAIErrorWithStatusC
 	}
 }
 
-func processChannelRelayError(ctx context.Context, channelId int, channelName string, err *types.OpenAIErrorWithStatusCode, channelType int) {
-	logger.LogError(ctx, fmt.Sprintf("relay error (channel #%d(%s)): %s", channelId, channelName, err.Message))
-	if controller.ShouldDisableChannel(channelType, err) {
-		controller.DisableChannel(channelId, channelName, err.Message, true)
+func processChannelRelayError(ctx context.Context, channel *model.Channel, err *types.OpenAIErrorWithStatusCode) {
+	logger.LogError(ctx, fmt.Sprintf("relay error (channel #%d(%s)): %s", channel.Id, channel.Name, err.Message))
+	if channel.KeyId > 0 && !err.LocalError {
+		model.ChannelKeyPoolInstance.RecordError(channel.Id, channel.KeyId)
+	}
+	if controller.ShouldDisableChannel(channel.Type, err) {
+		// 使用密钥池时只禁用出错的密钥
+		if channel.KeyId > 0 {
+			controller.DisableChannelKey(channel, err.Message)
+			return
+		}
+		controller.DisableChannel(channel.Id, channel.Name, err.Message, true)
 	}
 }
 
@@ -7,11 +7,9 @@ import (
 	"math"
 	"net/http"
 	"one-api/common"
-	"one-api/common/config"
 	"one-api/common/requester"
 	"one-api/common/utils"
 	providersBase "one-api/providers/base"
-	"one-api/safty"
 	"one-api/types"
 	"time"
 
@@ -70,15 +68,17 @@ func (r *relayCompletions) send() (err *types.OpenAIErrorWithStatusCode, done bo
 	r.request.Model = r.modelName
 
 	// 内容审查
-	if config.EnableSafe {
-		if r.request.Prompt != nil {
-			CheckResult, _ := safty.CheckContent(r.request.Prompt)
-			if !CheckResult.IsSafe {
-				err = common.StringErrorWrapperLocal(CheckResult.Reason, CheckResult.Code, http.StatusBadRequest)
-				done = true
-				return
-			}
+	err = checkInputSafetyOnce(r.c, func() *types.OpenAIErrorWithStatusCode {
+		prompt, errWithCode := checkInputSafety(r.c, r.request.Prompt)
+		if errWithCode != nil {
+			return errWithCode
 		}
+		r.request.Prompt = prompt
+		return nil
+	})
+	if err != nil {
+		done = true
+		return
 	}
 
 	if r.request.Stream {
@@ -101,6 +101,12 @@ func (r *relayCompletions) send() (err *types.OpenAIErrorWithStatusCode, done bo
 		if err != nil {
 			return
 		}
+
+		err = checkCompletionResponseSafety(r.c, response)
+		if err != nil {
+			done = true
+			return
+		}
 		err = responseJsonClient(r.c, response)
 	}
 
@@ -3,9 +3,7 @@ package relay
 import (
 	"net/http"
 	"one-api/common"
-	"one-api/common/config"
 	providersBase "one-api/providers/base"
-	"one-api/safty"
 	"one-api/types"
 	"strings"
 
@@ -50,15 +48,17 @@ func (r *relayEmbeddings) send() (err *types.OpenAIErrorWithStatusCode, done boo
 	}
 
 	// 内容审查
-	if config.EnableSafe {
-		if r.request.Input != nil {
-			CheckResult, _ := safty.CheckContent(r.request)
-			if !CheckResult.IsSafe {
-				err = common.StringErrorWrapperLocal(CheckResult.Reason, CheckResult.Code, http.StatusBadRequest)
-				done = true
-				return
-			}
+	err = checkInputSafetyOnce(r.c, func() *types.OpenAIErrorWithStatusCode {
+		input, errWithCode := checkInputSafety(r.c, r.request.Input)
+		if errWithCode != nil {
+			return errWithCode
 		}
+		r.request.Input = input
+		return nil
+	})
+	if err != nil {
+		done = true
+		return
 	}
 
 	r.request.Model = r.modelName
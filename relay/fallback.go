@@ -0,0 +1,133 @@
+package relay
+
+import (
+	"fmt"
+	"net/http"
+	"one-api/common/logger"
+	"one-api/metrics"
+	"one-api/model"
+	"one-api/types"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var contextLengthKeywords = []string{
+	"context_length_exceeded",
+	"maximum context length",
+	"context length",
+	"context window",
+	"prompt is too long",
+	"input is too long",
+	"exceeds the maximum number of tokens",
+	"too many tokens",
+}
+
+// classifyFallbackError 将上游错误归类为可触发模型回退的错误类型，返回空字符串表示不触发回退
+func classifyFallbackError(apiErr *types.OpenAIErrorWithStatusCode) string {
+	if apiErr == nil || apiErr.LocalError {
+		return ""
+	}
+
+	if code, ok := apiErr.OpenAIError.Code.(string); ok && code == "context_length_exceeded" {
+		return model.FallbackOnContextLength
+	}
+
+	message := strings.ToLower(apiErr.OpenAIError.Message)
+	for _, keyword := range contextLengthKeywords {
+		if strings.Contains(message, keyword) {
+			return model.FallbackOnContextLength
+		}
+	}
+
+	if apiErr.StatusCode == http.StatusTooManyRequests {
+		return model.FallbackOnRateLimit
+	}
+
+	// 524 等非标准状态码同样归为 5xx
+	if apiErr.StatusCode/100 == 5 {
+		return model.FallbackOnServerError
+	}
+
+	return ""
+}
+
+// getFallbackChain 获取模型的回退链，令牌设置优先于系统设置
+func getFallbackChain(c *gin.Context, modelName string) []model.ModelFallbackStep {
+	if setting, ok := c.Get("token_setting"); ok {
+		if tokenSetting, ok := setting.(*model.TokenSetting); ok && tokenSetting != nil && tokenSetting.Fallback.Enabled {
+			if steps := tokenSetting.Fallback.Chains.Get(modelName); len(steps) > 0 {
+				return steps
+			}
+		}
+	}
+
+	return model.ModelFallbackInstance.GetChain(modelName)
+}
+
+// relayFallback 在同模型重试耗尽后，按回退链依次尝试其他模型
+func relayFallback(relay RelayBaseInterface, apiErr *types.OpenAIErrorWithStatusCode, baseSkipChannelIds []int) *types.OpenAIErrorWithStatusCode {
+	c := relay.getContext()
+
+	// 指定渠道的请求不进行回退
+	if c.GetInt("specific_channel_id") > 0 && !c.GetBool("specific_channel_id_ignore") {
+		return apiErr
+	}
+
+	requestModel := relay.getOriginalModel()
+	steps := getFallbackChain(c, requestModel)
+	if len(steps) == 0 {
+		return apiErr
+	}
+
+	for _, step := range steps {
+		errorClass := classifyFallbackError(apiErr)
+		if errorClass == "" {
+			break
+		}
+
+		if !step.Match(errorClass) || step.Model == relay.getOriginalModel() {
+			continue
+		}
+
+		// 回退模型同样需要满足令牌的模型限制
+		if err := checkLimitModel(c, step.Model); err != nil {
+			logger.LogWarn(c.Request.Context(), fmt.Sprintf("skip fallback model %s: %s", step.Model, err.Error()))
+			continue
+		}
+
+		c.Set("skip_channel_ids", append([]int{}, baseSkipChannelIds...))
+		relay.setOriginalModel(step.Model)
+		if err := relay.setProvider(step.Model); err != nil {
+			logger.LogWarn(c.Request.Context(), fmt.Sprintf("skip fallback model %s: %s", step.Model, err.Error()))
+			continue
+		}
+
+		c.Set("fallback_from", requestModel)
+		c.Set("fallback_reason", errorClass)
+
+		channel := relay.getProvider().GetChannel()
+		logger.LogWarn(c.Request.Context(), fmt.Sprintf("model %s fallback to %s (%s), using channel #%d(%s)", requestModel, step.Model, errorClass, channel.Id, channel.Name))
+		// 原始模型没有可用渠道时，请求还未按渠道设置处理过
+		prepareChannelRequest(relay)
+
+		var done bool
+		apiErr, done = RelayHandler(relay)
+		if apiErr == nil {
+			metrics.RecordProvider(c, 200)
+			return nil
+		}
+		go processChannelRelayError(c.Request.Context(), channel, apiErr)
+
+		apiErr, done = relayRetry(relay, channel, apiErr, done)
+		if apiErr == nil {
+			return nil
+		}
+
+		if done {
+			break
+		}
+	}
+
+	return apiErr
+}
@@ -3,12 +3,10 @@ package relay
 import (
 	"encoding/json"
 	"errors"
-	"net/http"
 	"one-api/common"
 	"one-api/common/config"
 	"one-api/common/requester"
 	"one-api/providers/gemini"
-	"one-api/safty"
 	"one-api/types"
 	"strings"
 
@@ -82,17 +80,25 @@ func (r *relayGeminiOnly) send() (err *types.OpenAIErrorWithStatusCode, done boo
 	}
 
 	// 内容审查
-	if config.EnableSafe {
-		for _, message := range r.geminiRequest.Contents {
-			if message.Parts != nil {
-				CheckResult, _ := safty.CheckContent(message.Parts)
-				if !CheckResult.IsSafe {
-					err = common.StringErrorWrapperLocal(CheckResult.Reason, CheckResult.Code, http.StatusBadRequest)
-					done = true
-					return
+	err = checkInputSafetyOnce(r.c, func() *types.OpenAIErrorWithStatusCode {
+		for i := range r.geminiRequest.Contents {
+			parts := r.geminiRequest.Contents[i].Parts
+			for j := range parts {
+				if parts[j].Text == "" {
+					continue
 				}
+				text, errWithCode := checkInputSafety(r.c, parts[j].Text)
+				if errWithCode != nil {
+					return errWithCode
+				}
+				parts[j].Text = text.(string)
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		done = true
+		return
 	}
 
 	r.geminiRequest.Model = r.modelName
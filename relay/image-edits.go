@@ -38,6 +38,10 @@ func (r *relayImageEdits) setRequest() error {
 		r.request.Size = "1024x1024"
 	}
 
+	if r.request.N == 0 {
+		r.request.N = 1
+	}
+
 	r.setOriginalModel(r.request.Model)
 
 	return nil
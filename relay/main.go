@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func Relay(c *gin.Context) {
@@ -36,10 +37,40 @@ func Relay(c *gin.Context) {
 		return
 	}
 
+	if err := resolveVirtualModel(relay); err != nil {
+		openaiErr := common.StringErrorWrapperLocal(err.Error(), "one_hub_error", http.StatusBadRequest)
+		relay.HandleJsonError(openaiErr)
+		return
+	}
+
+	applyPIIRedaction(relay)
+
 	c.Set("is_stream", relay.IsStream())
+	// 记录初始的跳过渠道，模型回退时以此为基础重新选择渠道
+	baseSkipChannelIds, _ := utils.GetGinValue[[]int](c, "skip_channel_ids")
+
 	if err := relay.setProvider(relay.getOriginalModel()); err != nil {
 		openaiErr := common.StringErrorWrapperLocal(err.Error(), "one_hub_error", http.StatusServiceUnavailable)
-		relay.HandleJsonError(openaiErr)
+		// 原始模型没有可用渠道时，尝试回退链中的模型
+		if c.IsAborted() || len(getFallbackChain(c, relay.getOriginalModel())) == 0 {
+			relay.HandleJsonError(openaiErr)
+			return
+		}
+
+		heartbeat := relay.SetHeartbeat(relay.IsStream())
+		if heartbeat != nil {
+			defer heartbeat.Close()
+		}
+
+		unavailableErr := common.StringErrorWrapper(err.Error(), "one_hub_error", http.StatusServiceUnavailable)
+		fallbackErr := relayFallback(relay, unavailableErr, baseSkipChannelIds)
+		if fallbackErr == nil {
+			return
+		}
+		if fallbackErr != unavailableErr {
+			openaiErr = fallbackErr
+		}
+		handleRelayError(relay, heartbeat, openaiErr)
 		return
 	}
 
@@ -48,28 +79,70 @@ func Relay(c *gin.Context) {
 		defer heartbeat.Close()
 	}
 
+	channel := relay.getProvider().GetChannel()
+	prepareChannelRequest(relay)
+
+	apiErr, done := RelayHandler(relay)
+	if apiErr == nil {
+		metrics.RecordProvider(c, 200)
+		return
+	}
+
+	go processChannelRelayError(c.Request.Context(), channel, apiErr)
+
+	apiErr, done = relayRetry(relay, channel, apiErr, done)
+	if apiErr != nil && !done {
+		apiErr = relayFallback(relay, apiErr, baseSkipChannelIds)
+	}
+
+	if apiErr != nil {
+		handleRelayError(relay, heartbeat, apiErr)
+	}
+}
+
+// handleRelayError 心跳已开始写入流时以流的形式返回错误
+func handleRelayError(relay RelayBaseInterface, heartbeat *relay_util.Heartbeat, apiErr *types.OpenAIErrorWithStatusCode) {
+	if heartbeat != nil && heartbeat.IsSafeWriteStream() {
+		relay.HandleStreamError(apiErr)
+		return
+	}
+
+	relay.HandleJsonError(apiErr)
+}
+
+// prepareChannelRequest 按首个选中渠道的设置处理请求（联网搜索、系统提示词），
+// 同一请求只处理一次，避免重试或回退到其他渠道时重复注入
+func prepareChannelRequest(relay RelayBaseInterface) {
+	c := relay.getContext()
+	if c.GetBool("channel_request_prepared") {
+		return
+	}
+	c.Set("channel_request_prepared", true)
+
+	request, ok := relay.getRequest().(*types.ChatCompletionRequest)
+	if !ok {
+		return
+	}
+
 	// 这里在setProvider的多次请求中没有去判定当前上下文是否存在第一次判定过的情况从而将数据写入异常的问题
 	BillingOriginalModel := c.GetBool("billing_original_model")
 	channel := relay.getProvider().GetChannel()
 	// 获取用户设置的一些工具(设置一下原本请求模型和渠道的id防止日志异常)
 	if channel.EnableSearch || c.GetBool("enable_search") {
-		handleSearch(c, relay.getRequest().(*types.ChatCompletionRequest), true)
+		handleSearch(c, request, true)
 		c.Set("billing_original_model", BillingOriginalModel)
 		c.Set("channel_id", channel.Id)
 		c.Set("channel_type", channel.Type)
 	}
 	// 处理systemPrompt
 	if channel.SystemPrompt != "" {
-		systemPrompt(channel.SystemPrompt, relay.getRequest().(*types.ChatCompletionRequest))
-	}
-
-	apiErr, done := RelayHandler(relay)
-	if apiErr == nil {
-		metrics.RecordProvider(c, 200)
-		return
+		systemPrompt(channel.SystemPrompt, request)
 	}
+}
 
-	go processChannelRelayError(c.Request.Context(), channel.Id, channel.Name, apiErr, channel.Type)
+// relayRetry 使用同一模型的其他渠道进行重试
+func relayRetry(relay RelayBaseInterface, channel *model.Channel, apiErr *types.OpenAIErrorWithStatusCode, done bool) (*types.OpenAIErrorWithStatusCode, bool) {
+	c := relay.getContext()
 
 	retryTimes := config.RetryTimes
 	if done || !shouldRetry(c, apiErr, channel.Type) {
@@ -86,7 +159,7 @@ func Relay(c *gin.Context) {
 
 		if time.Since(startTime) > timeout {
 			apiErr = common.StringErrorWrapperLocal("重试超时，上游负载已饱和，请稍后再试", "system_error", http.StatusTooManyRequests)
-			break
+			return apiErr, true
 		}
 
 		if err := relay.setProvider(relay.getOriginalModel()); err != nil {
@@ -98,25 +171,23 @@ func Relay(c *gin.Context) {
 		apiErr, done = RelayHandler(relay)
 		if apiErr == nil {
 			metrics.RecordProvider(c, 200)
-			return
+			return nil, false
 		}
-		go processChannelRelayError(c.Request.Context(), channel.Id, channel.Name, apiErr, channel.Type)
+		go processChannelRelayError(c.Request.Context(), channel, apiErr)
 		if done || !shouldRetry(c, apiErr, channel.Type) {
 			break
 		}
 	}
 
-	if apiErr != nil {
-		if heartbeat != nil && heartbeat.IsSafeWriteStream() {
-			relay.HandleStreamError(apiErr)
-			return
-		}
-
-		relay.HandleJsonError(apiErr)
-	}
+	return apiErr, done
 }
 
 func RelayHandler(relay RelayBaseInterface) (err *types.OpenAIErrorWithStatusCode, done bool) {
+	span := startUpstreamSpan(relay)
+	defer func() {
+		endUpstreamSpan(relay, span, err)
+	}()
+
 	promptTokens, tonkeErr := relay.getPromptTokens()
 	if tonkeErr != nil {
 		err = common.ErrorWrapperLocal(tonkeErr, "token_error", http.StatusBadRequest)
@@ -136,7 +207,17 @@ func RelayHandler(relay RelayBaseInterface) (err *types.OpenAIErrorWithStatusCod
 		return
 	}
 
+	if relay.IsStream() {
+		defer metrics.StreamStarted()()
+	}
+
+	sendStartTime := time.Now()
 	err, done = relay.send()
+	firstResponseTime := relay.GetFirstResponseTime()
+	if !firstResponseTime.IsZero() {
+		span.AddEvent("first_byte", trace.WithTimestamp(firstResponseTime))
+	}
+	metrics.RecordProviderLatency(relay.getProvider().GetChannel().Id, relay.getModelName(), sendStartTime, firstResponseTime)
 	// 最后处理流式中断时计算tokens
 	if usage.CompletionTokens == 0 && usage.TextBuilder.Len() > 0 {
 		usage.CompletionTokens = common.CountTokenText(usage.TextBuilder.String(), relay.getModelName())
@@ -158,9 +239,13 @@ func shouldCooldowns(c *gin.Context, channel *model.Channel, apiErr *types.OpenA
 	modelName := c.GetString("new_model")
 	channelId := channel.Id
 
-	// 如果是频率限制，冻结通道
+	// 如果是频率限制，冻结通道，使用密钥池时只冻结出错的密钥
 	if apiErr.StatusCode == http.StatusTooManyRequests {
-		model.ChannelGroup.SetCooldowns(channelId, modelName)
+		if channel.KeyId > 0 {
+			model.ChannelKeyPoolInstance.Cooldown(channelId, channel.KeyId)
+		} else {
+			model.ChannelGroup.SetCooldowns(channelId, modelName)
+		}
 	}
 
 	skipChannelIds, ok := utils.GetGinValue[[]int](c, "skip_channel_ids")
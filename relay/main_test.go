@@ -0,0 +1,54 @@
+package relay
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"one-api/common/config"
+	"one-api/model"
+	"one-api/providers"
+	"one-api/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareChannelRequest(t *testing.T) {
+	newChannel := func(systemPrompt string) *model.Channel {
+		proxy := ""
+		return &model.Channel{Type: config.ChannelTypeOpenAI, Proxy: &proxy, SystemPrompt: systemPrompt}
+	}
+
+	tests := []struct {
+		name     string
+		channels []*model.Channel
+		want     []string
+	}{
+		{name: "without system prompt", channels: []*model.Channel{newChannel("")}, want: []string{"user"}},
+		{name: "first channel system prompt", channels: []*model.Channel{newChannel("first")}, want: []string{"system", "user"}},
+		{name: "fallback channel does not prepare again", channels: []*model.Channel{newChannel("first"), newChannel("second")}, want: []string{"system", "user"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			relay := &relayChat{}
+			relay.c = c
+			relay.chatRequest.Messages = []types.ChatCompletionMessage{{Role: "user", Content: "hi"}}
+
+			for _, channel := range tt.channels {
+				relay.provider = providers.GetProvider(channel, c)
+				prepareChannelRequest(relay)
+			}
+
+			roles := make([]string, 0, len(relay.chatRequest.Messages))
+			for _, message := range relay.chatRequest.Messages {
+				roles = append(roles, message.Role)
+			}
+			assert.Equal(t, tt.want, roles)
+			if len(roles) > 1 {
+				assert.Equal(t, tt.channels[0].SystemPrompt, relay.chatRequest.Messages[0].Content)
+			}
+		})
+	}
+}
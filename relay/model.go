@@ -37,6 +37,11 @@ func ListModelsByToken(c *gin.Context) {
 		for modelName := range publicModels {
 			allModels = append(allModels, modelName)
 		}
+		for modelName := range model.VirtualModelsInstance.GetAll() {
+			if _, ok := publicModels[modelName]; !ok {
+				allModels = append(allModels, modelName)
+			}
+		}
 		sort.Strings(allModels)
 
 		var groupOpenAIModels []*OpenAIModels
@@ -82,6 +87,11 @@ func ListModelsByToken(c *gin.Context) {
 		return
 	}
 
+	for _, modelName := range model.VirtualModelsInstance.GetGroupModels(groupName) {
+		if !utils.Contains(modelName, models) {
+			models = append(models, modelName)
+		}
+	}
 	sort.Strings(models)
 	var groupOpenAIModels []*OpenAIModels
 	for _, modelName := range models {
@@ -328,6 +338,7 @@ type AvailableModelResponse struct {
 	Groups  []string     `json:"groups"`
 	OwnedBy string       `json:"owned_by"`
 	Price   *model.Price `json:"price"`
+	Virtual bool         `json:"virtual,omitempty"`
 }
 
 func AvailableModel(c *gin.Context) {
@@ -375,6 +386,32 @@ func getAvailableModels(groupName string) map[string]*AvailableModelResponse {
 		}
 	}
 
+	// 虚拟模型按自身价格或默认模型价格展示
+	for modelName, virtualModel := range model.VirtualModelsInstance.GetAll() {
+		var groups []string
+		for _, publicGroup := range publicGroups {
+			if virtualModel.AllowGroup(publicGroup) {
+				groups = append(groups, publicGroup)
+			}
+		}
+
+		if len(groups) == 0 {
+			continue
+		}
+
+		priceModel := modelName
+		if !virtualModel.FixedPrice && virtualModel.DefaultModel != "" {
+			priceModel = virtualModel.DefaultModel
+		}
+		price := model.PricingInstance.GetPrice(priceModel)
+		availableModels[modelName] = &AvailableModelResponse{
+			Groups:  groups,
+			OwnedBy: *getModelOwnedBy(price.ChannelType),
+			Price:   price,
+			Virtual: true,
+		}
+	}
+
 	return availableModels
 }
 
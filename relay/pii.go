@@ -0,0 +1,309 @@
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"one-api/common/config"
+	"one-api/common/utils"
+	"one-api/model"
+	"one-api/safty/pii"
+	"one-api/types"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/sjson"
+)
+
+// applyPIIRedaction 在选择渠道前将对话请求中的敏感信息替换为占位符，响应返回前再还原
+func applyPIIRedaction(relay RelayBaseInterface) {
+	c := relay.getContext()
+	request, ok := relay.getRequest().(*types.ChatCompletionRequest)
+	if !ok {
+		return
+	}
+
+	tokenSetting, _ := utils.GetGinValue[*model.TokenSetting](c, "token_setting")
+	detectors := model.PIIRedactionInstance.GetDetectors(c.GetString("token_group"), tokenSetting)
+	if len(detectors) == 0 {
+		return
+	}
+
+	vault := pii.NewVault(detectors, config.SessionSecret)
+	for i := range request.Messages {
+		request.Messages[i].Content = redactPIIContent(vault, request.Messages[i].Content)
+	}
+
+	if vault.IsEmpty() {
+		return
+	}
+
+	redactCachedRequestBody(c, request)
+	c.Set("pii_vault", vault)
+}
+
+// redactCachedRequestBody 缓存的原始请求体会被记录或直接转发，替换为脱敏后的消息内容，失败时丢弃
+// 只替换文本字段，其余内容保持原样
+func redactCachedRequestBody(c *gin.Context, request *types.ChatCompletionRequest) {
+	body, ok := utils.GetGinValue[[]byte](c, config.GinRequestBodyKey)
+	if !ok || len(body) == 0 {
+		return
+	}
+
+	var err error
+	for i := range request.Messages {
+		switch content := request.Messages[i].Content.(type) {
+		case string:
+			body, err = sjson.SetBytes(body, fmt.Sprintf("messages.%d.content", i), content)
+		case []any:
+			for j, item := range content {
+				part, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				text, ok := part["text"].(string)
+				if !ok {
+					continue
+				}
+				if body, err = sjson.SetBytes(body, fmt.Sprintf("messages.%d.content.%d.text", i, j), text); err != nil {
+					break
+				}
+			}
+		}
+
+		if err != nil {
+			c.Set(config.GinRequestBodyKey, []byte(nil))
+			return
+		}
+	}
+
+	c.Set(config.GinRequestBodyKey, body)
+}
+
+func redactPIIContent(vault *pii.Vault, content any) any {
+	switch v := content.(type) {
+	case string:
+		return vault.Redact(v)
+	case []any:
+		for _, item := range v {
+			part, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			if text, ok := part["text"].(string); ok {
+				part["text"] = vault.Redact(text)
+			}
+		}
+		return v
+	}
+
+	return content
+}
+
+func getPIIVault(c *gin.Context) *pii.Vault {
+	vault, _ := utils.GetGinValue[*pii.Vault](c, "pii_vault")
+	return vault
+}
+
+// restoreChatResponsePII 还原非流式响应中的占位符
+func restoreChatResponsePII(c *gin.Context, response *types.ChatCompletionResponse) {
+	vault := getPIIVault(c)
+	if vault == nil || response == nil {
+		return
+	}
+
+	for i := range response.Choices {
+		message := &response.Choices[i].Message
+		if content, ok := message.Content.(string); ok {
+			message.Content = vault.Restore(content)
+		}
+		for _, toolCall := range message.ToolCalls {
+			if toolCall.Function != nil {
+				toolCall.Function.Arguments = vault.Restore(toolCall.Function.Arguments)
+			}
+		}
+	}
+}
+
+// piiStreamRestorer 还原流式响应中的占位符，每个 choice 的内容和每个工具调用的参数单独处理被拆分的占位符
+type piiStreamRestorer struct {
+	vault     *pii.Vault
+	restorers map[int]*pii.StreamRestorer
+	// 工具调用参数的还原器，键为 choice 序号和工具调用序号
+	toolRestorers map[int]map[int]*pii.StreamRestorer
+	lastChunk     map[string]any
+}
+
+func newPIIStreamRestorer(c *gin.Context) *piiStreamRestorer {
+	vault := getPIIVault(c)
+	if vault == nil {
+		return nil
+	}
+
+	return &piiStreamRestorer{
+		vault:         vault,
+		restorers:     make(map[int]*pii.StreamRestorer),
+		toolRestorers: make(map[int]map[int]*pii.StreamRestorer),
+	}
+}
+
+func (p *piiStreamRestorer) getToolRestorer(choiceIndex, toolIndex int) *pii.StreamRestorer {
+	tools, ok := p.toolRestorers[choiceIndex]
+	if !ok {
+		tools = make(map[int]*pii.StreamRestorer)
+		p.toolRestorers[choiceIndex] = tools
+	}
+	restorer, ok := tools[toolIndex]
+	if !ok {
+		restorer = p.vault.NewStreamRestorer()
+		tools[toolIndex] = restorer
+	}
+	return restorer
+}
+
+// Restore 处理一条流数据
+func (p *piiStreamRestorer) Restore(data string) string {
+	var chunk map[string]any
+	if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+		return data
+	}
+
+	choices, ok := chunk["choices"].([]any)
+	if !ok || len(choices) == 0 {
+		return data
+	}
+	p.lastChunk = chunk
+
+	modified := false
+	for _, item := range choices {
+		choice, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		delta, ok := choice["delta"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		index := 0
+		if value, ok := choice["index"].(float64); ok {
+			index = int(value)
+		}
+
+		if text, ok := delta["content"].(string); ok && text != "" {
+			restorer, ok := p.restorers[index]
+			if !ok {
+				restorer = p.vault.NewStreamRestorer()
+				p.restorers[index] = restorer
+			}
+			if restored := restorer.Restore(text); restored != text {
+				delta["content"] = restored
+				modified = true
+			}
+		}
+
+		toolCalls, _ := delta["tool_calls"].([]any)
+		for position, item := range toolCalls {
+			toolCall, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			function, ok := toolCall["function"].(map[string]any)
+			if !ok {
+				continue
+			}
+			arguments, ok := function["arguments"].(string)
+			if !ok || arguments == "" {
+				continue
+			}
+
+			toolIndex := position
+			if value, ok := toolCall["index"].(float64); ok {
+				toolIndex = int(value)
+			}
+			if restored := p.getToolRestorer(index, toolIndex).Restore(arguments); restored != arguments {
+				function["arguments"] = restored
+				modified = true
+			}
+		}
+	}
+
+	if !modified {
+		return data
+	}
+
+	newData, err := json.Marshal(chunk)
+	if err != nil {
+		return data
+	}
+
+	return string(newData)
+}
+
+// Flush 输出被暂存的内容，没有时返回空字符串
+func (p *piiStreamRestorer) Flush() string {
+	if p.lastChunk == nil {
+		return ""
+	}
+
+	indexSet := make(map[int]bool, len(p.restorers)+len(p.toolRestorers))
+	for index := range p.restorers {
+		indexSet[index] = true
+	}
+	for index := range p.toolRestorers {
+		indexSet[index] = true
+	}
+	indexes := make([]int, 0, len(indexSet))
+	for index := range indexSet {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	choices := make([]any, 0, len(indexes))
+	for _, index := range indexes {
+		delta := map[string]any{}
+		if restorer, ok := p.restorers[index]; ok {
+			if text := restorer.Flush(); text != "" {
+				delta["content"] = text
+			}
+		}
+
+		tools := p.toolRestorers[index]
+		toolIndexes := make([]int, 0, len(tools))
+		for toolIndex := range tools {
+			toolIndexes = append(toolIndexes, toolIndex)
+		}
+		sort.Ints(toolIndexes)
+		toolCalls := make([]any, 0, len(toolIndexes))
+		for _, toolIndex := range toolIndexes {
+			if arguments := tools[toolIndex].Flush(); arguments != "" {
+				toolCalls = append(toolCalls, map[string]any{
+					"index":    toolIndex,
+					"function": map[string]any{"arguments": arguments},
+				})
+			}
+		}
+		if len(toolCalls) > 0 {
+			delta["tool_calls"] = toolCalls
+		}
+
+		if len(delta) > 0 {
+			choices = append(choices, map[string]any{
+				"index": index,
+				"delta": delta,
+			})
+		}
+	}
+
+	if len(choices) == 0 {
+		return ""
+	}
+
+	p.lastChunk["choices"] = choices
+	delete(p.lastChunk, "usage")
+	data, err := json.Marshal(p.lastChunk)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
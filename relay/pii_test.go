@@ -0,0 +1,115 @@
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"one-api/common/config"
+	"one-api/common/utils"
+	"one-api/safty/pii"
+	"one-api/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestRedactCachedRequestBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "string content",
+			body: `{"model":"gpt-4o","messages":[{"role":"user","content":"mail alice@example.com"}],"extra":{"keep":true}}`,
+		},
+		{
+			name: "content parts",
+			body: `{"model":"gpt-4o","messages":[{"role":"system","content":"be brief"},{"role":"user","content":[{"type":"text","text":"call 13812345678"},{"type":"image_url","image_url":{"url":"https://example.com/a.png"}}]}]}`,
+		},
+		{
+			name: "message without content",
+			body: `{"model":"gpt-4o","messages":[{"role":"assistant","tool_calls":[{"id":"call_1","type":"function","function":{"name":"f","arguments":"{}"}}]},{"role":"user","content":"alice@example.com"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set(config.GinRequestBodyKey, []byte(tt.body))
+
+			request := &types.ChatCompletionRequest{}
+			require.NoError(t, json.Unmarshal([]byte(tt.body), request))
+			vault := pii.NewVault(pii.BuiltinDetectors(), "secret")
+			for i := range request.Messages {
+				request.Messages[i].Content = redactPIIContent(vault, request.Messages[i].Content)
+			}
+
+			redactCachedRequestBody(c, request)
+
+			body, ok := utils.GetGinValue[[]byte](c, config.GinRequestBodyKey)
+			require.True(t, ok)
+			assert.NotContains(t, string(body), "alice@example.com")
+			assert.NotContains(t, string(body), "13812345678")
+			assert.Contains(t, string(body), "[PII_")
+
+			// 其他字段保持不变
+			assert.Equal(t, gjson.Get(tt.body, "model").String(), gjson.GetBytes(body, "model").String())
+			assert.Equal(t, gjson.Get(tt.body, "extra").Raw, gjson.GetBytes(body, "extra").Raw)
+			assert.Equal(t, gjson.Get(tt.body, "messages.#.role").Raw, gjson.GetBytes(body, "messages.#.role").Raw)
+			assert.Equal(t, gjson.Get(tt.body, "messages.0.tool_calls").Raw, gjson.GetBytes(body, "messages.0.tool_calls").Raw)
+			assert.Equal(t, gjson.Get(tt.body, "messages.1.content.1").Raw, gjson.GetBytes(body, "messages.1.content.1").Raw)
+			assert.Equal(t, gjson.Get(tt.body, "messages.0.content").Exists(), gjson.GetBytes(body, "messages.0.content").Exists())
+		})
+	}
+}
+
+func TestPIIStreamRestorer(t *testing.T) {
+	tests := []struct {
+		name string
+		// 每条数据的模板，%s 处填入脱敏后的片段
+		template string
+		path     string
+	}{
+		{
+			name:     "content",
+			template: `{"id":"1","choices":[{"index":0,"delta":{"content":%s}}]}`,
+			path:     "choices.0.delta.content",
+		},
+		{
+			name:     "tool call arguments",
+			template: `{"id":"1","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"function":{"arguments":%s}}]}}]}`,
+			path:     "choices.0.delta.tool_calls.0.function.arguments",
+		},
+	}
+
+	original := `{"email":"alice@example.com","phone":"13812345678"}`
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := pii.NewVault(pii.BuiltinDetectors(), "secret")
+			redacted := vault.Redact(original)
+			require.NotEqual(t, original, redacted)
+
+			restorer := &piiStreamRestorer{
+				vault:         vault,
+				restorers:     make(map[int]*pii.StreamRestorer),
+				toolRestorers: make(map[int]map[int]*pii.StreamRestorer),
+			}
+
+			// 按较小的片段拆分，使占位符跨越多条数据
+			var output string
+			for i := 0; i < len(redacted); i += 5 {
+				part, err := json.Marshal(redacted[i:min(i+5, len(redacted))])
+				require.NoError(t, err)
+				data := restorer.Restore(fmt.Sprintf(tt.template, part))
+				output += gjson.Get(data, tt.path).String()
+			}
+			output += gjson.Get(restorer.Flush(), tt.path).String()
+
+			assert.Equal(t, original, output)
+		})
+	}
+}
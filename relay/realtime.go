@@ -25,6 +25,8 @@ type RelayModeChatRealtime struct {
 	providerConn   *websocket.Conn
 	quota          *relay_util.Quota
 	usage          *types.UsageEvent
+	// 渠道或模型不支持 realtime 时使用对话接口模拟
+	emulator *realtimeEmulator
 }
 
 var upgrader = websocket.Upgrader{
@@ -64,6 +66,12 @@ func ChatRealtime(c *gin.Context) {
 
 	relay.usage = &types.UsageEvent{}
 
+	if relay.emulator != nil {
+		relay.emulator.run()
+		relay.quota.Consume(relay.c, relay.usage.ToChatUsage(), false)
+		return
+	}
+
 	wsProxy := requester.NewWSProxy(relay.userConn, relay.providerConn, time.Minute*2, relay.messageHandler, relay.usageHandler)
 
 	wsProxy.Start()
@@ -93,6 +101,11 @@ func (r *RelayModeChatRealtime) abortWithMessage(message string) {
 	r.userConn.Close()
 }
 
+// isRealtimeModel 渠道配置了该模型或上游命名表明原生支持 realtime 时直接转发，否则使用对话接口模拟
+func (r *RelayModeChatRealtime) isRealtimeModel(provider providersBase.RealtimeInterface) bool {
+	return provider.GetChannel().IsRealtimeModel(r.getOriginalModel()) || provider.IsRealtimeModel(r.modelName)
+}
+
 func (r *RelayModeChatRealtime) getProvider() bool {
 	retryTimes := config.RetryTimes
 	if retryTimes == 0 {
@@ -107,9 +120,17 @@ func (r *RelayModeChatRealtime) getProvider() bool {
 		}
 
 		realtimeProvider, ok := r.provider.(providersBase.RealtimeInterface)
-		if !ok {
-			r.abortWithMessage("channel not implemented")
-			return false
+		if !ok || !r.isRealtimeModel(realtimeProvider) {
+			chatProvider, ok := r.provider.(providersBase.ChatInterface)
+			if !ok {
+				r.abortWithMessage("channel not implemented")
+				return false
+			}
+
+			r.emulator = newRealtimeEmulator(r, chatProvider)
+			r.emulator.start()
+			metrics.RecordProvider(r.c, 200)
+			return true
 		}
 		channel := r.provider.GetChannel()
 
@@ -154,20 +175,27 @@ func (r *RelayModeChatRealtime) getRealtimeFirstMessage() bool {
 		return false
 	}
 
-	if messageType != websocket.TextMessage {
+	// Gemini Live 使用二进制帧发送 JSON
+	if messageType != websocket.TextMessage && messageType != websocket.BinaryMessage {
 		return false
 	}
 
-	shouldContinue, _, newMessage, err := r.messageHandler(requester.SupplierMessage, messageType, firstMessage)
+	shouldContinue, _, newMessages, err := r.messageHandler(requester.SupplierMessage, messageType, firstMessage)
 
 	if !shouldContinue || err != nil {
 		return false
 	}
 
-	if newMessage != nil {
-		r.userConn.WriteMessage(websocket.TextMessage, newMessage)
-	} else {
-		r.userConn.WriteMessage(websocket.TextMessage, firstMessage)
+	if newMessages == nil {
+		newMessages = []requester.ProxyMessage{{Data: firstMessage}}
+	}
+
+	for _, message := range newMessages {
+		if message.Reply {
+			r.providerConn.WriteMessage(websocket.TextMessage, message.Data)
+		} else {
+			r.userConn.WriteMessage(websocket.TextMessage, message.Data)
+		}
 	}
 
 	return true
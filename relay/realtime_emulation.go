@@ -0,0 +1,500 @@
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"one-api/common"
+	"one-api/common/logger"
+	providersBase "one-api/providers/base"
+	"one-api/types"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// realtimeEmulator 使用对话接口模拟 realtime 的文本会话，每次 response.create 发起一次流式对话请求
+type realtimeEmulator struct {
+	relay    *RelayModeChatRealtime
+	provider providersBase.ChatInterface
+	timeout  time.Duration
+
+	writeLock sync.Mutex
+	mu        sync.Mutex
+	session   types.RealtimeSession
+	items     []types.RealtimeItem
+	// 正在生成的回复，用于 response.cancel
+	active *realtimeEmulatorResponse
+	wg     sync.WaitGroup
+}
+
+type realtimeEmulatorResponse struct {
+	response  *types.ResponseEvent
+	cancelled bool
+	closeFunc func()
+}
+
+func newRealtimeEmulator(relay *RelayModeChatRealtime, provider providersBase.ChatInterface) *realtimeEmulator {
+	return &realtimeEmulator{
+		relay:    relay,
+		provider: provider,
+		timeout:  time.Minute * 2,
+		session: types.RealtimeSession{
+			Id:         types.NewRealtimeId("sess"),
+			Object:     "realtime.session",
+			Model:      relay.getOriginalModel(),
+			Modalities: []string{"text"},
+		},
+	}
+}
+
+func (e *realtimeEmulator) start() {
+	created := types.NewRealtimeEvent(types.EventTypeSessionCreated)
+	created.Session = &e.session
+	e.send(created)
+}
+
+// run 处理用户消息直到连接关闭，返回前等待正在生成的回复结束，保证用量已经累计
+func (e *realtimeEmulator) run() {
+	defer func() {
+		e.cancelResponse()
+		e.wg.Wait()
+		e.relay.userConn.Close()
+	}()
+
+	for {
+		e.relay.userConn.SetReadDeadline(time.Now().Add(e.timeout))
+		messageType, message, err := e.relay.userConn.ReadMessage()
+		if err != nil {
+			logger.LogInfo(e.relay.c.Request.Context(), fmt.Sprintf("realtime emulation closed: %s", err.Error()))
+			return
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		event := &types.RealtimeEvent{}
+		if err := json.Unmarshal(message, event); err != nil {
+			e.sendError("", "invalid_request_error", "invalid_event", err.Error())
+			continue
+		}
+
+		e.handleEvent(event)
+	}
+}
+
+func (e *realtimeEmulator) handleEvent(event *types.RealtimeEvent) {
+	switch event.Type {
+	case types.EventTypeSessionUpdate:
+		e.mu.Lock()
+		if event.Session != nil {
+			// 模拟模式只支持文本
+			event.Session.Modalities = nil
+		}
+		e.session.Merge(event.Session)
+		updated := types.NewRealtimeEvent(types.EventTypeSessionUpdated)
+		updated.Session = &e.session
+		e.mu.Unlock()
+		e.send(updated)
+
+	case types.EventTypeConversationItemCreate:
+		if event.Item == nil {
+			e.sendError(event.EventId, "invalid_request_error", "invalid_value", "item is required")
+			return
+		}
+		item := *event.Item
+		if item.Id == "" {
+			item.Id = types.NewRealtimeId("item")
+		}
+		item.Object = "realtime.item"
+		item.Status = "completed"
+
+		created := types.NewRealtimeEvent(types.EventTypeConversationItemCreated)
+		created.Item = &item
+		e.mu.Lock()
+		if len(e.items) > 0 {
+			created.PreviousItemId = e.items[len(e.items)-1].Id
+		}
+		e.items = append(e.items, item)
+		e.mu.Unlock()
+		e.send(created)
+
+	case types.EventTypeConversationItemDelete:
+		e.mu.Lock()
+		for i, item := range e.items {
+			if item.Id == event.ItemId {
+				e.items = append(e.items[:i], e.items[i+1:]...)
+				break
+			}
+		}
+		e.mu.Unlock()
+		deleted := types.NewRealtimeEvent(types.EventTypeConversationItemDeleted)
+		deleted.ItemId = event.ItemId
+		e.send(deleted)
+
+	case types.EventTypeResponseCreate:
+		e.createResponse(event.EventId)
+
+	case types.EventTypeResponseCancel:
+		e.cancelResponse()
+
+	case types.EventTypeInputAudioBufferAppend, types.EventTypeInputAudioBufferCommit, types.EventTypeInputAudioBufferClear:
+		e.sendError(event.EventId, "invalid_request_error", "unsupported_event", "audio input is not supported by this model, only text sessions are available")
+
+	default:
+		e.sendError(event.EventId, "invalid_request_error", "unsupported_event", "unsupported event type: "+event.Type)
+	}
+}
+
+func (e *realtimeEmulator) createResponse(eventId string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.active != nil {
+		e.sendError(eventId, "invalid_request_error", "conversation_already_has_active_response", "conversation already has an active response")
+		return
+	}
+
+	request := e.buildChatRequest()
+	active := &realtimeEmulatorResponse{
+		response: &types.ResponseEvent{
+			ID:     types.NewRealtimeId("resp"),
+			Object: "realtime.response",
+			Status: "in_progress",
+		},
+	}
+	e.active = active
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		e.generate(active, request)
+	}()
+}
+
+func (e *realtimeEmulator) cancelResponse() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.active == nil {
+		return
+	}
+	e.active.cancelled = true
+	if e.active.closeFunc != nil {
+		e.active.closeFunc()
+	}
+}
+
+// buildChatRequest 将会话配置和对话内容转换为对话请求，调用前需要持有 mu
+func (e *realtimeEmulator) buildChatRequest() *types.ChatCompletionRequest {
+	request := &types.ChatCompletionRequest{
+		Model:       e.relay.modelName,
+		Stream:      true,
+		Temperature: e.session.Temperature,
+	}
+
+	if maxTokens, ok := e.session.MaxResponseOutputTokens.(float64); ok {
+		request.MaxTokens = int(maxTokens)
+	}
+
+	if e.session.Instructions != "" {
+		request.Messages = append(request.Messages, types.ChatCompletionMessage{
+			Role:    types.ChatMessageRoleSystem,
+			Content: e.session.Instructions,
+		})
+	}
+
+	for _, item := range e.items {
+		switch item.Type {
+		case types.RealtimeItemTypeMessage:
+			request.Messages = append(request.Messages, types.ChatCompletionMessage{
+				Role:    item.Role,
+				Content: item.GetText(),
+			})
+		case types.RealtimeItemTypeFunctionCall:
+			toolCall := &types.ChatCompletionToolCalls{
+				Id:   item.CallId,
+				Type: types.ChatMessageRoleFunction,
+				Function: &types.ChatCompletionToolCallsFunction{
+					Name:      item.Name,
+					Arguments: item.Arguments,
+				},
+			}
+			// 连续的函数调用合并到同一条助手消息中
+			last := len(request.Messages) - 1
+			if last >= 0 && request.Messages[last].Role == types.ChatMessageRoleAssistant && request.Messages[last].ToolCalls != nil {
+				toolCall.Index = len(request.Messages[last].ToolCalls)
+				request.Messages[last].ToolCalls = append(request.Messages[last].ToolCalls, toolCall)
+			} else {
+				request.Messages = append(request.Messages, types.ChatCompletionMessage{
+					Role:      types.ChatMessageRoleAssistant,
+					ToolCalls: []*types.ChatCompletionToolCalls{toolCall},
+				})
+			}
+		case types.RealtimeItemTypeFunctionCallOutput:
+			request.Messages = append(request.Messages, types.ChatCompletionMessage{
+				Role:       types.ChatMessageRoleTool,
+				Content:    item.Output,
+				ToolCallID: item.CallId,
+			})
+		}
+	}
+
+	for _, tool := range e.session.Tools {
+		request.Tools = append(request.Tools, &types.ChatCompletionTool{
+			Type: "function",
+			Function: types.ChatCompletionFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+	if toolChoice, ok := e.session.ToolChoice.(string); ok && len(request.Tools) > 0 {
+		request.ToolChoice = toolChoice
+	}
+
+	return request
+}
+
+func (e *realtimeEmulator) generate(active *realtimeEmulatorResponse, request *types.ChatCompletionRequest) {
+	defer func() {
+		e.mu.Lock()
+		e.active = nil
+		e.mu.Unlock()
+	}()
+
+	created := types.NewRealtimeEvent(types.EventTypeResponseCreated)
+	created.Response = active.response
+	e.send(created)
+
+	channel := e.provider.GetChannel()
+	usage := &types.Usage{
+		PromptTokens: common.CountTokenMessages(request.Messages, request.Model, channel.PreCost),
+	}
+	e.provider.SetUsage(usage)
+
+	output, status, errWithCode := e.streamResponse(active, request)
+	if errWithCode != nil {
+		code, _ := errWithCode.Code.(string)
+		if code == "" {
+			code = "upstream_error"
+		}
+		e.sendError("", errWithCode.Type, code, errWithCode.Message)
+	}
+
+	usage = e.provider.GetUsage()
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	usageEvent := &types.UsageEvent{
+		InputTokens:        usage.PromptTokens,
+		OutputTokens:       usage.CompletionTokens,
+		TotalTokens:        usage.TotalTokens,
+		InputTokenDetails:  usage.PromptTokensDetails,
+		OutputTokenDetails: usage.CompletionTokensDetails,
+	}
+
+	e.mu.Lock()
+	e.items = append(e.items, output...)
+	e.mu.Unlock()
+
+	response := *active.response
+	response.Status = status
+	response.Output = output
+	response.Usage = usageEvent
+	done := types.NewRealtimeEvent(types.EventTypeResponseDone)
+	done.Response = &response
+	e.send(done)
+
+	// 请求失败时上游没有返回内容，不计费
+	if errWithCode != nil && len(output) == 0 {
+		return
+	}
+
+	if err := e.relay.usageHandler(usageEvent); err != nil {
+		e.writeMessage([]byte(err.Error()))
+		e.relay.userConn.Close()
+	}
+}
+
+// streamResponse 读取流式对话结果并转换为 realtime 事件，返回生成的对话内容和回复状态
+func (e *realtimeEmulator) streamResponse(active *realtimeEmulatorResponse, request *types.ChatCompletionRequest) ([]types.RealtimeItem, string, *types.OpenAIErrorWithStatusCode) {
+	stream, errWithCode := e.provider.CreateChatCompletionStream(request)
+	if errWithCode != nil {
+		return nil, "failed", errWithCode
+	}
+	defer stream.Close()
+
+	e.mu.Lock()
+	active.closeFunc = stream.Close
+	cancelled := active.cancelled
+	e.mu.Unlock()
+	if cancelled {
+		return nil, "cancelled", nil
+	}
+
+	var (
+		message   *types.RealtimeItem
+		text      strings.Builder
+		toolCalls []*types.RealtimeItem
+	)
+
+	dataChan, errChan := stream.Recv()
+	var streamErr error
+loop:
+	for {
+		select {
+		case data, ok := <-dataChan:
+			if !ok {
+				break loop
+			}
+			var chunk types.ChatCompletionStreamResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				continue
+			}
+			for _, choice := range chunk.Choices {
+				if choice.Delta.Content != "" {
+					if message == nil {
+						message = e.addOutputItem(active, &types.RealtimeItem{
+							Type: types.RealtimeItemTypeMessage,
+							Role: types.ChatMessageRoleAssistant,
+						}, 0)
+						partAdded := newResponseItemEvent(types.EventTypeResponseContentPartAdded, active, message, 0)
+						partAdded.Part = &types.RealtimeContent{Type: "text"}
+						e.send(partAdded)
+					}
+					text.WriteString(choice.Delta.Content)
+					delta := newResponseItemEvent(types.EventTypeResponseTextDelta, active, message, 0)
+					delta.Delta = choice.Delta.Content
+					e.send(delta)
+				}
+
+				for _, toolCall := range choice.Delta.ToolCalls {
+					for len(toolCalls) <= toolCall.Index {
+						toolCalls = append(toolCalls, &types.RealtimeItem{Type: types.RealtimeItemTypeFunctionCall})
+					}
+					item := toolCalls[toolCall.Index]
+					if toolCall.Id != "" {
+						item.CallId = toolCall.Id
+					}
+					if toolCall.Function != nil {
+						item.Name += toolCall.Function.Name
+						item.Arguments += toolCall.Function.Arguments
+					}
+				}
+			}
+		case err := <-errChan:
+			if !errors.Is(err, io.EOF) {
+				streamErr = err
+			}
+			break loop
+		}
+	}
+
+	e.mu.Lock()
+	cancelled = active.cancelled
+	e.mu.Unlock()
+
+	output := make([]types.RealtimeItem, 0, len(toolCalls)+1)
+	outputIndex := 0
+	if message != nil {
+		part := types.RealtimeContent{Type: "text", Text: text.String()}
+		textDone := newResponseItemEvent(types.EventTypeResponseTextDone, active, message, outputIndex)
+		textDone.Text = part.Text
+		partDone := newResponseItemEvent(types.EventTypeResponseContentPartDone, active, message, outputIndex)
+		partDone.Part = &part
+		e.send(textDone)
+		e.send(partDone)
+
+		message.Content = []types.RealtimeContent{part}
+		output = append(output, *e.doneOutputItem(active, message, outputIndex, cancelled))
+		outputIndex++
+	}
+
+	// 函数调用在参数完整后一次性发送
+	for _, toolCall := range toolCalls {
+		if cancelled || toolCall.Name == "" {
+			continue
+		}
+		if toolCall.CallId == "" {
+			toolCall.CallId = types.NewRealtimeId("call")
+		}
+		item := e.addOutputItem(active, toolCall, outputIndex)
+		argumentsDone := newResponseItemEvent(types.EventTypeResponseFunctionArgsDone, active, item, outputIndex)
+		argumentsDone.CallId = item.CallId
+		argumentsDone.Name = item.Name
+		argumentsDone.Arguments = item.Arguments
+		e.send(argumentsDone)
+		output = append(output, *e.doneOutputItem(active, item, outputIndex, false))
+		outputIndex++
+	}
+
+	if cancelled {
+		return output, "cancelled", nil
+	}
+	if streamErr != nil {
+		return output, "failed", common.ErrorWrapper(streamErr, "stream_error", http.StatusInternalServerError)
+	}
+
+	return output, "completed", nil
+}
+
+func (e *realtimeEmulator) addOutputItem(active *realtimeEmulatorResponse, item *types.RealtimeItem, outputIndex int) *types.RealtimeItem {
+	item.Id = types.NewRealtimeId("item")
+	item.Object = "realtime.item"
+	item.Status = "in_progress"
+
+	added := types.NewRealtimeEvent(types.EventTypeResponseOutputItemAdded)
+	added.ResponseId = active.response.ID
+	added.OutputIndex = outputIndex
+	added.Item = item
+	e.send(added)
+
+	return item
+}
+
+func (e *realtimeEmulator) doneOutputItem(active *realtimeEmulatorResponse, item *types.RealtimeItem, outputIndex int, cancelled bool) *types.RealtimeItem {
+	item.Status = "completed"
+	if cancelled {
+		item.Status = "incomplete"
+	}
+
+	done := types.NewRealtimeEvent(types.EventTypeResponseOutputItemDone)
+	done.ResponseId = active.response.ID
+	done.OutputIndex = outputIndex
+	done.Item = item
+	e.send(done)
+
+	return item
+}
+
+func newResponseItemEvent(eventType string, active *realtimeEmulatorResponse, item *types.RealtimeItem, outputIndex int) *types.RealtimeEvent {
+	event := types.NewRealtimeEvent(eventType)
+	event.ResponseId = active.response.ID
+	event.ItemId = item.Id
+	event.OutputIndex = outputIndex
+	return event
+}
+
+func (e *realtimeEmulator) send(event *types.RealtimeEvent) {
+	e.writeMessage(event.Marshal())
+}
+
+func (e *realtimeEmulator) sendError(eventId, errType, code, message string) {
+	errEvent := types.NewErrorEvent(eventId, errType, code, message)
+	e.writeMessage([]byte(errEvent.Error()))
+}
+
+func (e *realtimeEmulator) writeMessage(message []byte) {
+	e.writeLock.Lock()
+	defer e.writeLock.Unlock()
+
+	if err := e.relay.userConn.WriteMessage(websocket.TextMessage, message); err != nil {
+		logger.SysError(fmt.Sprintf("realtime emulation write error: %s", err.Error()))
+	}
+}
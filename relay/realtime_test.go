@@ -0,0 +1,50 @@
+package relay
+
+import (
+	"testing"
+
+	"one-api/common/config"
+	"one-api/model"
+	"one-api/providers"
+	providersBase "one-api/providers/base"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestRealtimeIsRealtimeModel(t *testing.T) {
+	azureRealtime := datatypes.JSONSlice[string]{"voice-prod"}
+	tests := []struct {
+		name          string
+		channel       *model.Channel
+		originalModel string
+		modelName     string
+		want          bool
+	}{
+		{name: "openai realtime model", channel: &model.Channel{Type: config.ChannelTypeOpenAI}, originalModel: "gpt-realtime", modelName: "gpt-realtime", want: true},
+		{name: "openai chat model is emulated", channel: &model.Channel{Type: config.ChannelTypeOpenAI}, originalModel: "gpt-4o", modelName: "gpt-4o"},
+		{name: "openai model named live is emulated", channel: &model.Channel{Type: config.ChannelTypeOpenAI}, originalModel: "live-chat", modelName: "live-chat"},
+		{name: "azure deployment with realtime name", channel: &model.Channel{Type: config.ChannelTypeAzure}, originalModel: "gpt-4o-realtime-preview", modelName: "gpt-4o-realtime-preview", want: true},
+		{name: "azure deployment without realtime name", channel: &model.Channel{Type: config.ChannelTypeAzure}, originalModel: "voice-prod", modelName: "voice-prod"},
+		{name: "azure deployment configured on channel", channel: &model.Channel{Type: config.ChannelTypeAzure, RealtimeModels: &azureRealtime}, originalModel: "voice-prod", modelName: "voice-prod", want: true},
+		{name: "channel setting uses the requested model", channel: &model.Channel{Type: config.ChannelTypeAzureV1, RealtimeModels: &azureRealtime}, originalModel: "voice-prod", modelName: "mapped-deployment", want: true},
+		{name: "gemini live model", channel: &model.Channel{Type: config.ChannelTypeGemini}, originalModel: "gemini-live-2.5-flash-preview", modelName: "gemini-live-2.5-flash-preview", want: true},
+		{name: "gemini native audio model", channel: &model.Channel{Type: config.ChannelTypeGemini}, originalModel: "gemini-2.5-flash-native-audio-preview", modelName: "gemini-2.5-flash-native-audio-preview", want: true},
+		{name: "gemini chat model is emulated", channel: &model.Channel{Type: config.ChannelTypeGemini}, originalModel: "gemini-2.5-flash", modelName: "gemini-2.5-flash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := ""
+			tt.channel.Proxy = &proxy
+			provider, ok := providers.GetProvider(tt.channel, nil).(providersBase.RealtimeInterface)
+			require.True(t, ok)
+
+			relay := &RelayModeChatRealtime{}
+			relay.originalModel = tt.originalModel
+			relay.modelName = tt.modelName
+			assert.Equal(t, tt.want, relay.isRealtimeModel(provider))
+		})
+	}
+}
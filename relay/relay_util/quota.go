@@ -8,11 +8,14 @@ import (
 	"one-api/common"
 	"one-api/common/config"
 	"one-api/common/logger"
+	"one-api/common/tracing"
+	"one-api/metrics"
 	"one-api/model"
 	"one-api/types"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Quota struct {
@@ -31,6 +34,10 @@ type Quota struct {
 	channelId        int
 	tokenId          int
 	HandelStatus     bool
+	fallbackFrom     string // 触发模型回退时的原始请求模型
+	fallbackReason   string
+	virtualModel     string
+	payloadRequestId string // 记录了请求内容时对应的请求 ID
 
 	startTime         time.Time
 	firstResponseTime time.Time
@@ -50,6 +57,11 @@ func NewQuota(c *gin.Context, modelName string, promptTokens int) *Quota {
 		isBackupGroup: isBackupGroup, // 记录是否使用备用分组
 	}
 
+	quota.fallbackFrom = c.GetString("fallback_from")
+	quota.fallbackReason = c.GetString("fallback_reason")
+	quota.virtualModel = c.GetString("virtual_model")
+	quota.payloadRequestId = c.GetString("payload_request_id")
+
 	quota.price = *model.PricingInstance.GetPrice(quota.modelName)
 	quota.groupName = c.GetString("token_group")
 	quota.backupGroupName = c.GetString("token_backup_group")
@@ -172,6 +184,7 @@ func (q *Quota) completedQuotaConsumption(usage *types.Usage, tokenName string,
 		sourceIp,
 	)
 	model.UpdateUserUsedQuotaAndRequestCount(q.userId, quota)
+	metrics.RecordUsage(q.modelName, q.groupName, q.userId, usage, quota)
 
 	return nil
 }
@@ -194,8 +207,15 @@ func (q *Quota) Consume(c *gin.Context, usage *types.Usage, isStream bool) {
 	q.startTime = c.GetTime("requestStartTime")
 	// 如果没有报错，则消费配额
 	go func(ctx context.Context) {
+		ctx, span := tracing.Start(ctx, "quota.settle",
+			attribute.String("model", q.modelName),
+			attribute.Int("channel.id", q.channelId),
+		)
+		defer span.End()
+
 		err := q.completedQuotaConsumption(usage, tokenName, isStream, c.ClientIP(), ctx)
 		if err != nil {
+			tracing.SetError(span, err.Error())
 			logger.LogError(ctx, err.Error())
 		}
 	}(c.Request.Context())
@@ -235,6 +255,19 @@ func (q *Quota) GetLogMeta(usage *types.Usage) map[string]any {
 		meta["extra_billing"] = q.extraBillingData
 	}
 
+	if q.fallbackFrom != "" {
+		meta["fallback_from"] = q.fallbackFrom
+		meta["fallback_reason"] = q.fallbackReason
+	}
+
+	if q.virtualModel != "" {
+		meta["virtual_model"] = q.virtualModel
+	}
+
+	if q.payloadRequestId != "" {
+		meta["payload_request_id"] = q.payloadRequestId
+	}
+
 	return meta
 }
 
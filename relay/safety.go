@@ -0,0 +1,475 @@
+package relay
+
+import (
+	"encoding/json"
+	"net/http"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/model"
+	"one-api/safty"
+	saftyTypes "one-api/safty/types"
+	"one-api/types"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 流式输出默认每累计多少字符检查一次
+const defaultSafetyStreamChunkSize = 200
+
+// 流式分段检查时保留上一段末尾的字符数，避免敏感内容被切断
+const safetyStreamOverlap = 32
+
+// 流式脱敏时每个 choice 最多暂存的字节数
+const safetyStreamMaxHeld = 1024
+
+// getSafetyPolicy 获取当前分组的审查策略，未单独配置时沿用系统设置只检查输入
+func getSafetyPolicy(c *gin.Context) *saftyTypes.Policy {
+	if !config.EnableSafe {
+		return nil
+	}
+
+	if policy := model.SafetyPolicyInstance.GetPolicy(c.GetString("token_group")); policy != nil {
+		return policy
+	}
+
+	return &saftyTypes.Policy{
+		Input:  true,
+		Action: saftyTypes.ActionBlock,
+	}
+}
+
+// checkSafetyText 按策略检查文本，返回处理后的文本
+func checkSafetyText(c *gin.Context, policy *saftyTypes.Policy, stage, text string) (string, *types.OpenAIErrorWithStatusCode) {
+	decision := safty.CheckWithPolicy(policy, text)
+	if decision.Action == "" {
+		return text, nil
+	}
+
+	recordSafetyEvent(c, stage, text, decision)
+
+	if decision.Action == saftyTypes.ActionBlock {
+		return text, common.StringErrorWrapperLocal(decision.Result.Reason, decision.Result.Code, http.StatusBadRequest)
+	}
+
+	return decision.Content, nil
+}
+
+// recordSafetyEvent 记录命中事件，原文可能包含敏感信息，只保存命中位置和脱敏后的内容
+func recordSafetyEvent(c *gin.Context, stage, text string, decision *safty.Decision) {
+	content := ""
+	if decision.Action == saftyTypes.ActionRedact {
+		content = decision.Content
+	}
+
+	event := &model.SafetyEvent{
+		UserId:    c.GetInt("id"),
+		TokenId:   c.GetInt("token_id"),
+		ChannelId: c.GetInt("channel_id"),
+		Group:     c.GetString("token_group"),
+		ModelName: c.GetString("original_model"),
+		Stage:     stage,
+		Tool:      decision.Tool,
+		Action:    decision.Action,
+		Code:      decision.Result.Code,
+		Reason:    decision.Result.Reason,
+		Details:   decision.Result.Details,
+		Matches:   decision.Result.Matches,
+		Content:   content,
+		RequestId: c.GetString(logger.RequestIdKey),
+		// 按字符计算，与命中位置一致
+		ContentLength: utf8.RuneCountInString(text),
+	}
+
+	go model.RecordSafetyEvent(event)
+}
+
+// checkInputSafety 检查输入内容，支持脱敏时返回处理后的内容
+// 同一请求重试时不再重复检查
+func checkInputSafety(c *gin.Context, content any) (any, *types.OpenAIErrorWithStatusCode) {
+	policy := getSafetyPolicy(c)
+	if policy == nil || !policy.Input || content == nil {
+		return content, nil
+	}
+
+	switch v := content.(type) {
+	case string:
+		return checkSafetyText(c, policy, saftyTypes.StageInput, v)
+	case []string:
+		for i, text := range v {
+			newText, err := checkSafetyText(c, policy, saftyTypes.StageInput, text)
+			if err != nil {
+				return content, err
+			}
+			v[i] = newText
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			switch part := item.(type) {
+			case string:
+				newText, err := checkSafetyText(c, policy, saftyTypes.StageInput, part)
+				if err != nil {
+					return content, err
+				}
+				v[i] = newText
+			case map[string]any:
+				text, ok := part["text"].(string)
+				if !ok || text == "" {
+					continue
+				}
+				newText, err := checkSafetyText(c, policy, saftyTypes.StageInput, text)
+				if err != nil {
+					return content, err
+				}
+				part["text"] = newText
+			}
+		}
+		return v, nil
+	default:
+		// 其他格式只做检查，无法脱敏时按拦截处理
+		contentByte, err := json.Marshal(v)
+		if err != nil {
+			return content, nil
+		}
+		text := string(contentByte)
+		newText, errWithCode := checkSafetyText(c, policy, saftyTypes.StageInput, text)
+		if errWithCode != nil {
+			return content, errWithCode
+		}
+		if newText != text {
+			return content, common.StringErrorWrapperLocal(saftyTypes.SafeDefaultErrorMessage, saftyTypes.SafeDefaultErrorCode, http.StatusBadRequest)
+		}
+		return content, nil
+	}
+}
+
+// checkInputSafetyOnce 在首次发送前检查输入，重试时跳过
+func checkInputSafetyOnce(c *gin.Context, check func() *types.OpenAIErrorWithStatusCode) *types.OpenAIErrorWithStatusCode {
+	if c.GetBool("safety_input_checked") {
+		return nil
+	}
+
+	if err := check(); err != nil {
+		return err
+	}
+	c.Set("safety_input_checked", true)
+
+	return nil
+}
+
+// getOutputSafetyPolicy 获取需要检查输出时的策略
+// 只用于 OpenAI 格式的对话和补全接口，原生 Claude/Gemini 接口原样转发上游输出，不做输出审查
+func getOutputSafetyPolicy(c *gin.Context) *saftyTypes.Policy {
+	policy := getSafetyPolicy(c)
+	if policy == nil || !policy.Output {
+		return nil
+	}
+
+	return policy
+}
+
+// checkChatResponseSafety 检查非流式对话的输出
+func checkChatResponseSafety(c *gin.Context, response *types.ChatCompletionResponse) *types.OpenAIErrorWithStatusCode {
+	policy := getOutputSafetyPolicy(c)
+	if policy == nil || response == nil {
+		return nil
+	}
+
+	for i := range response.Choices {
+		content, ok := response.Choices[i].Message.Content.(string)
+		if !ok || content == "" {
+			continue
+		}
+
+		newContent, err := checkSafetyText(c, policy, saftyTypes.StageOutput, content)
+		if err != nil {
+			return err
+		}
+		response.Choices[i].Message.Content = newContent
+	}
+
+	return nil
+}
+
+// checkCompletionResponseSafety 检查非流式补全的输出
+func checkCompletionResponseSafety(c *gin.Context, response *types.CompletionResponse) *types.OpenAIErrorWithStatusCode {
+	policy := getOutputSafetyPolicy(c)
+	if policy == nil || response == nil {
+		return nil
+	}
+
+	for i := range response.Choices {
+		if response.Choices[i].Text == "" {
+			continue
+		}
+
+		newText, err := checkSafetyText(c, policy, saftyTypes.StageOutput, response.Choices[i].Text)
+		if err != nil {
+			return err
+		}
+		response.Choices[i].Text = newText
+	}
+
+	return nil
+}
+
+// safetyStreamFilter 对流式输出分段审查
+// 输出累计到一定长度后检查一次，命中拦截时中断输出
+// 脱敏时每个 choice 暂存末尾的内容，避免敏感信息被拆分到多个分片中而无法识别
+type safetyStreamFilter struct {
+	c       *gin.Context
+	policy  *saftyTypes.Policy
+	size    int
+	pending strings.Builder
+	overlap string
+
+	held      map[int]string
+	chat      bool
+	lastChunk map[string]any
+}
+
+func newSafetyStreamFilter(c *gin.Context) *safetyStreamFilter {
+	policy := getOutputSafetyPolicy(c)
+	if policy == nil {
+		return nil
+	}
+
+	size := policy.StreamChunkSize
+	if size <= 0 {
+		size = defaultSafetyStreamChunkSize
+	}
+
+	return &safetyStreamFilter{
+		c:      c,
+		policy: policy,
+		size:   size,
+		held:   make(map[int]string),
+	}
+}
+
+// Filter 处理一条流数据，返回需要发送给客户端的数据
+func (f *safetyStreamFilter) Filter(data string) (string, *types.OpenAIErrorWithStatusCode) {
+	var chunk map[string]any
+	if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+		return data, nil
+	}
+
+	choices, ok := chunk["choices"].([]any)
+	if !ok || len(choices) == 0 {
+		return data, nil
+	}
+	redact := f.policy.Action == saftyTypes.ActionRedact
+	if redact {
+		f.lastChunk = chunk
+	}
+
+	modified := false
+	for _, item := range choices {
+		choice, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		// chat 格式在 delta.content，completions 格式在 text
+		container := choice
+		key := "text"
+		if delta, ok := choice["delta"].(map[string]any); ok {
+			container = delta
+			key = "content"
+			f.chat = true
+		}
+
+		text, _ := container[key].(string)
+		if redact {
+			index := 0
+			if value, ok := choice["index"].(float64); ok {
+				index = int(value)
+			}
+			// 结束时输出该 choice 暂存的全部内容
+			finished := choice["finish_reason"] != nil
+			redacted := f.redactStream(index, text, finished)
+			if redacted != text {
+				container[key] = redacted
+				text = redacted
+				modified = true
+			}
+		}
+
+		if text != "" {
+			f.pending.WriteString(text)
+		}
+	}
+
+	if f.pending.Len() >= f.size {
+		if err := f.check(); err != nil {
+			return "", err
+		}
+	}
+
+	if !modified {
+		return data, nil
+	}
+
+	newData, err := json.Marshal(chunk)
+	if err != nil {
+		return data, nil
+	}
+
+	return string(newData), nil
+}
+
+// redactStream 脱敏一个 choice 的分片，返回可以输出的内容
+func (f *safetyStreamFilter) redactStream(index int, text string, finished bool) string {
+	data := f.held[index] + text
+	delete(f.held, index)
+	if data == "" {
+		return ""
+	}
+
+	if finished {
+		return safty.RedactWithPolicy(f.policy, data)
+	}
+
+	output, held := splitSafetyRedact(f.policy, data)
+	if held != "" {
+		f.held[index] = held
+	}
+
+	return output
+}
+
+// Flush 输出暂存的内容并检查剩余未检查的输出，没有暂存内容时返回空字符串
+func (f *safetyStreamFilter) Flush() (string, *types.OpenAIErrorWithStatusCode) {
+	data := f.flushHeld()
+
+	if f.pending.Len() > 0 {
+		if err := f.check(); err != nil {
+			return "", err
+		}
+	}
+
+	return data, nil
+}
+
+func (f *safetyStreamFilter) flushHeld() string {
+	if f.lastChunk == nil || len(f.held) == 0 {
+		return ""
+	}
+
+	indexes := make([]int, 0, len(f.held))
+	for index := range f.held {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	choices := make([]any, 0, len(indexes))
+	for _, index := range indexes {
+		text := safty.RedactWithPolicy(f.policy, f.held[index])
+		f.pending.WriteString(text)
+		if f.chat {
+			choices = append(choices, map[string]any{
+				"index": index,
+				"delta": map[string]any{"content": text},
+			})
+		} else {
+			choices = append(choices, map[string]any{
+				"index": index,
+				"text":  text,
+			})
+		}
+	}
+	f.held = make(map[int]string)
+
+	f.lastChunk["choices"] = choices
+	delete(f.lastChunk, "usage")
+	data, err := json.Marshal(f.lastChunk)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+// splitSafetyRedact 将内容分为可以输出的脱敏内容和需要暂存的末尾内容
+// 末尾至少保留 safetyStreamOverlap 个字符，分割点不能切断可能是敏感信息的连续字符，且分割前后的脱敏结果需要一致
+func splitSafetyRedact(policy *saftyTypes.Policy, data string) (string, string) {
+	if utf8.RuneCountInString(data) <= safetyStreamOverlap {
+		return "", data
+	}
+
+	split := len(data)
+	for i := 0; i < safetyStreamOverlap; i++ {
+		_, size := utf8.DecodeLastRuneInString(data[:split])
+		split -= size
+	}
+
+	redacted := safty.RedactWithPolicy(policy, data)
+	for split > 0 {
+		r, size := utf8.DecodeLastRuneInString(data[:split])
+		if !isSensitiveRune(r) {
+			head := safty.RedactWithPolicy(policy, data[:split])
+			if head+safty.RedactWithPolicy(policy, data[split:]) == redacted {
+				return head, data[split:]
+			}
+		}
+		split -= size
+	}
+
+	// 暂存内容过长时不再等待，直接输出
+	if len(data) > safetyStreamMaxHeld {
+		return redacted, ""
+	}
+
+	return "", data
+}
+
+// isSensitiveRune 可能组成邮箱、密钥、卡号等敏感信息的字符
+func isSensitiveRune(r rune) bool {
+	if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+		return true
+	}
+
+	return strings.ContainsRune("@._%+-", r)
+}
+
+func (f *safetyStreamFilter) check() *types.OpenAIErrorWithStatusCode {
+	text := f.overlap + f.pending.String()
+	f.pending.Reset()
+
+	runes := []rune(text)
+	if len(runes) > safetyStreamOverlap {
+		f.overlap = string(runes[len(runes)-safetyStreamOverlap:])
+	} else {
+		f.overlap = text
+	}
+
+	// 已发送的内容无法再脱敏，这里只记录命中或中断输出
+	_, err := checkSafetyText(f.c, f.policy, saftyTypes.StageOutput, text)
+
+	return err
+}
+
+// safetyStreamErrorData 拦截时发送给客户端的错误数据
+func safetyStreamErrorData(err *types.OpenAIErrorWithStatusCode) string {
+	errorBody, _ := json.Marshal(types.OpenAIErrorResponse{
+		Error: err.OpenAIError,
+	})
+
+	return "data: " + string(errorBody) + "\n\n"
+}
+
+// writeSafetyStreamError 流式输出被拦截时，发送错误信息并结束输出
+func writeSafetyStreamError(c *gin.Context, err *types.OpenAIErrorWithStatusCode) {
+	select {
+	case <-c.Request.Context().Done():
+		// 客户端已断开，不执行任何操作，直接跳过
+	default:
+		c.Writer.Write([]byte(safetyStreamErrorData(err)))
+		c.Writer.Write([]byte("data: [DONE]\n\n"))
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,220 @@
+package relay
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"one-api/model"
+	"one-api/safty"
+	"one-api/safty/providers/regex"
+	saftyTypes "one-api/safty/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const testSafetyApiKey = "sk-abcdefghijklmnopqrstuvwxyz0123"
+
+func setupSafetyTest(t *testing.T, action string) (*gin.Context, *saftyTypes.Policy) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存数据库每个连接独立，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.SafetyEvent{}))
+
+	originDB := model.DB
+	originTool, hasTool := safty.Tools["Regex"]
+	model.DB = db
+	safty.Tools["Regex"] = regex.NewRegexChecker()
+	t.Cleanup(func() {
+		model.DB = originDB
+		if hasTool {
+			safty.Tools["Regex"] = originTool
+		} else {
+			delete(safty.Tools, "Regex")
+		}
+	})
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	return c, &saftyTypes.Policy{Tools: []string{"Regex"}, Output: true, Action: action}
+}
+
+func newTestSafetyStreamFilter(c *gin.Context, policy *saftyTypes.Policy) *safetyStreamFilter {
+	return &safetyStreamFilter{
+		c:      c,
+		policy: policy,
+		size:   defaultSafetyStreamChunkSize,
+		held:   make(map[int]string),
+	}
+}
+
+func chatStreamChunk(content string, finishReason any) string {
+	data, _ := json.Marshal(map[string]any{
+		"id":     "chatcmpl-test",
+		"object": "chat.completion.chunk",
+		"choices": []any{map[string]any{
+			"index":         0,
+			"delta":         map[string]any{"content": content},
+			"finish_reason": finishReason,
+		}},
+	})
+	return string(data)
+}
+
+// collectStreamContent 拼接流数据中输出给客户端的内容
+func collectStreamContent(t *testing.T, chunks []string) string {
+	var builder strings.Builder
+	for _, chunk := range chunks {
+		if chunk == "" {
+			continue
+		}
+		var data struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(chunk), &data))
+		for _, choice := range data.Choices {
+			builder.WriteString(choice.Delta.Content)
+		}
+	}
+	return builder.String()
+}
+
+func TestSafetyStreamFilterRedactAcrossChunks(t *testing.T) {
+	tests := []struct {
+		name   string
+		deltas []string
+		finish bool
+		want   string
+	}{
+		{
+			name:   "api key split across deltas",
+			deltas: []string{"your key is sk-abcdefgh", "ijklmnopqrstuvwx", "yz0123 keep it safe"},
+			finish: true,
+			want:   "your key is [REDACTED:api_key] keep it safe",
+		},
+		{
+			name:   "email split by character",
+			deltas: strings.Split("contact alice.smith@example.com for details", ""),
+			finish: true,
+			want:   "contact [REDACTED:email] for details",
+		},
+		{
+			name:   "long text without secrets",
+			deltas: []string{strings.Repeat("hello world ", 10), strings.Repeat("你好世界", 20)},
+			finish: true,
+			want:   strings.Repeat("hello world ", 10) + strings.Repeat("你好世界", 20),
+		},
+		{
+			name:   "held content flushed without finish reason",
+			deltas: []string{"token: ", testSafetyApiKey},
+			want:   "token: [REDACTED:api_key]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, policy := setupSafetyTest(t, saftyTypes.ActionRedact)
+			filter := newTestSafetyStreamFilter(c, policy)
+
+			var output []string
+			for _, delta := range tt.deltas {
+				data, err := filter.Filter(chatStreamChunk(delta, nil))
+				require.Nil(t, err)
+				output = append(output, data)
+			}
+			if tt.finish {
+				data, err := filter.Filter(chatStreamChunk("", "stop"))
+				require.Nil(t, err)
+				output = append(output, data)
+			}
+			data, err := filter.Flush()
+			require.Nil(t, err)
+			output = append(output, data)
+
+			assert.Equal(t, tt.want, collectStreamContent(t, output))
+		})
+	}
+}
+
+func TestSplitSafetyRedact(t *testing.T) {
+	policy := &saftyTypes.Policy{Tools: []string{"Regex"}, Action: saftyTypes.ActionRedact}
+	_, _ = setupSafetyTest(t, saftyTypes.ActionRedact)
+
+	tests := []struct {
+		name       string
+		data       string
+		wantOutput string
+		wantHeld   string
+	}{
+		{name: "short content is held", data: "hello", wantHeld: "hello"},
+		{
+			name:       "keeps the last characters",
+			data:       strings.Repeat("a ", 20) + strings.Repeat("b ", 16),
+			wantOutput: strings.Repeat("a ", 20),
+			wantHeld:   strings.Repeat("b ", 16),
+		},
+		{
+			name:       "does not split a secret",
+			data:       "key " + testSafetyApiKey + strings.Repeat(" ", 10),
+			wantOutput: "key ",
+			wantHeld:   testSafetyApiKey + strings.Repeat(" ", 10),
+		},
+		{
+			name:       "redacts complete secrets",
+			data:       "mail bob@example.com then " + strings.Repeat("x ", 16) + "end",
+			wantOutput: "mail [REDACTED:email] then x ",
+			wantHeld:   strings.Repeat("x ", 15) + "end",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, held := splitSafetyRedact(policy, tt.data)
+			assert.Equal(t, tt.wantOutput, output)
+			assert.Equal(t, tt.wantHeld, held)
+		})
+	}
+}
+
+func TestRecordSafetyEventWithoutRawText(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      string
+		wantContent string
+	}{
+		{name: "block keeps no content", action: saftyTypes.ActionBlock},
+		{name: "flag keeps no content", action: saftyTypes.ActionFlag},
+		{name: "redact keeps redacted content", action: saftyTypes.ActionRedact, wantContent: "我的邮箱是 [REDACTED:email]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, policy := setupSafetyTest(t, tt.action)
+			text := "我的邮箱是 alice@example.com"
+			checkSafetyText(c, policy, saftyTypes.StageOutput, text)
+
+			var event model.SafetyEvent
+			require.Eventually(t, func() bool {
+				return model.DB.First(&event).Error == nil
+			}, time.Second, 10*time.Millisecond)
+
+			assert.Equal(t, tt.wantContent, event.Content)
+			assert.NotContains(t, event.Content, "alice@example.com")
+			assert.Equal(t, 23, event.ContentLength)
+			require.Len(t, event.Matches, 1)
+			assert.Equal(t, saftyTypes.Match{Detector: "email", Start: 6, End: 23}, event.Matches[0])
+		})
+	}
+}
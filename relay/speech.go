@@ -5,6 +5,7 @@ import (
 	"one-api/common"
 	providersBase "one-api/providers/base"
 	"one-api/types"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 )
@@ -31,7 +32,8 @@ func (r *relaySpeech) setRequest() error {
 }
 
 func (r *relaySpeech) getPromptTokens() (int, error) {
-	return len(r.request.Input), nil
+	// 语音合成按字符数计费
+	return utf8.RuneCountInString(r.request.Input), nil
 }
 
 func (r *relaySpeech) send() (err *types.OpenAIErrorWithStatusCode, done bool) {
@@ -0,0 +1,49 @@
+package relay
+
+import (
+	"net/http"
+	"one-api/common/tracing"
+	"one-api/types"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startUpstreamSpan 为每一次上游请求创建 span，并绑定到请求上游使用的 context 中用于传递 traceparent
+func startUpstreamSpan(relay RelayBaseInterface) trace.Span {
+	c := relay.getContext()
+	attempt := c.GetInt("relay_attempt")
+	c.Set("relay_attempt", attempt+1)
+
+	provider := relay.getProvider()
+	channel := provider.GetChannel()
+	_, span := tracing.Start(c.Request.Context(), "upstream",
+		attribute.Int("channel.id", channel.Id),
+		attribute.Int("channel.type", channel.Type),
+		attribute.String("model", relay.getModelName()),
+		attribute.String("original_model", relay.getOriginalModel()),
+		attribute.Int("retry_index", attempt),
+		attribute.Bool("stream", relay.IsStream()),
+	)
+
+	if httpRequester := provider.GetRequester(); httpRequester != nil {
+		httpRequester.Context = trace.ContextWithSpan(httpRequester.Context, span)
+	}
+
+	return span
+}
+
+func endUpstreamSpan(relay RelayBaseInterface, span trace.Span, apiErr *types.OpenAIErrorWithStatusCode) {
+	if apiErr == nil {
+		tracing.SetStatus(span, http.StatusOK, "")
+	} else {
+		tracing.SetStatus(span, apiErr.StatusCode, apiErr.Message)
+		tracing.SetError(span, apiErr.Message)
+	}
+
+	if fallbackFrom := relay.getContext().GetString("fallback_from"); fallbackFrom != "" {
+		span.SetAttributes(attribute.String("fallback_from", fallbackFrom))
+	}
+
+	span.End()
+}
@@ -1,17 +1,24 @@
 package relay
 
 import (
+	"mime/multipart"
 	"net/http"
 	"one-api/common"
+	"one-api/common/audio"
+	"one-api/common/logger"
 	providersBase "one-api/providers/base"
 	"one-api/types"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
 )
 
 type relayTranscriptions struct {
 	relayBase
 	request types.AudioRequest
+	// 音频时长（秒），无法识别格式时为 0
+	duration float64
 }
 
 func NewRelayTranscriptions(c *gin.Context) *relayTranscriptions {
@@ -26,12 +33,13 @@ func (r *relayTranscriptions) setRequest() error {
 	}
 
 	r.setOriginalModel(r.request.Model)
+	r.duration = getAudioDuration(r.c, r.request.File)
 
 	return nil
 }
 
 func (r *relayTranscriptions) getPromptTokens() (int, error) {
-	return 0, nil
+	return audio.DurationToTokens(r.duration), nil
 }
 
 func (r *relayTranscriptions) send() (err *types.OpenAIErrorWithStatusCode, done bool) {
@@ -48,6 +56,7 @@ func (r *relayTranscriptions) send() (err *types.OpenAIErrorWithStatusCode, done
 	if err != nil {
 		return
 	}
+	setAudioDurationUsage(r.provider.GetUsage(), r.duration, response)
 	err = responseCustom(r.c, response)
 
 	if err != nil {
@@ -56,3 +65,41 @@ func (r *relayTranscriptions) send() (err *types.OpenAIErrorWithStatusCode, done
 
 	return
 }
+
+// getAudioDuration 读取上传音频的时长，用于按时长计费
+func getAudioDuration(c *gin.Context, file *multipart.FileHeader) float64 {
+	duration, err := audio.GetFileDuration(file)
+	if err != nil {
+		logger.LogWarn(c.Request.Context(), "failed to get audio duration: "+err.Error())
+		return 0
+	}
+	return duration
+}
+
+// setAudioDurationUsage 按音频时长计费，优先使用上游返回的时长，
+// 折算的 tokens 不会低于供应商按识别文本计算的用量
+func setAudioDurationUsage(usage *types.Usage, duration float64, response *types.AudioResponseWrapper) {
+	if usage == nil {
+		return
+	}
+	if upstreamDuration := getResponseAudioDuration(response); upstreamDuration > 0 {
+		duration = upstreamDuration
+	}
+
+	tokens := audio.DurationToTokens(duration)
+	if tokens <= usage.PromptTokens+usage.CompletionTokens {
+		return
+	}
+	usage.PromptTokens = tokens
+	usage.CompletionTokens = 0
+	usage.TotalTokens = usage.PromptTokens
+}
+
+// getResponseAudioDuration 读取 verbose_json 响应中上游识别的音频时长
+func getResponseAudioDuration(response *types.AudioResponseWrapper) float64 {
+	if response == nil || !strings.Contains(response.Headers["Content-Type"], "json") {
+		return 0
+	}
+
+	return gjson.GetBytes(response.Body, "duration").Float()
+}
@@ -0,0 +1,65 @@
+package relay
+
+import (
+	"testing"
+
+	"one-api/common/audio"
+	"one-api/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAudioDurationUsage(t *testing.T) {
+	jsonResponse := func(body string) *types.AudioResponseWrapper {
+		return &types.AudioResponseWrapper{
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    []byte(body),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		usage    types.Usage
+		duration float64
+		response *types.AudioResponseWrapper
+		want     int
+	}{
+		{
+			name:     "bill by local duration",
+			usage:    types.Usage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3},
+			duration: 60,
+			response: jsonResponse(`{"text":"hi"}`),
+			want:     audio.TokensPerMinute,
+		},
+		{
+			name:     "upstream duration is preferred",
+			usage:    types.Usage{},
+			duration: 1,
+			response: jsonResponse(`{"text":"hi","duration":120}`),
+			want:     audio.TokensPerMinute * 2,
+		},
+		{
+			name:     "never below provider usage",
+			usage:    types.Usage{PromptTokens: 500, CompletionTokens: 100, TotalTokens: 600},
+			duration: 1,
+			response: jsonResponse(`{"text":"hi"}`),
+			want:     600,
+		},
+		{
+			name:     "unknown duration keeps provider usage",
+			usage:    types.Usage{PromptTokens: 0, CompletionTokens: 30, TotalTokens: 30},
+			duration: 0,
+			response: &types.AudioResponseWrapper{Headers: map[string]string{"Content-Type": "text/plain"}, Body: []byte(`{"duration":600}`)},
+			want:     30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := tt.usage
+			setAudioDurationUsage(&usage, tt.duration, tt.response)
+			assert.Equal(t, tt.want, usage.TotalTokens)
+			assert.Equal(t, usage.PromptTokens+usage.CompletionTokens, usage.TotalTokens)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package relay
 import (
 	"net/http"
 	"one-api/common"
+	"one-api/common/audio"
 	providersBase "one-api/providers/base"
 	"one-api/types"
 
@@ -12,6 +13,8 @@ import (
 type relayTranslations struct {
 	relayBase
 	request types.AudioRequest
+	// 音频时长（秒），无法识别格式时为 0
+	duration float64
 }
 
 func NewRelayTranslations(c *gin.Context) *relayTranslations {
@@ -26,12 +29,13 @@ func (r *relayTranslations) setRequest() error {
 	}
 
 	r.setOriginalModel(r.request.Model)
+	r.duration = getAudioDuration(r.c, r.request.File)
 
 	return nil
 }
 
 func (r *relayTranslations) getPromptTokens() (int, error) {
-	return 0, nil
+	return audio.DurationToTokens(r.duration), nil
 }
 
 func (r *relayTranslations) send() (err *types.OpenAIErrorWithStatusCode, done bool) {
@@ -48,6 +52,7 @@ func (r *relayTranslations) send() (err *types.OpenAIErrorWithStatusCode, done b
 	if err != nil {
 		return
 	}
+	setAudioDurationUsage(r.provider.GetUsage(), r.duration, response)
 	err = responseCustom(r.c, response)
 
 	if err != nil {
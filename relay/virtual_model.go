@@ -0,0 +1,84 @@
+package relay
+
+import (
+	"fmt"
+	"one-api/common"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/model"
+	"one-api/types"
+	"time"
+)
+
+// resolveVirtualModel 如果请求的是虚拟模型，根据规则将其解析为具体模型
+func resolveVirtualModel(relay RelayBaseInterface) error {
+	c := relay.getContext()
+	virtualName := relay.getOriginalModel()
+
+	virtualModel := model.VirtualModelsInstance.Get(virtualName)
+	if virtualModel == nil {
+		return nil
+	}
+
+	group := c.GetString("token_group")
+	if !virtualModel.AllowGroup(group) {
+		return fmt.Errorf("当前分组 %s 不可使用模型 %s", group, virtualName)
+	}
+
+	if err := checkLimitModel(c, virtualName); err != nil {
+		return err
+	}
+
+	req := getVirtualModelRequest(relay.getRequest(), virtualName)
+	req.Group = group
+
+	modelName, err := virtualModel.Resolve(req)
+	if err != nil {
+		return err
+	}
+
+	c.Set("virtual_model", virtualName)
+	c.Set("billing_virtual_model", virtualModel.FixedPrice)
+	relay.setOriginalModel(modelName)
+	logger.LogInfo(c.Request.Context(), fmt.Sprintf("virtual model %s resolved to %s", virtualName, modelName))
+
+	return nil
+}
+
+// getVirtualModelRequest 提取用于规则匹配的请求特征
+func getVirtualModelRequest(request any, modelName string) *model.VirtualModelRequest {
+	req := &model.VirtualModelRequest{
+		Now: time.Now(),
+	}
+
+	switch r := request.(type) {
+	case *types.ChatCompletionRequest:
+		req.PromptTokens = common.CountTokenMessages(r.Messages, modelName, config.PreCostNotImage)
+		req.HasTools = len(r.Tools) > 0 || len(r.Functions) > 0
+		if r.ResponseFormat != nil {
+			req.ResponseFormat = r.ResponseFormat.Type
+		}
+		for _, message := range r.Messages {
+			if len(message.Image) > 0 || len(message.Images) > 0 {
+				req.HasImages = true
+				break
+			}
+			for _, part := range message.ParseContent() {
+				if part.Type == types.ContentTypeImageURL {
+					req.HasImages = true
+					break
+				}
+			}
+		}
+	case *types.OpenAIResponsesRequest:
+		req.PromptTokens = common.CountTokenInputMessages(r.Input, modelName, config.PreCostNotImage)
+		req.HasTools = len(r.Tools) > 0
+		if r.Text != nil && r.Text.Format != nil {
+			req.ResponseFormat = r.Text.Format.Type
+		}
+	case *types.CompletionRequest:
+		req.PromptTokens = common.CountTokenInput(r.Prompt, modelName)
+	}
+
+	return req
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"one-api/common/config"
 	"one-api/controller"
 	"one-api/middleware"
 	"one-api/relay"
@@ -16,7 +17,7 @@ func SetApiRouter(router *gin.Engine) {
 	apiRouter.Use(gzip.Gzip(gzip.DefaultCompression))
 
 	systemInfo := apiRouter.Group("/system_info")
-	systemInfo.Use(middleware.RootAuth())
+	systemInfo.Use(middleware.PermissionAuth(config.PermissionOptionsWrite))
 	{
 		systemInfo.POST("/log", controller.SystemLog)
 	}
@@ -46,6 +47,10 @@ func SetApiRouter(router *gin.Engine) {
 		apiRouter.GET("/oauth/endpoint", middleware.CriticalRateLimit(), controller.OIDCEndpoint)
 		apiRouter.GET("/oauth/oidc", middleware.CriticalRateLimit(), controller.OIDCAuth)
 
+		apiRouter.GET("/saml/metadata", controller.SAMLMetadata)
+		apiRouter.GET("/saml/login", middleware.CriticalRateLimit(), controller.SAMLLogin)
+		apiRouter.POST("/saml/acs", middleware.CriticalRateLimit(), controller.SAMLAcs)
+
 		webauthnGroup := apiRouter.Group("/webauthn")
 		{
 			// 注册相关
@@ -69,16 +74,23 @@ func SetApiRouter(router *gin.Engine) {
 			userRoute.POST("/login", middleware.CriticalRateLimit(), controller.Login)
 			userRoute.GET("/logout", controller.Logout)
 
+			// 用量相关的只读接口，可以使用具备 management.read 范围的令牌访问
+			usageRoute := userRoute.Group("/")
+			usageRoute.Use(middleware.UserOrTokenAuth(config.TokenScopeManagementRead))
+			{
+				usageRoute.GET("/dashboard", controller.GetUserDashboard)
+				usageRoute.GET("/dashboard/rate", controller.GetRateRealtime)
+				usageRoute.GET("/invoice", controller.GetUserInvoice)
+				usageRoute.GET("/invoice/detail", controller.GetUserInvoiceDetail)
+			}
+
 			selfRoute := userRoute.Group("/")
 			selfRoute.Use(middleware.UserAuth())
 			{
-				selfRoute.GET("/dashboard", controller.GetUserDashboard)
-				selfRoute.GET("/dashboard/rate", controller.GetRateRealtime)
 				selfRoute.GET("/dashboard/uptimekuma/status-page", controller.UptimeKumaStatusPage)
 				selfRoute.GET("/dashboard/uptimekuma/status-page/heartbeat", controller.UptimeKumaStatusPageHeartbeat)
-				selfRoute.GET("/invoice", controller.GetUserInvoice)
-				selfRoute.GET("/invoice/detail", controller.GetUserInvoiceDetail)
 				selfRoute.GET("/self", controller.GetSelf)
+				selfRoute.GET("/self/permissions", controller.GetSelfPermissions)
 				selfRoute.PUT("/self", controller.UpdateSelf)
 				selfRoute.POST("/unbind", controller.Unbind)
 				// selfRoute.DELETE("/self", controller.DeleteSelf)
@@ -91,19 +103,18 @@ func SetApiRouter(router *gin.Engine) {
 			}
 
 			adminRoute := userRoute.Group("/")
-			adminRoute.Use(middleware.AdminAuth())
 			{
-				adminRoute.GET("/", controller.GetUsersList)
-				adminRoute.GET("/:id", controller.GetUser)
-				adminRoute.POST("/", controller.CreateUser)
-				adminRoute.POST("/manage", controller.ManageUser)
-				adminRoute.POST("/quota/:id", controller.ChangeUserQuota)
-				adminRoute.PUT("/", controller.UpdateUser)
-				adminRoute.DELETE("/:id", controller.DeleteUser)
+				adminRoute.GET("/", middleware.PermissionAuth(config.PermissionUsersRead), controller.GetUsersList)
+				adminRoute.GET("/:id", middleware.PermissionAuth(config.PermissionUsersRead), controller.GetUser)
+				adminRoute.POST("/", middleware.PermissionAuth(config.PermissionUsersManage), controller.CreateUser)
+				adminRoute.POST("/manage", middleware.PermissionAuth(config.PermissionUsersManage), controller.ManageUser)
+				adminRoute.POST("/quota/:id", middleware.PermissionAuth(config.PermissionUsersQuota), controller.ChangeUserQuota)
+				adminRoute.PUT("/", middleware.PermissionAuth(config.PermissionUsersManage), controller.UpdateUser)
+				adminRoute.DELETE("/:id", middleware.PermissionAuth(config.PermissionUsersManage), controller.DeleteUser)
 			}
 		}
 		optionRoute := apiRouter.Group("/option")
-		optionRoute.Use(middleware.RootAuth())
+		optionRoute.Use(middleware.PermissionAuth(config.PermissionOptionsWrite))
 		{
 			optionRoute.GET("/", controller.GetOptions)
 			optionRoute.PUT("/", controller.UpdateOption)
@@ -121,66 +132,83 @@ func SetApiRouter(router *gin.Engine) {
 
 		modelOwnedByRoute := apiRouter.Group("/model_ownedby")
 		modelOwnedByRoute.GET("/", controller.GetAllModelOwnedBy)
-		modelOwnedByRoute.Use(middleware.AdminAuth())
 		{
-			modelOwnedByRoute.GET("/:id", controller.GetModelOwnedBy)
-			modelOwnedByRoute.POST("/", controller.CreateModelOwnedBy)
-			modelOwnedByRoute.PUT("/", controller.UpdateModelOwnedBy)
-			modelOwnedByRoute.DELETE("/:id", controller.DeleteModelOwnedBy)
+			modelOwnedByRoute.GET("/:id", middleware.PermissionAuth(config.PermissionModelsRead), controller.GetModelOwnedBy)
+			modelOwnedByRoute.POST("/", middleware.PermissionAuth(config.PermissionModelsWrite), controller.CreateModelOwnedBy)
+			modelOwnedByRoute.PUT("/", middleware.PermissionAuth(config.PermissionModelsWrite), controller.UpdateModelOwnedBy)
+			modelOwnedByRoute.DELETE("/:id", middleware.PermissionAuth(config.PermissionModelsWrite), controller.DeleteModelOwnedBy)
 		}
 
 		modelInfoRoute := apiRouter.Group("/model_info")
 		modelInfoRoute.GET("/", controller.GetAllModelInfo)
-		modelInfoRoute.Use(middleware.AdminAuth())
 		{
-			modelInfoRoute.GET("/:id", controller.GetModelInfo)
-			modelInfoRoute.POST("/", controller.CreateModelInfo)
-			modelInfoRoute.PUT("/", controller.UpdateModelInfo)
-			modelInfoRoute.DELETE("/:id", controller.DeleteModelInfo)
+			modelInfoRoute.GET("/:id", middleware.PermissionAuth(config.PermissionModelsRead), controller.GetModelInfo)
+			modelInfoRoute.POST("/", middleware.PermissionAuth(config.PermissionModelsWrite), controller.CreateModelInfo)
+			modelInfoRoute.PUT("/", middleware.PermissionAuth(config.PermissionModelsWrite), controller.UpdateModelInfo)
+			modelInfoRoute.DELETE("/:id", middleware.PermissionAuth(config.PermissionModelsWrite), controller.DeleteModelInfo)
+		}
+
+		virtualModelRoute := apiRouter.Group("/virtual_model")
+		{
+			virtualModelRoute.GET("/", middleware.PermissionAuth(config.PermissionModelsRead), controller.GetAllVirtualModels)
+			virtualModelRoute.GET("/:id", middleware.PermissionAuth(config.PermissionModelsRead), controller.GetVirtualModel)
+			virtualModelRoute.POST("/", middleware.PermissionAuth(config.PermissionModelsWrite), controller.CreateVirtualModel)
+			virtualModelRoute.PUT("/", middleware.PermissionAuth(config.PermissionModelsWrite), controller.UpdateVirtualModel)
+			virtualModelRoute.DELETE("/:id", middleware.PermissionAuth(config.PermissionModelsWrite), controller.DeleteVirtualModel)
+		}
+
+		safetyEventRoute := apiRouter.Group("/safety_event")
+		{
+			safetyEventRoute.GET("/", middleware.PermissionAuth(config.PermissionSafetyRead), controller.GetSafetyEventsList)
+			safetyEventRoute.PUT("/:id/review", middleware.PermissionAuth(config.PermissionSafetyWrite), controller.ReviewSafetyEvent)
 		}
 
 		userGroup := apiRouter.Group("/user_group")
-		userGroup.Use(middleware.AdminAuth())
 		{
-			userGroup.GET("/", controller.GetUserGroups)
-			userGroup.GET("/:id", controller.GetUserGroupById)
-			userGroup.POST("/", controller.AddUserGroup)
-			userGroup.PUT("/enable/:id", controller.ChangeUserGroupEnable)
-			userGroup.PUT("/", controller.UpdateUserGroup)
-			userGroup.DELETE("/:id", controller.DeleteUserGroup)
+			userGroup.GET("/", middleware.PermissionAuth(config.PermissionUserGroupsRead), controller.GetUserGroups)
+			userGroup.GET("/:id", middleware.PermissionAuth(config.PermissionUserGroupsRead), controller.GetUserGroupById)
+			userGroup.POST("/", middleware.PermissionAuth(config.PermissionUserGroupsWrite), controller.AddUserGroup)
+			userGroup.PUT("/enable/:id", middleware.PermissionAuth(config.PermissionUserGroupsWrite), controller.ChangeUserGroupEnable)
+			userGroup.PUT("/", middleware.PermissionAuth(config.PermissionUserGroupsWrite), controller.UpdateUserGroup)
+			userGroup.DELETE("/:id", middleware.PermissionAuth(config.PermissionUserGroupsWrite), controller.DeleteUserGroup)
 
 		}
 		channelRoute := apiRouter.Group("/channel")
-		channelRoute.Use(middleware.AdminAuth())
 		{
-			channelRoute.GET("/", controller.GetChannelsList)
-			channelRoute.GET("/models", relay.ListModelsForAdmin)
-			channelRoute.POST("/provider_models_list", controller.GetModelList)
-			channelRoute.GET("/:id", controller.GetChannel)
-			channelRoute.GET("/test", controller.TestAllChannels)
-			channelRoute.GET("/test/:id", controller.TestChannel)
-			channelRoute.GET("/update_balance", controller.UpdateAllChannelsBalance)
-			channelRoute.GET("/update_balance/:id", controller.UpdateChannelBalance)
-			channelRoute.POST("/", controller.AddChannel)
-			channelRoute.PUT("/", controller.UpdateChannel)
-			channelRoute.PUT("/batch/azure_api", controller.BatchUpdateChannelsAzureApi)
-			channelRoute.PUT("/batch/del_model", controller.BatchDelModelChannels)
-			channelRoute.DELETE("/disabled", controller.DeleteDisabledChannel)
-			channelRoute.DELETE("/:id/tag", controller.DeleteChannelTag)
-			channelRoute.DELETE("/:id", controller.DeleteChannel)
-			channelRoute.DELETE("/batch", controller.BatchDeleteChannel)
+			channelRoute.GET("/", middleware.PermissionAuth(config.PermissionChannelsRead), controller.GetChannelsList)
+			channelRoute.GET("/models", middleware.PermissionAuth(config.PermissionChannelsRead), relay.ListModelsForAdmin)
+			channelRoute.POST("/provider_models_list", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.GetModelList)
+			channelRoute.GET("/:id", middleware.PermissionAuth(config.PermissionChannelsRead), controller.GetChannel)
+			// 测试和更新余额会修改渠道状态
+			channelRoute.GET("/test", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.TestAllChannels)
+			channelRoute.GET("/test/:id", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.TestChannel)
+			channelRoute.GET("/update_balance", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.UpdateAllChannelsBalance)
+			channelRoute.GET("/update_balance/:id", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.UpdateChannelBalance)
+			channelRoute.GET("/balance_history/:id", middleware.PermissionAuth(config.PermissionChannelsRead), controller.GetChannelBalanceHistory)
+			channelRoute.POST("/", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.AddChannel)
+			channelRoute.PUT("/", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.UpdateChannel)
+			channelRoute.PUT("/batch/azure_api", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.BatchUpdateChannelsAzureApi)
+			channelRoute.PUT("/batch/del_model", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.BatchDelModelChannels)
+			channelRoute.DELETE("/disabled", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.DeleteDisabledChannel)
+			channelRoute.DELETE("/:id/tag", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.DeleteChannelTag)
+			channelRoute.GET("/:id/keys", middleware.PermissionAuth(config.PermissionChannelsRead), controller.GetChannelKeysList)
+			channelRoute.POST("/:id/keys", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.ImportChannelKeys)
+			channelRoute.GET("/:id/keys/export", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.ExportChannelKeys)
+			channelRoute.PUT("/:id/keys/:key_id/status", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.UpdateChannelKeyStatus)
+			channelRoute.DELETE("/:id/keys", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.DeleteChannelKeys)
+			channelRoute.DELETE("/:id", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.DeleteChannel)
+			channelRoute.DELETE("/batch", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.BatchDeleteChannel)
 		}
 		channelTagRoute := apiRouter.Group("/channel_tag")
-		channelTagRoute.Use(middleware.AdminAuth())
 		{
-			channelTagRoute.GET("/_all", controller.GetChannelsTagAllList)
-			channelTagRoute.GET("/:tag/list", controller.GetChannelsTagList)
-			channelTagRoute.GET("/:tag", controller.GetChannelsTag)
-			channelTagRoute.PUT("/:tag", controller.UpdateChannelsTag)
-			channelTagRoute.DELETE("/:tag", controller.DeleteChannelsTag)
-			channelTagRoute.DELETE("/:tag/disabled", controller.DeleteDisabledChannelsTag)
-			channelTagRoute.PUT("/:tag/priority", controller.UpdateChannelsTagPriority)
-			channelTagRoute.PUT("/:tag/status/:status", controller.ChangeChannelsTagStatus)
+			channelTagRoute.GET("/_all", middleware.PermissionAuth(config.PermissionChannelsRead), controller.GetChannelsTagAllList)
+			channelTagRoute.GET("/:tag/list", middleware.PermissionAuth(config.PermissionChannelsRead), controller.GetChannelsTagList)
+			channelTagRoute.GET("/:tag", middleware.PermissionAuth(config.PermissionChannelsRead), controller.GetChannelsTag)
+			channelTagRoute.PUT("/:tag", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.UpdateChannelsTag)
+			channelTagRoute.DELETE("/:tag", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.DeleteChannelsTag)
+			channelTagRoute.DELETE("/:tag/disabled", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.DeleteDisabledChannelsTag)
+			channelTagRoute.PUT("/:tag/priority", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.UpdateChannelsTagPriority)
+			channelTagRoute.PUT("/:tag/status/:status", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.ChangeChannelsTagStatus)
 
 		}
 
@@ -195,30 +223,32 @@ func SetApiRouter(router *gin.Engine) {
 			tokenRoute.DELETE("/:id", controller.DeleteToken)
 		}
 		redemptionRoute := apiRouter.Group("/redemption")
-		redemptionRoute.Use(middleware.AdminAuth())
 		{
-			redemptionRoute.GET("/", controller.GetRedemptionsList)
-			redemptionRoute.GET("/:id", controller.GetRedemption)
-			redemptionRoute.POST("/", controller.AddRedemption)
-			redemptionRoute.PUT("/", controller.UpdateRedemption)
-			redemptionRoute.DELETE("/:id", controller.DeleteRedemption)
+			redemptionRoute.GET("/", middleware.PermissionAuth(config.PermissionRedemptionsRead), controller.GetRedemptionsList)
+			redemptionRoute.GET("/:id", middleware.PermissionAuth(config.PermissionRedemptionsRead), controller.GetRedemption)
+			redemptionRoute.POST("/", middleware.PermissionAuth(config.PermissionRedemptionsWrite), controller.AddRedemption)
+			redemptionRoute.PUT("/", middleware.PermissionAuth(config.PermissionRedemptionsWrite), controller.UpdateRedemption)
+			redemptionRoute.DELETE("/:id", middleware.PermissionAuth(config.PermissionRedemptionsWrite), controller.DeleteRedemption)
 		}
 		logRoute := apiRouter.Group("/log")
-		logRoute.GET("/", middleware.AdminAuth(), controller.GetLogsList)
-		logRoute.DELETE("/", middleware.AdminAuth(), controller.DeleteHistoryLogs)
-		logRoute.GET("/stat", middleware.AdminAuth(), controller.GetLogsStat)
-		logRoute.GET("/self/stat", middleware.UserAuth(), controller.GetLogsSelfStat)
+		logRoute.GET("/", middleware.PermissionAuth(config.PermissionLogsRead), controller.GetLogsList)
+		logRoute.DELETE("/", middleware.PermissionAuth(config.PermissionLogsDelete), controller.DeleteHistoryLogs)
+		logRoute.GET("/stat", middleware.PermissionAuth(config.PermissionLogsRead), controller.GetLogsStat)
+		logRoute.GET("/self/stat", middleware.UserOrTokenAuth(config.TokenScopeManagementRead), controller.GetLogsSelfStat)
 		// logRoute.GET("/search", middleware.AdminAuth(), controller.SearchAllLogs)
-		logRoute.GET("/self", middleware.UserAuth(), controller.GetUserLogsList)
+		logRoute.GET("/self", middleware.UserOrTokenAuth(config.TokenScopeManagementRead), controller.GetUserLogsList)
+		logRoute.GET("/archive", middleware.PermissionAuth(config.PermissionLogsRead), controller.GetLogArchivesList)
+		logRoute.POST("/archive/restore", middleware.PermissionAuth(config.PermissionLogsRestore), controller.RestoreLogArchives)
+		logRoute.GET("/:id", middleware.PermissionAuth(config.PermissionLogsRead), controller.GetLogDetail)
 		// logRoute.GET("/self/search", middleware.UserAuth(), controller.SearchUserLogs)
 		groupRoute := apiRouter.Group("/group")
-		groupRoute.Use(middleware.AdminAuth())
+		groupRoute.Use(middleware.PermissionAuth(config.PermissionChannelsRead))
 		{
 			groupRoute.GET("/", controller.GetGroups)
 		}
 
 		analyticsRoute := apiRouter.Group("/analytics")
-		analyticsRoute.Use(middleware.AdminAuth())
+		analyticsRoute.Use(middleware.PermissionAuth(config.PermissionAnalyticsRead))
 		{
 			analyticsRoute.GET("/statistics", controller.GetStatisticsDetail)
 			analyticsRoute.GET("/period", controller.GetStatisticsByPeriod)
@@ -226,43 +256,56 @@ func SetApiRouter(router *gin.Engine) {
 			analyticsRoute.GET("/multi_user_stats/export", controller.ExportMultiUserStatisticsCSV)
 		}
 		pricesRoute := apiRouter.Group("/prices")
-		pricesRoute.Use(middleware.AdminAuth())
 		{
-			pricesRoute.GET("/model_list", controller.GetAllModelList)
-			pricesRoute.POST("/single", controller.AddPrice)
-			pricesRoute.PUT("/single/*model", controller.UpdatePrice)
-			pricesRoute.DELETE("/single/*model", controller.DeletePrice)
-			pricesRoute.POST("/multiple", controller.BatchSetPrices)
-			pricesRoute.PUT("/multiple/delete", controller.BatchDeletePrices)
-			pricesRoute.POST("/sync", controller.SyncPricing)
-			pricesRoute.GET("/updateService", controller.GetUpdatePriceService)
+			pricesRoute.GET("/model_list", middleware.PermissionAuth(config.PermissionPricesRead), controller.GetAllModelList)
+			pricesRoute.POST("/single", middleware.PermissionAuth(config.PermissionPricesWrite), controller.AddPrice)
+			pricesRoute.PUT("/single/*model", middleware.PermissionAuth(config.PermissionPricesWrite), controller.UpdatePrice)
+			pricesRoute.DELETE("/single/*model", middleware.PermissionAuth(config.PermissionPricesWrite), controller.DeletePrice)
+			pricesRoute.POST("/multiple", middleware.PermissionAuth(config.PermissionPricesWrite), controller.BatchSetPrices)
+			pricesRoute.PUT("/multiple/delete", middleware.PermissionAuth(config.PermissionPricesWrite), controller.BatchDeletePrices)
+			pricesRoute.POST("/sync", middleware.PermissionAuth(config.PermissionPricesWrite), controller.SyncPricing)
+			pricesRoute.GET("/updateService", middleware.PermissionAuth(config.PermissionPricesRead), controller.GetUpdatePriceService)
 
 		}
 
 		paymentRoute := apiRouter.Group("/payment")
-		paymentRoute.Use(middleware.AdminAuth())
 		{
-			paymentRoute.GET("/order", controller.GetOrderList)
-			paymentRoute.GET("/", controller.GetPaymentList)
-			paymentRoute.GET("/:id", controller.GetPayment)
-			paymentRoute.POST("/", controller.AddPayment)
-			paymentRoute.PUT("/", controller.UpdatePayment)
-			paymentRoute.DELETE("/:id", controller.DeletePayment)
+			paymentRoute.GET("/order", middleware.PermissionAuth(config.PermissionPaymentsRead), controller.GetOrderList)
+			paymentRoute.POST("/order/:id/refund", middleware.PermissionAuth(config.PermissionPaymentsRefund), controller.RefundOrder)
+			paymentRoute.GET("/", middleware.PermissionAuth(config.PermissionPaymentsRead), controller.GetPaymentList)
+			paymentRoute.GET("/:id", middleware.PermissionAuth(config.PermissionPaymentsRead), controller.GetPayment)
+			paymentRoute.POST("/", middleware.PermissionAuth(config.PermissionPaymentsWrite), controller.AddPayment)
+			paymentRoute.PUT("/", middleware.PermissionAuth(config.PermissionPaymentsWrite), controller.UpdatePayment)
+			paymentRoute.DELETE("/:id", middleware.PermissionAuth(config.PermissionPaymentsWrite), controller.DeletePayment)
 		}
 
 		mjRoute := apiRouter.Group("/mj")
-		mjRoute.GET("/self", middleware.UserAuth(), controller.GetUserMidjourney)
-		mjRoute.GET("/", middleware.AdminAuth(), controller.GetAllMidjourney)
+		mjRoute.GET("/self", middleware.UserOrTokenAuth(config.TokenScopeManagementRead), controller.GetUserMidjourney)
+		mjRoute.GET("/", middleware.PermissionAuth(config.PermissionLogsRead), controller.GetAllMidjourney)
 
 		taskRoute := apiRouter.Group("/task")
-		taskRoute.GET("/self", middleware.UserAuth(), controller.GetUserAllTask)
-		taskRoute.GET("/", middleware.AdminAuth(), controller.GetAllTask)
+		taskRoute.GET("/self", middleware.UserOrTokenAuth(config.TokenScopeManagementRead), controller.GetUserAllTask)
+		taskRoute.GET("/", middleware.PermissionAuth(config.PermissionLogsRead), controller.GetAllTask)
+
+		adminRoleRoute := apiRouter.Group("/admin_role")
+		adminRoleRoute.Use(middleware.PermissionAuth(config.PermissionRolesManage))
+		{
+			adminRoleRoute.GET("/permissions", controller.GetAllPermissions)
+			adminRoleRoute.GET("/", controller.GetAllAdminRoles)
+			adminRoleRoute.GET("/:id", controller.GetAdminRole)
+			adminRoleRoute.POST("/", controller.CreateAdminRole)
+			adminRoleRoute.PUT("/", controller.UpdateAdminRole)
+			adminRoleRoute.DELETE("/:id", controller.DeleteAdminRole)
+			adminRoleRoute.PUT("/assign", controller.AssignAdminRole)
+		}
+
+		apiRouter.GET("/audit_log", middleware.PermissionAuth(config.PermissionAuditRead), controller.GetAuditLogsList)
 	}
 
 	sseRouter := router.Group("/api/sse")
 	sseRouter.Use(middleware.GlobalAPIRateLimit())
 	{
-		sseRouter.POST("/channel/check", middleware.AdminAuth(), controller.CheckChannel)
+		sseRouter.POST("/channel/check", middleware.PermissionAuth(config.PermissionChannelsWrite), controller.CheckChannel)
 	}
 
 }
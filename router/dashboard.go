@@ -1,6 +1,7 @@
 package router
 
 import (
+	"one-api/common/config"
 	"one-api/controller"
 	"one-api/middleware"
 
@@ -14,6 +15,7 @@ func SetDashboardRouter(router *gin.Engine) {
 	apiRouter.Use(gzip.Gzip(gzip.DefaultCompression))
 	apiRouter.Use(middleware.GlobalAPIRateLimit())
 	apiRouter.Use(middleware.OpenaiAuth())
+	apiRouter.Use(middleware.TokenScope(config.TokenScopeBillingRead))
 	{
 		apiRouter.GET("/dashboard/billing/subscription", controller.GetSubscription)
 		apiRouter.GET("/v1/dashboard/billing/subscription", controller.GetSubscription)
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"one-api/common/config"
 	"one-api/middleware"
 	"one-api/relay"
 	"one-api/relay/midjourney"
@@ -30,39 +31,60 @@ func setOpenAIRouter(router *gin.Engine) {
 		modelsRouter.GET("", relay.ListModelsByToken)
 		modelsRouter.GET("/:model", relay.RetrieveModel)
 	}
-	relayV1Router := router.Group("/v1")
-	relayV1Router.Use(middleware.RelayPanicRecover(), middleware.OpenaiAuth(), middleware.Distribute(), middleware.DynamicRedisRateLimiter())
+	chatRouter := relayV1Group(router, config.TokenScopeChat)
 	{
-		relayV1Router.POST("/completions", relay.Relay)
-		relayV1Router.POST("/chat/completions", relay.Relay)
-		relayV1Router.POST("/responses", relay.Relay)
-		// relayV1Router.POST("/edits", controller.Relay)
-		relayV1Router.POST("/images/generations", relay.Relay)
-		relayV1Router.POST("/images/edits", relay.Relay)
-		relayV1Router.POST("/images/variations", relay.Relay)
-		relayV1Router.POST("/embeddings", relay.Relay)
-		// relayV1Router.POST("/engines/:model/embeddings", controller.RelayEmbeddings)
-		relayV1Router.POST("/audio/transcriptions", relay.Relay)
-		relayV1Router.POST("/audio/translations", relay.Relay)
-		relayV1Router.POST("/audio/speech", relay.Relay)
-		relayV1Router.POST("/moderations", relay.Relay)
-		relayV1Router.POST("/rerank", relay.RelayRerank)
-		relayV1Router.GET("/realtime", relay.ChatRealtime)
-
-		relayV1Router.Use(middleware.SpecifiedChannel())
-		{
-			relayV1Router.Any("/files", relay.RelayOnly)
-			relayV1Router.Any("/files/*any", relay.RelayOnly)
-			relayV1Router.Any("/fine_tuning/*any", relay.RelayOnly)
-			relayV1Router.Any("/assistants", relay.RelayOnly)
-			relayV1Router.Any("/assistants/*any", relay.RelayOnly)
-			relayV1Router.Any("/threads", relay.RelayOnly)
-			relayV1Router.Any("/threads/*any", relay.RelayOnly)
-			relayV1Router.Any("/batches/*any", relay.RelayOnly)
-			relayV1Router.Any("/vector_stores/*any", relay.RelayOnly)
-			relayV1Router.DELETE("/models/:model", relay.RelayOnly)
-		}
+		chatRouter.POST("/completions", relay.Relay)
+		chatRouter.POST("/chat/completions", relay.Relay)
+		chatRouter.POST("/responses", relay.Relay)
+		// chatRouter.POST("/edits", controller.Relay)
+		chatRouter.POST("/moderations", relay.Relay)
 	}
+
+	imagesRouter := relayV1Group(router, config.TokenScopeImages)
+	{
+		imagesRouter.POST("/images/generations", relay.Relay)
+		imagesRouter.POST("/images/edits", relay.Relay)
+		imagesRouter.POST("/images/variations", relay.Relay)
+	}
+
+	embeddingsRouter := relayV1Group(router, config.TokenScopeEmbeddings)
+	{
+		embeddingsRouter.POST("/embeddings", relay.Relay)
+		// embeddingsRouter.POST("/engines/:model/embeddings", controller.RelayEmbeddings)
+		embeddingsRouter.POST("/rerank", relay.RelayRerank)
+	}
+
+	audioRouter := relayV1Group(router, config.TokenScopeAudio)
+	{
+		audioRouter.POST("/audio/transcriptions", relay.Relay)
+		audioRouter.POST("/audio/translations", relay.Relay)
+		audioRouter.POST("/audio/speech", relay.Relay)
+	}
+
+	realtimeRouter := relayV1Group(router, config.TokenScopeRealtime)
+	realtimeRouter.GET("/realtime", relay.ChatRealtime)
+
+	filesRouter := relayV1Group(router, config.TokenScopeFiles)
+	filesRouter.Use(middleware.SpecifiedChannel())
+	{
+		filesRouter.Any("/files", relay.RelayOnly)
+		filesRouter.Any("/files/*any", relay.RelayOnly)
+		filesRouter.Any("/fine_tuning/*any", relay.RelayOnly)
+		filesRouter.Any("/assistants", relay.RelayOnly)
+		filesRouter.Any("/assistants/*any", relay.RelayOnly)
+		filesRouter.Any("/threads", relay.RelayOnly)
+		filesRouter.Any("/threads/*any", relay.RelayOnly)
+		filesRouter.Any("/batches/*any", relay.RelayOnly)
+		filesRouter.Any("/vector_stores/*any", relay.RelayOnly)
+		filesRouter.DELETE("/models/:model", relay.RelayOnly)
+	}
+}
+
+// relayV1Group 按令牌范围划分路由组，范围校验在分发渠道之前进行
+func relayV1Group(router *gin.Engine, scope string) *gin.RouterGroup {
+	group := router.Group("/v1")
+	group.Use(middleware.RelayPanicRecover(), middleware.OpenaiAuth(), middleware.TokenScope(scope), middleware.Distribute(), middleware.DynamicRedisRateLimiter(), middleware.PayloadCapture())
+	return group
 }
 
 func setMJRouter(router *gin.Engine) {
@@ -78,7 +100,7 @@ func setMJRouter(router *gin.Engine) {
 // Path: router/relay-router.go
 func registerMjRouterGroup(relayMjRouter *gin.RouterGroup) {
 	relayMjRouter.GET("/image/:id", midjourney.RelayMidjourneyImage)
-	relayMjRouter.Use(middleware.RelayMJPanicRecover(), middleware.MjAuth(), middleware.Distribute(), middleware.DynamicRedisRateLimiter())
+	relayMjRouter.Use(middleware.RelayMJPanicRecover(), middleware.MjAuth(), middleware.TokenScope(config.TokenScopeTasks), middleware.Distribute(), middleware.DynamicRedisRateLimiter(), middleware.PayloadCapture())
 	{
 		relayMjRouter.POST("/submit/action", midjourney.RelayMidjourney)
 		relayMjRouter.POST("/submit/shorten", midjourney.RelayMidjourney)
@@ -99,7 +121,7 @@ func registerMjRouterGroup(relayMjRouter *gin.RouterGroup) {
 
 func setSunoRouter(router *gin.Engine) {
 	relaySunoRouter := router.Group("/suno")
-	relaySunoRouter.Use(middleware.RelaySunoPanicRecover(), middleware.OpenaiAuth(), middleware.Distribute(), middleware.DynamicRedisRateLimiter())
+	relaySunoRouter.Use(middleware.RelaySunoPanicRecover(), middleware.OpenaiAuth(), middleware.TokenScope(config.TokenScopeTasks), middleware.Distribute(), middleware.DynamicRedisRateLimiter(), middleware.PayloadCapture())
 	{
 		relaySunoRouter.POST("/submit/:action", task.RelayTaskSubmit)
 		relaySunoRouter.POST("/fetch", suno.GetFetch)
@@ -110,16 +132,16 @@ func setSunoRouter(router *gin.Engine) {
 func setClaudeRouter(router *gin.Engine) {
 	relayClaudeRouter := router.Group("/claude")
 	relayV1Router := relayClaudeRouter.Group("/v1")
-	relayV1Router.Use(middleware.APIEnabled("claude"), middleware.RelayCluadePanicRecover(), middleware.ClaudeAuth(), middleware.Distribute(), middleware.DynamicRedisRateLimiter())
+	relayV1Router.Use(middleware.APIEnabled("claude"), middleware.RelayCluadePanicRecover(), middleware.ClaudeAuth(), middleware.Distribute(), middleware.DynamicRedisRateLimiter(), middleware.PayloadCapture())
 	{
-		relayV1Router.POST("/messages", relay.Relay)
+		relayV1Router.POST("/messages", middleware.TokenScope(config.TokenScopeChat), relay.Relay)
 		relayV1Router.GET("/models", relay.ListClaudeModelsByToken)
 	}
 }
 
 func setGeminiRouter(router *gin.Engine) {
 	relayGeminiRouter := router.Group("/gemini")
-	relayGeminiRouter.Use(middleware.APIEnabled("gemini"), middleware.RelayGeminiPanicRecover(), middleware.GeminiAuth(), middleware.Distribute(), middleware.DynamicRedisRateLimiter())
+	relayGeminiRouter.Use(middleware.APIEnabled("gemini"), middleware.RelayGeminiPanicRecover(), middleware.GeminiAuth(), middleware.TokenScope(config.TokenScopeChat), middleware.Distribute(), middleware.DynamicRedisRateLimiter(), middleware.PayloadCapture())
 	{
 		relayGeminiRouter.POST("/:version/models/:model", relay.Relay)
 		relayGeminiRouter.GET("/:version/models", relay.ListGeminiModelsByToken)
@@ -128,7 +150,7 @@ func setGeminiRouter(router *gin.Engine) {
 
 func setRecraftRouter(router *gin.Engine) {
 	relayRecraftRouter := router.Group("/recraftAI/v1")
-	relayRecraftRouter.Use(middleware.RelayPanicRecover(), middleware.OpenaiAuth(), middleware.Distribute(), middleware.DynamicRedisRateLimiter())
+	relayRecraftRouter.Use(middleware.RelayPanicRecover(), middleware.OpenaiAuth(), middleware.TokenScope(config.TokenScopeImages), middleware.Distribute(), middleware.DynamicRedisRateLimiter(), middleware.PayloadCapture())
 	{
 		relayRecraftRouter.POST("/images/generations", relay.Relay)
 		relayRecraftRouter.POST("/images/vectorize", relay.RelayRecraftAI)
@@ -141,7 +163,7 @@ func setRecraftRouter(router *gin.Engine) {
 
 func setKlingRouter(router *gin.Engine) {
 	relayKlingRouter := router.Group("/kling")
-	relayKlingRouter.Use(middleware.RelayKlingPanicRecover(), middleware.OpenaiAuth(), middleware.Distribute())
+	relayKlingRouter.Use(middleware.RelayKlingPanicRecover(), middleware.OpenaiAuth(), middleware.TokenScope(config.TokenScopeTasks), middleware.Distribute())
 	relayKlingRouter.GET("/v1/videos/text2video/:id", kling.GetFetchByID)
 	relayKlingRouter.GET("/v1/videos/image2video/:id", kling.GetFetchByID)
 
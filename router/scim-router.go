@@ -0,0 +1,30 @@
+package router
+
+import (
+	"one-api/controller"
+	"one-api/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetScimRouter(router *gin.Engine) {
+	scimRouter := router.Group("/scim/v2")
+	scimRouter.Use(middleware.ScimAuth())
+	{
+		scimRouter.GET("/ServiceProviderConfig", controller.ScimServiceProviderConfig)
+
+		scimRouter.GET("/Users", controller.ScimListUsers)
+		scimRouter.GET("/Users/:id", controller.ScimGetUser)
+		scimRouter.POST("/Users", controller.ScimCreateUser)
+		scimRouter.PUT("/Users/:id", controller.ScimReplaceUser)
+		scimRouter.PATCH("/Users/:id", controller.ScimPatchUser)
+		scimRouter.DELETE("/Users/:id", controller.ScimDeleteUser)
+
+		scimRouter.GET("/Groups", controller.ScimListGroups)
+		scimRouter.GET("/Groups/:id", controller.ScimGetGroup)
+		scimRouter.POST("/Groups", controller.ScimCreateGroup)
+		scimRouter.PUT("/Groups/:id", controller.ScimReplaceGroup)
+		scimRouter.PATCH("/Groups/:id", controller.ScimPatchGroup)
+		scimRouter.DELETE("/Groups/:id", controller.ScimDeleteGroup)
+	}
+}
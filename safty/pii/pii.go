@@ -0,0 +1,221 @@
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Detector 个人敏感信息检测规则
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Validate 对匹配结果进行二次校验，减少误判
+	Validate func(match string) bool
+}
+
+// 内置检测规则
+var builtinDetectors = []*Detector{
+	{Name: "email", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{Name: "phone", Pattern: regexp.MustCompile(`\b(?:\+?86[\- ]?)?1[3-9]\d{9}\b`)},
+	{Name: "id_card", Pattern: regexp.MustCompile(`\b[1-9]\d{16}[\dXx]\b`)},
+	{Name: "credit_card", Pattern: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`), Validate: luhnValid},
+	{Name: "api_key", Pattern: regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{20,}`)},
+}
+
+// BuiltinDetectors 获取全部内置检测规则
+func BuiltinDetectors() []*Detector {
+	return builtinDetectors
+}
+
+// GetBuiltinDetector 根据名称获取内置检测规则
+func GetBuiltinDetector(name string) *Detector {
+	for _, detector := range builtinDetectors {
+		if detector.Name == name {
+			return detector
+		}
+	}
+
+	return nil
+}
+
+// FindAll 查找内容中所有命中的值
+func (d *Detector) FindAll(data string) []string {
+	indexes := d.FindAllIndex(data)
+	matches := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		matches = append(matches, data[index[0]:index[1]])
+	}
+
+	return matches
+}
+
+// FindAllIndex 查找内容中所有命中值的字节位置
+func (d *Detector) FindAllIndex(data string) [][]int {
+	indexes := d.Pattern.FindAllStringIndex(data, -1)
+	if d.Validate == nil {
+		return indexes
+	}
+
+	valid := indexes[:0]
+	for _, index := range indexes {
+		if d.Validate(data[index[0]:index[1]]) {
+			valid = append(valid, index)
+		}
+	}
+
+	return valid
+}
+
+// 占位符格式为 [PII_<NAME>_<hash>]
+var placeholderPattern = regexp.MustCompile(`\[PII_[A-Z0-9_]+_[0-9a-f]{8}\]`)
+
+var placeholderNameReplacer = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// 占位符最大长度，用于流式输出时判断是否需要等待后续内容
+const maxPlaceholderLength = 64
+
+// Vault 单个请求内的脱敏映射
+// 相同的值始终生成相同的占位符，多轮对话中占位符保持稳定
+type Vault struct {
+	sync.Mutex
+	detectors []*Detector
+	secret    []byte
+	values    map[string]string
+}
+
+func NewVault(detectors []*Detector, secret string) *Vault {
+	return &Vault{
+		detectors: detectors,
+		secret:    []byte(secret),
+		values:    make(map[string]string),
+	}
+}
+
+func (v *Vault) placeholder(name, value string) string {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(name + ":" + value))
+	hash := hex.EncodeToString(mac.Sum(nil))[:8]
+
+	name = placeholderNameReplacer.ReplaceAllString(strings.ToUpper(name), "_")
+
+	return fmt.Sprintf("[PII_%s_%s]", name, hash)
+}
+
+// Redact 将敏感信息替换为占位符
+func (v *Vault) Redact(data string) string {
+	if data == "" {
+		return data
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	for _, detector := range v.detectors {
+		matches := detector.FindAll(data)
+		if len(matches) == 0 {
+			continue
+		}
+
+		// 先替换较长的值，避免部分覆盖
+		sort.Slice(matches, func(i, j int) bool {
+			return len(matches[i]) > len(matches[j])
+		})
+		for _, match := range matches {
+			placeholder := v.placeholder(detector.Name, match)
+			v.values[placeholder] = match
+			data = strings.ReplaceAll(data, match, placeholder)
+		}
+	}
+
+	return data
+}
+
+// Restore 将占位符还原为原始值，未知的占位符保持不变
+func (v *Vault) Restore(data string) string {
+	if data == "" || !strings.Contains(data, "[PII_") {
+		return data
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	return placeholderPattern.ReplaceAllStringFunc(data, func(placeholder string) string {
+		if value, ok := v.values[placeholder]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// IsEmpty 是否没有任何被替换的内容
+func (v *Vault) IsEmpty() bool {
+	v.Lock()
+	defer v.Unlock()
+
+	return len(v.values) == 0
+}
+
+// StreamRestorer 流式输出还原，占位符可能被拆分到多个分片中
+type StreamRestorer struct {
+	vault   *Vault
+	pending string
+}
+
+func (v *Vault) NewStreamRestorer() *StreamRestorer {
+	return &StreamRestorer{vault: v}
+}
+
+// Restore 处理一个分片，返回可以输出的内容，可能是占位符前缀的部分会保留到下一个分片
+func (s *StreamRestorer) Restore(delta string) string {
+	data := s.pending + delta
+	s.pending = ""
+
+	if idx := strings.LastIndex(data, "["); idx != -1 && !strings.Contains(data[idx:], "]") && len(data)-idx < maxPlaceholderLength {
+		if strings.HasPrefix("[PII_", data[idx:]) || strings.HasPrefix(data[idx:], "[PII_") {
+			s.pending = data[idx:]
+			data = data[:idx]
+		}
+	}
+
+	return s.vault.Restore(data)
+}
+
+// Flush 输出剩余内容
+func (s *StreamRestorer) Flush() string {
+	data := s.vault.Restore(s.pending)
+	s.pending = ""
+
+	return data
+}
+
+// luhnValid 校验银行卡号
+func luhnValid(match string) bool {
+	sum := 0
+	digits := 0
+	double := false
+	for i := len(match) - 1; i >= 0; i-- {
+		ch := match[i]
+		if ch < '0' || ch > '9' {
+			continue
+		}
+
+		n := int(ch - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		digits++
+		double = !double
+	}
+
+	return digits >= 13 && sum%10 == 0
+}
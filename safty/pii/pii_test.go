@@ -0,0 +1,140 @@
+package pii
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinDetectors(t *testing.T) {
+	tests := []struct {
+		detector string
+		data     string
+		want     []string
+	}{
+		{detector: "email", data: "mail alice@example.com or bob.smith+tag@mail.example.org", want: []string{"alice@example.com", "bob.smith+tag@mail.example.org"}},
+		{detector: "phone", data: "call 13812345678 or +86 13912345678", want: []string{"13812345678", "86 13912345678"}},
+		{detector: "phone", data: "order 12812345678", want: []string{}},
+		{detector: "id_card", data: "id 11010519491231002X", want: []string{"11010519491231002X"}},
+		{detector: "credit_card", data: "card 4111 1111 1111 1111", want: []string{"4111 1111 1111 1111"}},
+		{detector: "credit_card", data: "card 4111 1111 1111 1112", want: []string{}},
+		{detector: "api_key", data: "key sk-abcdefghijklmnopqrstuvwxyz", want: []string{"sk-abcdefghijklmnopqrstuvwxyz"}},
+		{detector: "api_key", data: "key sk-short", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.detector+" "+tt.data, func(t *testing.T) {
+			detector := GetBuiltinDetector(tt.detector)
+			require.NotNil(t, detector)
+			matches := detector.FindAll(tt.data)
+			if len(tt.want) == 0 {
+				assert.Empty(t, matches)
+				return
+			}
+			assert.Equal(t, tt.want, matches)
+		})
+	}
+}
+
+func TestDetectorFindAllIndex(t *testing.T) {
+	detector := GetBuiltinDetector("credit_card")
+	data := "a 4111 1111 1111 1112 b 4111111111111111"
+
+	indexes := detector.FindAllIndex(data)
+	require.Len(t, indexes, 1)
+	assert.Equal(t, "4111111111111111", data[indexes[0][0]:indexes[0][1]])
+}
+
+func TestVaultRedactRestore(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "single value", data: "my email is alice@example.com"},
+		{name: "repeated value", data: "alice@example.com, again alice@example.com"},
+		{name: "several detectors", data: "alice@example.com 13812345678 sk-abcdefghijklmnopqrstuvwxyz"},
+		{name: "unicode text", data: "邮箱：alice@example.com，电话：13812345678。"},
+		{name: "nothing to redact", data: "hello world"},
+		{name: "empty", data: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := NewVault(BuiltinDetectors(), "secret")
+			redacted := vault.Redact(tt.data)
+
+			for _, detector := range BuiltinDetectors() {
+				for _, match := range detector.FindAll(tt.data) {
+					assert.NotContains(t, redacted, match)
+				}
+			}
+			assert.Equal(t, tt.data, vault.Restore(redacted))
+		})
+	}
+}
+
+func TestVaultPlaceholder(t *testing.T) {
+	vault := NewVault(BuiltinDetectors(), "secret")
+	first := vault.Redact("alice@example.com")
+	second := vault.Redact("contact alice@example.com")
+	other := vault.Redact("bob@example.com")
+
+	assert.Regexp(t, `^\[PII_EMAIL_[0-9a-f]{8}\]$`, first)
+	assert.Equal(t, "contact "+first, second, "same value must map to the same placeholder")
+	assert.NotEqual(t, first, other)
+	assert.False(t, vault.IsEmpty())
+
+	// 不同密钥生成不同的占位符
+	assert.NotEqual(t, first, NewVault(BuiltinDetectors(), "other").Redact("alice@example.com"))
+
+	// 未知占位符保持不变
+	assert.Equal(t, "[PII_EMAIL_00000000]", vault.Restore("[PII_EMAIL_00000000]"))
+	assert.True(t, NewVault(BuiltinDetectors(), "secret").IsEmpty())
+}
+
+func TestStreamRestorer(t *testing.T) {
+	vault := NewVault(BuiltinDetectors(), "secret")
+	original := "reply to alice@example.com and call 13812345678 [not a placeholder] done"
+	redacted := vault.Redact(original)
+	require.NotEqual(t, original, redacted)
+
+	tests := []struct {
+		name   string
+		chunks []string
+	}{
+		{name: "single chunk", chunks: []string{redacted}},
+		{name: "one character per chunk", chunks: strings.Split(redacted, "")},
+		{name: "split inside placeholder", chunks: splitAt(redacted, strings.Index(redacted, "[PII_")+3)},
+		{name: "split after bracket", chunks: splitAt(redacted, strings.Index(redacted, "[PII_")+1)},
+		{name: "split before closing bracket", chunks: splitAt(redacted, strings.Index(redacted, "]"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restorer := vault.NewStreamRestorer()
+			var builder strings.Builder
+			for _, chunk := range tt.chunks {
+				output := restorer.Restore(chunk)
+				assert.NotContains(t, output, "[PII_")
+				builder.WriteString(output)
+			}
+			builder.WriteString(restorer.Flush())
+			assert.Equal(t, original, builder.String())
+		})
+	}
+}
+
+func TestStreamRestorerFlushIncomplete(t *testing.T) {
+	vault := NewVault(BuiltinDetectors(), "secret")
+	restorer := vault.NewStreamRestorer()
+
+	assert.Equal(t, "text ", restorer.Restore("text [PII_EM"))
+	assert.Equal(t, "[PII_EM", restorer.Flush())
+	assert.Equal(t, "", restorer.Flush())
+}
+
+func splitAt(data string, index int) []string {
+	return []string{data[:index], data[index:]}
+}
@@ -0,0 +1,120 @@
+package safty
+
+import (
+	"fmt"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/safty/providers/regex"
+	"one-api/safty/types"
+)
+
+// Decision 按策略检查后的处理结果
+type Decision struct {
+	// Action 命中后的处理方式，为空表示内容安全
+	Action string
+	// Tool 命中的检查器名称
+	Tool   string
+	Result types.CheckResult
+	// Content 处理后的内容，脱敏时为脱敏后的内容
+	Content string
+}
+
+func getPolicyTools(policy *types.Policy) []string {
+	if len(policy.Tools) > 0 {
+		return policy.Tools
+	}
+
+	return []string{config.SafeToolName}
+}
+
+// CheckWithPolicy 按策略依次使用检查器检查内容
+// 检查器不存在或出错时，仅标记的策略跳过该检查器，拦截和脱敏的策略直接拦截，避免内容未经检查放行
+func CheckWithPolicy(policy *types.Policy, content string) *Decision {
+	decision := &Decision{Content: content}
+	if policy == nil || content == "" {
+		return decision
+	}
+
+	for _, name := range getPolicyTools(policy) {
+		tool, err := getTool(name)
+		if err != nil {
+			logger.SysError(fmt.Sprintf("Safety tool %s not found", name))
+			if policy.Action == types.ActionFlag {
+				continue
+			}
+			return checkFailedDecision(decision, name)
+		}
+
+		result, err := tool.Check(decision.Content)
+		if err != nil {
+			logger.SysError(fmt.Sprintf("Safety tool %s check failed: %v", name, err))
+			if policy.Action == types.ActionFlag {
+				continue
+			}
+			return checkFailedDecision(decision, name)
+		}
+
+		if result.IsSafe {
+			continue
+		}
+
+		decision.Tool = name
+		decision.Result = result
+
+		switch policy.Action {
+		case types.ActionFlag:
+			decision.Action = types.ActionFlag
+			return decision
+		case types.ActionRedact:
+			if redactor, ok := tool.(SaftyRedactor); ok {
+				decision.Content = redactor.Redact(decision.Content)
+				decision.Action = types.ActionRedact
+				continue
+			}
+			// 不支持脱敏的检查器命中时直接拦截
+			decision.Action = types.ActionBlock
+			return decision
+		default:
+			decision.Action = types.ActionBlock
+			return decision
+		}
+	}
+
+	return decision
+}
+
+// checkFailedDecision 检查器不可用时的拦截结果
+func checkFailedDecision(decision *Decision, name string) *Decision {
+	decision.Action = types.ActionBlock
+	decision.Tool = name
+	decision.Result = types.CheckResult{
+		Code:   types.SafeCheckFailedCode,
+		Reason: types.SafeCheckFailedMessage,
+	}
+	return decision
+}
+
+// RedactWithPolicy 仅使用支持脱敏的检查器处理内容，不会调用远程审查
+func RedactWithPolicy(policy *types.Policy, content string) string {
+	if policy == nil || content == "" {
+		return content
+	}
+
+	for _, name := range getPolicyTools(policy) {
+		tool, err := getTool(name)
+		if err != nil {
+			continue
+		}
+
+		if redactor, ok := tool.(SaftyRedactor); ok {
+			content = redactor.Redact(content)
+		}
+	}
+
+	return content
+}
+
+// ValidateRegexRules 校验正则审查的自定义规则
+func ValidateRegexRules(source string) error {
+	return regex.ValidateRules(source)
+}
@@ -0,0 +1,78 @@
+package safty
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"one-api/common/logger"
+	"one-api/safty/types"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeTool struct {
+	err error
+}
+
+func (f *fakeTool) Name() string { return "fake" }
+func (f *fakeTool) Init() error  { return nil }
+
+func (f *fakeTool) Check(data string) (types.CheckResult, error) {
+	if f.err != nil {
+		return types.CheckResult{}, f.err
+	}
+	if strings.Contains(data, "bad") {
+		return types.CheckResult{Code: "fake", Reason: "bad word"}, nil
+	}
+	return types.CheckResult{IsSafe: true}, nil
+}
+
+func (f *fakeTool) Redact(data string) string {
+	return strings.ReplaceAll(data, "bad", "***")
+}
+
+func TestCheckWithPolicy(t *testing.T) {
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	originTools := Tools
+	Tools = map[string]SaftyTool{
+		"fake":   &fakeTool{},
+		"broken": &fakeTool{err: errors.New("service unavailable")},
+	}
+	t.Cleanup(func() {
+		Tools = originTools
+	})
+
+	tests := []struct {
+		name        string
+		tools       []string
+		action      string
+		content     string
+		wantAction  string
+		wantTool    string
+		wantCode    string
+		wantContent string
+	}{
+		{name: "safe content", tools: []string{"fake"}, action: types.ActionBlock, content: "hello", wantContent: "hello"},
+		{name: "block", tools: []string{"fake"}, action: types.ActionBlock, content: "bad", wantAction: types.ActionBlock, wantTool: "fake", wantCode: "fake", wantContent: "bad"},
+		{name: "redact", tools: []string{"fake"}, action: types.ActionRedact, content: "a bad b", wantAction: types.ActionRedact, wantTool: "fake", wantCode: "fake", wantContent: "a *** b"},
+		{name: "flag", tools: []string{"fake"}, action: types.ActionFlag, content: "bad", wantAction: types.ActionFlag, wantTool: "fake", wantCode: "fake", wantContent: "bad"},
+		{name: "block with missing tool", tools: []string{"missing", "fake"}, action: types.ActionBlock, content: "hello", wantAction: types.ActionBlock, wantTool: "missing", wantCode: types.SafeCheckFailedCode, wantContent: "hello"},
+		{name: "redact with failing tool", tools: []string{"fake", "broken"}, action: types.ActionRedact, content: "hello", wantAction: types.ActionBlock, wantTool: "broken", wantCode: types.SafeCheckFailedCode, wantContent: "hello"},
+		{name: "flag skips failing tool", tools: []string{"broken", "fake"}, action: types.ActionFlag, content: "hello", wantContent: "hello"},
+		{name: "flag skips missing tool", tools: []string{"missing", "fake"}, action: types.ActionFlag, content: "bad", wantAction: types.ActionFlag, wantTool: "fake", wantCode: "fake", wantContent: "bad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := CheckWithPolicy(&types.Policy{Tools: tt.tools, Action: tt.action}, tt.content)
+			assert.Equal(t, tt.wantAction, decision.Action)
+			assert.Equal(t, tt.wantTool, decision.Tool)
+			assert.Equal(t, tt.wantCode, decision.Result.Code)
+			assert.Equal(t, tt.wantContent, decision.Content)
+		})
+	}
+}
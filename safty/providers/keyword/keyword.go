@@ -67,10 +67,14 @@ func (k *KeywordChecker) Check(data string) (types.CheckResult, error) {
 		Details:   make([]string, 0),
 	}
 
+	lowerData := strings.ToLower(data)
 	for _, keyword := range config.SafeKeyWords {
-		if strings.Contains(strings.ToLower(data), strings.ToLower(keyword)) {
+		lowerKeyword := strings.ToLower(keyword)
+		if index := strings.Index(lowerData, lowerKeyword); index != -1 {
 			result.IsSafe = false
 			result.Details = append(result.Details, types.SafeDefaultErrorMessage)
+			// 转小写不改变字符数，位置与原文一致
+			result.Matches = append(result.Matches, types.NewMatch(lowerData, "keyword", index, index+len(lowerKeyword)))
 			result.Code = types.SafeDefaultErrorCode
 			result.Reason = types.SafeDefaultErrorMessage
 			result.RiskLevel = 10
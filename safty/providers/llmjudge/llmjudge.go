@@ -0,0 +1,113 @@
+package llmjudge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"one-api/common/config"
+	providersBase "one-api/providers/base"
+	"one-api/safty/providers/upstream"
+	saftyTypes "one-api/safty/types"
+	"one-api/types"
+	"strings"
+)
+
+type judgeResult struct {
+	Safe   bool   `json:"safe"`
+	Reason string `json:"reason"`
+}
+
+// LLMJudgeChecker 使用大模型判断内容是否安全
+// 通过系统设置的提示词要求模型返回 {"safe": bool, "reason": string}
+type LLMJudgeChecker struct{}
+
+// NewLLMJudgeChecker 创建新的 LLM 审查器实例
+func NewLLMJudgeChecker() *LLMJudgeChecker {
+	return &LLMJudgeChecker{}
+}
+
+// Name 返回检查器名称
+func (l *LLMJudgeChecker) Name() string {
+	return "LLMJudge"
+}
+
+// Init 渠道和模型在检查时读取，无需初始化
+func (l *LLMJudgeChecker) Init() error {
+	return nil
+}
+
+// Check 执行 LLM 审查
+func (l *LLMJudgeChecker) Check(data string) (saftyTypes.CheckResult, error) {
+	result := saftyTypes.CheckResult{
+		IsSafe:    true,
+		RiskLevel: 0,
+		Code:      saftyTypes.SafeDefaultSuccessCode,
+		Reason:    saftyTypes.SafeDefaultSuccessMessage,
+		Details:   make([]string, 0),
+	}
+
+	provider, err := upstream.GetProvider(config.SafeLLMJudgeChannelId, "/v1/chat/completions")
+	if err != nil {
+		return result, err
+	}
+
+	chatProvider, ok := provider.(providersBase.ChatInterface)
+	if !ok {
+		return result, errors.New("safety channel does not support chat completions")
+	}
+
+	modelName, err := upstream.GetModelName(provider, config.SafeLLMJudgeModel)
+	if err != nil {
+		return result, err
+	}
+
+	temperature := 0.0
+	response, errWithCode := chatProvider.CreateChatCompletion(&types.ChatCompletionRequest{
+		Model: modelName,
+		Messages: []types.ChatCompletionMessage{
+			{Role: types.ChatMessageRoleSystem, Content: config.SafeLLMJudgePrompt},
+			{Role: types.ChatMessageRoleUser, Content: data},
+		},
+		Temperature: &temperature,
+	})
+	if errWithCode != nil {
+		return result, fmt.Errorf("llm judge request failed: %s", errWithCode.Message)
+	}
+
+	if len(response.Choices) == 0 {
+		return result, errors.New("llm judge returned empty response")
+	}
+
+	judge, err := parseJudgeResult(response.Choices[0].Message.StringContent())
+	if err != nil {
+		return result, err
+	}
+
+	if !judge.Safe {
+		result.IsSafe = false
+		result.RiskLevel = 10
+		result.Code = saftyTypes.SafeDefaultErrorCode
+		result.Reason = saftyTypes.SafeDefaultErrorMessage
+		if judge.Reason != "" {
+			result.Details = append(result.Details, judge.Reason)
+		}
+	}
+
+	return result, nil
+}
+
+// parseJudgeResult 解析模型返回的结果，兼容代码块包裹等情况
+func parseJudgeResult(content string) (*judgeResult, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start == -1 || end <= start {
+		return nil, fmt.Errorf("invalid llm judge response: %s", content)
+	}
+
+	judge := &judgeResult{}
+	if err := json.Unmarshal([]byte(content[start:end+1]), judge); err != nil {
+		return nil, fmt.Errorf("invalid llm judge response: %s", content)
+	}
+
+	return judge, nil
+}
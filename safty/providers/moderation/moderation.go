@@ -0,0 +1,101 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"one-api/common/config"
+	providersBase "one-api/providers/base"
+	"one-api/safty/providers/upstream"
+	saftyTypes "one-api/safty/types"
+	"one-api/types"
+)
+
+type moderationResult struct {
+	Flagged        bool               `json:"flagged"`
+	Categories     map[string]bool    `json:"categories"`
+	CategoryScores map[string]float64 `json:"category_scores"`
+}
+
+// ModerationChecker 调用渠道的 /v1/moderations 接口进行内容审查
+type ModerationChecker struct{}
+
+// NewModerationChecker 创建新的 Moderation 检查器实例
+func NewModerationChecker() *ModerationChecker {
+	return &ModerationChecker{}
+}
+
+// Name 返回检查器名称
+func (m *ModerationChecker) Name() string {
+	return "Moderation"
+}
+
+// Init 渠道和模型在检查时读取，无需初始化
+func (m *ModerationChecker) Init() error {
+	return nil
+}
+
+// Check 执行 Moderation 检查
+func (m *ModerationChecker) Check(data string) (saftyTypes.CheckResult, error) {
+	result := saftyTypes.CheckResult{
+		IsSafe:    true,
+		RiskLevel: 0,
+		Code:      saftyTypes.SafeDefaultSuccessCode,
+		Reason:    saftyTypes.SafeDefaultSuccessMessage,
+		Details:   make([]string, 0),
+	}
+
+	provider, err := upstream.GetProvider(config.SafeModerationChannelId, "/v1/moderations")
+	if err != nil {
+		return result, err
+	}
+
+	moderationProvider, ok := provider.(providersBase.ModerationInterface)
+	if !ok {
+		return result, errors.New("safety channel does not support moderations")
+	}
+
+	modelName, err := upstream.GetModelName(provider, config.SafeModerationModel)
+	if err != nil {
+		return result, err
+	}
+
+	response, errWithCode := moderationProvider.CreateModeration(&types.ModerationRequest{
+		Input: data,
+		Model: modelName,
+	})
+	if errWithCode != nil {
+		return result, fmt.Errorf("moderation request failed: %s", errWithCode.Message)
+	}
+
+	resultsJson, err := json.Marshal(response.Results)
+	if err != nil {
+		return result, err
+	}
+
+	var results []moderationResult
+	if err := json.Unmarshal(resultsJson, &results); err != nil {
+		return result, err
+	}
+
+	for _, item := range results {
+		if !item.Flagged {
+			continue
+		}
+
+		result.IsSafe = false
+		for category, flagged := range item.Categories {
+			if flagged {
+				result.Details = append(result.Details, category)
+			}
+		}
+	}
+
+	if !result.IsSafe {
+		result.RiskLevel = 10
+		result.Code = saftyTypes.SafeDefaultErrorCode
+		result.Reason = saftyTypes.SafeDefaultErrorMessage
+	}
+
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package regex
+
+import (
+	"fmt"
+	"one-api/common/config"
+	"one-api/common/logger"
+	"one-api/safty/pii"
+	"one-api/safty/types"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RegexChecker 基于正则表达式的内容安全检查器
+// 内置常见的个人敏感信息规则，并支持自定义规则，命中的内容可以被脱敏
+type RegexChecker struct {
+	sync.RWMutex
+	// source 当前已编译的自定义规则原文，规则变更时重新编译
+	source string
+	rules  []*pii.Detector
+}
+
+// NewRegexChecker 创建新的正则检查器实例
+func NewRegexChecker() *RegexChecker {
+	return &RegexChecker{}
+}
+
+// Name 返回检查器名称
+func (r *RegexChecker) Name() string {
+	return "Regex"
+}
+
+// Init 初始化正则检查器
+func (r *RegexChecker) Init() error {
+	rules := r.getRules()
+	logger.SysLog(fmt.Sprintf("SafeTools %s load rules：%d pcs", r.Name(), len(rules)))
+	return nil
+}
+
+// Check 检查内容中是否包含命中规则的信息
+func (r *RegexChecker) Check(data string) (types.CheckResult, error) {
+	result := types.CheckResult{
+		IsSafe:    true,
+		RiskLevel: 0,
+		Code:      types.SafeDefaultSuccessCode,
+		Reason:    types.SafeDefaultSuccessMessage,
+		Details:   make([]string, 0),
+	}
+
+	for _, rule := range r.getRules() {
+		indexes := rule.FindAllIndex(data)
+		if len(indexes) == 0 {
+			continue
+		}
+		result.Details = append(result.Details, rule.Name)
+		for _, index := range indexes {
+			result.Matches = append(result.Matches, types.NewMatch(data, rule.Name, index[0], index[1]))
+		}
+	}
+
+	if len(result.Details) > 0 {
+		result.IsSafe = false
+		result.RiskLevel = 5
+		result.Code = types.SafeDefaultErrorCode
+		result.Reason = types.SafeDefaultErrorMessage
+	}
+
+	return result, nil
+}
+
+// Redact 将命中规则的内容替换为占位符
+func (r *RegexChecker) Redact(data string) string {
+	for _, rule := range r.getRules() {
+		for _, match := range rule.FindAll(data) {
+			data = strings.ReplaceAll(data, match, "[REDACTED:"+rule.Name+"]")
+		}
+	}
+
+	return data
+}
+
+// getRules 获取内置规则和自定义规则，自定义规则发生变化时重新编译
+func (r *RegexChecker) getRules() []*pii.Detector {
+	source := strings.Join(config.SafeRegexRules, "\n")
+
+	r.RLock()
+	if r.rules != nil && r.source == source {
+		rules := r.rules
+		r.RUnlock()
+		return rules
+	}
+	r.RUnlock()
+
+	rules := append([]*pii.Detector{}, pii.BuiltinDetectors()...)
+	custom, err := compileRules(source)
+	if err != nil {
+		logger.SysError("SafeTools Regex compile rules failed: " + err.Error())
+	}
+	rules = append(rules, custom...)
+
+	r.Lock()
+	defer r.Unlock()
+	r.source = source
+	r.rules = rules
+
+	return rules
+}
+
+// ValidateRules 校验自定义规则是否可以编译
+func ValidateRules(source string) error {
+	_, err := compileRules(source)
+	return err
+}
+
+func compileRules(source string) ([]*pii.Detector, error) {
+	var rules []*pii.Detector
+	for i, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		pattern, err := regexp.Compile(line)
+		if err != nil {
+			return rules, fmt.Errorf("第 %d 行: %w", i+1, err)
+		}
+		rules = append(rules, &pii.Detector{Name: fmt.Sprintf("custom_%d", i+1), Pattern: pattern})
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,53 @@
+package upstream
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"one-api/model"
+	"one-api/providers"
+	providersBase "one-api/providers/base"
+	"one-api/types"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProvider 获取审查请求使用的渠道供应商，仅可使用已启用的渠道
+func GetProvider(channelId int, path string) (providersBase.ProviderInterface, error) {
+	if channelId == 0 {
+		return nil, fmt.Errorf("safety channel not configured")
+	}
+
+	channel := model.ChannelGroup.GetChannel(channelId)
+	if channel == nil {
+		return nil, fmt.Errorf("safety channel #%d not found or disabled", channelId)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, err := http.NewRequest(http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	provider := providers.GetProvider(channel, c)
+	if provider == nil {
+		return nil, fmt.Errorf("safety channel #%d not implemented", channelId)
+	}
+	provider.SetUsage(&types.Usage{})
+
+	return provider, nil
+}
+
+// GetModelName 获取渠道映射后的模型名称
+func GetModelName(provider providersBase.ProviderInterface, modelName string) (string, error) {
+	newModelName, err := provider.ModelMappingHandler(modelName)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(newModelName, "+"), nil
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"one-api/common/logger"
 	"one-api/safty/providers/keyword"
+	"one-api/safty/providers/llmjudge"
+	"one-api/safty/providers/moderation"
+	"one-api/safty/providers/regex"
 	"one-api/safty/types"
 )
 
@@ -26,6 +29,12 @@ type SaftyTool interface {
 	Check(data string) (types.CheckResult, error)
 }
 
+// SaftyRedactor 支持脱敏的检查器需要实现这个接口
+type SaftyRedactor interface {
+	// Redact 返回脱敏后的内容
+	Redact(data string) string
+}
+
 // Tools 存储所有注册的安全检查器
 // key: 检查器名称
 // value: 检查器实例
@@ -36,7 +45,13 @@ var Tools = make(map[string]SaftyTool)
 func InitSaftyTools() error {
 	// 注册关键词检查器
 	keywordChecker := keyword.NewKeywordChecker()
-	RegisterTool("Keyword", keywordChecker)
+	RegisterTool(types.ToolKeyword, keywordChecker)
+	// 注册正则及敏感信息检查器
+	RegisterTool(types.ToolRegex, regex.NewRegexChecker())
+	// 注册 Moderation 接口检查器
+	RegisterTool(types.ToolModeration, moderation.NewModerationChecker())
+	// 注册 LLM 审查器
+	RegisterTool(types.ToolLLMJudge, llmjudge.NewLLMJudgeChecker())
 
 	// 初始化所有已注册的检查器
 	for name, tool := range Tools {
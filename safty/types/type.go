@@ -1,8 +1,13 @@
 package types
 
+import "unicode/utf8"
+
 const SafeDefaultErrorCode = "content_security_policy_blocking"
 const SafeDefaultErrorMessage = "content contains sensitive information"
 
+const SafeCheckFailedCode = "content_security_check_failed"
+const SafeCheckFailedMessage = "content security check is unavailable"
+
 const SafeDefaultSuccessCode = "content_security_policy_through"
 const SafeDefaultSuccessMessage = "content safe"
 
@@ -18,6 +23,25 @@ type CheckResult struct {
 	Details []string `json:"details,omitempty"`
 	// RiskLevel 风险等级，数值越大风险越高
 	RiskLevel int `json:"risk_level,omitempty"`
+	// Matches 本地检查器命中内容的位置，远程审查无法提供
+	Matches []Match `json:"matches,omitempty"`
+}
+
+// Match 命中内容在原文中的位置，按字符计算，不包含命中的内容本身
+type Match struct {
+	Detector string `json:"detector"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+}
+
+// NewMatch 将字节位置转换为字符位置
+func NewMatch(data, detector string, start, end int) Match {
+	runeStart := utf8.RuneCountInString(data[:start])
+	return Match{
+		Detector: detector,
+		Start:    runeStart,
+		End:      runeStart + utf8.RuneCountInString(data[start:end]),
+	}
 }
 
 // CheckConfig 定义了安全检查器的配置
@@ -27,3 +51,40 @@ type CheckConfig struct {
 	// Options 其他配置选项，具体含义由检查器自行定义
 	Options map[string]interface{} `json:"options,omitempty"`
 }
+
+// 内置的检查器名称
+const (
+	ToolKeyword    = "Keyword"
+	ToolRegex      = "Regex"
+	ToolModeration = "Moderation"
+	ToolLLMJudge   = "LLMJudge"
+)
+
+var ToolNames = []string{ToolKeyword, ToolRegex, ToolModeration, ToolLLMJudge}
+
+// 命中后的处理方式
+const (
+	ActionBlock  = "block"
+	ActionFlag   = "flag"
+	ActionRedact = "redact"
+)
+
+// 检查阶段
+const (
+	StageInput  = "input"
+	StageOutput = "output"
+)
+
+// Policy 定义了分组的内容审查策略
+type Policy struct {
+	// Tools 使用的检查器，为空时使用系统设置的检查器
+	Tools []string `json:"tools,omitempty"`
+	// Input 是否检查输入
+	Input bool `json:"input"`
+	// Output 是否检查输出，仅支持 OpenAI 格式的对话和补全接口，原生 Claude/Gemini 接口的输出不会检查
+	Output bool `json:"output"`
+	// Action 命中后的处理方式 block/flag/redact
+	Action string `json:"action"`
+	// StreamChunkSize 流式输出每累计多少字符检查一次
+	StreamChunkSize int `json:"stream_chunk_size,omitempty"`
+}
@@ -1,6 +1,11 @@
 package types
 
-import "mime/multipart"
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"strings"
+)
 
 type SpeechAudioRequest struct {
 	Model          string  `json:"model" binding:"required"`
@@ -38,3 +43,82 @@ type AudioResponseWrapper struct {
 	Headers map[string]string
 	Body    []byte
 }
+
+// AudioSegment verbose_json 中的分段
+type AudioSegment struct {
+	Id    int     `json:"id"`
+	Seek  int     `json:"seek"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// NewAudioResponse 按 response_format 生成与 OpenAI 一致的识别结果，用于不兼容 OpenAI 格式的供应商
+func (r *AudioRequest) NewAudioResponse(task, language string, duration float64, text string, segments []AudioSegment) (*AudioResponseWrapper, error) {
+	wrapper := &AudioResponseWrapper{
+		Headers: map[string]string{"Content-Type": "text/plain; charset=utf-8"},
+	}
+
+	switch r.ResponseFormat {
+	case "text":
+		wrapper.Body = []byte(text)
+	case "srt":
+		wrapper.Body = []byte(formatSubtitles(segments, text, duration, false))
+	case "vtt":
+		wrapper.Body = []byte(formatSubtitles(segments, text, duration, true))
+	case "verbose_json":
+		body, err := json.Marshal(&AudioResponse{
+			Task:     task,
+			Language: language,
+			Duration: duration,
+			Segments: segments,
+			Text:     text,
+		})
+		if err != nil {
+			return nil, err
+		}
+		wrapper.Headers["Content-Type"] = "application/json"
+		wrapper.Body = body
+	default:
+		body, err := json.Marshal(&AudioResponse{Text: text})
+		if err != nil {
+			return nil, err
+		}
+		wrapper.Headers["Content-Type"] = "application/json"
+		wrapper.Body = body
+	}
+
+	return wrapper, nil
+}
+
+// 没有分段时整段文本作为一个字幕
+func formatSubtitles(segments []AudioSegment, text string, duration float64, vtt bool) string {
+	if len(segments) == 0 && text != "" {
+		segments = []AudioSegment{{Start: 0, End: duration, Text: text}}
+	}
+
+	var builder strings.Builder
+	if vtt {
+		builder.WriteString("WEBVTT\n\n")
+	}
+	for i, segment := range segments {
+		if !vtt {
+			builder.WriteString(fmt.Sprintf("%d\n", i+1))
+		}
+		builder.WriteString(formatSubtitleTime(segment.Start, vtt) + " --> " + formatSubtitleTime(segment.End, vtt) + "\n")
+		builder.WriteString(strings.TrimSpace(segment.Text) + "\n\n")
+	}
+	return builder.String()
+}
+
+func formatSubtitleTime(seconds float64, vtt bool) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	millis := int64(seconds*1000 + 0.5)
+	separator := ","
+	if vtt {
+		separator = "."
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", millis/3600000, millis/60000%60, millis/1000%60, separator, millis%1000)
+}
@@ -5,6 +5,7 @@ import "encoding/json"
 const (
 	ContentTypeText     = "text"
 	ContentTypeImageURL = "image_url"
+	ContentTypeFile     = "file"
 )
 
 const (
@@ -165,6 +166,34 @@ type ChatMessageFile struct {
 	FileData string `json:"file_data,omitempty"`
 }
 
+// ChatMessageAnnotation 回复中的引用标注，网页引用使用 url_citation，文档引用使用 file_citation
+type ChatMessageAnnotation struct {
+	Type         string            `json:"type"`
+	URLCitation  *ChatURLCitation  `json:"url_citation,omitempty"`
+	FileCitation *ChatFileCitation `json:"file_citation,omitempty"`
+}
+
+type ChatURLCitation struct {
+	StartIndex int    `json:"start_index"`
+	EndIndex   int    `json:"end_index"`
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+	CitedText  string `json:"cited_text,omitempty"`
+}
+
+// ChatFileCitation 文档引用，FileIndex 为文档在请求中的序号
+type ChatFileCitation struct {
+	StartIndex      int    `json:"start_index"`
+	EndIndex        int    `json:"end_index"`
+	FileIndex       int    `json:"file_index"`
+	Filename        string `json:"filename,omitempty"`
+	CitedText       string `json:"cited_text,omitempty"`
+	StartPageNumber int    `json:"start_page_number,omitempty"`
+	EndPageNumber   int    `json:"end_page_number,omitempty"`
+	StartCharIndex  int    `json:"start_char_index,omitempty"`
+	EndCharIndex    int    `json:"end_char_index,omitempty"`
+}
+
 type ChatCompletionResponseFormat struct {
 	Type       string            `json:"type,omitempty"`
 	JsonSchema *FormatJsonSchema `json:"json_schema,omitempty"`
@@ -218,6 +247,14 @@ type ChatCompletionRequest struct {
 	EnableSearch   *bool `json:"enable_search,omitempty"`   // qwen 搜索开关
 
 	OneOtherArg string `json:"-"`
+	// OnePromptCache 网关自动插入提示词缓存断点的策略，为空时不处理
+	OnePromptCache *PromptCachePolicy `json:"-"`
+}
+
+// PromptCachePolicy 提示词缓存策略，由渠道插件或令牌设置开启
+type PromptCachePolicy struct {
+	// MinTokens 可缓存前缀的最小 token 数，低于该值不插入断点
+	MinTokens int
 }
 
 type ChatReasoning struct {
@@ -400,6 +437,7 @@ type ChatCompletionStreamChoiceDelta struct {
 	Reasoning        string                           `json:"reasoning,omitempty"`
 	Image            []MultimediaData                 `json:"image,omitempty"`
 	Images           []ChatMessagePart                `json:"images,omitempty"`
+	Annotations      []ChatMessageAnnotation          `json:"annotations,omitempty"`
 }
 
 func (m *ChatCompletionStreamChoiceDelta) ToolToFuncCalls() {
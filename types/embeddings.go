@@ -1,11 +1,19 @@
 package types
 
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+)
+
 type EmbeddingRequest struct {
 	Model          string `json:"model" binding:"required"`
 	Input          any    `json:"input" binding:"required"`
 	EncodingFormat string `json:"encoding_format,omitempty"`
 	Dimensions     int    `json:"dimensions,omitempty"`
 	User           string `json:"user,omitempty"`
+	// InputType 检索场景的输入类型，与 Cohere 一致，例如 search_document、search_query、classification、clustering
+	InputType string `json:"input_type,omitempty"`
 }
 
 type Embedding struct {
@@ -57,3 +65,35 @@ func (r EmbeddingRequest) ParseInputString() string {
 	}
 	return input
 }
+
+// FormatEmbedding encoding_format 为 base64 时按 float32 小端序编码，与 OpenAI 一致
+func (r EmbeddingRequest) FormatEmbedding(vector []float64) any {
+	if r.EncodingFormat != "base64" {
+		return vector
+	}
+
+	buf := make([]byte, 4*len(vector))
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(float32(value)))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// NewEmbeddingResponse 按输入顺序组装向量
+func (r EmbeddingRequest) NewEmbeddingResponse(vectors [][]float64, usage *Usage) *EmbeddingResponse {
+	data := make([]Embedding, 0, len(vectors))
+	for i, vector := range vectors {
+		data = append(data, Embedding{
+			Object:    "embedding",
+			Embedding: r.FormatEmbedding(vector),
+			Index:     i,
+		})
+	}
+
+	return &EmbeddingResponse{
+		Object: "list",
+		Data:   data,
+		Model:  r.Model,
+		Usage:  usage,
+	}
+}
@@ -62,10 +62,11 @@ func (e *Event) Error() string {
 }
 
 type ResponseEvent struct {
-	ID     string      `json:"id"`
-	Object string      `json:"object"`
-	Status string      `json:"status"`
-	Usage  *UsageEvent `json:"usage,omitempty"`
+	ID     string         `json:"id"`
+	Object string         `json:"object"`
+	Status string         `json:"status"`
+	Output []RealtimeItem `json:"output,omitempty"`
+	Usage  *UsageEvent    `json:"usage,omitempty"`
 }
 
 type UsageEvent struct {
@@ -45,3 +45,12 @@ type ImageEditRequest struct {
 	ResponseFormat string                  `form:"response_format"`
 	User           string                  `form:"user"`
 }
+
+// GetImages 返回上传的全部图片，兼容 image 和 image[] 两种字段
+func (r *ImageEditRequest) GetImages() []*multipart.FileHeader {
+	images := make([]*multipart.FileHeader, 0, len(r.Images)+1)
+	if r.Image != nil {
+		images = append(images, r.Image)
+	}
+	return append(images, r.Images...)
+}
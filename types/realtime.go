@@ -0,0 +1,173 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"one-api/common/utils"
+)
+
+// 需要协议转换的 realtime 事件，用于 Gemini Live 和文本模拟模式
+const (
+	EventTypeSessionUpdate               = "session.update"
+	EventTypeSessionUpdated              = "session.updated"
+	EventTypeInputAudioBufferAppend      = "input_audio_buffer.append"
+	EventTypeInputAudioBufferCommit      = "input_audio_buffer.commit"
+	EventTypeInputAudioBufferCommitted   = "input_audio_buffer.committed"
+	EventTypeInputAudioBufferClear       = "input_audio_buffer.clear"
+	EventTypeInputAudioBufferCleared     = "input_audio_buffer.cleared"
+	EventTypeInputAudioBufferSpeechStart = "input_audio_buffer.speech_started"
+	EventTypeConversationItemCreate      = "conversation.item.create"
+	EventTypeConversationItemCreated     = "conversation.item.created"
+	EventTypeConversationItemDelete      = "conversation.item.delete"
+	EventTypeConversationItemDeleted     = "conversation.item.deleted"
+	EventTypeInputTranscriptionDelta     = "conversation.item.input_audio_transcription.delta"
+	EventTypeResponseCreate              = "response.create"
+	EventTypeResponseCancel              = "response.cancel"
+	EventTypeResponseCreated             = "response.created"
+	EventTypeResponseOutputItemAdded     = "response.output_item.added"
+	EventTypeResponseOutputItemDone      = "response.output_item.done"
+	EventTypeResponseContentPartAdded    = "response.content_part.added"
+	EventTypeResponseContentPartDone     = "response.content_part.done"
+	EventTypeResponseTextDelta           = "response.text.delta"
+	EventTypeResponseTextDone            = "response.text.done"
+	EventTypeResponseAudioDelta          = "response.audio.delta"
+	EventTypeResponseAudioDone           = "response.audio.done"
+	EventTypeResponseTranscriptDelta     = "response.audio_transcript.delta"
+	EventTypeResponseTranscriptDone      = "response.audio_transcript.done"
+	EventTypeResponseFunctionArgsDone    = "response.function_call_arguments.done"
+)
+
+const (
+	RealtimeItemTypeMessage            = "message"
+	RealtimeItemTypeFunctionCall       = "function_call"
+	RealtimeItemTypeFunctionCallOutput = "function_call_output"
+)
+
+// RealtimeEvent 客户端和服务端事件的通用结构，只包含协议转换需要的字段
+type RealtimeEvent struct {
+	EventId        string           `json:"event_id"`
+	Type           string           `json:"type"`
+	Session        *RealtimeSession `json:"session,omitempty"`
+	Response       *ResponseEvent   `json:"response,omitempty"`
+	Item           *RealtimeItem    `json:"item,omitempty"`
+	Part           *RealtimeContent `json:"part,omitempty"`
+	PreviousItemId string           `json:"previous_item_id,omitempty"`
+	ResponseId     string           `json:"response_id,omitempty"`
+	ItemId         string           `json:"item_id,omitempty"`
+	OutputIndex    int              `json:"output_index"`
+	ContentIndex   int              `json:"content_index"`
+	Delta          string           `json:"delta,omitempty"`
+	Text           string           `json:"text,omitempty"`
+	Transcript     string           `json:"transcript,omitempty"`
+	Audio          string           `json:"audio,omitempty"`
+	CallId         string           `json:"call_id,omitempty"`
+	Name           string           `json:"name,omitempty"`
+	Arguments      string           `json:"arguments,omitempty"`
+}
+
+type RealtimeSession struct {
+	Id                      string         `json:"id,omitempty"`
+	Object                  string         `json:"object,omitempty"`
+	Model                   string         `json:"model,omitempty"`
+	Modalities              []string       `json:"modalities,omitempty"`
+	Instructions            string         `json:"instructions,omitempty"`
+	Voice                   string         `json:"voice,omitempty"`
+	InputAudioFormat        string         `json:"input_audio_format,omitempty"`
+	OutputAudioFormat       string         `json:"output_audio_format,omitempty"`
+	InputAudioTranscription any            `json:"input_audio_transcription,omitempty"`
+	TurnDetection           any            `json:"turn_detection,omitempty"`
+	Tools                   []RealtimeTool `json:"tools,omitempty"`
+	ToolChoice              any            `json:"tool_choice,omitempty"`
+	Temperature             *float64       `json:"temperature,omitempty"`
+	MaxResponseOutputTokens any            `json:"max_response_output_tokens,omitempty"`
+}
+
+// Merge 合并 session.update 中设置的字段
+func (s *RealtimeSession) Merge(other *RealtimeSession) {
+	if other == nil {
+		return
+	}
+	if other.Modalities != nil {
+		s.Modalities = other.Modalities
+	}
+	if other.Instructions != "" {
+		s.Instructions = other.Instructions
+	}
+	if other.Voice != "" {
+		s.Voice = other.Voice
+	}
+	if other.InputAudioTranscription != nil {
+		s.InputAudioTranscription = other.InputAudioTranscription
+	}
+	if other.TurnDetection != nil {
+		s.TurnDetection = other.TurnDetection
+	}
+	if other.Tools != nil {
+		s.Tools = other.Tools
+	}
+	if other.ToolChoice != nil {
+		s.ToolChoice = other.ToolChoice
+	}
+	if other.Temperature != nil {
+		s.Temperature = other.Temperature
+	}
+	if other.MaxResponseOutputTokens != nil {
+		s.MaxResponseOutputTokens = other.MaxResponseOutputTokens
+	}
+}
+
+type RealtimeTool struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+type RealtimeItem struct {
+	Id        string            `json:"id,omitempty"`
+	Object    string            `json:"object,omitempty"`
+	Type      string            `json:"type"`
+	Status    string            `json:"status,omitempty"`
+	Role      string            `json:"role,omitempty"`
+	Content   []RealtimeContent `json:"content,omitempty"`
+	CallId    string            `json:"call_id,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Arguments string            `json:"arguments,omitempty"`
+	Output    string            `json:"output,omitempty"`
+}
+
+// GetText 返回消息中的文本和音频转写内容
+func (i *RealtimeItem) GetText() string {
+	text := ""
+	for _, content := range i.Content {
+		if content.Text != "" {
+			text += content.Text
+		} else {
+			text += content.Transcript
+		}
+	}
+	return text
+}
+
+type RealtimeContent struct {
+	Type       string `json:"type"`
+	Text       string `json:"text,omitempty"`
+	Audio      string `json:"audio,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+}
+
+func NewRealtimeEvent(eventType string) *RealtimeEvent {
+	return &RealtimeEvent{
+		EventId: NewRealtimeId("event"),
+		Type:    eventType,
+	}
+}
+
+func (e *RealtimeEvent) Marshal() []byte {
+	data, _ := json.Marshal(e)
+	return data
+}
+
+func NewRealtimeId(prefix string) string {
+	return fmt.Sprintf("%s_%s", prefix, utils.GetRandomString(20))
+}
@@ -10,20 +10,42 @@ type RerankRequest struct {
 	Query     string `json:"query" binding:"required"`
 	TopN      int    `json:"top_n"`
 	Documents []any  `json:"documents" binding:"required"`
+	// ReturnDocuments 为空时默认返回文档内容
+	ReturnDocuments *bool `json:"return_documents,omitempty"`
 }
 
+// GetDocumentsList 文档支持字符串或 {"text": "..."} 格式
 func (r *RerankRequest) GetDocumentsList() ([]string, error) {
 	documents := make([]string, len(r.Documents))
 	for i, doc := range r.Documents {
-		str, ok := doc.(string)
-		if !ok {
+		switch v := doc.(type) {
+		case string:
+			documents[i] = v
+		case map[string]any:
+			text, ok := v["text"].(string)
+			if !ok {
+				return nil, fmt.Errorf("document at index %d has no text", i)
+			}
+			documents[i] = text
+		default:
 			return nil, fmt.Errorf("document at index %d is not a string", i)
 		}
-		documents[i] = str
 	}
 	return documents, nil
 }
 
+func (r *RerankRequest) ShouldReturnDocuments() bool {
+	return r.ReturnDocuments == nil || *r.ReturnDocuments
+}
+
+// GetTopN 未指定或超出文档数量时返回全部
+func (r *RerankRequest) GetTopN() int {
+	if r.TopN <= 0 || r.TopN > len(r.Documents) {
+		return len(r.Documents)
+	}
+	return r.TopN
+}
+
 // type MultimodalDocument struct {
 // 	Text  string `json:"text,omitempty"`
 // 	Image string `json:"image,omitempty"`
@@ -36,9 +58,9 @@ type RerankResponse struct {
 }
 
 type RerankResult struct {
-	Index          int                  `json:"index"`
-	Document       RerankResultDocument `json:"document,omitempty"`
-	RelevanceScore float64              `json:"relevance_score"`
+	Index          int                   `json:"index"`
+	Document       *RerankResultDocument `json:"document,omitempty"`
+	RelevanceScore float64               `json:"relevance_score"`
 }
 
 type RerankResultDocument struct {
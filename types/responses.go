@@ -376,7 +376,7 @@ func (c *ContentResponses) ToChatContent() (*ChatMessagePart, error) {
 			return nil, errors.New("input_file must have either file_data or file_name")
 		}
 		return &ChatMessagePart{
-			Type: "file",
+			Type: ContentTypeFile,
 			File: &ChatMessageFile{
 				Filename: c.FileName,
 				FileData: c.FileData,
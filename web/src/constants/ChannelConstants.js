@@ -222,6 +222,13 @@ export const CHANNEL_OPTIONS = {
     color: 'orange',
     url: 'https://jina.ai/'
   },
+  48: {
+    key: 48,
+    text: 'Rerank',
+    value: 48,
+    color: 'default',
+    url: ''
+  },
   24: {
     key: 24,
     text: 'Azure Speech',
@@ -271,6 +278,27 @@ export const CHANNEL_OPTIONS = {
     color: 'orange',
     url: 'https://x.ai'
   },
+  57: {
+    key: 57,
+    text: 'Whisper',
+    value: 57,
+    color: 'default',
+    url: ''
+  },
+  58: {
+    key: 58,
+    text: 'TTS',
+    value: 58,
+    color: 'default',
+    url: ''
+  },
+  59: {
+    key: 59,
+    text: '声明式渠道',
+    value: 59,
+    color: 'primary',
+    url: ''
+  },
   8: {
     key: 8,
     text: '自定义渠道',
@@ -60,6 +60,7 @@ const getValidationSchema = (t) =>
     key: Yup.string().when('is_edit', { is: false, then: Yup.string().required(t('channel_edit.requiredKey')) }),
     other: Yup.string(),
     proxy: Yup.string(),
+    balance_threshold: Yup.number(),
     test_model: Yup.string(),
     models: Yup.array().min(1, t('channel_edit.requiredModels')),
     groups: Yup.array().min(1, t('channel_edit.requiredGroup')),
@@ -257,6 +258,10 @@ const EditModal = ({ open, channelId, onCancel, onOk, groupOptions, isTag, model
       values.disabled_stream = removeDuplicates(values.disabled_stream);
     }
 
+    if (values.realtime_models) {
+      values.realtime_models = removeDuplicates(values.realtime_models);
+    }
+
     // 获取现有的模型 ID
     const existingModelIds = values.models.map((model) => model.id);
 
@@ -549,6 +554,8 @@ const EditModal = ({ open, channelId, onCancel, onOk, groupOptions, isTag, model
                       id="channel-other-label"
                       label={customizeT(inputLabel.other)}
                       type="text"
+                      multiline={values.type === 59}
+                      minRows={values.type === 59 ? 8 : undefined}
                       value={values.other}
                       name="other"
                       disabled={hasTag}
@@ -1008,6 +1015,26 @@ const EditModal = ({ open, channelId, onCancel, onOk, groupOptions, isTag, model
                     />
                   </FormControl>
                 )}
+                {inputPrompt.realtime_models && (
+                  <FormControl
+                    fullWidth
+                    error={Boolean(touched.realtime_models && errors.realtime_models)}
+                    sx={{ ...theme.typography.otherInput }}
+                  >
+                    <ListInput
+                      listValue={values.realtime_models}
+                      onChange={(newValue) => {
+                        setFieldValue('realtime_models', newValue);
+                      }}
+                      disabled={hasTag}
+                      error={Boolean(touched.realtime_models && errors.realtime_models)}
+                      label={{
+                        name: customizeT(inputLabel.realtime_models),
+                        itemName: customizeT(inputPrompt.realtime_models)
+                      }}
+                    />
+                  </FormControl>
+                )}
 
                 <FormControl fullWidth error={Boolean(touched.proxy && errors.proxy)} sx={{ ...theme.typography.otherInput }}>
                   <InputLabel htmlFor="channel-proxy-label">{customizeT(inputLabel.proxy)}</InputLabel>
@@ -1031,6 +1058,54 @@ const EditModal = ({ open, channelId, onCancel, onOk, groupOptions, isTag, model
                     <FormHelperText id="helper-tex-channel-proxy-label"> {customizeT(inputPrompt.proxy)} </FormHelperText>
                   )}
                 </FormControl>
+                {inputPrompt.balance_disable && (
+                  <FormControl fullWidth>
+                    <FormControlLabel
+                      control={
+                        <Switch
+                          disabled={hasTag}
+                          checked={Boolean(values.balance_disable)}
+                          onChange={(event) => {
+                            setFieldValue('balance_disable', event.target.checked);
+                          }}
+                        />
+                      }
+                      label={customizeT(inputLabel.balance_disable)}
+                    />
+                    <FormHelperText id="helper-tex-balance_disable-label">{customizeT(inputPrompt.balance_disable)}</FormHelperText>
+                  </FormControl>
+                )}
+                {inputPrompt.balance_threshold && values.balance_disable && (
+                  <FormControl
+                    fullWidth
+                    error={Boolean(touched.balance_threshold && errors.balance_threshold)}
+                    sx={{ ...theme.typography.otherInput }}
+                  >
+                    <InputLabel htmlFor="channel-balance_threshold-label">{customizeT(inputLabel.balance_threshold)}</InputLabel>
+                    <OutlinedInput
+                      id="channel-balance_threshold-label"
+                      label={customizeT(inputLabel.balance_threshold)}
+                      disabled={hasTag}
+                      type="number"
+                      value={values.balance_threshold}
+                      name="balance_threshold"
+                      onBlur={handleBlur}
+                      onChange={handleChange}
+                      inputProps={{ step: 'any' }}
+                      aria-describedby="helper-text-channel-balance_threshold-label"
+                    />
+                    {touched.balance_threshold && errors.balance_threshold ? (
+                      <FormHelperText error id="helper-tex-channel-balance_threshold-label">
+                        {errors.balance_threshold}
+                      </FormHelperText>
+                    ) : (
+                      <FormHelperText id="helper-tex-channel-balance_threshold-label">
+                        {' '}
+                        {customizeT(inputPrompt.balance_threshold)}{' '}
+                      </FormHelperText>
+                    )}
+                  </FormControl>
+                )}
                 {inputPrompt.test_model && (
                   <FormControl fullWidth error={Boolean(touched.test_model && errors.test_model)} sx={{ ...theme.typography.otherInput }}>
                     <InputLabel htmlFor="channel-test_model-label">{customizeT(inputLabel.test_model)}</InputLabel>
@@ -1125,6 +1200,59 @@ const EditModal = ({ open, channelId, onCancel, onOk, groupOptions, isTag, model
                     <FormHelperText id="helper-tex-compatible_response-label">{customizeT(inputPrompt.compatible_response)}</FormHelperText>
                   </FormControl>
                 )}
+                {inputPrompt.model_sync && (
+                  <FormControl fullWidth>
+                    <FormControlLabel
+                      control={
+                        <Switch
+                          disabled={hasTag}
+                          checked={Boolean(values.model_sync)}
+                          onChange={(event) => {
+                            setFieldValue('model_sync', event.target.checked);
+                          }}
+                        />
+                      }
+                      label={customizeT(inputLabel.model_sync)}
+                    />
+                    <FormHelperText id="helper-tex-model_sync-label">{customizeT(inputPrompt.model_sync)}</FormHelperText>
+                  </FormControl>
+                )}
+                {inputPrompt.model_sync && values.model_sync && (
+                  <>
+                    <FormControl fullWidth sx={{ ...theme.typography.otherInput }}>
+                      <InputLabel htmlFor="channel-model_sync_patterns-label">{customizeT(inputLabel.model_sync_patterns)}</InputLabel>
+                      <OutlinedInput
+                        id="channel-model_sync_patterns-label"
+                        label={customizeT(inputLabel.model_sync_patterns)}
+                        type="text"
+                        disabled={hasTag}
+                        value={values.model_sync_patterns}
+                        name="model_sync_patterns"
+                        onBlur={handleBlur}
+                        onChange={handleChange}
+                        aria-describedby="helper-text-channel-model_sync_patterns-label"
+                      />
+                      <FormHelperText id="helper-tex-channel-model_sync_patterns-label">
+                        {customizeT(inputPrompt.model_sync_patterns)}
+                      </FormHelperText>
+                    </FormControl>
+                    <FormControl fullWidth>
+                      <FormControlLabel
+                        control={
+                          <Switch
+                            disabled={hasTag}
+                            checked={Boolean(values.model_sync_remove)}
+                            onChange={(event) => {
+                              setFieldValue('model_sync_remove', event.target.checked);
+                            }}
+                          />
+                        }
+                        label={customizeT(inputLabel.model_sync_remove)}
+                      />
+                      <FormHelperText id="helper-tex-model_sync_remove-label">{customizeT(inputPrompt.model_sync_remove)}</FormHelperText>
+                    </FormControl>
+                  </>
+                )}
                 {pluginList[values.type] &&
                   Object.keys(pluginList[values.type]).map((pluginId) => {
                     const plugin = pluginList[values.type][pluginId];
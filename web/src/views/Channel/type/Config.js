@@ -18,8 +18,14 @@ const defaultConfig = {
     system_prompt: '',
     enable_search: false,
     pre_cost: 1,
+    balance_disable: false,
+    balance_threshold: 0,
     disabled_stream: [],
-    compatible_response: false
+    realtime_models: [],
+    compatible_response: false,
+    model_sync: false,
+    model_sync_patterns: '',
+    model_sync_remove: false
   },
   inputLabel: {
     name: '渠道名称',
@@ -40,8 +46,14 @@ const defaultConfig = {
     system_prompt: '系统提示词',
     enable_search: '启用搜索',
     pre_cost: '预计费选项',
+    balance_disable: '余额不足自动禁用',
+    balance_threshold: '余额阈值',
     disabled_stream: '禁用流式的模型',
-    compatible_response: '兼容Response API'
+    realtime_models: 'Realtime 模型',
+    compatible_response: '兼容Response API',
+    model_sync: '定时同步模型',
+    model_sync_patterns: '自动添加规则',
+    model_sync_remove: '自动移除下线模型'
   },
   prompt: {
     type: '请选择渠道类型',
@@ -66,8 +78,16 @@ const defaultConfig = {
     enable_search: '启用后，此渠道的Chat对话将会被加入搜索功能',
     pre_cost:
       '这里选择预计费选项，用于预估费用，如果你觉得计算图片占用太多资源，可以选择关闭图片计费。但是请注意：有些渠道在stream下是不会返回tokens的，这会导致输入tokens计算错误。',
+    balance_disable:
+      '开启后定时更新余额时，余额不高于阈值会自动禁用渠道，余额恢复后只重新启用因余额不足被禁用的渠道，需要开启自动禁用/启用渠道，仅支持可以查询余额的渠道，请确认该渠道的余额查询准确后再开启',
+    balance_threshold: '单位为美元，余额不高于该值时自动禁用渠道',
     disabled_stream: '这里填写禁用流式的模型，注意：如果填写了禁用流式的模型，那么这些模型在流式请求时会跳过该渠道',
-    compatible_response: '兼容Response API'
+    realtime_models:
+      '这里填写原生支持 realtime 的模型，这些模型的 realtime 请求直接转发给上游，其余模型使用对话接口模拟，名称包含 realtime 的 OpenAI/Azure 模型和 Gemini Live 模型无需填写，适用于部署名不含 realtime 的 Azure 渠道',
+    compatible_response: '兼容Response API',
+    model_sync: '开启后将按配置文件中的 channel.model_sync_frequency 定时拉取上游模型列表，模型有变化时会通知管理员，仅支持可以获取模型列表的渠道',
+    model_sync_patterns: '上游新增的模型匹配规则时自动添加到渠道，并从价格服务补充价格，多个规则用逗号分隔，支持*通配符，例如：gpt-4*,*-preview，为空时只通知不添加',
+    model_sync_remove: '开启后上游已下线的模型会自动从渠道中移除，否则只通知'
   },
   modelGroup: 'OpenAI'
 };
@@ -502,6 +522,20 @@ const typeConfig = {
     },
     modelGroup: 'Jina'
   },
+  48: {
+    input: {
+      models: []
+    },
+    inputLabel: {
+      other: '接口格式'
+    },
+    prompt: {
+      base_url: '兼容 Jina/Cohere 重排序接口的服务地址，例如 http://127.0.0.1:7997，默认请求 /v1/rerank，地址以 /rerank 结尾时直接使用',
+      key: '服务未开启鉴权时可随意填写',
+      other: '使用 text-embeddings-inference 时填写 tei，其他服务留空',
+      test_model: ''
+    }
+  },
   49: {
     input: {
       models: ['gpt-4o', 'gpt-4o-mini', 'text-embedding-3-large', 'text-embedding-3-small', 'Cohere-command-r-plus', 'Cohere-command-r'],
@@ -567,6 +601,44 @@ const typeConfig = {
     inputLabel: {
       provider_models_list: '从OR获取模型列表'
     }
+  },
+  57: {
+    input: {
+      models: ['whisper-1']
+    },
+    inputLabel: {
+      other: '接口格式'
+    },
+    prompt: {
+      base_url: '语音识别服务地址，例如 http://127.0.0.1:8000',
+      key: '服务未开启鉴权时可随意填写',
+      other: '使用 whisper.cpp server 时填写 whisper.cpp，OpenAI 兼容接口（faster-whisper-server 等）留空',
+      test_model: ''
+    }
+  },
+  58: {
+    input: {
+      models: ['tts-1']
+    },
+    inputLabel: {
+      other: '支持的输出格式'
+    },
+    prompt: {
+      base_url: '语音合成服务地址，例如 http://127.0.0.1:8880',
+      key: '服务未开启鉴权时可随意填写',
+      other: '服务支持的 response_format，多个用英文逗号隔开，例如 wav,pcm。留空表示支持全部格式，不支持的格式将使用 ffmpeg 转码',
+      test_model: ''
+    }
+  },
+  59: {
+    inputLabel: {
+      other: '接口描述'
+    },
+    prompt: {
+      base_url: '上游服务地址，留空时使用接口描述中的 base_url',
+      other:
+        '使用 JSON 或 YAML 描述上游接口：auth 鉴权方式（bearer/header/query/basic/none），chat、embeddings、models 接口的 url（支持 {model} 占位符）、request 请求字段映射、response 响应字段映射、stream 流式格式（sse/ndjson）、usage 用量路径，error 错误信息路径。字段路径使用 $.a.b[0].c 形式，未配置映射时按 OpenAI 格式处理'
+    }
   }
 };
 
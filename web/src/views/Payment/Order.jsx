@@ -1,5 +1,5 @@
 import { useState, useEffect, useCallback } from 'react';
-import { showError, trims } from 'utils/common';
+import { showError, showSuccess, trims } from 'utils/common';
 import { useTranslation } from 'react-i18next';
 
 import Table from '@mui/material/Table';
@@ -101,6 +101,21 @@ export default function Order() {
     setSearching(false);
   }, []);
 
+  const refundOrder = async (id) => {
+    try {
+      const res = await API.post(`/api/payment/order/${id}/refund`);
+      const { success, message } = res.data;
+      if (success) {
+        showSuccess('退款成功');
+        setRefreshFlag(!refreshFlag);
+      } else {
+        showError(message);
+      }
+    } catch (error) {
+      console.error(error);
+    }
+  };
+
   // 处理刷新
   const handleRefresh = async () => {
     setOrderBy('created_at');
@@ -162,12 +177,13 @@ export default function Order() {
                   { id: 'discount', label: t('orderlogPage.tableHeaders.discount'), disableSort: true },
                   { id: 'order_amount', label: t('orderlogPage.tableHeaders.order_amount'), disableSort: true },
                   { id: 'quota', label: t('orderlogPage.tableHeaders.quota'), disableSort: true },
-                  { id: 'status', label: t('orderlogPage.tableHeaders.status'), disableSort: false }
+                  { id: 'status', label: t('orderlogPage.tableHeaders.status'), disableSort: false },
+                  { id: 'action', label: t('common.action'), disableSort: true }
                 ]}
               />
               <TableBody>
                 {orderList.map((row, index) => (
-                  <LogTableRow item={row} key={`${row.id}_${index}`} />
+                  <LogTableRow item={row} refundOrder={refundOrder} key={`${row.id}_${index}`} />
                 ))}
               </TableBody>
             </Table>
@@ -1,6 +1,7 @@
 import PropTypes from 'prop-types';
+import { useState } from 'react';
 
-import { TableRow, TableCell } from '@mui/material';
+import { TableRow, TableCell, Button, Dialog, DialogTitle, DialogContent, DialogContentText, DialogActions } from '@mui/material';
 
 import { timestamp2string } from 'utils/common';
 import Label from 'ui-component/Label';
@@ -9,7 +10,8 @@ const StatusType = {
   pending: { name: '待支付', value: 'pending', color: 'primary' },
   success: { name: '支付成功', value: 'success', color: 'success' },
   failed: { name: '支付失败', value: 'failed', color: 'error' },
-  closed: { name: '已关闭', value: 'closed', color: 'default' }
+  closed: { name: '已关闭', value: 'closed', color: 'default' },
+  refunded: { name: '已退款', value: 'refunded', color: 'warning' }
 };
 
 function statusLabel(status) {
@@ -20,7 +22,14 @@ function statusLabel(status) {
 
 export { StatusType };
 
-export default function OrderTableRow({ item }) {
+export default function OrderTableRow({ item, refundOrder }) {
+  const [openRefund, setOpenRefund] = useState(false);
+
+  const handleRefund = async () => {
+    setOpenRefund(false);
+    await refundOrder(item.id);
+  };
+
   return (
     <>
       <TableRow tabIndex={item.id}>
@@ -39,11 +48,34 @@ export default function OrderTableRow({ item }) {
         </TableCell>
         <TableCell>{item.quota}</TableCell>
         <TableCell>{statusLabel(item.status)}</TableCell>
+        <TableCell>
+          {item.status === 'success' && (
+            <Button size="small" color="error" onClick={() => setOpenRefund(true)}>
+              退款
+            </Button>
+          )}
+        </TableCell>
       </TableRow>
+
+      <Dialog open={openRefund} onClose={() => setOpenRefund(false)}>
+        <DialogTitle>订单退款</DialogTitle>
+        <DialogContent>
+          <DialogContentText>
+            确定将订单 {item.trade_no} 标记为已退款，并扣除用户 {item.quota} 点额度？款项需在支付平台后台退回。
+          </DialogContentText>
+        </DialogContent>
+        <DialogActions>
+          <Button onClick={() => setOpenRefund(false)}>取消</Button>
+          <Button onClick={handleRefund} sx={{ color: 'error.main' }} autoFocus>
+            退款
+          </Button>
+        </DialogActions>
+      </Dialog>
     </>
   );
 }
 
 OrderTableRow.propTypes = {
-  item: PropTypes.object
+  item: PropTypes.object,
+  refundOrder: PropTypes.func
 };
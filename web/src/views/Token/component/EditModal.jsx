@@ -72,6 +72,10 @@ const originInputs = {
       enabled: false,
       timeout_seconds: 30
     },
+    prompt_cache: {
+      enabled: false,
+      min_tokens: 0
+    },
     limits: {
       limit_model_setting: {
         enabled: false,
@@ -134,6 +138,9 @@ const EditModal = ({ open, tokenId, onCancel, onOk, userGroupOptions }) => {
     setSubmitting(true);
     values.remain_quota = parseInt(values.remain_quota);
     values.setting.heartbeat.timeout_seconds = parseInt(values.setting.heartbeat.timeout_seconds);
+    if (values.setting?.prompt_cache) {
+      values.setting.prompt_cache.min_tokens = parseInt(values.setting.prompt_cache.min_tokens) || 0;
+    }
 
     // 过滤掉空的 IP 行
     if (values.setting?.limits?.limits_ip_setting?.whitelist) {
@@ -356,6 +363,42 @@ const EditModal = ({ open, tokenId, onCancel, onOk, userGroupOptions }) => {
                 </FormControl>
               )}
 
+              <Divider sx={{ margin: '16px 0px' }} />
+              <Typography variant="h4">{t('token_index.promptCache')}</Typography>
+              <Typography variant="caption">{t('token_index.promptCacheTip')}</Typography>
+
+              <FormControl fullWidth>
+                <FormControlLabel
+                  control={
+                    <Switch
+                      checked={values?.setting?.prompt_cache?.enabled === true}
+                      onClick={() => {
+                        setFieldValue('setting.prompt_cache.enabled', !values.setting?.prompt_cache?.enabled);
+                      }}
+                    />
+                  }
+                  label={t('token_index.promptCache')}
+                />
+              </FormControl>
+
+              {values?.setting?.prompt_cache?.enabled && (
+                <FormControl fullWidth>
+                  <InputLabel>{t('token_index.promptCacheMinTokens')}</InputLabel>
+                  <OutlinedInput
+                    id="token-prompt-cache-min-tokens-label"
+                    label={t('token_index.promptCacheMinTokens')}
+                    type="number"
+                    value={values?.setting?.prompt_cache?.min_tokens || 0}
+                    onChange={(e) => {
+                      setFieldValue('setting.prompt_cache.min_tokens', e.target.value);
+                    }}
+                  />
+                  <FormHelperText id="helper-tex-token-prompt-cache-min-tokens-label">
+                    {t('token_index.promptCacheMinTokensHelperText')}
+                  </FormHelperText>
+                </FormControl>
+              )}
+
               <Divider sx={{ margin: '16px 0px' }} />
               <Typography variant="h4">{t('token_index.selectGroup')}</Typography>
               <Typography variant="caption">{t('token_index.selectGroupInfo')}</Typography>